@@ -28,9 +28,20 @@ func StartCatalogIndexer(
 		return err
 	}
 
+	// generic catalogs are described by the source configuration
+	if err := app.GenericCatalog(cfg.CatalogIndexer.Source); err != nil {
+		return err
+	}
+
+	// detections are added to objects already in the mastercat, so only the metadata writer runs
+	detections := app.DetectionsSource(cfg.CatalogIndexer.Source)
+	indexMetadata := cfg.CatalogIndexer.Source.Metadata || detections
+
 	// update catalogs table
-	catalogRegister := app.CatalogRegister(ctx, repo, cfg.CatalogIndexer.Source)
-	catalogRegister.RegisterCatalog()
+	if !detections {
+		catalogRegister := app.CatalogRegister(ctx, repo, cfg.CatalogIndexer.Source)
+		catalogRegister.RegisterCatalog()
+	}
 
 	src, err := app.Source(cfg.CatalogIndexer.Source)
 	if err != nil {
@@ -38,34 +49,47 @@ func StartCatalogIndexer(
 	}
 
 	// initialize mastercatWriter
-	mastercatWriter, err := app.MastercatWriter(ctx, cfg, repo, src)
-	if err != nil {
-		return err
+	var mastercatWriter *actor.Actor
+	if !detections {
+		mastercatWriter, err = app.MastercatWriter(ctx, cfg, repo, src)
+		if err != nil {
+			return err
+		}
+		mastercatWriter.Start()
 	}
-	mastercatWriter.Start()
 
 	// initialize metadata writer
-	metadataWriter, err := app.MetadataWriter(ctx, cfg, repo, src)
-	if cfg.CatalogIndexer.Source.Metadata {
+	var metadataWriter *actor.Actor
+	if indexMetadata {
+		metadataWriter, err = app.MetadataWriter(ctx, cfg, repo, src)
+		if err != nil {
+			return err
+		}
 		metadataWriter.Start()
 	}
 
 	// initialize indexer
-	mastercatIndexer, err := app.MastercatIndexer(cfg.CatalogIndexer, mastercatWriter, ctx)
-	if err != nil {
-		return err
+	var mastercatIndexer *actor.Actor
+	if !detections {
+		mastercatIndexer, err = app.MastercatIndexer(cfg.CatalogIndexer, mastercatWriter, ctx)
+		if err != nil {
+			return err
+		}
+		mastercatIndexer.Start()
 	}
-	mastercatIndexer.Start()
 
 	// initialize metadata indexer
 	var metadataIndexer *actor.Actor
-	if cfg.CatalogIndexer.Source.Metadata {
+	if indexMetadata {
 		metadataIndexer = app.MetadataIndexer(cfg.CatalogIndexer, metadataWriter, ctx)
 		metadataIndexer.Start()
 	}
 
 	// initialize reader
-	sourceReader, err := app.Reader(src, cfg.CatalogIndexer.Reader, cfg.CatalogIndexer.Source, mastercatIndexer, metadataIndexer)
+	sourceReader, err := app.Reader(src, cfg.CatalogIndexer.Reader, mastercatIndexer, metadataIndexer)
+	if err != nil {
+		return err
+	}
 	defer func() error {
 		err := sourceReader.Close()
 		if err != nil {
@@ -75,13 +99,21 @@ func StartCatalogIndexer(
 	}()
 
 	sourceReader.Read()
-	mastercatIndexer.Stop()
-	mastercatWriter.Stop()
-	if cfg.CatalogIndexer.Source.Metadata {
+	if !detections {
+		mastercatIndexer.Stop()
+		mastercatWriter.Stop()
+	}
+	if indexMetadata {
 		metadataIndexer.Stop()
 		metadataWriter.Stop()
 	}
 
+	// the service drops what it cached while the catalog was being written
+	// once it sees a new version, so the version is bumped as the last step
+	if err := repo.BumpIndexVersion(ctx); err != nil {
+		return fmt.Errorf("could not bump the index version: %w", err)
+	}
+
 	slog.Info("Catalog indexer finished successfully")
 	return nil
 }
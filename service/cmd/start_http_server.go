@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"time"
 
 	"github.com/dirodriguezm/xmatch/service/internal/app"
 
@@ -39,22 +40,36 @@ func StartHttpServer(
 
 	repo := app.ServiceRepository(db)
 
-	conesearchService, err := app.ConesearchService(repo)
+	conesearchService, err := app.ConesearchService(repo, app.ConesearchCacheOptions(cfg.Service.Cache)...)
 	if err != nil {
 		return fmt.Errorf("creating conesearch service: %w", err)
 	}
+	if interval := cfg.Service.Cache.IndexRefreshInterval; interval > 0 {
+		go conesearchService.WatchIndexVersion(ctx, time.Duration(interval)*time.Second)
+	}
 
 	metadataService, err := app.MetadataService(repo)
 	if err != nil {
 		return fmt.Errorf("creating metadata service: %w", err)
 	}
 
-	lightcurveService, err := app.LightcurveService(cfg, conesearchService)
+	lightcurveService, err := app.LightcurveService(cfg, conesearchService, app.LightcurveRepository(db))
 	if err != nil {
 		return fmt.Errorf("creating lightcurve service: %w", err)
 	}
 
-	api, err := app.API(conesearchService, metadataService, lightcurveService, cfg.Service, getenv)
+	tapService, err := app.TapService(repo)
+	if err != nil {
+		return fmt.Errorf("creating TAP service: %w", err)
+	}
+
+	jobsService, err := app.JobsService(app.JobsRepository(db), conesearchService, cfg.Service)
+	if err != nil {
+		return fmt.Errorf("creating jobs service: %w", err)
+	}
+	go jobsService.Start(ctx)
+
+	api, err := app.API(conesearchService, metadataService, lightcurveService, tapService, jobsService, cfg.Service, getenv)
 	if err != nil {
 		return fmt.Errorf("creating API: %w", err)
 	}
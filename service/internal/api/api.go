@@ -19,14 +19,18 @@ import (
 
 	"github.com/dirodriguezm/xmatch/service/internal/config"
 	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+	"github.com/dirodriguezm/xmatch/service/internal/search/jobs"
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
 	"github.com/dirodriguezm/xmatch/service/internal/search/metadata"
+	"github.com/dirodriguezm/xmatch/service/internal/search/tap"
 )
 
 type API struct {
 	conesearchService *conesearch.ConesearchService
 	metadataService   *metadata.MetadataService
 	lightcurveService *lightcurve.LightcurveService
+	tapService        *tap.TapService
+	jobsService       *jobs.JobsService
 	config            config.ServiceConfig
 	getenv            func(string) string
 }
@@ -35,6 +39,8 @@ func New(
 	conesearchService *conesearch.ConesearchService,
 	metadataService *metadata.MetadataService,
 	lightcurveService *lightcurve.LightcurveService,
+	tapService *tap.TapService,
+	jobsService *jobs.JobsService,
 	config config.ServiceConfig,
 	getenv func(string) string,
 ) (*API, error) {
@@ -47,5 +53,11 @@ func New(
 	if lightcurveService == nil {
 		return nil, fmt.Errorf("LightcurveService was nil while creating HttpServer")
 	}
-	return &API{conesearchService, metadataService, lightcurveService, config, getenv}, nil
+	if tapService == nil {
+		return nil, fmt.Errorf("TapService was nil while creating HttpServer")
+	}
+	if jobsService == nil {
+		return nil, fmt.Errorf("JobsService was nil while creating HttpServer")
+	}
+	return &API{conesearchService, metadataService, lightcurveService, tapService, jobsService, config, getenv}, nil
 }
@@ -25,16 +25,18 @@ import (
 // Search for objects in a given region using multiple coordinates
 //
 //	@Summary		Search for objects in a given region using multiple coordinates
-//	@Description	Search for objects in a given region using list of ra, dec and a single radius
+//	@Description	Search for objects in a given region using list of ra, dec and radius. The radius and nneighbor can be a single value for every position, or an array with one value for each position. With an Accept header of application/x-ndjson, results are streamed as one JSON object per line as soon as each chunk of coordinates is done.
 //	@Tags			conesearch
 //	@Accept			json
 //	@Produce		json
+//	@Produce		application/x-ndjson
+//	@Produce		application/x-votable+xml
 //
 //	@Param			ra			body		[]float64	true	"Right ascension in degrees"
 //	@Param			dec			body		[]float64	true	"Declination in degrees"
-//	@Param			radius		body		float64		true	"Radius in degrees"
+//	@Param			radius		body		[]float64	true	"Radius in arcsec, for every position or for each one"
 //	@Param			catalog		body		string		false	"Catalog to search in"
-//	@Param			nneighbor	body		int			false	"Number of neighbors to return"
+//	@Param			nneighbor	body		[]int		false	"Number of neighbors to return, for every position or for each one"
 //
 //	@Success		200			{array}		repository.Mastercat
 //	@Success		204			{string}	string
@@ -49,14 +51,38 @@ func (api *API) conesearchBulk(c *gin.Context) {
 		return
 	}
 
-	if bulkRequest.Nneighbor == 0 {
-		bulkRequest.Nneighbor = 1
+	if len(bulkRequest.Nneighbor) == 0 {
+		bulkRequest.Nneighbor = OneOrMany[int]{1}
+	}
+	// a missing or zero nneighbor takes the default of one neighbor, only negatives are rejected
+	for i := range bulkRequest.Nneighbor {
+		if bulkRequest.Nneighbor[i] == 0 {
+			bulkRequest.Nneighbor[i] = 1
+		}
 	}
 	if bulkRequest.Catalog == "" {
 		bulkRequest.Catalog = "all"
 	}
 
+	if wantsNDJSON(c) {
+		writer := newNDJSONWriter[conesearch.MastercatResult](c)
+		err := api.conesearchService.BulkConesearchStream(
+			c.Request.Context(),
+			bulkRequest.Ra,
+			bulkRequest.Dec,
+			bulkRequest.Radius,
+			bulkRequest.Nneighbor,
+			bulkRequest.Catalog,
+			api.config.BulkChunkSize,
+			api.config.MaxBulkConcurrency,
+			writer.write,
+		)
+		writer.finish(err, "Could not execute conesearch", handleServiceError)
+		return
+	}
+
 	result, err := api.conesearchService.BulkConesearch(
+		c.Request.Context(),
 		bulkRequest.Ra,
 		bulkRequest.Dec,
 		bulkRequest.Radius,
@@ -66,20 +92,10 @@ func (api *API) conesearchBulk(c *gin.Context) {
 		api.config.MaxBulkConcurrency,
 	)
 	if err != nil {
-		if errors.As(err, &conesearch.ValidationError{}) {
-			c.JSON(http.StatusBadRequest, err)
-		} else {
-			c.Error(err)
-			c.JSON(http.StatusInternalServerError, "Could not execute conesearch")
-		}
-		return
-	}
-	if len(result) == 0 {
-		c.Writer.WriteHeader(http.StatusNoContent)
+		handleServiceError(err, c)
 		return
 	}
-
-	c.JSON(http.StatusOK, result)
+	handleServiceSuccess(result, c)
 }
 
 // Search for objects in a given region
@@ -89,65 +105,153 @@ func (api *API) conesearchBulk(c *gin.Context) {
 //		@Tags			conesearch
 //		@Accept			json
 //		@Produce		json
-//		@Param			ra			query		string	true	"Right ascension in degrees"
-//		@Param			dec			query		string	true	"Declination in degrees"
+//		@Produce		application/x-votable+xml
+//		@Param			ra			query		string	false	"Right ascension in decimal degrees, or sexagesimal like 12:34:56.7 or 12h34m56.7s. Galactic or ecliptic longitude with the frame parameter"
+//		@Param			dec			query		string	false	"Declination in decimal degrees, or sexagesimal like -05:06:07 or -05d06m07s. Galactic or ecliptic latitude with the frame parameter"
+//		@Param			frame		query		string	false	"Frame of ra and dec: icrs, galactic or ecliptic"
+//		@Param			name		query		string	false	"Name of an object in the indexed catalogs to search around instead of ra and dec, like a Gaia designation, an eROSITA IAU name or an AllWISE designation"
 //		@Param			radius		query		string	true	"Radius in degrees"
 //		@Param			catalog		query		string	false	"Catalog to search in"
 //		@Param			nneighbor	query		string	false	"Number of neighbors to return"
 //	 @Param			getMetadata	query		string	false	"Return metadata results"
+//	 @Param			pos_err		query		string	false	"Positional error of the target in arcsec. When given, neighbors are scored with a match probability"
+//	 @Param			epoch		query		string	false	"Julian year of the target coordinates. When given, objects are moved to this epoch using their proper motion"
+//	 @Param			limit		query		string	false	"Maximum number of objects to return. When given, results are paginated by distance and the X-Next-Cursor header is set when there are more objects"
+//	 @Param			cursor		query		string	false	"Value of the X-Next-Cursor header of the previous page"
+//	 @Param			format		query		string	false	"Set to votable to get a VOTable instead of JSON. An Accept header of application/x-votable+xml works too"
 //		@Success		200			{array}		repository.Mastercat
 //		@Success		204			{string}	string
 //		@Failure		400			{object}	conesearch.ValidationError
 //		@Failure		500			{string}	string
 //		@Router			/conesearch [get]
 func (api *API) conesearch(c *gin.Context) {
-	ra := c.Query("ra")
-	dec := c.Query("dec")
 	radius := c.Query("radius")
 	catalog := c.DefaultQuery("catalog", "all")
 	nneighbor := c.DefaultQuery("nneighbor", "1")
 	getMetadata := c.DefaultQuery("getMetadata", "false")
+	posErr, probabilistic := c.GetQuery("pos_err")
+	epoch, propagate := c.GetQuery("epoch")
 
-	parsedRa, err := parseRa(ra)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+	parsedRa, parsedDec, ok := api.parsePosition(c)
+	if !ok {
 		return
 	}
-	parsedDec, err := parseDec(dec)
+	parsedRadius, err := parseRadius(radius)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
-	parsedRadius, err := parseRadius(radius)
+	parsedNneighbor, err := parseNneighbor(nneighbor)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
-	parsedNneighbor, err := parseNneighbor(nneighbor)
+	p, err := parsePagination(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
 
+	if probabilistic && propagate {
+		c.JSON(http.StatusBadRequest, NewParseError(epoch, "epoch", "Epoch can't be combined with pos_err."))
+		return
+	}
+
+	if propagate {
+		parsedEpoch, err := parseEpoch(epoch)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+		api.conesearchAtEpoch(c, parsedRa, parsedDec, parsedRadius, parsedNneighbor, catalog, parsedEpoch, getMetadata == "true", p)
+		return
+	}
+
+	if probabilistic {
+		parsedPosErr, err := parsePosErr(posErr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+		api.probabilisticConesearch(c, parsedRa, parsedDec, parsedRadius, parsedPosErr, parsedNneighbor, catalog, getMetadata == "true", p)
+		return
+	}
+
 	if getMetadata == "true" {
-		result, err := api.conesearchService.FindMetadataByConesearch(parsedRa, parsedDec, parsedRadius, parsedNneighbor, catalog)
+		result, err := api.conesearchService.FindMetadataByConesearch(c.Request.Context(), parsedRa, parsedDec, parsedRadius, parsedNneighbor, catalog)
 		if err != nil {
 			handleServiceError(err, c)
 			return
 		}
-		handleServiceSuccess(result, c)
+		handleMetadataPage(result, p, c)
 	} else {
-		result, err := api.conesearchService.Conesearch(parsedRa, parsedDec, parsedRadius, parsedNneighbor, catalog)
+		result, err := api.conesearchService.Conesearch(c.Request.Context(), parsedRa, parsedDec, parsedRadius, parsedNneighbor, catalog)
 		if err != nil {
 			handleServiceError(err, c)
+			return
 		}
-		handleServiceSuccess(result, c)
+		handleMastercatPage(result, p, c)
 	}
 }
 
+func (api *API) probabilisticConesearch(
+	c *gin.Context,
+	ra, dec, radius, posErr float64,
+	nneighbor int,
+	catalog string,
+	getMetadata bool,
+	p pagination,
+) {
+	if getMetadata {
+		result, err := api.conesearchService.FindMetadataByProbabilisticConesearch(c.Request.Context(), ra, dec, radius, posErr, nneighbor, catalog)
+		if err != nil {
+			handleServiceError(err, c)
+			return
+		}
+		handleMetadataPage(result, p, c)
+		return
+	}
+
+	result, err := api.conesearchService.ProbabilisticConesearch(c.Request.Context(), ra, dec, radius, posErr, nneighbor, catalog)
+	if err != nil {
+		handleServiceError(err, c)
+		return
+	}
+	handleMastercatPage(result, p, c)
+}
+
+func (api *API) conesearchAtEpoch(
+	c *gin.Context,
+	ra, dec, radius float64,
+	nneighbor int,
+	catalog string,
+	epoch float64,
+	getMetadata bool,
+	p pagination,
+) {
+	if getMetadata {
+		result, err := api.conesearchService.FindMetadataByConesearchAtEpoch(c.Request.Context(), ra, dec, radius, nneighbor, catalog, epoch)
+		if err != nil {
+			handleServiceError(err, c)
+			return
+		}
+		handleMetadataPage(result, p, c)
+		return
+	}
+
+	result, err := api.conesearchService.ConesearchAtEpoch(c.Request.Context(), ra, dec, radius, nneighbor, catalog, epoch)
+	if err != nil {
+		handleServiceError(err, c)
+		return
+	}
+	handleMastercatPage(result, p, c)
+}
+
 func handleServiceError(serviceErr error, c *gin.Context) {
 	if errors.As(serviceErr, &conesearch.ValidationError{}) {
 		c.JSON(http.StatusBadRequest, serviceErr)
+	} else if timedOut(serviceErr, c) {
+		handleTimeout(serviceErr, c)
 	} else {
 		c.Error(serviceErr)
 		c.JSON(http.StatusInternalServerError, "Could not execute conesearch")
@@ -159,6 +263,9 @@ func handleServiceSuccess[T any](result []T, c *gin.Context) {
 		c.Writer.WriteHeader(http.StatusNoContent)
 		return
 	}
+	if wantsVOTable(c) && handleVOTableSuccess(result, c) {
+		return
+	}
 
 	c.JSON(http.StatusOK, result)
 }
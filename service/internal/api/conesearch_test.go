@@ -69,6 +69,16 @@ func TestConesearch_Validation(t *testing.T) {
 			"ErrValue": "-1",
 			"Reason":   "Nneighbor must be a positive integer",
 		}},
+		"/v1/conesearch?ra=1&dec=1&radius=1&limit=0": {400, map[string]string{
+			"Field":    "limit",
+			"ErrValue": "0",
+			"Reason":   "Limit must be between 1 and 10000.",
+		}},
+		"/v1/conesearch?ra=1&dec=1&radius=1&cursor=a": {400, map[string]string{
+			"Field":    "cursor",
+			"ErrValue": "a",
+			"Reason":   "Invalid cursor.",
+		}},
 	}
 
 	for testPath, expected := range testCases {
@@ -225,6 +235,70 @@ func TestConesearch_NNeighbor(t *testing.T) {
 	}
 }
 
+func TestConesearch_Cursor(t *testing.T) {
+	beforeTest(t)
+
+	getenv := func(key string) string {
+		switch key {
+		case "LOG_LEVEL":
+			return "debug"
+		case "CONFIG_PATH":
+			return configPath
+		default:
+			return ""
+		}
+	}
+
+	cfg, err := app.Config(getenv)
+	require.NoError(t, err)
+
+	db, err := app.ServiceDatabase(cfg)
+	require.NoError(t, err)
+
+	repo := repository.New(db)
+	mapper, err := healpix.NewHEALPixMapper(18, healpix.Nest)
+	require.NoError(t, err)
+
+	expected := []string{}
+	for i := range 5 {
+		ra := float64(i) * 0.0001
+		id := fmt.Sprintf("allwise-%d", i)
+		err = repo.InsertObject(context.Background(), repository.InsertObjectParams{
+			ID:   id,
+			Ra:   ra,
+			Dec:  0,
+			Ipix: mapper.PixelAt(healpix.RADec(ra, 0)),
+			Cat:  "allwise",
+		})
+		require.NoError(t, err)
+		expected = append(expected, id)
+	}
+
+	ids := []string{}
+	path := "/v1/conesearch?ra=0&dec=0&radius=5&nneighbor=10&limit=2"
+	for range 5 {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		ids = append(ids, resultIds(t, w.Body.Bytes())...)
+
+		next := w.Header().Get("X-Next-Cursor")
+		if next == "" {
+			break
+		}
+		path = "/v1/conesearch?ra=0&dec=0&radius=5&nneighbor=10&limit=2&cursor=" + next
+	}
+	require.Equal(t, expected, ids, "pages follow the distance order")
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/conesearch?ra=0&dec=0&radius=5&nneighbor=10", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Header().Get("X-Next-Cursor"), "results are not paginated without limit or cursor")
+	require.Len(t, resultIds(t, w.Body.Bytes()), 5)
+}
+
 func TestBulkConesearch(t *testing.T) {
 	beforeTest(t)
 
@@ -297,3 +371,348 @@ func TestBulkConesearch(t *testing.T) {
 		require.GreaterOrEqualf(t, len(result), 1, "On ra=%d, dec=%d", ra, dec)
 	}
 }
+
+func TestConesearch_PosErr(t *testing.T) {
+	beforeTest(t)
+
+	getenv := func(key string) string {
+		switch key {
+		case "LOG_LEVEL":
+			return "debug"
+		case "CONFIG_PATH":
+			return configPath
+		default:
+			return ""
+		}
+	}
+	stdout := &strings.Builder{}
+
+	cfg, err := app.Config(getenv)
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+
+	logger := app.ServiceLogger(getenv, stdout)
+	slog.SetDefault(logger)
+
+	db, err := app.ServiceDatabase(cfg)
+	if err != nil {
+		t.Fatalf("creating database connection: %v", err)
+	}
+
+	repo := repository.New(db)
+	mapper, err := healpix.NewHEALPixMapper(18, healpix.Nest)
+	require.NoError(t, err)
+
+	posErr := 0.2
+	err = repo.InsertObject(context.Background(), repository.InsertObjectParams{
+		ID:     "allwise-1",
+		Ra:     1,
+		Dec:    1,
+		Ipix:   mapper.PixelAt(healpix.RADec(1, 1)),
+		Cat:    "allwise",
+		PosErr: &posErr,
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/conesearch?ra=1&dec=1&radius=1&catalog=allwise&pos_err=0.2", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var result []conesearch.MastercatResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	require.Len(t, result[0].Data, 1)
+	require.Equal(t, posErr, *result[0].Data[0].PosErr)
+	require.NotNil(t, result[0].Data[0].BayesFactor)
+	require.NotNil(t, result[0].Data[0].Probability)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/conesearch?ra=1&dec=1&radius=1&catalog=allwise&pos_err=a", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestConesearch_Epoch(t *testing.T) {
+	beforeTest(t)
+
+	getenv := func(key string) string {
+		switch key {
+		case "LOG_LEVEL":
+			return "debug"
+		case "CONFIG_PATH":
+			return configPath
+		default:
+			return ""
+		}
+	}
+	stdout := &strings.Builder{}
+
+	cfg, err := app.Config(getenv)
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+
+	logger := app.ServiceLogger(getenv, stdout)
+	slog.SetDefault(logger)
+
+	db, err := app.ServiceDatabase(cfg)
+	if err != nil {
+		t.Fatalf("creating database connection: %v", err)
+	}
+
+	repo := repository.New(db)
+	mapper, err := healpix.NewHEALPixMapper(18, healpix.Nest)
+	require.NoError(t, err)
+
+	// moves 1 arcsec/yr to the north
+	pmra, pmdec, refEpoch := 0.0, 1000.0, 2016.0
+	err = repo.InsertObject(context.Background(), repository.InsertObjectParams{
+		ID:       "gaia-1",
+		Ra:       1,
+		Dec:      1,
+		Ipix:     mapper.PixelAt(healpix.RADec(1, 1)),
+		Cat:      "gaia",
+		Pmra:     &pmra,
+		Pmdec:    &pmdec,
+		RefEpoch: &refEpoch,
+	})
+	require.NoError(t, err)
+
+	target := "/v1/conesearch?ra=1&dec=1.0027777777777778&radius=1"
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", target, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", target+"&epoch=2026", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var result []conesearch.MastercatResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	require.Len(t, result[0].Data, 1)
+	require.Equal(t, "gaia-1", result[0].Data[0].ID)
+	require.InDelta(t, 1+10.0/3600, result[0].Data[0].Dec, 1e-6)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", target+"&epoch=2026&pos_err=0.1", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", target+"&epoch=a", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBulkConesearch_NDJSON(t *testing.T) {
+	beforeTest(t)
+	insertRegionObjects(t)
+
+	bbody, err := json.Marshal(map[string]any{
+		"ra":        []float64{359.5, 100, 0.5},
+		"dec":       []float64{0.5, 0, 0.5},
+		"radius":    1,
+		"nneighbor": 10,
+	})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest("POST", "/v1/bulk-conesearch", bytes.NewReader(bbody))
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	ids := make(map[int]string)
+	decoder := json.NewDecoder(w.Body)
+	for decoder.More() {
+		var result conesearch.MastercatResult
+		require.NoError(t, decoder.Decode(&result))
+		require.Len(t, result.Data, 1)
+		ids[result.Index] = result.Data[0].ID
+	}
+	require.Equal(t, map[int]string{0: "west", 2: "east"}, ids)
+
+	// validation errors are answered before the stream starts
+	bbody, err = json.Marshal(map[string]any{"ra": []float64{1}, "dec": []float64{1}, "radius": -1})
+	require.NoError(t, err)
+	req, err = http.NewRequest("POST", "/v1/bulk-conesearch", bytes.NewReader(bbody))
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBulkConesearch_PerPositionRadius(t *testing.T) {
+	beforeTest(t)
+	insertUploadTestObject(t)
+
+	// allwise-1 is 1.8 arcsec away from both positions
+	bbody, err := json.Marshal(map[string]any{
+		"ra":        []float64{10.0005, 10.0005},
+		"dec":       []float64{10, 10},
+		"radius":    []float64{1, 3},
+		"nneighbor": []int{1, 1},
+		"catalog":   "allwise",
+	})
+	require.NoError(t, err)
+	req, err := http.NewRequest("POST", "/v1/bulk-conesearch", bytes.NewReader(bbody))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var result []conesearch.MastercatResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result, 1)
+	require.Equal(t, 1, result[0].Index)
+	require.Equal(t, "allwise-1", result[0].Data[0].ID)
+
+	// the radius must have one value for each position
+	bbody, err = json.Marshal(map[string]any{"ra": []float64{1, 2, 3}, "dec": []float64{1, 2, 3}, "radius": []float64{1, 2}})
+	require.NoError(t, err)
+	req, err = http.NewRequest("POST", "/v1/bulk-conesearch", bytes.NewReader(bbody))
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var validationErr map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &validationErr))
+	require.Equal(t, "radius", validationErr["Field"])
+}
+
+func TestBulkConesearch_DefaultNneighbor(t *testing.T) {
+	beforeTest(t)
+	insertUploadTestObject(t)
+
+	testCases := map[string]struct {
+		nneighbor any
+		status    int
+	}{
+		"scalar zero":    {0, http.StatusOK},
+		"zero elements":  {[]int{0, 2}, http.StatusOK},
+		"negative":       {-1, http.StatusBadRequest},
+		"negative array": {[]int{1, -1}, http.StatusBadRequest},
+	}
+
+	for name, testCase := range testCases {
+		bbody, err := json.Marshal(map[string]any{
+			"ra":        []float64{10, 10},
+			"dec":       []float64{10, 10},
+			"radius":    1,
+			"nneighbor": testCase.nneighbor,
+			"catalog":   "allwise",
+		})
+		require.NoError(t, err)
+		req, err := http.NewRequest("POST", "/v1/bulk-conesearch", bytes.NewReader(bbody))
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, testCase.status, w.Code, "%s: %s", name, w.Body.String())
+		if testCase.status != http.StatusOK {
+			continue
+		}
+
+		var result []conesearch.MastercatResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result), name)
+		require.Len(t, result, 2, name)
+		require.Equal(t, "allwise-1", result[0].Data[0].ID, name)
+	}
+}
+
+func TestConesearch_PositionFormats(t *testing.T) {
+	beforeTest(t)
+	insertUploadTestObject(t)
+
+	// allwise-1 is at ra = dec = 10 degrees, or l = 118.2744, b = -52.7683
+	testCases := map[string]int{
+		"ra=00:40:00&dec=%2B10:00:00":             http.StatusOK,
+		"ra=0h40m0s&dec=10d00m00s":                http.StatusOK,
+		"ra=118.2744&dec=-52.7683&frame=galactic": http.StatusOK,
+		"name=allwise-1":                          http.StatusOK,
+		"name=unknown":                            http.StatusBadRequest,
+		"name=allwise-1&ra=10&dec=10":             http.StatusBadRequest,
+		"ra=10&dec=10&frame=fk4":                  http.StatusBadRequest,
+		"ra=00:40:00&dec=10:70:00":                http.StatusBadRequest,
+	}
+
+	for query, status := range testCases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/conesearch?radius=5&catalog=allwise&"+query, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, status, w.Code, "%s: %s", query, w.Body.String())
+		if status != http.StatusOK {
+			continue
+		}
+
+		var result []conesearch.MastercatResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result), query)
+		require.Len(t, result, 1, query)
+		require.Equal(t, "allwise-1", result[0].Data[0].ID, query)
+	}
+}
+
+func TestConesearch_ErositaMetadata(t *testing.T) {
+	beforeTest(t)
+
+	getenv := func(key string) string {
+		if key == "CONFIG_PATH" {
+			return configPath
+		}
+		return ""
+	}
+	cfg, err := app.Config(getenv)
+	require.NoError(t, err)
+	db, err := app.ServiceDatabase(cfg)
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, test_helpers.InsertAllwiseMastercat(3, db))
+	require.NoError(t, test_helpers.InsertAllwiseMetadata(3, db))
+	require.NoError(t, test_helpers.InsertErositaMastercat(3, db))
+	require.NoError(t, test_helpers.InsertErositaMetadata(3, db))
+
+	type metadataResult struct {
+		Catalog string           `json:"catalog"`
+		Data    []map[string]any `json:"data"`
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/conesearch?ra=1&dec=1&radius=1&catalog=erosita&getMetadata=true", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var result []metadataResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result, 1)
+	require.Equal(t, "eROSITA", result[0].Catalog)
+	require.Len(t, result[0].Data, 1)
+	require.Equal(t, "erosita-1", result[0].Data[0]["id"])
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/conesearch?ra=1&dec=1&radius=1&catalog=all&getMetadata=true&nneighbor=2", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// one result for each catalog with metadata, in the order of the catalogs table
+	result = nil
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result, 2)
+	require.Equal(t, "AllWISE", result[0].Catalog)
+	require.Equal(t, "allwise-1", result[0].Data[0]["id"])
+	require.Equal(t, "eROSITA", result[1].Catalog)
+	require.Equal(t, "erosita-1", result[1].Data[0]["id"])
+}
@@ -0,0 +1,156 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Frames the input coordinates can be given in
+const (
+	frameICRS     = "icrs"
+	frameGalactic = "galactic"
+	frameEcliptic = "ecliptic"
+)
+
+// galacticToICRS rotates galactic unit vectors to ICRS.
+// It is the transpose of the ICRS to galactic matrix of the Hipparcos catalogue.
+var galacticToICRS = [3][3]float64{
+	{-0.0548755604162154, 0.4941094278755837, -0.8676661490190047},
+	{-0.8734370902348850, -0.4448296299600112, -0.1980763734312015},
+	{-0.4838350155487132, 0.7469822444972189, 0.4559837761750669},
+}
+
+// obliquity of the ecliptic at J2000, in degrees
+const obliquityJ2000 = 23.4392911
+
+// parseSexagesimal parses an angle given as degrees or hours, minutes and seconds,
+// like 12:34:56.7, 12 34 56.7, 12h34m56.7s or -05d06m07s.
+// Values with an h are hours and values with a d are degrees. Otherwise,
+// the unit is hours when hours is true.
+func parseSexagesimal(value string, hours bool) (float64, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if strings.Contains(value, "h") {
+		hours = true
+	} else if strings.ContainsAny(value, "d°") {
+		hours = false
+	}
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimLeft(value, "+-")
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return strings.ContainsRune(" :hdms°'\"", r)
+	})
+	if len(parts) == 0 || len(parts) > 3 {
+		return 0, errors.New("expected degrees or hours, minutes and seconds")
+	}
+
+	angle := 0.0
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil || v < 0 {
+			return 0, errors.New("could not parse " + part)
+		}
+		// only the last component can have decimals
+		if i < len(parts)-1 && v != math.Trunc(v) {
+			return 0, errors.New("only the last component can have decimals")
+		}
+		if i > 0 && v >= 60 {
+			return 0, errors.New("minutes and seconds must be less than 60")
+		}
+		angle += v / math.Pow(60, float64(i))
+	}
+	if hours {
+		angle *= 15
+	}
+	if negative {
+		angle = -angle
+	}
+	return angle, nil
+}
+
+// toICRS converts a longitude and latitude in degrees of the given frame to ICRS ra and dec
+func toICRS(frame string, lon, lat float64) (float64, float64, error) {
+	lonRad := lon * math.Pi / 180
+	latRad := lat * math.Pi / 180
+	v := [3]float64{math.Cos(latRad) * math.Cos(lonRad), math.Cos(latRad) * math.Sin(lonRad), math.Sin(latRad)}
+
+	switch strings.ToLower(frame) {
+	case "", frameICRS:
+		return lon, lat, nil
+	case frameGalactic:
+		var r [3]float64
+		for i := range 3 {
+			r[i] = galacticToICRS[i][0]*v[0] + galacticToICRS[i][1]*v[1] + galacticToICRS[i][2]*v[2]
+		}
+		v = r
+	case frameEcliptic:
+		eps := obliquityJ2000 * math.Pi / 180
+		v = [3]float64{v[0], v[1]*math.Cos(eps) - v[2]*math.Sin(eps), v[1]*math.Sin(eps) + v[2]*math.Cos(eps)}
+	default:
+		return 0, 0, NewParseError(frame, "frame", "Frame must be icrs, galactic or ecliptic.")
+	}
+
+	ra := math.Atan2(v[1], v[0]) * 180 / math.Pi
+	if ra < 0 {
+		ra += 360
+	}
+	dec := math.Asin(max(-1, min(1, v[2]))) * 180 / math.Pi
+	return ra, dec, nil
+}
+
+// parsePosition reads the target of a search from the query. The target is
+// either an object name resolved against the indexed catalogs, or ra and dec
+// in the frame given by the frame parameter.
+// The error response is written when the position can't be read.
+func (api *API) parsePosition(c *gin.Context) (float64, float64, bool) {
+	if name := c.Query("name"); name != "" {
+		if c.Query("ra") != "" || c.Query("dec") != "" {
+			c.JSON(http.StatusBadRequest, NewParseError(name, "name", "Name can't be combined with ra and dec."))
+			return 0, 0, false
+		}
+		obj, err := api.conesearchService.ResolveName(c.Request.Context(), name)
+		if err != nil {
+			handleServiceError(err, c)
+			return 0, 0, false
+		}
+		return obj.Ra, obj.Dec, true
+	}
+
+	// sexagesimal longitudes are only read as hours in the equatorial frame
+	frame := strings.ToLower(c.Query("frame"))
+	ra, err := parseAngle(c.Query("ra"), "RA", frame == "" || frame == frameICRS)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return 0, 0, false
+	}
+	dec, err := parseDec(c.Query("dec"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return 0, 0, false
+	}
+	ra, dec, err = toICRS(frame, ra, dec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return 0, 0, false
+	}
+	return ra, dec, true
+}
@@ -0,0 +1,165 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary		Crossmatch a table of sources against the indexed catalogs
+// @Description	For each input row returns the best match (or all matches) in each requested catalog, preserving input order. Rows without counterpart are returned with rank 0.
+// @Tags			crossmatch
+// @Accept			json
+// @Produce		json
+// @Param			id			body		[]string	true	"Identifier of each input row"
+// @Param			ra			body		[]float64	true	"Right ascension in degrees"
+// @Param			dec			body		[]float64	true	"Declination in degrees"
+// @Param			pos_err		body		[]float64	false	"Positional error of each input row in arcsec"
+// @Param			epoch		body		float64		false	"Julian year of the input coordinates. When given, candidates are moved to this epoch using their proper motion"
+// @Param			radius		body		float64		true	"Radius in arcsec"
+// @Param			catalogs	body		[]string	false	"Catalogs to match against"
+// @Param			all_matches	body		bool		false	"Return all matches instead of the best one"
+// @Success		200			{array}		conesearch.CrossmatchResult
+// @Failure		400			{object}	conesearch.ValidationError
+// @Failure		500			{string}	string
+// @Router			/crossmatch [post]
+func (api *API) crossmatch(c *gin.Context) {
+	var request CrossmatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	rows, err := crossmatchInputFromRequest(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := api.conesearchService.Crossmatch(
+		c.Request.Context(),
+		rows,
+		request.Radius,
+		request.Catalogs,
+		request.AllMatches,
+		api.config.BulkChunkSize,
+		api.config.MaxBulkConcurrency,
+	)
+	if err != nil {
+		handleServiceError(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary		Crossmatch an uploaded table against the indexed catalogs
+// @Description	Upload a csv, parquet or VOTable file and get back the same table with the match columns added to each row. There is one row for each input row and catalog, or one for each match when all_matches is set. The result is a VOTable when a VOTable was uploaded or asked for with the Accept header, and csv otherwise.
+// @Tags			crossmatch
+// @Accept			multipart/form-data
+// @Produce		text/csv
+// @Produce		application/x-votable+xml
+// @Param			file		formData	file	true	"Table to crossmatch"
+// @Param			format		formData	string	false	"Format of the file: csv, parquet or votable. Taken from the file extension when not given"
+// @Param			id_column	formData	string	true	"Column with the identifier of each row"
+// @Param			ra_column	formData	string	true	"Column with the right ascension of each row in degrees"
+// @Param			dec_column	formData	string	true	"Column with the declination of each row in degrees"
+// @Param			radius		formData	number	true	"Radius in arcsec"
+// @Param			catalogs	formData	[]string	false	"Catalogs to match against"
+// @Param			all_matches	formData	bool	false	"Return all matches instead of the best one"
+// @Success		200			{string}	string
+// @Failure		400			{object}	ParseError
+// @Failure		500			{string}	string
+// @Router			/crossmatch/upload [post]
+func (api *API) crossmatchUpload(c *gin.Context) {
+	radius, err := parseRadius(c.PostForm("radius"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	allMatches := false
+	if value := c.PostForm("all_matches"); value != "" {
+		if allMatches, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, NewParseError(value, "all_matches", "Could not parse bool."))
+			return
+		}
+	}
+
+	table, ok := receiveUploadedTable(c)
+	if !ok {
+		return
+	}
+
+	result, err := api.conesearchService.Crossmatch(
+		c.Request.Context(),
+		table.crossmatchInput(),
+		radius,
+		uploadCatalogs(c.PostFormArray("catalogs")),
+		allMatches,
+		api.config.BulkChunkSize,
+		api.config.MaxBulkConcurrency,
+	)
+	if err != nil {
+		handleServiceError(err, c)
+		return
+	}
+	writeUploadResult(c, table, result)
+}
+
+// uploadCatalogs accepts the catalogs as repeated fields, as a comma separated list, or both
+func uploadCatalogs(values []string) []string {
+	catalogs := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, catalog := range strings.Split(value, ",") {
+			if catalog = strings.TrimSpace(catalog); catalog != "" {
+				catalogs = append(catalogs, catalog)
+			}
+		}
+	}
+	return catalogs
+}
+
+func crossmatchInputFromRequest(request CrossmatchRequest) ([]conesearch.CrossmatchInput, error) {
+	if len(request.Id) != len(request.Ra) {
+		return nil, NewParseError(fmt.Sprintf("%d", len(request.Id)), "id", "Id and Ra must have the same length.")
+	}
+	if len(request.Dec) != len(request.Ra) {
+		return nil, NewParseError(fmt.Sprintf("%d", len(request.Dec)), "dec", "Ra and Dec must have the same length.")
+	}
+	if len(request.PosErr) > 0 && len(request.PosErr) != len(request.Ra) {
+		return nil, NewParseError(fmt.Sprintf("%d", len(request.PosErr)), "pos_err", "Pos_err must be empty or have the same length as Ra.")
+	}
+
+	rows := make([]conesearch.CrossmatchInput, len(request.Ra))
+	for i := range request.Ra {
+		rows[i] = conesearch.CrossmatchInput{
+			ID:    request.Id[i],
+			Ra:    request.Ra[i],
+			Dec:   request.Dec[i],
+			Epoch: request.Epoch,
+		}
+		if len(request.PosErr) > 0 {
+			rows[i].PosErr = &request.PosErr[i]
+		}
+	}
+	return rows, nil
+}
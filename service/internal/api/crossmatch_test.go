@@ -0,0 +1,314 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dirodriguezm/healpix"
+	"github.com/dirodriguezm/xmatch/service/internal/app"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+	"github.com/dirodriguezm/xmatch/service/internal/utils"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+func TestCrossmatch(t *testing.T) {
+	beforeTest(t)
+
+	getenv := func(key string) string {
+		switch key {
+		case "LOG_LEVEL":
+			return "debug"
+		case "CONFIG_PATH":
+			return configPath
+		default:
+			return ""
+		}
+	}
+	stdout := &strings.Builder{}
+
+	cfg, err := app.Config(getenv)
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+
+	logger := app.ServiceLogger(getenv, stdout)
+	slog.SetDefault(logger)
+
+	db, err := app.ServiceDatabase(cfg)
+	if err != nil {
+		t.Fatalf("creating database connection: %v", err)
+	}
+
+	repo := repository.New(db)
+	mapper, err := healpix.NewHEALPixMapper(18, healpix.Nest)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = repo.InsertObject(ctx, repository.InsertObjectParams{
+		ID:   "allwise-1",
+		Ra:   10,
+		Dec:  10,
+		Ipix: mapper.PixelAt(healpix.RADec(10, 10)),
+		Cat:  "allwise",
+	})
+	require.NoError(t, err)
+
+	jsonBody := map[string]any{
+		"id":       []string{"first", "second"},
+		"ra":       []float64{20, 10},
+		"dec":      []float64{20, 10},
+		"radius":   1,
+		"catalogs": []string{"allwise"},
+	}
+	bbody, err := json.Marshal(jsonBody)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/v1/crossmatch", bytes.NewReader(bbody))
+	require.NoError(t, err)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var result []conesearch.CrossmatchResult
+	err = json.Unmarshal(w.Body.Bytes(), &result)
+	require.NoError(t, err)
+
+	require.Len(t, result, 2)
+	require.Equal(t, "first", result[0].ID)
+	require.Equal(t, 0, result[0].Rank)
+	require.Nil(t, result[0].Match)
+	require.Equal(t, "second", result[1].ID)
+	require.Equal(t, 1, result[1].Index)
+	require.Equal(t, 1, result[1].Rank)
+	require.Equal(t, "allwise-1", result[1].Match.ID)
+}
+
+func TestCrossmatch_Validation(t *testing.T) {
+	testCases := map[string]struct {
+		body  map[string]any
+		field string
+	}{
+		"mismatched id": {
+			body:  map[string]any{"id": []string{"a"}, "ra": []float64{1, 2}, "dec": []float64{1, 2}, "radius": 1},
+			field: "id",
+		},
+		"mismatched pos_err": {
+			body:  map[string]any{"id": []string{"a"}, "ra": []float64{1}, "dec": []float64{1}, "pos_err": []float64{1, 2}, "radius": 1},
+			field: "pos_err",
+		},
+		"invalid radius": {
+			body:  map[string]any{"id": []string{"a"}, "ra": []float64{1}, "dec": []float64{1}, "radius": 0},
+			field: "radius",
+		},
+	}
+
+	for name, tc := range testCases {
+		bbody, err := json.Marshal(tc.body)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/v1/crossmatch", bytes.NewReader(bbody))
+		require.NoError(t, err)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code, name)
+
+		var result map[string]any
+		err = json.Unmarshal(w.Body.Bytes(), &result)
+		require.NoError(t, err)
+		require.Equal(t, tc.field, result["Field"], name)
+	}
+}
+
+func insertUploadTestObject(t *testing.T) {
+	t.Helper()
+	getenv := func(key string) string {
+		if key == "CONFIG_PATH" {
+			return configPath
+		}
+		return ""
+	}
+	cfg, err := app.Config(getenv)
+	require.NoError(t, err)
+	db, err := app.ServiceDatabase(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	mapper, err := healpix.NewHEALPixMapper(18, healpix.Nest)
+	require.NoError(t, err)
+	err = repository.New(db).InsertObject(context.Background(), repository.InsertObjectParams{
+		ID:   "allwise-1",
+		Ra:   10,
+		Dec:  10,
+		Ipix: mapper.PixelAt(healpix.RADec(10, 10)),
+		Cat:  "allwise",
+	})
+	require.NoError(t, err)
+}
+
+func uploadTable(t *testing.T, fileName string, content []byte, fields map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	return postFile(t, "/v1/crossmatch/upload", fileName, content, fields)
+}
+
+// postFile sends a multipart form with the file and fields to the given path
+func postFile(t *testing.T, path, fileName string, content []byte, fields map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	for name, value := range fields {
+		require.NoError(t, form.WriteField(name, value))
+	}
+	require.NoError(t, form.Close())
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", path, &body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	router.ServeHTTP(w, req)
+	return w
+}
+
+var uploadFields = map[string]string{
+	"id_column":  "name",
+	"ra_column":  "RA",
+	"dec_column": "DEC",
+	"radius":     "1",
+	"catalogs":   "allwise",
+}
+
+func TestCrossmatchUpload_CSV(t *testing.T) {
+	beforeTest(t)
+	insertUploadTestObject(t)
+
+	content := "name,RA,DEC,mag\nfirst,20,20,12.5\nsecond,10,10,\n"
+	w := uploadTable(t, "table.csv", []byte(content), uploadFields)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"name", "RA", "DEC", "mag", "match_catalog", "match_rank", "match_id", "match_ra", "match_dec", "match_separation"},
+		{"first", "20", "20", "12.5", "allwise", "0", "", "", "", ""},
+		{"second", "10", "10", "", "allwise", "1", "allwise-1", "10", "10", "0"},
+	}, records)
+}
+
+type uploadParquetRow struct {
+	Name string  `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Ra   float64 `parquet:"name=RA, type=DOUBLE"`
+	Dec  float64 `parquet:"name=DEC, type=DOUBLE"`
+	Nobs int64   `parquet:"name=nobs, type=INT64"`
+}
+
+func TestCrossmatchUpload_Parquet(t *testing.T) {
+	beforeTest(t)
+	insertUploadTestObject(t)
+
+	parquetFile := filepath.Join(t.TempDir(), "table.parquet")
+	fw, err := local.NewLocalFileWriter(parquetFile)
+	require.NoError(t, err)
+	pw, err := writer.NewParquetWriter(fw, new(uploadParquetRow), 1)
+	require.NoError(t, err)
+	require.NoError(t, pw.Write(uploadParquetRow{Name: "second", Ra: 10, Dec: 10, Nobs: 3}))
+	require.NoError(t, pw.WriteStop())
+	require.NoError(t, fw.Close())
+	content, err := os.ReadFile(parquetFile)
+	require.NoError(t, err)
+
+	w := uploadTable(t, "table.parquet", content, uploadFields)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"name", "RA", "DEC", "nobs", "match_catalog", "match_rank", "match_id", "match_ra", "match_dec", "match_separation"},
+		{"second", "10", "10", "3", "allwise", "1", "allwise-1", "10", "10", "0"},
+	}, records)
+}
+
+func TestCrossmatchUpload_VOTable(t *testing.T) {
+	beforeTest(t)
+	insertUploadTestObject(t)
+
+	input := utils.NewVOTable(utils.Table{
+		Name: "input",
+		Fields: []utils.Field{
+			{Name: "name", Datatype: "char", ArraySize: "*"},
+			{Name: "RA", Datatype: "double", Unit: "deg"},
+			{Name: "DEC", Datatype: "double", Unit: "deg"},
+		},
+		Data: utils.Data{TableData: utils.TableData{Rows: []utils.Row{
+			{Columns: []utils.Column{{Value: "second"}, {Value: "10"}, {Value: "10"}}},
+		}}},
+	})
+	content, err := input.Bytes()
+	require.NoError(t, err)
+
+	w := uploadTable(t, "table.vot", content, uploadFields)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	votable, err := utils.NewVOTableFromBytes(w.Body.Bytes())
+	require.NoError(t, err)
+	table := votable.Resource.Tables[0]
+	require.Len(t, table.Fields, 9)
+	require.Equal(t, "deg", table.Fields[1].Unit, "the uploaded fields are kept")
+	require.Equal(t, "match_id", table.Fields[5].Name)
+	require.Len(t, table.Data.TableData.Rows, 1)
+	require.Equal(t, "allwise-1", table.Data.TableData.Rows[0].Columns[5].Value)
+}
+
+func TestCrossmatchUpload_Validation(t *testing.T) {
+	testCases := map[string]struct {
+		fileName string
+		fields   map[string]string
+		field    string
+	}{
+		"unknown format":   {"table.txt", uploadFields, "format"},
+		"missing column":   {"table.csv", map[string]string{"id_column": "name", "ra_column": "RA", "radius": "1"}, "dec_column"},
+		"column not found": {"table.csv", map[string]string{"id_column": "id", "ra_column": "RA", "dec_column": "DEC", "radius": "1"}, "file"},
+		"invalid radius":   {"table.csv", map[string]string{"id_column": "name", "ra_column": "RA", "dec_column": "DEC", "radius": "a"}, "radius"},
+	}
+
+	for name, tc := range testCases {
+		w := uploadTable(t, tc.fileName, []byte("name,RA,DEC\nfirst,1,1\n"), tc.fields)
+		require.Equal(t, http.StatusBadRequest, w.Code, name)
+
+		var result map[string]any
+		err := json.Unmarshal(w.Body.Bytes(), &result)
+		require.NoError(t, err)
+		require.Equal(t, tc.field, result["Field"], name)
+	}
+}
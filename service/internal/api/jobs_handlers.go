@@ -0,0 +1,157 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dirodriguezm/xmatch/service/internal/search/jobs"
+	"github.com/gin-gonic/gin"
+)
+
+var jobResultTypes = map[string]string{
+	jobs.FormatCSV:     "text/csv",
+	jobs.FormatParquet: "application/vnd.apache.parquet",
+}
+
+// @Summary		Submit a crossmatch job
+// @Description	Upload a csv, parquet or VOTable file with positions and run its conesearch in the background. The job can be followed at the returned location, and its result downloaded once it is COMPLETED. Jobs and their results are removed once their destruction time is reached.
+// @Tags			jobs
+// @Accept			multipart/form-data
+// @Produce		json
+// @Param			file			formData	file	true	"Table with the positions to search"
+// @Param			format			formData	string	false	"Format of the file: csv, parquet or votable. Taken from the file extension when not given"
+// @Param			id_column		formData	string	true	"Column with the identifier of each row"
+// @Param			ra_column		formData	string	true	"Column with the right ascension of each row in degrees"
+// @Param			dec_column		formData	string	true	"Column with the declination of each row in degrees"
+// @Param			radius			formData	number	true	"Radius in arcsec"
+// @Param			catalog			formData	string	false	"Catalog to search in"
+// @Param			nneighbor		formData	int		false	"Maximum number of neighbors for each position"
+// @Param			result_format	formData	string	false	"Format of the result file: csv or parquet"
+// @Success		303				{object}	jobs.Job
+// @Header			303				{string}	Location	"Location of the job"
+// @Failure		400				{object}	ParseError
+// @Failure		503				{string}	string
+// @Failure		500				{string}	string
+// @Router			/jobs [post]
+func (api *API) submitJob(c *gin.Context) {
+	radius, err := parseRadius(c.PostForm("radius"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	nneighbor := 1
+	if value := c.PostForm("nneighbor"); value != "" {
+		if nneighbor, err = parseNneighbor(value); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+	}
+	params := jobs.JobParameters{
+		Radius:    radius,
+		Catalog:   c.DefaultPostForm("catalog", "all"),
+		Nneighbor: nneighbor,
+		Format:    c.DefaultPostForm("result_format", jobs.FormatCSV),
+	}
+
+	table, ok := receiveUploadedTable(c)
+	if !ok {
+		return
+	}
+	ids := make([]string, len(table.rows))
+	ra := make([]float64, len(table.rows))
+	dec := make([]float64, len(table.rows))
+	for i, row := range table.rows {
+		ids[i], ra[i], dec[i] = row.ID, row.Ra, row.Dec
+	}
+
+	job, err := api.jobsService.Submit(c.Request.Context(), ids, ra, dec, params)
+	if err != nil {
+		handleJobsError(err, c)
+		return
+	}
+	c.Header("Location", api.config.BasePath+"/jobs/"+job.ID)
+	c.JSON(http.StatusSeeOther, job)
+}
+
+// @Summary		Get the status of a crossmatch job
+// @Description	Get the phase and progress of a job. The phase is one of QUEUED, EXECUTING, COMPLETED or ERROR.
+// @Tags			jobs
+// @Produce		json
+// @Param			id	path		string	true	"Job id"
+// @Success		200	{object}	jobs.Job
+// @Failure		404	{string}	string
+// @Failure		500	{string}	string
+// @Router			/jobs/{id} [get]
+func (api *API) getJob(c *gin.Context) {
+	job, err := api.jobsService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleJobsError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// @Summary		Download the result of a crossmatch job
+// @Description	Download the matches of a COMPLETED job, as a csv or parquet file with the index and id of each input position.
+// @Tags			jobs
+// @Produce		text/csv
+// @Produce		application/vnd.apache.parquet
+// @Param			id	path		string	true	"Job id"
+// @Success		200	{file}		file
+// @Failure		404	{string}	string
+// @Failure		409	{string}	string
+// @Failure		500	{string}	string
+// @Router			/jobs/{id}/result [get]
+func (api *API) getJobResult(c *gin.Context) {
+	id := c.Param("id")
+	fileName, format, err := api.jobsService.Result(c.Request.Context(), id)
+	if err != nil {
+		handleJobsError(err, c)
+		return
+	}
+	c.Header("Content-Type", jobResultTypes[format])
+	c.FileAttachment(fileName, id+"."+format)
+}
+
+// @Summary		Delete a crossmatch job
+// @Description	Abort a job if it is executing, and remove it with its result.
+// @Tags			jobs
+// @Param			id	path	string	true	"Job id"
+// @Success		204
+// @Failure		404	{string}	string
+// @Failure		500	{string}	string
+// @Router			/jobs/{id} [delete]
+func (api *API) deleteJob(c *gin.Context) {
+	if err := api.jobsService.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		handleJobsError(err, c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func handleJobsError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, jobs.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, jobs.ErrJobNotCompleted):
+		c.JSON(http.StatusConflict, err.Error())
+	case errors.Is(err, jobs.ErrQueueFull):
+		c.JSON(http.StatusServiceUnavailable, err.Error())
+	default:
+		handleServiceError(err, c)
+	}
+}
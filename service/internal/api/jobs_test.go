@@ -0,0 +1,105 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dirodriguezm/xmatch/service/internal/search/jobs"
+	"github.com/stretchr/testify/require"
+)
+
+var jobFields = map[string]string{
+	"id_column":  "name",
+	"ra_column":  "RA",
+	"dec_column": "DEC",
+	"radius":     "1",
+	"catalog":    "allwise",
+}
+
+func requestJob(t *testing.T, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(method, path, nil)
+	require.NoError(t, err)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestJobs(t *testing.T) {
+	beforeTest(t)
+	insertUploadTestObject(t)
+
+	content := "name,RA,DEC\nfirst,20,20\nsecond,10,10\n"
+	w := postFile(t, "/v1/jobs", "positions.csv", []byte(content), jobFields)
+	require.Equal(t, http.StatusSeeOther, w.Code, w.Body.String())
+
+	var job jobs.Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	require.Equal(t, jobs.PhaseQueued, job.Phase)
+	require.Equal(t, 2, job.Total)
+	location := w.Header().Get("Location")
+	require.Equal(t, "/v1/jobs/"+job.ID, location)
+
+	require.Eventually(t, func() bool {
+		w := requestJob(t, "GET", location)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+		return job.Phase == jobs.PhaseCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, 2, job.Progress)
+
+	w = requestJob(t, "GET", location+"/result")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, []string{"1", "second", "allwise", "allwise-1", "10", "10"}, records[1][:6])
+
+	w = requestJob(t, "DELETE", location)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = requestJob(t, "GET", location)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	w = requestJob(t, "GET", location+"/result")
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestJobs_Validation(t *testing.T) {
+	testCases := map[string]struct {
+		fields map[string]string
+		field  string
+	}{
+		"invalid radius":    {map[string]string{"id_column": "name", "ra_column": "RA", "dec_column": "DEC", "radius": "a"}, "radius"},
+		"invalid nneighbor": {map[string]string{"id_column": "name", "ra_column": "RA", "dec_column": "DEC", "radius": "1", "nneighbor": "a"}, "nneighbor"},
+		"missing column":    {map[string]string{"id_column": "name", "ra_column": "RA", "radius": "1"}, "dec_column"},
+		"invalid format":    {map[string]string{"id_column": "name", "ra_column": "RA", "dec_column": "DEC", "radius": "1", "result_format": "fits"}, "format"},
+	}
+
+	for name, tc := range testCases {
+		w := postFile(t, "/v1/jobs", "positions.csv", []byte("name,RA,DEC\nfirst,1,1\n"), tc.fields)
+		require.Equal(t, http.StatusBadRequest, w.Code, name)
+
+		var result map[string]any
+		err := json.Unmarshal(w.Body.Bytes(), &result)
+		require.NoError(t, err)
+		require.Equal(t, tc.field, result["Field"], name)
+	}
+}
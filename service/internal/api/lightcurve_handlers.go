@@ -13,49 +13,55 @@ import (
 //	@Tags			lightcurve
 //	@Accept			json
 //	@Produce		json
-//	@Param			ra			query		string	true	"Right Ascension coordinate"
-//	@Param			dec			query		string	true	"Declination coordinate"
+//	@Param			ra			query		string	false	"Right Ascension coordinate, in decimal degrees or sexagesimal"
+//	@Param			dec			query		string	false	"Declination coordinate, in decimal degrees or sexagesimal"
+//	@Param			frame		query		string	false	"Frame of ra and dec: icrs, galactic or ecliptic"
+//	@Param			name		query		string	false	"Name of an object in the indexed catalogs, instead of ra and dec"
 //	@Param			radius		query		string	true	"Search radius in arcseconds"
-//	@Param			catalog		query		string	false	"Catalog to query (all, ztf, neowise, allwise)"
+//	@Param			catalog		query		string	false	"Catalog to query: all, a configured lightcurve source, or a catalog served by one, like allwise"
 //	@Param			nneighbor	query		string	false	"Number of neighbors to return (default: 1)"
+//	@Param			require_all	query		bool	false	"Fail with 502 when any source could not be queried (default: false)"
 //	@Success		200			{object}	LightcurveResponse
 //	@Failure		400			{string}	string
 //	@Failure		500			{string}	string
+//	@Failure		502			{object}	LightcurveResponse
 //	@Router			/lightcurve [get]
 func (api *API) Lightcurve(c *gin.Context) {
-	ra := c.Query("ra")
-	dec := c.Query("dec")
 	radius := c.Query("radius")
 	catalog := c.DefaultQuery("catalog", "all")
 	nneighbor := c.DefaultQuery("nneighbor", "1")
+	requireAll := c.DefaultQuery("require_all", "false")
 
-	parsedRa, err := parseRa(ra)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
+	parsedRa, parsedDec, ok := api.parsePosition(c)
+	if !ok {
 		return
 	}
-	parsedDec, err := parseDec(dec)
+	parsedRadius, err := parseRadius(radius)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
-	parsedRadius, err := parseRadius(radius)
+	parsedNneighbor, err := parseNneighbor(nneighbor)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
-	parsedNneighbor, err := parseNneighbor(nneighbor)
+	parsedCatalog, err := parseLightcurveCatalog(catalog, api.lightcurveService.Catalogs())
 	if err != nil {
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
-	parsedCatalog, err := parseLightcurveCatalog(catalog)
+	parsedRequireAll, err := parseRequireAll(requireAll)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
-	lightcurve, err := api.lightcurveService.GetLightcurve(parsedRa, parsedDec, parsedRadius, parsedNneighbor, parsedCatalog)
+	lightcurve, err := api.lightcurveService.GetLightcurve(c.Request.Context(), parsedRa, parsedDec, parsedRadius, parsedNneighbor, parsedCatalog)
 	if err != nil {
+		if timedOut(err, c) {
+			handleTimeout(err, c)
+			return
+		}
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, "Could not fetch lightcurve")
 		return
@@ -68,5 +74,10 @@ func (api *API) Lightcurve(c *gin.Context) {
 		return
 	}
 
+	if parsedRequireAll && len(lightcurve.Failed()) > 0 {
+		c.JSON(http.StatusBadGateway, response)
+		return
+	}
+
 	c.JSON(http.StatusOK, response)
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/gaia"
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/neowise"
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/ztfdr"
 )
@@ -16,6 +17,18 @@ type LightcurveResponse struct {
 	Detections       []LightcurveEntry `json:"detections"`
 	NonDetections    []LightcurveEntry `json:"non_detections"`
 	ForcedPhotometry []LightcurveEntry `json:"forced_photometry"`
+	// Sources lists every queried source, including the ones that failed
+	Sources []LightcurveSourceStatus `json:"sources"`
+}
+
+// LightcurveSourceStatus tells how a lightcurve source answered the request.
+//
+// swagger:model LightcurveSourceStatus
+type LightcurveSourceStatus struct {
+	Source    string `json:"source"`
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
 }
 
 // LightcurveEntry represents a catalog-aware lightcurve measurement.
@@ -31,7 +44,7 @@ type LightcurveEntry struct {
 	Data     map[string]any `json:"data" swaggertype:"object"`
 }
 
-func newLightcurveResponse(lightcurveData lightcurve.Lightcurve) (LightcurveResponse, error) {
+func newLightcurveResponse(lightcurveData lightcurve.LightcurveResult) (LightcurveResponse, error) {
 	detections, err := newLightcurveEntries(lightcurveData.Detections)
 	if err != nil {
 		return LightcurveResponse{}, err
@@ -51,9 +64,27 @@ func newLightcurveResponse(lightcurveData lightcurve.Lightcurve) (LightcurveResp
 		Detections:       detections,
 		NonDetections:    nonDetections,
 		ForcedPhotometry: forcedPhotometry,
+		Sources:          newLightcurveSourceStatuses(lightcurveData.Sources),
 	}, nil
 }
 
+func newLightcurveSourceStatuses(statuses []lightcurve.SourceStatus) []LightcurveSourceStatus {
+	sources := make([]LightcurveSourceStatus, len(statuses))
+
+	for i, status := range statuses {
+		sources[i] = LightcurveSourceStatus{
+			Source:    status.Source,
+			Status:    status.Status,
+			LatencyMs: status.Latency.Milliseconds(),
+		}
+		if status.Error != nil {
+			sources[i].Error = status.Error.Error()
+		}
+	}
+
+	return sources
+}
+
 func newLightcurveEntries(objects []lightcurve.LightcurveObject) ([]LightcurveEntry, error) {
 	entries := make([]LightcurveEntry, len(objects))
 
@@ -84,6 +115,20 @@ func newLightcurveEntry(object lightcurve.LightcurveObject) (LightcurveEntry, er
 			return LightcurveEntry{}, fmt.Errorf("unsupported nil lightcurve object")
 		}
 		return newNeowiseEntry(*detection)
+	case gaia.Detection:
+		return newGaiaEntry(detection)
+	case *gaia.Detection:
+		if detection == nil {
+			return LightcurveEntry{}, fmt.Errorf("unsupported nil lightcurve object")
+		}
+		return newGaiaEntry(*detection)
+	case lightcurve.XwaveDetection:
+		return newXwaveEntry(detection)
+	case *lightcurve.XwaveDetection:
+		if detection == nil {
+			return LightcurveEntry{}, fmt.Errorf("unsupported nil lightcurve object")
+		}
+		return newXwaveEntry(*detection)
 	default:
 		return LightcurveEntry{}, fmt.Errorf("unsupported lightcurve object type %T", object)
 	}
@@ -123,6 +168,40 @@ func newNeowiseEntry(detection neowise.Detection) (LightcurveEntry, error) {
 	}, nil
 }
 
+func newGaiaEntry(detection gaia.Detection) (LightcurveEntry, error) {
+	data, err := dataFromObject(detection)
+	if err != nil {
+		return LightcurveEntry{}, err
+	}
+
+	return LightcurveEntry{
+		Catalog:  "gaia",
+		ID:       detection.GetId(),
+		ObjectID: detection.GetObjectId(),
+		Mjd:      detection.GetMjd(),
+		Mag:      detection.GetBrightness(),
+		Magerr:   detection.GetBrightnessError(),
+		Data:     data,
+	}, nil
+}
+
+func newXwaveEntry(detection lightcurve.XwaveDetection) (LightcurveEntry, error) {
+	data, err := dataFromObject(detection)
+	if err != nil {
+		return LightcurveEntry{}, err
+	}
+
+	return LightcurveEntry{
+		Catalog:  lightcurve.XwaveSource,
+		ID:       detection.GetId(),
+		ObjectID: detection.GetObjectId(),
+		Mjd:      detection.GetMjd(),
+		Mag:      detection.GetBrightness(),
+		Magerr:   detection.GetBrightnessError(),
+		Data:     data,
+	}, nil
+}
+
 func dataFromObject(object any) (map[string]any, error) {
 	payload, err := json.Marshal(object)
 	if err != nil {
@@ -1,9 +1,13 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/gaia"
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/neowise"
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/ztfdr"
 	"github.com/stretchr/testify/require"
@@ -32,18 +36,20 @@ func (unsupportedLightcurveObject) GetMjd() float64 {
 }
 
 func TestNewLightcurveResponse(t *testing.T) {
-	response, err := newLightcurveResponse(lightcurve.Lightcurve{
+	response, err := newLightcurveResponse(lightcurve.LightcurveResult{Lightcurve: lightcurve.Lightcurve{
 		Detections: []lightcurve.LightcurveObject{
 			ztfdr.Detection{Oid: 42, FilterId: 1, Hmjd: 60234.1, Mag: 18.2, Magerr: 0.05},
 			neowise.Detection{Mjd: 60321.4, W1mpro: 15.8, W1sigmpro: 0.12, W2mpro: 15.1, W2sigmpro: 0.2, Cntr: 77, Source_id: "neo-77"},
+			lightcurve.XwaveDetection{ObjectId: "xw1", Mjd: 60400.5, Band: "g", Mag: 17.1, MagErr: 0.03},
+			&gaia.Detection{SourceId: 4295806720, TransitId: 17091913127413259, Band: "G", Mjd: 56902.9, Mag: 17.5, Flux: 2000, FluxError: 20},
 		},
 		ForcedPhotometry: []lightcurve.LightcurveObject{
 			&ztfdr.Detection{Oid: 7, Hmjd: 60235.5, Mag: 19.4, Magerr: 0.2},
 		},
-	})
+	}})
 	require.NoError(t, err)
 
-	require.Len(t, response.Detections, 2)
+	require.Len(t, response.Detections, 4)
 	require.Empty(t, response.NonDetections)
 	require.Len(t, response.ForcedPhotometry, 1)
 
@@ -67,14 +73,47 @@ func TestNewLightcurveResponse(t *testing.T) {
 	require.Equal(t, 15.1, neowiseEntry.Data["w2mpro"])
 	require.Equal(t, "neo-77", neowiseEntry.Data["source_id"])
 
+	xwaveEntry := response.Detections[2]
+	require.Equal(t, "xwave", xwaveEntry.Catalog)
+	require.Equal(t, "xw1", xwaveEntry.ObjectID)
+	require.Equal(t, "xw1_g_60400.5", xwaveEntry.ID)
+	require.Equal(t, 17.1, xwaveEntry.Mag)
+	require.Equal(t, "g", xwaveEntry.Data["band"])
+
+	gaiaEntry := response.Detections[3]
+	require.Equal(t, "gaia", gaiaEntry.Catalog)
+	require.Equal(t, "4295806720", gaiaEntry.ObjectID)
+	require.Equal(t, "4295806720_17091913127413259_G", gaiaEntry.ID)
+	require.Equal(t, 56902.9, gaiaEntry.Mjd)
+	require.Equal(t, "G", gaiaEntry.Data["band"])
+
 	forcedEntry := response.ForcedPhotometry[0]
 	require.Equal(t, "ztf", forcedEntry.Catalog)
 	require.Equal(t, "7", forcedEntry.ObjectID)
 }
 
 func TestNewLightcurveResponse_UnsupportedObject(t *testing.T) {
-	_, err := newLightcurveResponse(lightcurve.Lightcurve{
+	_, err := newLightcurveResponse(lightcurve.LightcurveResult{Lightcurve: lightcurve.Lightcurve{
 		Detections: []lightcurve.LightcurveObject{unsupportedLightcurveObject{}},
-	})
+	}})
 	require.EqualError(t, err, "unsupported lightcurve object type api.unsupportedLightcurveObject")
 }
+
+func TestNewLightcurveResponse_Sources(t *testing.T) {
+	response, err := newLightcurveResponse(lightcurve.LightcurveResult{
+		Sources: []lightcurve.SourceStatus{
+			{Source: "ztf", Status: lightcurve.StatusOK, Latency: 120 * time.Millisecond},
+			{Source: "neowise", Status: lightcurve.StatusError, Latency: 2 * time.Second, Error: errors.New("irsa is down")},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []LightcurveSourceStatus{
+		{Source: "ztf", Status: "ok", LatencyMs: 120},
+		{Source: "neowise", Status: "error", LatencyMs: 2000, Error: "irsa is down"},
+	}, response.Sources)
+
+	payload, err := json.Marshal(response.Sources[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"source":"ztf","status":"ok","latency_ms":120}`, string(payload))
+}
@@ -43,17 +43,7 @@ func (api *API) metadata(c *gin.Context) {
 
 	result, err := api.metadataService.FindByID(c.Request.Context(), id, catalog)
 	if err != nil {
-		if errors.As(err, &metadata.ValidationError{}) {
-			c.JSON(http.StatusBadRequest, err)
-			// WARN: sql reference should be handled inside service, not in this layer
-		} else if errors.Is(err, sql.ErrNoRows) {
-			c.Writer.WriteHeader(http.StatusNoContent)
-		} else if errors.As(err, &metadata.ArgumentError{}) {
-			c.JSON(http.StatusInternalServerError, err)
-		} else {
-			c.Error(err)
-			c.JSON(http.StatusInternalServerError, "Could not execute metadata query")
-		}
+		handleMetadataError(err, c)
 		return
 	}
 
@@ -63,10 +53,11 @@ func (api *API) metadata(c *gin.Context) {
 // Find metadata by multiple ids
 //
 //	@Summary		Search for metadata by multiple ids
-//	@Description	Search for metadata by multiple ids in bulk
+//	@Description	Search for metadata by multiple ids in bulk. With an Accept header of application/x-ndjson, rows are streamed as one JSON object per line as soon as each chunk of ids is done.
 //	@Tags			metadata
 //	@Accept			json
 //	@Produce		json
+//	@Produce		application/x-ndjson
 //	@Param			request	body		BulkMetadataRequest	true	"Bulk metadata request"
 //	@Success		200		{object}	[]repository.Allwise
 //	@Success		204		{string}	string
@@ -81,21 +72,41 @@ func (api *API) metadataBulk(c *gin.Context) {
 		return
 	}
 
+	if wantsNDJSON(c) {
+		writer := newNDJSONWriter[any](c)
+		err := api.metadataService.BulkFindByIDStream(
+			c.Request.Context(),
+			bulkRequest.Ids,
+			bulkRequest.Catalog,
+			api.config.BulkChunkSize,
+			api.config.MaxBulkConcurrency,
+			writer.write,
+		)
+		writer.finish(err, "Could not execute metadata query", handleMetadataError)
+		return
+	}
+
 	result, err := api.metadataService.BulkFindByID(c.Request.Context(), bulkRequest.Ids, bulkRequest.Catalog)
 	if err != nil {
-		if errors.As(err, &metadata.ValidationError{}) {
-			c.JSON(http.StatusBadRequest, err)
-			// WARN: sql reference should be handled inside service, not in this layer
-		} else if errors.Is(err, sql.ErrNoRows) {
-			c.Writer.WriteHeader(http.StatusNoContent)
-		} else if errors.As(err, &metadata.ArgumentError{}) {
-			c.JSON(http.StatusInternalServerError, err)
-		} else {
-			c.Error(err)
-			c.JSON(http.StatusInternalServerError, "Could not execute metadata query")
-		}
+		handleMetadataError(err, c)
 		return
 	}
 
 	c.JSON(http.StatusOK, result)
 }
+
+func handleMetadataError(err error, c *gin.Context) {
+	if errors.As(err, &metadata.ValidationError{}) {
+		c.JSON(http.StatusBadRequest, err)
+		// WARN: sql reference should be handled inside service, not in this layer
+	} else if errors.Is(err, sql.ErrNoRows) {
+		c.Writer.WriteHeader(http.StatusNoContent)
+	} else if errors.As(err, &metadata.ArgumentError{}) {
+		c.JSON(http.StatusInternalServerError, err)
+	} else if timedOut(err, c) {
+		handleTimeout(err, c)
+	} else {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, "Could not execute metadata query")
+	}
+}
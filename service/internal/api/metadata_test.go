@@ -154,3 +154,52 @@ func TestMetadata_BulkFindByID(t *testing.T) {
 	}
 	require.Len(t, result, 10)
 }
+
+func TestMetadata_BulkFindByID_NDJSON(t *testing.T) {
+	beforeTest(t)
+
+	getenv := func(key string) string {
+		switch key {
+		case "LOG_LEVEL":
+			return "debug"
+		case "CONFIG_PATH":
+			return configPath
+		default:
+			return ""
+		}
+	}
+
+	cfg, err := app.Config(getenv)
+	require.NoError(t, err)
+
+	db, err := app.ServiceDatabase(cfg)
+	require.NoError(t, err)
+
+	test_helpers.InsertAllwiseMastercat(10, db)
+	test_helpers.InsertAllwiseMetadata(10, db)
+
+	ids := make([]string, 10)
+	for i := range 10 {
+		ids[i] = fmt.Sprintf("allwise-%v", i)
+	}
+	bbody, err := json.Marshal(map[string]any{"ids": ids, "catalog": "allwise"})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest("POST", "/v1/bulk-metadata", bytes.NewReader(bbody))
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	received := []string{}
+	decoder := json.NewDecoder(w.Body)
+	for decoder.More() {
+		var row repository.BulkGetAllwiseRow
+		require.NoError(t, decoder.Decode(&row))
+		received = append(received, row.ID)
+	}
+	require.ElementsMatch(t, ids, received)
+}
@@ -0,0 +1,205 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strconv"
+
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+	"github.com/gin-gonic/gin"
+)
+
+// cursor points to the last object of a page. Objects are ordered by
+// distance, catalog and id, so the next page starts right after it.
+type cursor struct {
+	Distance float64 `json:"d"`
+	Catalog  string  `json:"c"`
+	ID       string  `json:"i"`
+}
+
+func (c cursor) compare(other cursor) int {
+	return cmp.Or(
+		cmp.Compare(c.Distance, other.Distance),
+		cmp.Compare(c.Catalog, other.Catalog),
+		cmp.Compare(c.ID, other.ID),
+	)
+}
+
+func (c cursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// pagination holds the limit and cursor query parameters.
+// Results are only paginated when one of them was given.
+type pagination struct {
+	enabled bool
+	limit   int
+	after   *cursor
+}
+
+func parsePagination(c *gin.Context) (pagination, error) {
+	limit, hasLimit := c.GetQuery("limit")
+	after, hasCursor := c.GetQuery("cursor")
+	if !hasLimit && !hasCursor {
+		return pagination{}, nil
+	}
+
+	p := pagination{enabled: true, limit: conesearch.DefaultPageSize}
+	if hasLimit {
+		parsedLimit, err := parseIntField(limit, "limit")
+		if err != nil {
+			return p, err
+		}
+		if parsedLimit < 1 || parsedLimit > conesearch.MaxPageSize {
+			return p, NewParseError(limit, "limit", "Limit must be between 1 and "+strconv.Itoa(conesearch.MaxPageSize)+".")
+		}
+		p.limit = parsedLimit
+	}
+	if hasCursor {
+		b, err := base64.RawURLEncoding.DecodeString(after)
+		if err != nil {
+			return p, NewParseError(after, "cursor", "Invalid cursor.")
+		}
+		var parsedCursor cursor
+		if err := json.Unmarshal(b, &parsedCursor); err != nil {
+			return p, NewParseError(after, "cursor", "Invalid cursor.")
+		}
+		p.after = &parsedCursor
+	}
+	return p, nil
+}
+
+type pageEntry[T any] struct {
+	key   cursor
+	value T
+}
+
+// page sorts the entries and keeps the ones after the cursor, up to the limit.
+// The returned cursor is empty when there are no more entries.
+func page[T any](entries []pageEntry[T], p pagination) ([]pageEntry[T], string) {
+	slices.SortFunc(entries, func(a, b pageEntry[T]) int {
+		return a.key.compare(b.key)
+	})
+	if p.after != nil {
+		start, _ := slices.BinarySearchFunc(entries, *p.after, func(e pageEntry[T], target cursor) int {
+			return cmp.Or(e.key.compare(target), -1)
+		})
+		entries = entries[start:]
+	}
+	if len(entries) <= p.limit {
+		return entries, ""
+	}
+	return entries[:p.limit], entries[p.limit-1].key.encode()
+}
+
+func paginateMastercat(results []conesearch.MastercatResult, p pagination) ([]conesearch.MastercatResult, string) {
+	entries := make([]pageEntry[conesearch.MastercatExtended], 0)
+	indexes := make(map[string]int)
+	for _, r := range results {
+		indexes[r.Catalog] = r.Index
+		for _, m := range r.Data {
+			entries = append(entries, pageEntry[conesearch.MastercatExtended]{
+				key:   cursor{Distance: m.Distance, Catalog: r.Catalog, ID: m.ID},
+				value: m,
+			})
+		}
+	}
+
+	entries, next := page(entries, p)
+	paginated := make([]conesearch.MastercatResult, 0)
+	for _, e := range entries {
+		i := slices.IndexFunc(paginated, func(r conesearch.MastercatResult) bool { return r.Catalog == e.key.Catalog })
+		if i < 0 {
+			paginated = append(paginated, conesearch.MastercatResult{Catalog: e.key.Catalog, Index: indexes[e.key.Catalog]})
+			i = len(paginated) - 1
+		}
+		paginated[i].Data = append(paginated[i].Data, e.value)
+	}
+	return paginated, next
+}
+
+func paginateMetadata(results []conesearch.MetadataResult, p pagination) ([]conesearch.MetadataResult, string) {
+	entries := make([]pageEntry[conesearch.MetadataExtended], 0)
+	for _, r := range results {
+		for _, m := range r.Data {
+			entries = append(entries, pageEntry[conesearch.MetadataExtended]{
+				key:   cursor{Distance: m.Distance, Catalog: r.Catalog, ID: m.GetId()},
+				value: m,
+			})
+		}
+	}
+
+	entries, next := page(entries, p)
+	paginated := make([]conesearch.MetadataResult, 0)
+	for _, e := range entries {
+		i := slices.IndexFunc(paginated, func(r conesearch.MetadataResult) bool { return r.Catalog == e.key.Catalog })
+		if i < 0 {
+			paginated = append(paginated, conesearch.MetadataResult{Catalog: e.key.Catalog})
+			i = len(paginated) - 1
+		}
+		paginated[i].Data = append(paginated[i].Data, e.value)
+	}
+	return paginated, next
+}
+
+func handleMastercatPage(result []conesearch.MastercatResult, p pagination, c *gin.Context) {
+	if p.enabled {
+		var next string
+		result, next = paginateMastercat(result, p)
+		setNextCursor(c, next)
+	}
+	handleServiceSuccess(result, c)
+}
+
+func handleMetadataPage(result []conesearch.MetadataResult, p pagination, c *gin.Context) {
+	if p.enabled {
+		var next string
+		result, next = paginateMetadata(result, p)
+		setNextCursor(c, next)
+	}
+	handleServiceSuccess(result, c)
+}
+
+func setNextCursor(c *gin.Context, next string) {
+	if next != "" {
+		c.Header("X-Next-Cursor", next)
+	}
+}
+
+func encodeRegionCursor(c conesearch.RegionCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// parseRegionCursor reads the page of a region search, which is empty for the first page
+func parseRegionCursor(page string) (*conesearch.RegionCursor, error) {
+	if page == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(page)
+	if err != nil {
+		return nil, NewParseError(page, "page", "Invalid page.")
+	}
+	var parsedCursor conesearch.RegionCursor
+	if err := json.Unmarshal(b, &parsedCursor); err != nil {
+		return nil, NewParseError(page, "page", "Invalid page.")
+	}
+	return &parsedCursor, nil
+}
@@ -49,20 +49,62 @@ func parseRadius(rad string) (float64, error) {
 	return radius, nil
 }
 
+// parseRa parses a right ascension in decimal degrees or in sexagesimal hours
 func parseRa(ra string) (float64, error) {
-	parsedRa, err := strconv.ParseFloat(ra, 64)
+	return parseAngle(ra, "RA", true)
+}
+
+// parseDec parses a declination in decimal or sexagesimal degrees
+func parseDec(dec string) (float64, error) {
+	return parseAngle(dec, "Dec", false)
+}
+
+// parseAngle parses a decimal angle in degrees, or a sexagesimal one
+// in hours or degrees as described in parseSexagesimal
+func parseAngle(value, field string, hours bool) (float64, error) {
+	if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+		return parsed, nil
+	}
+	if !strings.ContainsAny(strings.ToLower(value), " :hdms°") {
+		return -999, NewParseError(value, field, "Could not parse float.")
+	}
+	parsed, err := parseSexagesimal(value, hours)
 	if err != nil {
-		return -999, NewParseError(ra, "RA", "Could not parse float.")
+		return -999, NewParseError(value, field, "Could not parse sexagesimal angle: "+err.Error()+".")
 	}
-	return parsedRa, nil
+	return parsed, nil
 }
 
-func parseDec(dec string) (float64, error) {
-	parsedDec, err := strconv.ParseFloat(dec, 64)
+func parsePosErr(posErr string) (float64, error) {
+	parsedPosErr, err := strconv.ParseFloat(posErr, 64)
+	if err != nil {
+		return -999, NewParseError(posErr, "pos_err", "Could not parse float.")
+	}
+	return parsedPosErr, nil
+}
+
+func parseEpoch(epoch string) (float64, error) {
+	parsedEpoch, err := strconv.ParseFloat(epoch, 64)
+	if err != nil {
+		return -999, NewParseError(epoch, "epoch", "Could not parse float.")
+	}
+	return parsedEpoch, nil
+}
+
+func parseFloatField(value, field string) (float64, error) {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return -999, NewParseError(value, field, "Could not parse float.")
+	}
+	return parsed, nil
+}
+
+func parseIntField(value, field string) (int, error) {
+	parsed, err := strconv.Atoi(value)
 	if err != nil {
-		return -999, NewParseError(dec, "Dec", "Could not parse float.")
+		return -999, NewParseError(value, field, "Could not parse int.")
 	}
-	return parsedDec, nil
+	return parsed, nil
 }
 
 func parseNneighbor(nneighbor string) (int, error) {
@@ -73,19 +115,23 @@ func parseNneighbor(nneighbor string) (int, error) {
 	return parsedNneighbor, nil
 }
 
-func parseLightcurveCatalog(catalog string) (string, error) {
-	normalizedCatalog := strings.ToLower(strings.TrimSpace(catalog))
+func parseRequireAll(requireAll string) (bool, error) {
+	parsedRequireAll, err := strconv.ParseBool(requireAll)
+	if err != nil {
+		return false, NewParseError(requireAll, "require_all", "Could not parse bool.")
+	}
+	return parsedRequireAll, nil
+}
+
+// parseLightcurveCatalog validates the catalog against the catalogs the lightcurve service can serve
+func parseLightcurveCatalog(catalogName string, availableCatalogs []string) (string, error) {
+	normalizedCatalog := strings.ToLower(strings.TrimSpace(catalogName))
 	if normalizedCatalog == "" {
 		return "all", nil
 	}
 
-	availableCatalogs := []string{"all", "ztf", "neowise", "allwise"}
 	if !slices.Contains(availableCatalogs, normalizedCatalog) {
-		return "", NewParseError(catalog, "catalog", "Catalog must be one of all, ztf, neowise, allwise.")
-	}
-
-	if normalizedCatalog == "allwise" {
-		return "neowise", nil
+		return "", NewParseError(catalogName, "catalog", fmt.Sprintf("Catalog must be one of %s.", strings.Join(availableCatalogs, ", ")))
 	}
 
 	return normalizedCatalog, nil
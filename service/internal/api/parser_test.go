@@ -52,6 +52,68 @@ func TestDecValidation(t *testing.T) {
 	}
 }
 
+func TestSexagesimalParsing(t *testing.T) {
+	raCases := map[string]float64{
+		"12:34:56.7":    188.73625,
+		"12 34 56.7":    188.73625,
+		"12h34m56.7s":   188.73625,
+		"12d34m56.4s":   12.582333333333333,
+		"00:40":         10,
+		"12:60:00":      -999,
+		"12.5:30:00":    -999,
+		"12:34:56:7":    -999,
+		"12h34x56s":     -999,
+		"1:2:3:4:5:6:7": -999,
+	}
+	for value, expected := range raCases {
+		result, _ := parseRa(value)
+		require.InDelta(t, expected, result, 1e-9, value)
+	}
+
+	decCases := map[string]float64{
+		"-05d06m07s": -5.101944444444445,
+		"-00:30:00":  -0.5,
+		"+10:00:00":  10,
+		"45°30'00\"": 45.5,
+		"10 30":      10.5,
+	}
+	for value, expected := range decCases {
+		result, err := parseDec(value)
+		require.NoError(t, err, value)
+		require.InDelta(t, expected, result, 1e-9, value)
+	}
+
+	_, err := parseDec("10:70:00")
+	require.Equal(t, "Dec", err.(ParseError).Field)
+}
+
+func TestToICRS(t *testing.T) {
+	testCases := []struct {
+		frame    string
+		lon, lat float64
+		ra, dec  float64
+	}{
+		{"icrs", 10, 20, 10, 20},
+		{"", 10, 20, 10, 20},
+		// galactic center and north galactic pole
+		{"galactic", 0, 0, 266.40499, -28.93617},
+		{"galactic", 0, 90, 192.85948, 27.12825},
+		{"ecliptic", 0, 0, 0, 0},
+		{"Ecliptic", 90, 0, 90, 23.4392911},
+		// north ecliptic pole
+		{"ecliptic", 0, 90, 270, 66.5607089},
+	}
+	for _, tc := range testCases {
+		ra, dec, err := toICRS(tc.frame, tc.lon, tc.lat)
+		require.NoError(t, err)
+		require.InDelta(t, tc.ra, ra, 1e-4, "%v", tc)
+		require.InDelta(t, tc.dec, dec, 1e-4, "%v", tc)
+	}
+
+	_, _, err := toICRS("fk4", 0, 0)
+	require.Equal(t, "frame", err.(ParseError).Field)
+}
+
 func TestRadiusValidation(t *testing.T) {
 	testCases := map[string]float64{
 		"":    -999,
@@ -84,21 +146,43 @@ func TestNneighborValidation(t *testing.T) {
 
 func TestLightcurveCatalogValidation(t *testing.T) {
 	testCases := map[string]string{
-		"":          "all",
-		"all":       "all",
-		"ztf":       "ztf",
-		"neowise":   "neowise",
-		"allwise":   "neowise",
-		"ALLWISE":   "neowise",
-		" NeOWISE ": "neowise",
+		"":           "all",
+		"all":        "all",
+		"ztf":        "ztf",
+		"neowise":    "neowise",
+		"allwise":    "allwise",
+		"ALLWISE":    "allwise",
+		" NeOWISE ":  "neowise",
+		"ZTF-mirror": "ztf-mirror",
 	}
+	availableCatalogs := []string{"all", "neowise", "ztf", "ztf-mirror", "allwise"}
 
 	for catalog, expectedCatalog := range testCases {
-		result, err := parseLightcurveCatalog(catalog)
+		result, err := parseLightcurveCatalog(catalog, availableCatalogs)
 		require.NoError(t, err)
 		require.Equal(t, expectedCatalog, result)
 	}
 
-	_, err := parseLightcurveCatalog("gaia")
+	_, err := parseLightcurveCatalog("vlass", availableCatalogs)
+	require.ErrorContains(t, err, "Catalog must be one of all, neowise, ztf, ztf-mirror, allwise.")
+
+	_, err = parseLightcurveCatalog("gaia", availableCatalogs)
+	require.Error(t, err, "sources that are not configured are rejected")
+}
+
+func TestRequireAllValidation(t *testing.T) {
+	testCases := map[string]bool{
+		"true":  true,
+		"1":     true,
+		"false": false,
+		"0":     false,
+	}
+	for testCase, expectedResult := range testCases {
+		result, err := parseRequireAll(testCase)
+		require.NoError(t, err)
+		require.Equal(t, expectedResult, result)
+	}
+
+	_, err := parseRequireAll("always")
 	require.Error(t, err)
 }
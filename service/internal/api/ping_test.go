@@ -15,10 +15,13 @@
 package api_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -31,3 +34,13 @@ func TestPing(t *testing.T) {
 	require.Equal(t, http.StatusOK, w.Code)
 	require.Equal(t, "pong", w.Body.String())
 }
+
+func TestCacheStats(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/cache/stats", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var stats conesearch.ConesearchCacheStats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+}
@@ -0,0 +1,171 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+	"github.com/gin-gonic/gin"
+)
+
+// Search for objects inside a range of right ascension and declination
+//
+//	@Summary		Search for objects inside a range of right ascension and declination
+//	@Description	Search for objects inside a box. When ra_min is greater than ra_max the box crosses RA=0. Results are paginated and the X-Next-Page header is set to the page parameter of the next page when there are more objects.
+//	@Tags			regionsearch
+//	@Produce		json
+//	@Produce		application/x-votable+xml
+//	@Param			ra_min		query		string	true	"Lower right ascension in degrees"
+//	@Param			ra_max		query		string	true	"Upper right ascension in degrees"
+//	@Param			dec_min		query		string	true	"Lower declination in degrees"
+//	@Param			dec_max		query		string	true	"Upper declination in degrees"
+//	@Param			catalog		query		string	false	"Catalog to search in"
+//	@Param			getMetadata	query		string	false	"Return metadata results"
+//	@Param			page		query		string	false	"Value of the X-Next-Page header of the previous page"
+//	@Param			page_size	query		string	false	"Number of objects per page"
+//	@Param			format		query		string	false	"Set to votable to get a VOTable instead of JSON"
+//	@Success		200			{array}		conesearch.MastercatResult
+//	@Success		204			{string}	string
+//	@Failure		400			{object}	conesearch.ValidationError
+//	@Failure		500			{string}	string
+//	@Router			/boxsearch [get]
+func (api *API) boxsearch(c *gin.Context) {
+	catalog := c.DefaultQuery("catalog", "all")
+	getMetadata := c.DefaultQuery("getMetadata", "false")
+
+	limits := make(map[string]float64)
+	for _, field := range []string{"ra_min", "ra_max", "dec_min", "dec_max"} {
+		value, err := parseFloatField(c.Query(field), field)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+		limits[field] = value
+	}
+	after, err := parseRegionCursor(c.Query("page"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	pageSize, err := parseIntField(c.DefaultQuery("page_size", strconv.Itoa(conesearch.DefaultPageSize)), "page_size")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	box, err := conesearch.NewBox(limits["ra_min"], limits["ra_max"], limits["dec_min"], limits["dec_max"])
+	if err != nil {
+		handleServiceError(err, c)
+		return
+	}
+
+	api.searchRegion(c, box, catalog, getMetadata == "true", after, pageSize)
+}
+
+// Search for objects inside a polygon or a MOC
+//
+//	@Summary		Search for objects inside a polygon or a MOC
+//	@Description	Search for objects inside a spherical polygon, given by the ra and dec of its vertices, or inside a MOC, given as nested HEALPix pixels by order. Results are paginated and the X-Next-Page header is set to the page parameter of the next page when there are more objects.
+//	@Tags			regionsearch
+//	@Accept			json
+//	@Produce		json
+//	@Produce		application/x-votable+xml
+//	@Param			ra			body		[]float64			false	"Right ascension of the polygon vertices in degrees"
+//	@Param			dec			body		[]float64			false	"Declination of the polygon vertices in degrees"
+//	@Param			moc			body		map[int][]int64		false	"MOC cells, as nested pixels by order"
+//	@Param			catalog		body		string				false	"Catalog to search in"
+//	@Param			getMetadata	body		bool				false	"Return metadata results"
+//	@Param			page		body		string				false	"Value of the X-Next-Page header of the previous page"
+//	@Param			page_size	body		int					false	"Number of objects per page"
+//	@Success		200			{array}		conesearch.MastercatResult
+//	@Success		204			{string}	string
+//	@Failure		400			{object}	conesearch.ValidationError
+//	@Failure		500			{string}	string
+//	@Router			/regionsearch [post]
+func (api *API) regionsearch(c *gin.Context) {
+	var request RegionSearchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	if request.Catalog == "" {
+		request.Catalog = "all"
+	}
+	if request.PageSize == 0 {
+		request.PageSize = conesearch.DefaultPageSize
+	}
+
+	after, err := parseRegionCursor(request.Page)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	var region conesearch.Region
+	switch {
+	case len(request.Moc) > 0 && len(request.Ra) > 0:
+		c.JSON(http.StatusBadRequest, NewParseError(fmt.Sprintf("%d", len(request.Moc)), "moc", "Only one of a polygon or a MOC can be given."))
+		return
+	case len(request.Moc) > 0:
+		region, err = conesearch.NewMOC(request.Moc)
+	default:
+		region, err = conesearch.NewPolygon(request.Ra, request.Dec)
+	}
+	if err != nil {
+		handleServiceError(err, c)
+		return
+	}
+
+	api.searchRegion(c, region, request.Catalog, request.GetMetadata, after, request.PageSize)
+}
+
+func (api *API) searchRegion(
+	c *gin.Context,
+	region conesearch.Region,
+	catalog string,
+	getMetadata bool,
+	after *conesearch.RegionCursor,
+	pageSize int,
+) {
+	if getMetadata {
+		result, next, err := api.conesearchService.FindMetadataByRegionSearch(c.Request.Context(), region, catalog, after, pageSize)
+		if err != nil {
+			handleServiceError(err, c)
+			return
+		}
+		setNextPage(c, next)
+		handleServiceSuccess(result, c)
+		return
+	}
+
+	result, next, err := api.conesearchService.RegionSearch(c.Request.Context(), region, catalog, after, pageSize)
+	if err != nil {
+		handleServiceError(err, c)
+		return
+	}
+	setNextPage(c, next)
+	handleServiceSuccess(result, c)
+}
+
+func setNextPage(c *gin.Context, next *conesearch.RegionCursor) {
+	if next != nil {
+		c.Header("X-Next-Page", encodeRegionCursor(*next))
+	}
+}
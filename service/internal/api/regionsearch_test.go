@@ -0,0 +1,225 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dirodriguezm/healpix"
+	"github.com/dirodriguezm/xmatch/service/internal/app"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+
+	"github.com/stretchr/testify/require"
+)
+
+func insertRegionObjects(t *testing.T) {
+	t.Helper()
+
+	getenv := func(key string) string {
+		switch key {
+		case "LOG_LEVEL":
+			return "debug"
+		case "CONFIG_PATH":
+			return configPath
+		default:
+			return ""
+		}
+	}
+	stdout := &strings.Builder{}
+
+	cfg, err := app.Config(getenv)
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+
+	logger := app.ServiceLogger(getenv, stdout)
+	slog.SetDefault(logger)
+
+	db, err := app.ServiceDatabase(cfg)
+	if err != nil {
+		t.Fatalf("creating database connection: %v", err)
+	}
+
+	repo := repository.New(db)
+	mapper, err := healpix.NewHEALPixMapper(18, healpix.Nest)
+	require.NoError(t, err)
+
+	objects := map[string][2]float64{
+		"west":    {359.5, 0.5},
+		"east":    {0.5, 0.5},
+		"outside": {10, 0.5},
+	}
+	for id, coords := range objects {
+		err = repo.InsertObject(context.Background(), repository.InsertObjectParams{
+			ID:   id,
+			Ra:   coords[0],
+			Dec:  coords[1],
+			Ipix: mapper.PixelAt(healpix.RADec(coords[0], coords[1])),
+			Cat:  "allwise",
+		})
+		require.NoError(t, err)
+	}
+}
+
+func resultIds(t *testing.T, body []byte) []string {
+	t.Helper()
+
+	var result []conesearch.MastercatResult
+	err := json.Unmarshal(body, &result)
+	require.NoError(t, err)
+
+	ids := []string{}
+	for _, r := range result {
+		for _, m := range r.Data {
+			ids = append(ids, m.ID)
+		}
+	}
+	return ids
+}
+
+func TestBoxsearch(t *testing.T) {
+	beforeTest(t)
+	insertRegionObjects(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/boxsearch?ra_min=359&ra_max=1&dec_min=0&dec_max=1&catalog=allwise", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Empty(t, w.Header().Get("X-Next-Page"))
+	require.ElementsMatch(t, []string{"west", "east"}, resultIds(t, w.Body.Bytes()))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/boxsearch?ra_min=359&ra_max=1&dec_min=0&dec_max=1&page_size=1", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	next := w.Header().Get("X-Next-Page")
+	require.NotEmpty(t, next)
+	first := resultIds(t, w.Body.Bytes())
+	require.Len(t, first, 1)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/boxsearch?ra_min=359&ra_max=1&dec_min=0&dec_max=1&page_size=1&page="+next, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Empty(t, w.Header().Get("X-Next-Page"))
+	second := resultIds(t, w.Body.Bytes())
+	require.Len(t, second, 1)
+	require.ElementsMatch(t, []string{"west", "east"}, append(first, second...))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/boxsearch?ra_min=100&ra_max=101&dec_min=0&dec_max=1", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestBoxsearch_Validation(t *testing.T) {
+	testCases := map[string]string{
+		"/v1/boxsearch?ra_max=1&dec_min=0&dec_max=1":                      "ra_min",
+		"/v1/boxsearch?ra_min=0&ra_max=1&dec_min=1&dec_max=0":             "dec_min",
+		"/v1/boxsearch?ra_min=0&ra_max=1&dec_min=0&dec_max=1&page=2":      "page",
+		"/v1/boxsearch?ra_min=0&ra_max=1&dec_min=0&dec_max=1&page_size=a": "page_size",
+		"/v1/boxsearch?ra_min=0&ra_max=1&dec_min=0&dec_max=1&catalog=a":   "catalog",
+	}
+
+	for testPath, field := range testCases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", testPath, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code, testPath)
+
+		var result map[string]any
+		err := json.Unmarshal(w.Body.Bytes(), &result)
+		require.NoError(t, err)
+		require.Equal(t, field, result["Field"], testPath)
+	}
+}
+
+func TestRegionsearch(t *testing.T) {
+	beforeTest(t)
+	insertRegionObjects(t)
+
+	mapper, err := healpix.NewHEALPixMapper(8, healpix.Nest)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		body     map[string]any
+		expected []string
+	}{
+		"polygon": {
+			body:     map[string]any{"ra": []float64{359, 1, 1, 359}, "dec": []float64{0, 0, 1, 1}},
+			expected: []string{"west", "east"},
+		},
+		"moc": {
+			body:     map[string]any{"moc": map[string][]int64{"8": {mapper.PixelAt(healpix.RADec(10, 0.5))}}},
+			expected: []string{"outside"},
+		},
+	}
+
+	for name, tc := range testCases {
+		bbody, err := json.Marshal(tc.body)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/v1/regionsearch", bytes.NewReader(bbody))
+		require.NoError(t, err)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, name)
+		require.ElementsMatch(t, tc.expected, resultIds(t, w.Body.Bytes()), name)
+	}
+}
+
+func TestRegionsearch_Validation(t *testing.T) {
+	testCases := map[string]struct {
+		body  map[string]any
+		field string
+	}{
+		"polygon and moc": {
+			body:  map[string]any{"ra": []float64{1, 2, 3}, "dec": []float64{1, 2, 3}, "moc": map[string][]int64{"1": {1}}},
+			field: "moc",
+		},
+		"too few vertices": {
+			body:  map[string]any{"ra": []float64{1, 2}, "dec": []float64{1, 2}},
+			field: "ra",
+		},
+		"invalid moc order": {
+			body:  map[string]any{"moc": map[string][]int64{"31": {1}}},
+			field: "moc",
+		},
+	}
+
+	for name, tc := range testCases {
+		bbody, err := json.Marshal(tc.body)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/v1/regionsearch", bytes.NewReader(bbody))
+		require.NoError(t, err)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code, name)
+
+		var result map[string]any
+		err = json.Unmarshal(w.Body.Bytes(), &result)
+		require.NoError(t, err)
+		require.Equal(t, tc.field, result["Field"], name)
+	}
+}
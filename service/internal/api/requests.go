@@ -14,15 +14,69 @@
 
 package api
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// BulkConesearchRequest searches around each pair of ra and dec.
+// Radius and Nneighbor are either a single value for every position,
+// or an array with one value for each position.
 type BulkConesearchRequest struct {
-	Ra        []float64 `json:"ra"`
-	Dec       []float64 `json:"dec"`
-	Radius    float64   `json:"radius"`
-	Catalog   string    `json:"catalog"`
-	Nneighbor int       `json:"nneighbor"`
+	Ra        []float64          `json:"ra"`
+	Dec       []float64          `json:"dec"`
+	Radius    OneOrMany[float64] `json:"radius"`
+	Catalog   string             `json:"catalog"`
+	Nneighbor OneOrMany[int]     `json:"nneighbor"`
+}
+
+// OneOrMany is a JSON field that can be given as a single value or as an array
+type OneOrMany[T any] []T
+
+func (v *OneOrMany[T]) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*v = nil
+		return nil
+	}
+	if len(data) > 0 && data[0] == '[' {
+		var values []T
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		*v = values
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*v = OneOrMany[T]{value}
+	return nil
 }
 
 type BulkMetadataRequest struct {
 	Ids     []string `json:"ids"`
 	Catalog string   `json:"catalog"`
 }
+
+type CrossmatchRequest struct {
+	Id         []string  `json:"id"`
+	Ra         []float64 `json:"ra"`
+	Dec        []float64 `json:"dec"`
+	PosErr     []float64 `json:"pos_err"`
+	Epoch      *float64  `json:"epoch"`
+	Radius     float64   `json:"radius"`
+	Catalogs   []string  `json:"catalogs"`
+	AllMatches bool      `json:"all_matches"`
+}
+
+type RegionSearchRequest struct {
+	Ra          []float64       `json:"ra"`
+	Dec         []float64       `json:"dec"`
+	Moc         map[int][]int64 `json:"moc"`
+	Catalog     string          `json:"catalog"`
+	GetMetadata bool            `json:"getMetadata"`
+	Page        string          `json:"page"`
+	PageSize    int             `json:"page_size"`
+}
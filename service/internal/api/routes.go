@@ -15,7 +15,10 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/dirodriguezm/xmatch/service/docs"
 	"github.com/gin-contrib/cors"
@@ -34,8 +37,9 @@ func (api *API) SetupRoutes(r *gin.Engine) {
 			"http://localhost:3000",
 			"https://xwave-rho.vercel.app",
 		},
-		AllowMethods:     []string{"GET", "POST", "OPTIONS"},
+		AllowMethods:     []string{"GET", "POST", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept"},
+		ExposeHeaders:    []string{"X-Next-Page", "X-Next-Cursor"},
 		AllowCredentials: true,
 	}))
 	if api.getenv("USE_LOGGER") != "" {
@@ -49,16 +53,65 @@ func (api *API) SetupRoutes(r *gin.Engine) {
 		c.String(http.StatusOK, "pong")
 	})
 
+	// hits, misses and size of the conesearch caches
+	r.GET("/cache/stats", func(c *gin.Context) {
+		c.JSON(http.StatusOK, api.conesearchService.CacheStats())
+	})
+
+	timeout := withTimeout(api.config.RequestTimeout)
+	bulkTimeout := withTimeout(api.config.BulkRequestTimeout)
+
 	v1 := r.Group("/v1")
 	{
-		v1.GET("/conesearch", api.conesearch)
-		v1.POST("/bulk-conesearch", api.conesearchBulk)
-		v1.GET("/metadata", api.metadata)
-		v1.POST("/bulk-metadata", api.metadataBulk)
-		v1.GET("/lightcurve", api.Lightcurve)
+		v1.GET("/conesearch", timeout, api.conesearch)
+		v1.GET("/scs", timeout, api.scs)
+		v1.POST("/bulk-conesearch", bulkTimeout, api.conesearchBulk)
+		v1.POST("/crossmatch", bulkTimeout, api.crossmatch)
+		v1.POST("/crossmatch/upload", bulkTimeout, api.crossmatchUpload)
+		v1.GET("/boxsearch", bulkTimeout, api.boxsearch)
+		v1.POST("/regionsearch", bulkTimeout, api.regionsearch)
+		v1.GET("/metadata", timeout, api.metadata)
+		v1.POST("/bulk-metadata", bulkTimeout, api.metadataBulk)
+		v1.GET("/lightcurve", timeout, api.Lightcurve)
+		v1.GET("/tap/sync", timeout, api.tapSync)
+		v1.POST("/tap/sync", timeout, api.tapSync)
+		v1.GET("/tap/tables", api.tapTables)
+		v1.GET("/tap/capabilities", api.tapCapabilities)
+		v1.POST("/jobs", bulkTimeout, api.submitJob)
+		v1.GET("/jobs/:id", timeout, api.getJob)
+		v1.DELETE("/jobs/:id", timeout, api.deleteJob)
+		v1.GET("/jobs/:id/result", api.getJobResult)
 	}
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	r.SetTrustedProxies([]string{"localhost"})
 }
+
+// withTimeout sets a deadline of the given seconds on the context of the request,
+// so the searches started by the handler stop once it is reached.
+// A timeout of zero or less leaves the request without deadline.
+func withTimeout(seconds int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if seconds <= 0 {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(seconds)*time.Second)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// timedOut reports whether a request failed because it reached its deadline.
+// The database and the external clients don't always wrap the context error,
+// so the context of the request is checked too.
+func timedOut(err error, c *gin.Context) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(c.Request.Context().Err(), context.DeadlineExceeded)
+}
+
+func handleTimeout(err error, c *gin.Context) {
+	c.Error(err)
+	c.JSON(http.StatusGatewayTimeout, "Request timed out")
+}
@@ -0,0 +1,50 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTimeout(t *testing.T) {
+	r := gin.New()
+	r.GET("/deadline", withTimeout(1), func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		require.True(t, ok)
+		handleServiceError(fmt.Errorf("could not find objects: %w", context.DeadlineExceeded), c)
+	})
+	r.GET("/no-deadline", withTimeout(-1), func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		require.False(t, ok)
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/deadline", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusGatewayTimeout, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/no-deadline", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+}
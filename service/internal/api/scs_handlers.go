@@ -0,0 +1,224 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+	"github.com/dirodriguezm/xmatch/service/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Content type of the Simple Cone Search 1.03 responses
+	mimeSCSVOTable = "text/xml;content=x-votable"
+	// Largest number of objects returned by a Simple Cone Search.
+	// Larger results are truncated and flagged with an OVERFLOW status.
+	scsMaxRecords = conesearch.MaxPageSize
+)
+
+// Columns returned by a Simple Cone Search for each value of VERB
+var scsColumns = map[int][]votableColumn[mastercatRow]{
+	1: {mastercatID, mastercatRa, mastercatDec},
+	2: {mastercatID, mastercatRa, mastercatDec, mastercatCatalog, mastercatDistance, mastercatPosErr},
+	3: {
+		mastercatID, mastercatRa, mastercatDec, mastercatCatalog, mastercatDistance, mastercatPosErr,
+		mastercatIpix, mastercatPmra, mastercatPmdec, mastercatParallax, mastercatRefEpoch,
+	},
+}
+
+// IVOA Simple Cone Search
+//
+//	@Summary		IVOA Simple Cone Search
+//	@Description	Simple Cone Search 1.03 service returning a VOTable with the objects inside the cone. VERB=1 returns the id and position, VERB=2 adds the catalog, distance and positional error, and VERB=3 returns every mastercat column, followed by the metadata columns when catalog is allwise or gaia. SR=0 returns the columns without rows. Errors are returned as a VOTable with an INFO named Error.
+//	@Tags			conesearch
+//	@Produce		xml
+//	@Param			RA		query		string	true	"Right ascension in degrees"
+//	@Param			DEC		query		string	true	"Declination in degrees"
+//	@Param			SR		query		string	true	"Radius in degrees"
+//	@Param			VERB	query		string	false	"Verbosity of the columns, from 1 to 3"
+//	@Param			catalog	query		string	false	"Catalog to search in"
+//	@Success		200		{string}	string
+//	@Failure		500		{string}	string
+//	@Router			/scs [get]
+func (api *API) scs(c *gin.Context) {
+	ra, err := parseFloatField(scsQuery(c, "RA"), "RA")
+	if err != nil {
+		scsError(c, http.StatusOK, err)
+		return
+	}
+	dec, err := parseFloatField(scsQuery(c, "DEC"), "DEC")
+	if err != nil {
+		scsError(c, http.StatusOK, err)
+		return
+	}
+	sr, err := parseFloatField(scsQuery(c, "SR"), "SR")
+	if err != nil {
+		scsError(c, http.StatusOK, err)
+		return
+	}
+	if sr < 0 {
+		scsError(c, http.StatusOK, NewParseError(scsQuery(c, "SR"), "SR", "SR can't be lower than 0."))
+		return
+	}
+	verb := 2
+	if value := scsQuery(c, "VERB"); value != "" {
+		verb, err = parseIntField(value, "VERB")
+		if err != nil {
+			scsError(c, http.StatusOK, err)
+			return
+		}
+		if verb < 1 || verb > 3 {
+			scsError(c, http.StatusOK, NewParseError(value, "VERB", "VERB must be 1, 2 or 3."))
+			return
+		}
+	}
+	catalog := strings.ToLower(c.DefaultQuery("catalog", "all"))
+	if err := conesearch.ValidateCatalog(catalog); err != nil {
+		scsError(c, http.StatusOK, err)
+		return
+	}
+
+	if _, ok := catalogMetadataColumns(catalog); ok && verb == 3 {
+		api.scsMetadata(c, ra, dec, sr, catalog)
+		return
+	}
+
+	var result []conesearch.MastercatResult
+	if sr > 0 {
+		result, err = api.conesearchService.Conesearch(c.Request.Context(), ra, dec, sr*3600, scsMaxRecords, catalog)
+		if err != nil {
+			handleSCSError(c, err)
+			return
+		}
+	}
+	table := mastercatTable(result, scsColumns[verb])
+	writeSCSVOTable(c, table, len(table.Data.TableData.Rows))
+}
+
+// scsMetadata returns the mastercat columns of each object joined with the
+// metadata columns of its catalog, since metadata rows don't hold the position
+func (api *API) scsMetadata(c *gin.Context, ra, dec, sr float64, catalog string) {
+	var objects []conesearch.MastercatResult
+	var metadata []conesearch.MetadataResult
+	if sr > 0 {
+		var err error
+		objects, err = api.conesearchService.Conesearch(c.Request.Context(), ra, dec, sr*3600, scsMaxRecords, catalog)
+		if err != nil {
+			handleSCSError(c, err)
+			return
+		}
+		metadata, err = api.conesearchService.FindMetadataByConesearch(c.Request.Context(), ra, dec, sr*3600, scsMaxRecords, catalog)
+		if err != nil {
+			handleSCSError(c, err)
+			return
+		}
+	}
+
+	metadataByID := make(map[string]repository.Metadata)
+	for _, r := range metadata {
+		for _, m := range r.Data {
+			metadataByID[m.GetId()] = m.Metadata
+		}
+	}
+
+	table := mastercatTable(objects, scsColumns[3])
+	table.Name = catalog
+	// columns already written from the mastercat, like the id, are not repeated
+	catalogColumns, _ := catalogMetadataColumns(catalog)
+	columns := slices.DeleteFunc(catalogColumns, func(column metadataColumn) bool {
+		return slices.ContainsFunc(table.Fields, func(field utils.Field) bool {
+			return field.Name == column.field.Name
+		})
+	})
+	for _, column := range columns {
+		table.Fields = append(table.Fields, column.field)
+	}
+	empty := make([]utils.Column, len(columns))
+	rowIndex := 0
+	for _, r := range objects {
+		for _, m := range r.Data {
+			cells := empty
+			if row, ok := metadataByID[m.ID]; ok {
+				cells = metadataCells(columns, row)
+			}
+			tr := &table.Data.TableData.Rows[rowIndex]
+			tr.Columns = append(tr.Columns, cells...)
+			rowIndex++
+		}
+	}
+	writeSCSVOTable(c, table, len(table.Data.TableData.Rows))
+}
+
+func writeSCSVOTable(c *gin.Context, table utils.Table, nrows int) {
+	status := "OK"
+	if nrows >= scsMaxRecords {
+		status = "OVERFLOW"
+	}
+	votable := utils.NewVOTable(table)
+	votable.Resource.Infos = []utils.Info{{Name: "QUERY_STATUS", Value: status}}
+	votable.Resource.Coosys = []utils.Coosys{{ID: "J2000", System: "ICRS"}}
+	writeVOTable(c, http.StatusOK, mimeSCSVOTable, votable)
+}
+
+func handleSCSError(c *gin.Context, err error) {
+	if errors.As(err, &conesearch.ValidationError{}) {
+		scsError(c, http.StatusOK, err)
+		return
+	}
+	c.Error(err)
+	if timedOut(err, c) {
+		scsError(c, http.StatusGatewayTimeout, errors.New("Request timed out"))
+		return
+	}
+	scsError(c, http.StatusInternalServerError, errors.New("Could not execute conesearch"))
+}
+
+// scsError writes the error document of the Simple Cone Search standard,
+// a VOTable with an INFO named Error holding the message
+func scsError(c *gin.Context, status int, err error) {
+	message := err.Error()
+	var parseErr ParseError
+	if errors.As(err, &parseErr) {
+		message = parseErr.Field + ": " + parseErr.Reason
+	}
+	var validationErr conesearch.ValidationError
+	if errors.As(err, &validationErr) {
+		message = validationErr.Field + ": " + validationErr.Reason
+	}
+
+	votable := utils.NewVOTable()
+	votable.Resource.Infos = []utils.Info{
+		{ID: "Error", Name: "Error", Value: message},
+		{Name: "QUERY_STATUS", Value: "ERROR"},
+	}
+	writeVOTable(c, status, mimeSCSVOTable, votable)
+}
+
+// scsQuery reads a query parameter ignoring its case, since clients
+// send the parameters of the standard both in upper and lower case
+func scsQuery(c *gin.Context, name string) string {
+	for key, values := range c.Request.URL.Query() {
+		if strings.EqualFold(key, name) && len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,220 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dirodriguezm/healpix"
+	"github.com/dirodriguezm/xmatch/service/internal/app"
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch/test_helpers"
+	"github.com/dirodriguezm/xmatch/service/internal/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func insertAllwise(t *testing.T, nobjects int) {
+	t.Helper()
+
+	getenv := func(key string) string {
+		switch key {
+		case "LOG_LEVEL":
+			return "debug"
+		case "CONFIG_PATH":
+			return configPath
+		default:
+			return ""
+		}
+	}
+
+	cfg, err := app.Config(getenv)
+	require.NoError(t, err)
+
+	db, err := app.ServiceDatabase(cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, test_helpers.InsertAllwiseMastercat(nobjects, db))
+	require.NoError(t, test_helpers.InsertAllwiseMetadata(nobjects, db))
+}
+
+func getVOTable(t *testing.T, path string) (*utils.VOTable, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	router.ServeHTTP(w, req)
+
+	votable, err := utils.NewVOTableFromBytes(w.Body.Bytes())
+	require.NoError(t, err, w.Body.String())
+	return votable, w
+}
+
+func fieldsByName(table utils.Table) map[string]utils.Field {
+	fields := make(map[string]utils.Field)
+	for _, f := range table.Fields {
+		fields[f.Name] = f
+	}
+	return fields
+}
+
+func TestSCS(t *testing.T) {
+	beforeTest(t)
+	insertAllwise(t, 3)
+
+	votable, w := getVOTable(t, "/v1/scs?RA=1&DEC=1&SR=0.0003")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "text/xml;content=x-votable", w.Header().Get("Content-Type"))
+	require.Equal(t, "OK", votable.Resource.Infos[0].Value)
+	require.Len(t, votable.Resource.Tables, 1)
+
+	table := votable.Resource.Tables[0]
+	fields := fieldsByName(table)
+	require.Equal(t, "ID_MAIN", fields["id"].Ucd)
+	require.Equal(t, "POS_EQ_RA_MAIN", fields["ra"].Ucd)
+	require.Equal(t, "POS_EQ_DEC_MAIN", fields["dec"].Ucd)
+	require.Equal(t, "deg", fields["ra"].Unit)
+	require.Equal(t, "arcsec", fields["distance"].Unit)
+
+	require.Len(t, table.Data.TableData.Rows, 1)
+	row := table.Data.TableData.Rows[0]
+	require.Equal(t, "allwise-1", row.Columns[0].Value)
+	require.Equal(t, "1", row.Columns[1].Value)
+	require.Equal(t, "1", row.Columns[2].Value)
+}
+
+func TestSCS_Verbosity(t *testing.T) {
+	beforeTest(t)
+	insertAllwise(t, 3)
+
+	votable, _ := getVOTable(t, "/v1/scs?ra=1&dec=1&sr=0.0003&verb=1")
+	require.Len(t, votable.Resource.Tables[0].Fields, 3, "parameters are not case sensitive")
+
+	votable, _ = getVOTable(t, "/v1/scs?RA=1&DEC=1&SR=0.0003&VERB=3&catalog=allwise")
+	table := votable.Resource.Tables[0]
+	fields := fieldsByName(table)
+	require.Equal(t, "phot.mag;em.IR.3-4um", fields["w1mpro"].Ucd)
+	require.Equal(t, "mag", fields["w1mpro"].Unit)
+	require.Equal(t, "double", fields["w1mpro"].Datatype)
+	require.Equal(t, "ID_MAIN", fields["id"].Ucd)
+	require.Len(t, table.Data.TableData.Rows, 1)
+
+	// SR=0 describes the columns without searching
+	votable, _ = getVOTable(t, "/v1/scs?RA=1&DEC=1&SR=0&VERB=3&catalog=allwise")
+	table = votable.Resource.Tables[0]
+	require.Equal(t, fields, fieldsByName(table))
+	require.Empty(t, table.Data.TableData.Rows)
+}
+
+func TestSCS_Error(t *testing.T) {
+	testCases := map[string]string{
+		"/v1/scs?DEC=1&SR=1":                     "RA: Could not parse float.",
+		"/v1/scs?RA=1&DEC=1&SR=-1":               "SR: SR can't be lower than 0.",
+		"/v1/scs?RA=1&DEC=1&SR=1&VERB=4":         "VERB: VERB must be 1, 2 or 3.",
+		"/v1/scs?RA=1&DEC=100&SR=0.0003":         "Dec: Dec can't be greater than 90",
+		"/v1/scs?RA=1&DEC=1&SR=0.0003&catalog=a": "catalog: Catalog not available",
+	}
+
+	for path, message := range testCases {
+		votable, w := getVOTable(t, path)
+		require.Equal(t, http.StatusOK, w.Code, path)
+		require.Len(t, votable.Resource.Infos, 2, path)
+		require.Equal(t, "Error", votable.Resource.Infos[0].Name, path)
+		require.Equal(t, message, votable.Resource.Infos[0].Value, path)
+		require.Equal(t, "ERROR", votable.Resource.Infos[1].Value, path)
+	}
+}
+
+func TestConesearch_VOTable(t *testing.T) {
+	beforeTest(t)
+	insertAllwise(t, 3)
+
+	votable, w := getVOTable(t, "/v1/conesearch?ra=1&dec=1&radius=1&format=votable")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/x-votable+xml", w.Header().Get("Content-Type"))
+	require.Len(t, votable.Resource.Tables[0].Data.TableData.Rows, 1)
+	require.Equal(t, "meta.dataset", fieldsByName(votable.Resource.Tables[0])["catalog"].Ucd)
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/conesearch?ra=1&dec=1&radius=1&getMetadata=true", nil)
+	req.Header.Set("Accept", "application/x-votable+xml")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	votable, err := utils.NewVOTableFromBytes(w.Body.Bytes())
+	require.NoError(t, err)
+	require.Len(t, votable.Resource.Tables, 1)
+	require.Equal(t, "AllWISE", votable.Resource.Tables[0].Name)
+	require.Equal(t, "mag", fieldsByName(votable.Resource.Tables[0])["w1mpro"].Unit)
+}
+
+func TestConesearch_GenericCatalog(t *testing.T) {
+	beforeTest(t)
+
+	getenv := func(key string) string {
+		if key == "CONFIG_PATH" {
+			return configPath
+		}
+		return ""
+	}
+	cfg, err := app.Config(getenv)
+	require.NoError(t, err)
+	db, err := app.ServiceDatabase(cfg)
+	require.NoError(t, err)
+	defer db.Close()
+
+	schema := repository.GenericSchema{
+		Catalog:   "generic_api",
+		IdColumn:  "name",
+		RaColumn:  "ra",
+		DecColumn: "dec",
+		Columns: []repository.GenericColumn{
+			{Name: "mag", Type: repository.GenericDouble},
+			{Name: "kind", Type: repository.GenericString},
+		},
+	}
+	require.NoError(t, catalog.RegisterGeneric(schema))
+
+	mapper, err := healpix.NewHEALPixMapper(18, healpix.Nest)
+	require.NoError(t, err)
+	ctx := context.Background()
+	repo := repository.New(db)
+	row := repository.GenericRow{Catalog: "generic_api", ID: "g-1", Ra: 10, Dec: 10, Columns: schema.Columns, Values: []any{12.5, "star"}}
+	require.NoError(t, repo.InsertObject(ctx, repository.InsertObjectParams{
+		ID: row.ID, Ra: row.Ra, Dec: row.Dec, Cat: "generic_api", Ipix: mapper.PixelAt(healpix.RADec(row.Ra, row.Dec)),
+	}))
+	require.NoError(t, repo.BulkInsertGeneric(ctx, db, schema, []any{row}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/conesearch?ra=10&dec=10&radius=1&catalog=generic_api&getMetadata=true", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.JSONEq(t, `[{"catalog":"generic_api","data":[{"id":"g-1","ra":10,"dec":10,"mag":12.5,"kind":"star","distance":0}]}]`, w.Body.String())
+
+	votable, w := getVOTable(t, "/v1/conesearch?ra=10&dec=10&radius=1&catalog=generic_api&getMetadata=true&format=votable")
+	require.Equal(t, http.StatusOK, w.Code)
+	fields := fieldsByName(votable.Resource.Tables[0])
+	require.Equal(t, "double", fields["mag"].Datatype)
+	require.Equal(t, "char", fields["kind"].Datatype)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/v1/metadata?id=g-1&catalog=generic_api", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.JSONEq(t, `{"id":"g-1","ra":10,"dec":10,"mag":12.5,"kind":"star"}`, w.Body.String())
+}
@@ -64,6 +64,8 @@ func beforeTest(t *testing.T) {
 	// The tables are created in TestMain via migrations
 	_, _ = db.Exec("DELETE FROM mastercat;")
 	_, _ = db.Exec("DELETE FROM allwise;")
+	_, _ = db.Exec("DELETE FROM erosita;")
+	_, _ = db.Exec("DELETE FROM jobs;")
 }
 
 func TestMain(m *testing.M) {
@@ -93,8 +95,11 @@ service:
     url: "file:%s?_journal_mode=WAL&_sync=NORMAL&_busy_timeout=5000"
   bulk_chunk_size: 1
   max_bulk_concurrency: 1
+  jobs:
+    directory: "%s"
 `
-	config = fmt.Sprintf(config, dbFile)
+	jobsDir := filepath.Join(tmpDir, "jobs")
+	config = fmt.Sprintf(config, dbFile, jobsDir)
 	err = test_helpers.WriteConfigFile(configPath, config)
 	if err != nil {
 		panic(err)
@@ -154,13 +159,27 @@ service:
 		panic(fmt.Errorf("creating metadata service: %w", err))
 	}
 
-	lightcurveService, err := app.LightcurveService(cfg, conesearchService)
+	lightcurveService, err := app.LightcurveService(cfg, conesearchService, app.LightcurveRepository(db))
 	if err != nil {
 		_ = db.Close()
 		panic(fmt.Errorf("creating lightcurve service: %w", err))
 	}
 
-	api, err := app.API(conesearchService, metadataService, lightcurveService, cfg.Service, getenv)
+	tapService, err := app.TapService(repo)
+	if err != nil {
+		_ = db.Close()
+		panic(fmt.Errorf("creating TAP service: %w", err))
+	}
+
+	jobsService, err := app.JobsService(app.JobsRepository(db), conesearchService, cfg.Service)
+	if err != nil {
+		_ = db.Close()
+		panic(fmt.Errorf("creating jobs service: %w", err))
+	}
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	go jobsService.Start(jobsCtx)
+
+	api, err := app.API(conesearchService, metadataService, lightcurveService, tapService, jobsService, cfg.Service, getenv)
 	if err != nil {
 		_ = db.Close()
 		panic(fmt.Errorf("creating API: %w", err))
@@ -173,7 +192,9 @@ service:
 	code := m.Run()
 
 	// cleanup
+	stopJobs()
 	_ = db.Close()
+	_ = os.RemoveAll(jobsDir)
 	_ = os.Remove(configPath)
 	_ = os.Remove(dbFile)
 	_ = os.Remove(dbDir)
@@ -0,0 +1,80 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const mimeNDJSON = "application/x-ndjson"
+
+// wantsNDJSON reports whether the client asked for a newline delimited JSON stream
+func wantsNDJSON(c *gin.Context) bool {
+	return c.NegotiateFormat(gin.MIMEJSON, mimeNDJSON) == mimeNDJSON
+}
+
+// ndjsonWriter writes each result as a JSON line, flushing after every batch.
+//
+// The status and headers are sent with the first batch, so errors found before
+// any result can still be answered with a regular response.
+type ndjsonWriter[T any] struct {
+	c       *gin.Context
+	encoder *json.Encoder
+	started bool
+}
+
+func newNDJSONWriter[T any](c *gin.Context) *ndjsonWriter[T] {
+	return &ndjsonWriter[T]{c: c, encoder: json.NewEncoder(c.Writer)}
+}
+
+func (w *ndjsonWriter[T]) write(results []T) error {
+	if len(results) == 0 {
+		return nil
+	}
+	if !w.started {
+		w.c.Header("Content-Type", mimeNDJSON)
+		w.c.Status(http.StatusOK)
+		w.started = true
+	}
+	for i := range results {
+		if err := w.encoder.Encode(results[i]); err != nil {
+			return err
+		}
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+// finish ends the stream. Without results the response is a 204.
+// Errors after the first result can't change the status anymore, so they are
+// reported with a last line holding the error message.
+func (w *ndjsonWriter[T]) finish(err error, message string, handleError func(error, *gin.Context)) {
+	if err == nil {
+		if !w.started {
+			w.c.Writer.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+	if !w.started {
+		handleError(err, w.c)
+		return
+	}
+	w.c.Error(err)
+	w.encoder.Encode(gin.H{"error": message})
+	w.c.Writer.Flush()
+}
@@ -0,0 +1,430 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/search/tap"
+	"github.com/dirodriguezm/xmatch/service/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const mimeCSV = "text/csv"
+
+// Output formats of TAP queries, by the values accepted in FORMAT and RESPONSEFORMAT
+var tapFormats = map[string]string{
+	"votable":    mimeVOTable,
+	mimeVOTable:  mimeVOTable,
+	"text/xml":   mimeVOTable,
+	"csv":        mimeCSV,
+	mimeCSV:      mimeCSV,
+	"json":       gin.MIMEJSON,
+	gin.MIMEJSON: gin.MIMEJSON,
+}
+
+// TapColumn describes a column of the result of a TAP query
+type TapColumn struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Datatype    string `json:"datatype"`
+	ArraySize   string `json:"arraysize,omitempty"`
+	Unit        string `json:"unit,omitempty"`
+	Ucd         string `json:"ucd,omitempty"`
+}
+
+// TapResult is the JSON representation of the result of a TAP query
+type TapResult struct {
+	Metadata []TapColumn `json:"metadata"`
+	Data     [][]any     `json:"data"`
+	Overflow bool        `json:"overflow"`
+}
+
+// TAP synchronous query
+//
+//	@Summary		Run an ADQL query
+//	@Description	Table Access Protocol synchronous query. Supports SELECT [TOP n] ... FROM a single table with WHERE and ORDER BY, and the geometry functions POINT, CIRCLE, CONTAINS and DISTANCE. Queries using geometry must constrain the position with CONTAINS(POINT(ra, dec), CIRCLE(ra, dec, radius)) = 1 or DISTANCE(POINT(ra, dec), POINT(ra, dec)) < radius. Errors are returned as a VOTable with QUERY_STATUS ERROR.
+//	@Tags			tap
+//	@Accept			x-www-form-urlencoded
+//	@Produce		application/x-votable+xml
+//	@Produce		text/csv
+//	@Produce		json
+//	@Param			QUERY			query		string	true	"ADQL query"
+//	@Param			REQUEST			query		string	false	"Must be doQuery"
+//	@Param			LANG			query		string	false	"Must be ADQL"
+//	@Param			RESPONSEFORMAT	query		string	false	"votable, csv or json"
+//	@Param			MAXREC			query		int		false	"Largest number of rows returned"
+//	@Success		200				{string}	string
+//	@Failure		400				{string}	string
+//	@Failure		500				{string}	string
+//	@Router			/tap/sync [get]
+//	@Router			/tap/sync [post]
+func (api *API) tapSync(c *gin.Context) {
+	if request := tapParam(c, "REQUEST"); request != "" && !strings.EqualFold(request, "doQuery") {
+		tapError(c, http.StatusBadRequest, NewParseError(request, "REQUEST", "Only doQuery requests are supported."))
+		return
+	}
+	if lang := tapParam(c, "LANG"); lang != "" && !strings.HasPrefix(strings.ToUpper(lang), "ADQL") {
+		tapError(c, http.StatusBadRequest, NewParseError(lang, "LANG", "Only ADQL queries are supported."))
+		return
+	}
+	format := strings.ToLower(cmp.Or(tapParam(c, "RESPONSEFORMAT"), tapParam(c, "FORMAT"), "votable"))
+	contentType, ok := tapFormats[format]
+	if !ok {
+		tapError(c, http.StatusBadRequest, NewParseError(format, "RESPONSEFORMAT", "Format must be votable, csv or json."))
+		return
+	}
+	maxrec := tap.DefaultMaxRec
+	if value := tapParam(c, "MAXREC"); value != "" {
+		var err error
+		maxrec, err = parseIntField(value, "MAXREC")
+		if err != nil {
+			tapError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	result, err := api.tapService.Query(c.Request.Context(), tapParam(c, "QUERY"), maxrec)
+	if err != nil {
+		if errors.As(err, &tap.ValidationError{}) {
+			tapError(c, http.StatusBadRequest, err)
+			return
+		}
+		c.Error(err)
+		if timedOut(err, c) {
+			tapError(c, http.StatusGatewayTimeout, errors.New("Request timed out"))
+			return
+		}
+		tapError(c, http.StatusInternalServerError, errors.New("Could not execute query"))
+		return
+	}
+
+	switch contentType {
+	case mimeCSV:
+		writeTapCSV(c, result)
+	case gin.MIMEJSON:
+		writeTapJSON(c, result)
+	default:
+		writeTapVOTable(c, result)
+	}
+}
+
+func writeTapVOTable(c *gin.Context, result tap.Result) {
+	table := utils.Table{Name: "result"}
+	for _, column := range result.Columns {
+		table.Fields = append(table.Fields, utils.Field{
+			Name:        column.Name,
+			Description: column.Description,
+			Datatype:    column.Datatype,
+			ArraySize:   column.ArraySize,
+			Unit:        column.Unit,
+			Ucd:         column.Ucd,
+		})
+	}
+	table.Data.TableData.Rows = make([]utils.Row, len(result.Rows))
+	for i, row := range result.Rows {
+		cells := make([]utils.Column, len(row))
+		for j := range row {
+			cells[j] = utils.Column{Value: formatValue(row[j])}
+		}
+		table.Data.TableData.Rows[i] = utils.Row{Columns: cells}
+	}
+
+	status := "OK"
+	if result.Overflow {
+		status = "OVERFLOW"
+	}
+	votable := utils.NewVOTable(table)
+	votable.Resource.Infos = []utils.Info{{Name: "QUERY_STATUS", Value: status}}
+	writeVOTable(c, http.StatusOK, mimeVOTable, votable)
+}
+
+func writeTapCSV(c *gin.Context, result tap.Result) {
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", mimeCSV)
+
+	w := csv.NewWriter(c.Writer)
+	header := make([]string, len(result.Columns))
+	for i, column := range result.Columns {
+		header[i] = column.Name
+	}
+	w.Write(header)
+	for _, row := range result.Rows {
+		record := make([]string, len(row))
+		for i := range row {
+			record[i] = formatValue(row[i])
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		c.Error(err)
+	}
+}
+
+func writeTapJSON(c *gin.Context, result tap.Result) {
+	response := TapResult{Metadata: make([]TapColumn, len(result.Columns)), Data: result.Rows, Overflow: result.Overflow}
+	for i, column := range result.Columns {
+		response.Metadata[i] = TapColumn{
+			Name:        column.Name,
+			Description: column.Description,
+			Datatype:    column.Datatype,
+			ArraySize:   column.ArraySize,
+			Unit:        column.Unit,
+			Ucd:         column.Ucd,
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// tapError writes the error document of the TAP standard, a VOTable
+// with a QUERY_STATUS of ERROR holding the message
+func tapError(c *gin.Context, status int, err error) {
+	message := err.Error()
+	var parseErr ParseError
+	if errors.As(err, &parseErr) {
+		message = parseErr.Field + ": " + parseErr.Reason
+	}
+	var validationErr tap.ValidationError
+	if errors.As(err, &validationErr) {
+		message = validationErr.Field + ": " + validationErr.Reason + " (" + validationErr.ErrValue + ")"
+	}
+
+	votable := utils.NewVOTable()
+	votable.Resource.Infos = []utils.Info{{Name: "QUERY_STATUS", Value: "ERROR", Text: message}}
+	writeVOTable(c, status, mimeVOTable, votable)
+}
+
+// tapParam reads a parameter from the query or the form ignoring its case,
+// since TAP parameter names are case insensitive
+func tapParam(c *gin.Context, name string) string {
+	if err := c.Request.ParseForm(); err != nil {
+		return ""
+	}
+	for key, values := range c.Request.Form {
+		if strings.EqualFold(key, name) && len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+type vosiTableset struct {
+	XMLName xml.Name     `xml:"vosi:tableset"`
+	Vosi    string       `xml:"xmlns:vosi,attr"`
+	Vs      string       `xml:"xmlns:vs,attr"`
+	Xsi     string       `xml:"xmlns:xsi,attr"`
+	Schema  []vosiSchema `xml:"schema"`
+}
+
+type vosiSchema struct {
+	Name   string      `xml:"name"`
+	Tables []vosiTable `xml:"table"`
+}
+
+type vosiTable struct {
+	Type        string       `xml:"type,attr"`
+	Name        string       `xml:"name"`
+	Description string       `xml:"description,omitempty"`
+	Columns     []vosiColumn `xml:"column"`
+}
+
+type vosiColumn struct {
+	Name        string       `xml:"name"`
+	Description string       `xml:"description,omitempty"`
+	Unit        string       `xml:"unit,omitempty"`
+	Ucd         string       `xml:"ucd,omitempty"`
+	DataType    vosiDataType `xml:"dataType"`
+}
+
+type vosiDataType struct {
+	Type      string `xml:"xsi:type,attr"`
+	ArraySize string `xml:"arraysize,attr,omitempty"`
+	Value     string `xml:",chardata"`
+}
+
+// TAP tables
+//
+//	@Summary		Tables that can be queried with TAP
+//	@Description	VOSI tableset with the tables and columns that can be used in ADQL queries
+//	@Tags			tap
+//	@Produce		xml
+//	@Success		200	{string}	string
+//	@Router			/tap/tables [get]
+func (api *API) tapTables(c *gin.Context) {
+	schema := vosiSchema{Name: "default"}
+	for _, table := range tap.Schema() {
+		t := vosiTable{Type: "output", Name: table.Name, Description: table.Description}
+		for _, column := range table.Columns {
+			t.Columns = append(t.Columns, vosiColumn{
+				Name:        column.Name,
+				Description: column.Description,
+				Unit:        column.Unit,
+				Ucd:         column.Ucd,
+				DataType:    vosiDataType{Type: "vs:VOTableType", ArraySize: column.ArraySize, Value: column.Datatype},
+			})
+		}
+		schema.Tables = append(schema.Tables, t)
+	}
+	writeXML(c, vosiTableset{
+		Vosi:   "http://www.ivoa.net/xml/VOSITables/v1.0",
+		Vs:     "http://www.ivoa.net/xml/VODataService/v1.1",
+		Xsi:    "http://www.w3.org/2001/XMLSchema-instance",
+		Schema: []vosiSchema{schema},
+	})
+}
+
+type vosiCapabilities struct {
+	XMLName      xml.Name         `xml:"vosi:capabilities"`
+	Vosi         string           `xml:"xmlns:vosi,attr"`
+	Vs           string           `xml:"xmlns:vs,attr"`
+	Tr           string           `xml:"xmlns:tr,attr"`
+	Xsi          string           `xml:"xmlns:xsi,attr"`
+	Capabilities []vosiCapability `xml:"capability"`
+}
+
+type vosiCapability struct {
+	StandardID    string            `xml:"standardID,attr"`
+	Type          string            `xml:"xsi:type,attr,omitempty"`
+	Interface     vosiInterface     `xml:"interface"`
+	Language      *tapLanguage      `xml:"language,omitempty"`
+	OutputFormats []tapOutputFormat `xml:"outputFormat"`
+	OutputLimit   *tapOutputLimit   `xml:"outputLimit,omitempty"`
+}
+
+type vosiInterface struct {
+	Type      string        `xml:"xsi:type,attr"`
+	Role      string        `xml:"role,attr,omitempty"`
+	AccessURL vosiAccessURL `xml:"accessURL"`
+}
+
+type vosiAccessURL struct {
+	Use   string `xml:"use,attr"`
+	Value string `xml:",chardata"`
+}
+
+type tapLanguage struct {
+	Name        string                `xml:"name"`
+	Version     tapLanguageVersion    `xml:"version"`
+	Description string                `xml:"description"`
+	Features    []tapLanguageFeatures `xml:"languageFeatures"`
+}
+
+type tapLanguageVersion struct {
+	IvoID string `xml:"ivo-id,attr"`
+	Value string `xml:",chardata"`
+}
+
+type tapLanguageFeatures struct {
+	Type     string       `xml:"type,attr"`
+	Features []tapFeature `xml:"feature"`
+}
+
+type tapFeature struct {
+	Form string `xml:"form"`
+}
+
+type tapOutputFormat struct {
+	Mime  string `xml:"mime"`
+	Alias string `xml:"alias"`
+}
+
+type tapOutputLimit struct {
+	Default tapLimit `xml:"default"`
+	Hard    tapLimit `xml:"hard"`
+}
+
+type tapLimit struct {
+	Unit  string `xml:"unit,attr"`
+	Value int    `xml:",chardata"`
+}
+
+// TAP capabilities
+//
+//	@Summary		Capabilities of the TAP service
+//	@Description	VOSI capabilities describing the supported ADQL subset, output formats and limits
+//	@Tags			tap
+//	@Produce		xml
+//	@Success		200	{string}	string
+//	@Router			/tap/capabilities [get]
+func (api *API) tapCapabilities(c *gin.Context) {
+	base := requestBaseURL(c) + strings.TrimSuffix(c.Request.URL.Path, "/capabilities")
+	geometry := tapLanguageFeatures{Type: "ivo://ivoa.net/std/TAPRegExt#features-adqlgeo"}
+	for _, function := range []string{"POINT", "CIRCLE", "CONTAINS", "DISTANCE"} {
+		geometry.Features = append(geometry.Features, tapFeature{Form: function})
+	}
+
+	writeXML(c, vosiCapabilities{
+		Vosi: "http://www.ivoa.net/xml/VOSICapabilities/v1.0",
+		Vs:   "http://www.ivoa.net/xml/VODataService/v1.1",
+		Tr:   "http://www.ivoa.net/xml/TAPRegExt/v1.0",
+		Xsi:  "http://www.w3.org/2001/XMLSchema-instance",
+		Capabilities: []vosiCapability{
+			{
+				StandardID: "ivo://ivoa.net/std/TAP",
+				Type:       "tr:TableAccess",
+				Interface:  vosiInterface{Type: "vs:ParamHTTP", Role: "std", AccessURL: vosiAccessURL{Use: "base", Value: base}},
+				Language: &tapLanguage{
+					Name:        "ADQL",
+					Version:     tapLanguageVersion{IvoID: "ivo://ivoa.net/std/ADQL#v2.0", Value: "2.0"},
+					Description: "Single table SELECT with TOP, WHERE and ORDER BY",
+					Features:    []tapLanguageFeatures{geometry},
+				},
+				OutputFormats: []tapOutputFormat{
+					{Mime: mimeVOTable, Alias: "votable"},
+					{Mime: mimeCSV, Alias: "csv"},
+					{Mime: gin.MIMEJSON, Alias: "json"},
+				},
+				OutputLimit: &tapOutputLimit{
+					Default: tapLimit{Unit: "row", Value: tap.DefaultMaxRec},
+					Hard:    tapLimit{Unit: "row", Value: tap.MaxRec},
+				},
+			},
+			{
+				StandardID: "ivo://ivoa.net/std/VOSI#capabilities",
+				Interface:  vosiInterface{Type: "vs:ParamHTTP", AccessURL: vosiAccessURL{Use: "full", Value: base + "/capabilities"}},
+			},
+			{
+				StandardID: "ivo://ivoa.net/std/VOSI#tables",
+				Interface:  vosiInterface{Type: "vs:ParamHTTP", AccessURL: vosiAccessURL{Use: "full", Value: base + "/tables"}},
+			},
+		},
+	})
+}
+
+// requestBaseURL returns the scheme and host the request was sent to
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	scheme = cmp.Or(c.GetHeader("X-Forwarded-Proto"), scheme)
+	return scheme + "://" + c.Request.Host
+}
+
+func writeXML(c *gin.Context, document any) {
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, "Could not write XML")
+		return
+	}
+	c.Data(http.StatusOK, gin.MIMEXML, append([]byte(xml.Header), body...))
+}
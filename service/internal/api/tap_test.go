@@ -0,0 +1,155 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dirodriguezm/xmatch/service/internal/api"
+
+	"github.com/stretchr/testify/require"
+)
+
+func tapSync(params url.Values) string {
+	return "/v1/tap/sync?" + params.Encode()
+}
+
+func TestTapSync_VOTable(t *testing.T) {
+	beforeTest(t)
+	insertAllwise(t, 3)
+
+	votable, w := getVOTable(t, tapSync(url.Values{
+		"REQUEST": {"doQuery"},
+		"LANG":    {"ADQL"},
+		"QUERY":   {"SELECT id, ra, dec, w1mpro FROM allwise WHERE CONTAINS(POINT('ICRS', ra, dec), CIRCLE('ICRS', 1, 1, 0.01)) = 1"},
+	}))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/x-votable+xml", w.Header().Get("Content-Type"))
+	require.Equal(t, "QUERY_STATUS", votable.Resource.Infos[0].Name)
+	require.Equal(t, "OK", votable.Resource.Infos[0].Value)
+
+	table := votable.Resource.Tables[0]
+	fields := fieldsByName(table)
+	require.Equal(t, "pos.eq.ra;meta.main", fields["ra"].Ucd)
+	require.Equal(t, "mag", fields["w1mpro"].Unit)
+	require.Len(t, table.Data.TableData.Rows, 1)
+	require.Equal(t, "allwise-1", table.Data.TableData.Rows[0].Columns[0].Value)
+	require.Equal(t, "1", table.Data.TableData.Rows[0].Columns[3].Value)
+}
+
+func TestTapSync_Overflow(t *testing.T) {
+	beforeTest(t)
+	insertAllwise(t, 3)
+
+	votable, w := getVOTable(t, tapSync(url.Values{"QUERY": {"SELECT id FROM mastercat"}, "MAXREC": {"2"}}))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "OVERFLOW", votable.Resource.Infos[0].Value)
+	require.Len(t, votable.Resource.Tables[0].Data.TableData.Rows, 2)
+}
+
+func TestTapSync_CSV(t *testing.T) {
+	beforeTest(t)
+	insertAllwise(t, 3)
+
+	w := httptest.NewRecorder()
+	form := url.Values{"query": {"SELECT TOP 2 id, ra FROM mastercat ORDER BY ra DESC"}, "responseformat": {"csv"}}
+	req, _ := http.NewRequest("POST", "/v1/tap/sync", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"id", "ra"}, {"allwise-2", "2"}, {"allwise-1", "1"}}, records)
+}
+
+func TestTapSync_JSON(t *testing.T) {
+	beforeTest(t)
+	insertAllwise(t, 3)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", tapSync(url.Values{
+		"QUERY":  {"SELECT id, DISTANCE(POINT(ra, dec), POINT(2, 2)) AS dist FROM mastercat WHERE DISTANCE(POINT(ra, dec), POINT(2, 2)) < 0.5"},
+		"FORMAT": {"json"},
+	}), nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var result api.TapResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Equal(t, []api.TapColumn{
+		{Name: "id", Description: "Object id in its catalog", Datatype: "char", ArraySize: "*", Ucd: "meta.id;meta.main"},
+		{Name: "dist", Description: "Angular distance", Datatype: "double", Unit: "deg", Ucd: "pos.angDistance"},
+	}, result.Metadata)
+	require.Equal(t, [][]any{{"allwise-2", 0.0}}, result.Data)
+	require.False(t, result.Overflow)
+}
+
+func TestTapSync_Error(t *testing.T) {
+	testCases := []struct {
+		params  url.Values
+		status  int
+		message string
+	}{
+		{url.Values{"QUERY": {"SELECT * FROM nothing"}}, http.StatusBadRequest, "QUERY: Unknown table (nothing)"},
+		{url.Values{"QUERY": {"SELEC * FROM mastercat"}}, http.StatusBadRequest, "QUERY: Expected SELECT (SELEC)"},
+		{url.Values{"QUERY": {"SELECT * FROM mastercat"}, "LANG": {"SQL"}}, http.StatusBadRequest, "LANG: Only ADQL queries are supported."},
+		{url.Values{"QUERY": {"SELECT * FROM mastercat"}, "REQUEST": {"getCapabilities"}}, http.StatusBadRequest, "REQUEST: Only doQuery requests are supported."},
+		{url.Values{"QUERY": {"SELECT * FROM mastercat"}, "RESPONSEFORMAT": {"fits"}}, http.StatusBadRequest, "RESPONSEFORMAT: Format must be votable, csv or json."},
+		{url.Values{"QUERY": {"SELECT * FROM mastercat"}, "MAXREC": {"-1"}}, http.StatusBadRequest, "MAXREC: MAXREC must be between 0 and 100000 (-1)"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.params.Encode(), func(t *testing.T) {
+			votable, w := getVOTable(t, tapSync(tc.params))
+			require.Equal(t, tc.status, w.Code)
+			require.Equal(t, "ERROR", votable.Resource.Infos[0].Value)
+			require.Equal(t, tc.message, votable.Resource.Infos[0].Text)
+		})
+	}
+}
+
+func TestTapTables(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/tap/tables", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	require.Contains(t, body, "<vosi:tableset")
+	require.Contains(t, body, "<name>allwise</name>")
+	require.Contains(t, body, `<dataType xsi:type="vs:VOTableType" arraysize="*">char</dataType>`)
+	require.Contains(t, body, "<ucd>pos.eq.dec;meta.main</ucd>")
+}
+
+func TestTapCapabilities(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/tap/capabilities", nil)
+	req.Host = "example.org"
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	require.Contains(t, body, `standardID="ivo://ivoa.net/std/TAP"`)
+	require.Contains(t, body, `<accessURL use="base">http://example.org/v1/tap</accessURL>`)
+	require.Contains(t, body, "<form>CONTAINS</form>")
+	require.Contains(t, body, `<hard unit="row">100000</hard>`)
+}
@@ -0,0 +1,360 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	csv_reader "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/reader/csv"
+	parquet_reader "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/reader/parquet"
+	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/source"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+	"github.com/dirodriguezm/xmatch/service/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Formats of the tables that can be uploaded
+const (
+	uploadCSV     = "csv"
+	uploadParquet = "parquet"
+	uploadVOTable = "votable"
+)
+
+// uploadCatalog is the catalog name given to the rows of uploaded tables
+const uploadCatalog = "upload"
+
+const uploadBatchSize = 1000
+
+// uploadedTable is a table uploaded to be crossmatched.
+// Each row holds the values of every column of the file, in the order of fields.
+type uploadedTable struct {
+	format string
+	fields []utils.Field
+	rows   []repository.GenericRow
+}
+
+// The columns added to each row of an uploaded table
+var uploadMatchFields = []utils.Field{
+	{Name: "match_catalog", Datatype: "char", ArraySize: "*", Ucd: "meta.dataset"},
+	{Name: "match_rank", Datatype: "int", Ucd: "meta.number"},
+	{Name: "match_id", Datatype: "char", ArraySize: "*", Ucd: "meta.id.cross"},
+	{Name: "match_ra", Datatype: "double", Unit: "deg", Ucd: "pos.eq.ra"},
+	{Name: "match_dec", Datatype: "double", Unit: "deg", Ucd: "pos.eq.dec"},
+	{Name: "match_separation", Datatype: "double", Unit: "arcsec", Ucd: "pos.angDistance"},
+}
+
+// uploadFormat returns the format given in the request,
+// or the one matching the extension of the file when no format was given
+func uploadFormat(format, fileName string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(fileName)) {
+		case ".csv":
+			format = uploadCSV
+		case ".parquet":
+			format = uploadParquet
+		case ".xml", ".vot", ".votable":
+			format = uploadVOTable
+		}
+	}
+	format = strings.ToLower(format)
+	if !slices.Contains([]string{uploadCSV, uploadParquet, uploadVOTable}, format) {
+		return "", NewParseError(format, "format", "Format must be csv, parquet or votable.")
+	}
+	return format, nil
+}
+
+// receiveUploadedTable reads the table uploaded in the file field of a multipart form,
+// with the format and the id and position columns given in the form.
+// The error response is written when the table can't be read.
+func receiveUploadedTable(c *gin.Context) (uploadedTable, bool) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewParseError("", "file", "A file must be uploaded."))
+		return uploadedTable{}, false
+	}
+	format, err := uploadFormat(c.PostForm("format"), file.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return uploadedTable{}, false
+	}
+	columns := make(map[string]string, 3)
+	for _, field := range []string{"id_column", "ra_column", "dec_column"} {
+		columns[field] = c.PostForm(field)
+		if columns[field] == "" {
+			c.JSON(http.StatusBadRequest, NewParseError("", field, "The column must be given."))
+			return uploadedTable{}, false
+		}
+	}
+
+	// the parquet reader needs a file on disk
+	dir, err := os.MkdirTemp("", "xmatch-upload-*")
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, "Could not read file")
+		return uploadedTable{}, false
+	}
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, filepath.Base(file.Filename))
+	if err := c.SaveUploadedFile(file, fileName); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, "Could not read file")
+		return uploadedTable{}, false
+	}
+
+	table, err := readUploadedTable(fileName, format, columns["id_column"], columns["ra_column"], columns["dec_column"])
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return uploadedTable{}, false
+	}
+	return table, true
+}
+
+// readUploadedTable reads a csv, parquet or VOTable file with the readers of the indexer.
+// The id and position of each row are taken from the given columns, and the rest of
+// the columns are kept as they are.
+func readUploadedTable(fileName, format, idColumn, raColumn, decColumn string) (uploadedTable, error) {
+	schema := repository.GenericSchema{
+		Catalog:   uploadCatalog,
+		IdColumn:  idColumn,
+		RaColumn:  raColumn,
+		DecColumn: decColumn,
+	}
+
+	var table uploadedTable
+	var err error
+	switch format {
+	case uploadCSV:
+		table, err = readUploadedCSV(fileName, schema)
+	case uploadParquet:
+		table, err = readUploadedParquet(fileName, schema)
+	case uploadVOTable:
+		table, err = readUploadedVOTable(fileName, schema)
+	default:
+		return uploadedTable{}, NewParseError(format, "format", "Format must be csv, parquet or votable.")
+	}
+	if err != nil {
+		return uploadedTable{}, NewParseError(filepath.Base(fileName), "file", err.Error())
+	}
+	table.format = format
+	return table, nil
+}
+
+func readUploadedCSV(fileName string, schema repository.GenericSchema) (uploadedTable, error) {
+	src := &source.Source{Sources: []string{fileName}, CatalogName: uploadCatalog}
+	r, err := csv_reader.NewCsvReader(src, csv_reader.WithFirstLineHeader(true), csv_reader.WithCsvBatchSize(uploadBatchSize))
+	if err != nil {
+		return uploadedTable{}, err
+	}
+	header, err := r.ReadHeader()
+	if err != nil {
+		r.Close()
+		return uploadedTable{}, err
+	}
+
+	// csv values are kept as they were written
+	schema.Columns = make([]repository.GenericColumn, len(header))
+	for i := range header {
+		schema.Columns[i] = repository.GenericColumn{Name: header[i], Type: repository.GenericString}
+	}
+	if err := checkUploadColumns(schema); err != nil {
+		r.Close()
+		return uploadedTable{}, err
+	}
+	csv_reader.WithGenericSchema(schema)(r)
+
+	rows, err := r.Read()
+	if err != nil {
+		r.Close()
+		return uploadedTable{}, err
+	}
+	return newUploadedTable(schema, rows), nil
+}
+
+func readUploadedParquet(fileName string, schema repository.GenericSchema) (uploadedTable, error) {
+	columns, err := parquet_reader.ParquetColumns(fileName)
+	if err != nil {
+		return uploadedTable{}, err
+	}
+	schema.Columns = columns
+	if err := checkUploadColumns(schema); err != nil {
+		return uploadedTable{}, err
+	}
+
+	src := &source.Source{Sources: []string{fileName}, CatalogName: uploadCatalog}
+	r, err := parquet_reader.NewGenericParquetReader(src, schema, uploadBatchSize)
+	if err != nil {
+		return uploadedTable{}, err
+	}
+	defer r.Close()
+
+	rows, err := r.Read()
+	if err != nil && err != io.EOF {
+		return uploadedTable{}, err
+	}
+	return newUploadedTable(schema, rows), nil
+}
+
+func readUploadedVOTable(fileName string, schema repository.GenericSchema) (uploadedTable, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return uploadedTable{}, err
+	}
+	votable, err := utils.NewVOTableFromBytes(content)
+	if err != nil {
+		return uploadedTable{}, fmt.Errorf("could not parse VOTable: %w", err)
+	}
+	if len(votable.Resource.Tables) == 0 {
+		return uploadedTable{}, fmt.Errorf("no tables found in VOTable")
+	}
+	votableTable := votable.Resource.Tables[0]
+
+	schema.Columns = make([]repository.GenericColumn, len(votableTable.Fields))
+	for i, field := range votableTable.Fields {
+		schema.Columns[i] = repository.GenericColumn{Name: field.Name, Type: votableGenericType(field.Datatype)}
+	}
+	if err := checkUploadColumns(schema); err != nil {
+		return uploadedTable{}, err
+	}
+
+	rows := make([]repository.GenericRow, len(votableTable.Data.TableData.Rows))
+	for i, tr := range votableTable.Data.TableData.Rows {
+		row, err := schema.NewRow(func(column string) (any, bool) {
+			j := slices.IndexFunc(votableTable.Fields, func(f utils.Field) bool { return f.Name == column })
+			if j < 0 || j >= len(tr.Columns) {
+				return nil, false
+			}
+			value := strings.TrimSpace(tr.Columns[j].Value)
+			if value == "" {
+				return nil, true
+			}
+			return value, true
+		})
+		if err != nil {
+			return uploadedTable{}, fmt.Errorf("row %d: %w", i, err)
+		}
+		rows[i] = row
+	}
+	// the fields of the VOTable keep their units and UCDs
+	return uploadedTable{fields: votableTable.Fields, rows: rows}, nil
+}
+
+// checkUploadColumns checks that the id and position columns are in the table
+func checkUploadColumns(schema repository.GenericSchema) error {
+	for _, column := range []string{schema.IdColumn, schema.RaColumn, schema.DecColumn} {
+		if !slices.ContainsFunc(schema.Columns, func(c repository.GenericColumn) bool { return c.Name == column }) {
+			return fmt.Errorf("column %q not found in table", column)
+		}
+	}
+	return nil
+}
+
+func newUploadedTable(schema repository.GenericSchema, rows []repository.InputSchema) uploadedTable {
+	table := uploadedTable{
+		fields: make([]utils.Field, len(schema.Columns)),
+		rows:   make([]repository.GenericRow, len(rows)),
+	}
+	for i, column := range schema.Columns {
+		table.fields[i] = genericField(column)
+	}
+	for i := range rows {
+		table.rows[i] = rows[i].(repository.GenericRow)
+	}
+	return table
+}
+
+// votableGenericType returns the generic type the values of a VOTable datatype are read as
+func votableGenericType(datatype string) string {
+	switch datatype {
+	case "float", "double":
+		return repository.GenericDouble
+	case "unsignedByte", "short", "int", "long":
+		return repository.GenericLong
+	default:
+		return repository.GenericString
+	}
+}
+
+// crossmatchInput returns the id and position of each row of the table
+func (t uploadedTable) crossmatchInput() []conesearch.CrossmatchInput {
+	rows := make([]conesearch.CrossmatchInput, len(t.rows))
+	for i, row := range t.rows {
+		rows[i] = conesearch.CrossmatchInput{ID: row.ID, Ra: row.Ra, Dec: row.Dec}
+	}
+	return rows
+}
+
+// augmentedRows writes each crossmatch result as the values of its input row
+// followed by the match columns
+func (t uploadedTable) augmentedRows(results []conesearch.CrossmatchResult) [][]string {
+	rows := make([][]string, len(results))
+	for i, result := range results {
+		input := t.rows[result.Index]
+		row := make([]string, 0, len(input.Values)+len(uploadMatchFields))
+		for _, value := range input.Values {
+			row = append(row, formatValue(value))
+		}
+		row = append(row, result.Catalog, strconv.Itoa(result.Rank))
+		if result.Match != nil {
+			row = append(row, result.Match.ID, formatFloat(result.Match.Ra), formatFloat(result.Match.Dec))
+		} else {
+			row = append(row, "", "", "")
+		}
+		row = append(row, formatOptionalFloat(result.Separation))
+		rows[i] = row
+	}
+	return rows
+}
+
+// writeUploadResult answers with the augmented table, as a VOTable when the table
+// was uploaded as one or the client asked for it, and as csv otherwise
+func writeUploadResult(c *gin.Context, table uploadedTable, results []conesearch.CrossmatchResult) {
+	fields := append(slices.Clone(table.fields), uploadMatchFields...)
+	rows := table.augmentedRows(results)
+
+	if table.format == uploadVOTable || wantsVOTable(c) {
+		votableTable := utils.Table{Name: "crossmatch", Fields: fields}
+		for _, row := range rows {
+			tr := utils.Row{Columns: make([]utils.Column, len(row))}
+			for i := range row {
+				tr.Columns[i] = utils.Column{Value: row[i]}
+			}
+			votableTable.Data.TableData.Rows = append(votableTable.Data.TableData.Rows, tr)
+		}
+		writeVOTable(c, http.StatusOK, mimeVOTable, utils.NewVOTable(votableTable))
+		return
+	}
+
+	header := make([]string, len(fields))
+	for i := range fields {
+		header[i] = fields[i].Name
+	}
+	c.Header("Content-Type", "text/csv")
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	w.Write(header)
+	w.WriteAll(rows)
+	if err := w.Error(); err != nil {
+		c.Error(err)
+	}
+}
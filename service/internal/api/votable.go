@@ -0,0 +1,342 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+	"github.com/dirodriguezm/xmatch/service/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const mimeVOTable = "application/x-votable+xml"
+
+// wantsVOTable reports whether the client asked for a VOTable, either with the
+// format query parameter or with the Accept header
+func wantsVOTable(c *gin.Context) bool {
+	if strings.EqualFold(c.Query("format"), "votable") {
+		return true
+	}
+	return c.NegotiateFormat(gin.MIMEJSON, mimeVOTable) == mimeVOTable
+}
+
+// votableColumn describes a FIELD of a table and how to read its value from a row
+type votableColumn[T any] struct {
+	field utils.Field
+	value func(T) string
+}
+
+type mastercatRow struct {
+	index int
+	conesearch.MastercatExtended
+}
+
+// The main identifier and position use the UCD1 words required by the Simple Cone Search standard
+var (
+	idField  = utils.Field{Name: "id", Datatype: "char", ArraySize: "*", Ucd: "ID_MAIN"}
+	raField  = utils.Field{Name: "ra", Datatype: "double", Unit: "deg", Ucd: "POS_EQ_RA_MAIN"}
+	decField = utils.Field{Name: "dec", Datatype: "double", Unit: "deg", Ucd: "POS_EQ_DEC_MAIN"}
+)
+
+var (
+	mastercatID       = votableColumn[mastercatRow]{idField, func(r mastercatRow) string { return r.ID }}
+	mastercatRa       = votableColumn[mastercatRow]{raField, func(r mastercatRow) string { return formatFloat(r.Ra) }}
+	mastercatDec      = votableColumn[mastercatRow]{decField, func(r mastercatRow) string { return formatFloat(r.Dec) }}
+	mastercatCatalog  = votableColumn[mastercatRow]{utils.Field{Name: "catalog", Datatype: "char", ArraySize: "*", Ucd: "meta.dataset"}, func(r mastercatRow) string { return r.Cat }}
+	mastercatDistance = votableColumn[mastercatRow]{utils.Field{Name: "distance", Datatype: "double", Unit: "arcsec", Ucd: "pos.angDistance"}, func(r mastercatRow) string { return formatFloat(r.Distance) }}
+	mastercatIndex    = votableColumn[mastercatRow]{utils.Field{Name: "index", Datatype: "int", Ucd: "meta.number"}, func(r mastercatRow) string { return strconv.Itoa(r.index) }}
+	mastercatIpix     = votableColumn[mastercatRow]{utils.Field{Name: "ipix", Datatype: "long", Ucd: "pos.healpix"}, func(r mastercatRow) string { return strconv.FormatInt(r.Ipix, 10) }}
+	mastercatPosErr   = votableColumn[mastercatRow]{utils.Field{Name: "pos_err", Datatype: "double", Unit: "arcsec", Ucd: "stat.error;pos"}, func(r mastercatRow) string { return formatOptionalFloat(r.PosErr) }}
+	mastercatPmra     = votableColumn[mastercatRow]{utils.Field{Name: "pmra", Datatype: "double", Unit: "mas/yr", Ucd: "pos.pm;pos.eq.ra"}, func(r mastercatRow) string { return formatOptionalFloat(r.Pmra) }}
+	mastercatPmdec    = votableColumn[mastercatRow]{utils.Field{Name: "pmdec", Datatype: "double", Unit: "mas/yr", Ucd: "pos.pm;pos.eq.dec"}, func(r mastercatRow) string { return formatOptionalFloat(r.Pmdec) }}
+	mastercatParallax = votableColumn[mastercatRow]{utils.Field{Name: "parallax", Datatype: "double", Unit: "mas", Ucd: "pos.parallax"}, func(r mastercatRow) string { return formatOptionalFloat(r.Parallax) }}
+	mastercatRefEpoch = votableColumn[mastercatRow]{utils.Field{Name: "ref_epoch", Datatype: "double", Unit: "yr", Ucd: "time.epoch"}, func(r mastercatRow) string { return formatOptionalFloat(r.RefEpoch) }}
+	mastercatBayes    = votableColumn[mastercatRow]{utils.Field{Name: "bayes_factor", Datatype: "double", Ucd: "stat.likelihood"}, func(r mastercatRow) string { return formatOptionalFloat(r.BayesFactor) }}
+	mastercatProb     = votableColumn[mastercatRow]{utils.Field{Name: "probability", Datatype: "double", Ucd: "stat.probability"}, func(r mastercatRow) string { return formatOptionalFloat(r.Probability) }}
+)
+
+// mastercatColumns are the columns of a mastercat table outside of the Simple Cone Search
+var mastercatColumns = []votableColumn[mastercatRow]{
+	mastercatID, mastercatRa, mastercatDec, mastercatCatalog, mastercatDistance, mastercatIndex,
+	mastercatIpix, mastercatPosErr, mastercatPmra, mastercatPmdec, mastercatParallax, mastercatRefEpoch,
+	mastercatBayes, mastercatProb,
+}
+
+// mastercatTable writes the results of every catalog in a single table
+func mastercatTable(results []conesearch.MastercatResult, columns []votableColumn[mastercatRow]) utils.Table {
+	table := utils.Table{Name: "mastercat"}
+	for _, column := range columns {
+		table.Fields = append(table.Fields, column.field)
+	}
+	for _, result := range results {
+		for _, m := range result.Data {
+			row := mastercatRow{index: result.Index, MastercatExtended: m}
+			tr := utils.Row{Columns: make([]utils.Column, len(columns))}
+			for i, column := range columns {
+				tr.Columns[i] = utils.Column{Value: column.value(row)}
+			}
+			table.Data.TableData.Rows = append(table.Data.TableData.Rows, tr)
+		}
+	}
+	return table
+}
+
+// metadataFields holds the UCD and unit of the metadata columns of each catalog row.
+// Columns that are not listed keep the name and datatype of the row.
+var metadataFields = map[reflect.Type]map[string]utils.Field{
+	reflect.TypeFor[repository.Allwise](): {
+		"id":           idField,
+		"cntr":         {Ucd: "meta.id"},
+		"w1mpro":       {Unit: "mag", Ucd: "phot.mag;em.IR.3-4um"},
+		"w1sigmpro":    {Unit: "mag", Ucd: "stat.error;phot.mag;em.IR.3-4um"},
+		"w2mpro":       {Unit: "mag", Ucd: "phot.mag;em.IR.4-8um"},
+		"w2sigmpro":    {Unit: "mag", Ucd: "stat.error;phot.mag;em.IR.4-8um"},
+		"w3mpro":       {Unit: "mag", Ucd: "phot.mag;em.IR.8-15um"},
+		"w3sigmpro":    {Unit: "mag", Ucd: "stat.error;phot.mag;em.IR.8-15um"},
+		"w4mpro":       {Unit: "mag", Ucd: "phot.mag;em.IR.15-30um"},
+		"w4sigmpro":    {Unit: "mag", Ucd: "stat.error;phot.mag;em.IR.15-30um"},
+		"j_m_2mass":    {Unit: "mag", Ucd: "phot.mag;em.IR.J"},
+		"j_msig_2mass": {Unit: "mag", Ucd: "stat.error;phot.mag;em.IR.J"},
+		"h_m_2mass":    {Unit: "mag", Ucd: "phot.mag;em.IR.H"},
+		"h_msig_2mass": {Unit: "mag", Ucd: "stat.error;phot.mag;em.IR.H"},
+		"k_m_2mass":    {Unit: "mag", Ucd: "phot.mag;em.IR.K"},
+		"k_msig_2mass": {Unit: "mag", Ucd: "stat.error;phot.mag;em.IR.K"},
+	},
+	reflect.TypeFor[repository.Gaia](): {
+		"id":                      idField,
+		"phot_g_mean_flux":        {Unit: "e-/s", Ucd: "phot.flux;em.opt"},
+		"phot_g_mean_flux_error":  {Unit: "e-/s", Ucd: "stat.error;phot.flux;em.opt"},
+		"phot_g_mean_mag":         {Unit: "mag", Ucd: "phot.mag;em.opt"},
+		"phot_bp_mean_flux":       {Unit: "e-/s", Ucd: "phot.flux;em.opt.B"},
+		"phot_bp_mean_flux_error": {Unit: "e-/s", Ucd: "stat.error;phot.flux;em.opt.B"},
+		"phot_bp_mean_mag":        {Unit: "mag", Ucd: "phot.mag;em.opt.B"},
+		"phot_rp_mean_flux":       {Unit: "e-/s", Ucd: "phot.flux;em.opt.R"},
+		"phot_rp_mean_flux_error": {Unit: "e-/s", Ucd: "stat.error;phot.flux;em.opt.R"},
+		"phot_rp_mean_mag":        {Unit: "mag", Ucd: "phot.mag;em.opt.R"},
+	},
+}
+
+// catalogMetadataColumns returns the columns of the rows returned by a metadata conesearch of the catalog
+func catalogMetadataColumns(catalogName string) ([]metadataColumn, bool) {
+	desc, ok := catalog.Lookup(catalogName)
+	if !ok {
+		return nil, false
+	}
+	if desc.Generic != nil {
+		return genericColumns(desc.Generic.Columns), true
+	}
+	if desc.Metadata == nil {
+		return nil, false
+	}
+	return metadataColumns(reflect.TypeOf(desc.Metadata)), true
+}
+
+// metadataColumn is a column of a metadata table and the value it reads from each row
+type metadataColumn struct {
+	field utils.Field
+	value func(metadata repository.Metadata) any
+}
+
+// metadataColumns describes the fields of a metadata row, with the same names as the JSON response
+func metadataColumns(rowType reflect.Type) []metadataColumn {
+	columns := make([]metadataColumn, 0, rowType.NumField())
+	for i := range rowType.NumField() {
+		structField := rowType.Field(i)
+		name, ok := jsonName(structField)
+		if !ok {
+			continue
+		}
+		field := metadataFields[rowType][name]
+		field.Name = name
+		field.Datatype, field.ArraySize = votableDatatype(structField.Type)
+		columns = append(columns, metadataColumn{
+			field: field,
+			value: func(metadata repository.Metadata) any {
+				return reflect.Indirect(reflect.ValueOf(metadata)).Field(i).Interface()
+			},
+		})
+	}
+	return columns
+}
+
+// genericColumns describes the columns of a generic catalog, in the order of its schema
+func genericColumns(schemaColumns []repository.GenericColumn) []metadataColumn {
+	columns := []metadataColumn{
+		{idField, func(m repository.Metadata) any { return m.(repository.GenericRow).ID }},
+		{raField, func(m repository.Metadata) any { return m.(repository.GenericRow).Ra }},
+		{decField, func(m repository.Metadata) any { return m.(repository.GenericRow).Dec }},
+	}
+	for i, col := range schemaColumns {
+		columns = append(columns, metadataColumn{
+			field: genericField(col),
+			value: func(m repository.Metadata) any { return m.(repository.GenericRow).Values[i] },
+		})
+	}
+	return columns
+}
+
+// genericField describes a column of a generic catalog with the datatype of its values
+func genericField(col repository.GenericColumn) utils.Field {
+	field := utils.Field{Name: col.Name}
+	switch col.Type {
+	case repository.GenericDouble:
+		field.Datatype = "double"
+	case repository.GenericLong:
+		field.Datatype = "long"
+	default:
+		field.Datatype, field.ArraySize = "char", "*"
+	}
+	return field
+}
+
+func metadataCells(columns []metadataColumn, metadata repository.Metadata) []utils.Column {
+	cells := make([]utils.Column, len(columns))
+	for i, column := range columns {
+		cells[i] = utils.Column{Value: formatValue(column.value(metadata))}
+	}
+	return cells
+}
+
+// metadataTables writes one table for each catalog of the results, with the
+// columns of its rows followed by the distance and the score of the match
+func metadataTables(results []conesearch.MetadataResult) []utils.Table {
+	tables := make([]utils.Table, 0, len(results))
+	for _, result := range results {
+		if len(result.Data) == 0 {
+			continue
+		}
+		var columns []metadataColumn
+		if row, ok := result.Data[0].Metadata.(repository.GenericRow); ok {
+			columns = genericColumns(row.Columns)
+		} else {
+			columns = metadataColumns(reflect.Indirect(reflect.ValueOf(result.Data[0].Metadata)).Type())
+		}
+
+		table := utils.Table{Name: result.Catalog}
+		for _, column := range columns {
+			table.Fields = append(table.Fields, column.field)
+		}
+		table.Fields = append(table.Fields, mastercatDistance.field, mastercatBayes.field, mastercatProb.field)
+
+		for _, m := range result.Data {
+			tr := utils.Row{Columns: metadataCells(columns, m.Metadata)}
+			tr.Columns = append(tr.Columns,
+				utils.Column{Value: formatFloat(m.Distance)},
+				utils.Column{Value: formatOptionalFloat(m.BayesFactor)},
+				utils.Column{Value: formatOptionalFloat(m.Probability)},
+			)
+			table.Data.TableData.Rows = append(table.Data.TableData.Rows, tr)
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+func writeVOTable(c *gin.Context, status int, contentType string, votable *utils.VOTable) {
+	body, err := votable.Bytes()
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, "Could not write VOTable")
+		return
+	}
+	c.Data(status, contentType, body)
+}
+
+// handleVOTableSuccess writes the results of a search as a VOTable.
+// It returns false when the results can't be written as one.
+func handleVOTableSuccess(result any, c *gin.Context) bool {
+	switch r := result.(type) {
+	case []conesearch.MastercatResult:
+		writeVOTable(c, http.StatusOK, mimeVOTable, utils.NewVOTable(mastercatTable(r, mastercatColumns)))
+	case []conesearch.MetadataResult:
+		writeVOTable(c, http.StatusOK, mimeVOTable, utils.NewVOTable(metadataTables(r)...))
+	default:
+		return false
+	}
+	return true
+}
+
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+func votableDatatype(t reflect.Type) (string, string) {
+	switch t {
+	case reflect.TypeFor[repository.NullFloat64]():
+		return "double", ""
+	case reflect.TypeFor[repository.NullInt64]():
+		return "long", ""
+	case reflect.TypeFor[repository.NullString]():
+		return "char", "*"
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Float32:
+		return "float", ""
+	case reflect.Float64:
+		return "double", ""
+	case reflect.Int32:
+		return "int", ""
+	case reflect.Int, reflect.Int64:
+		return "long", ""
+	case reflect.Bool:
+		return "boolean", ""
+	default:
+		return "char", "*"
+	}
+}
+
+// formatValue writes a value as it appears in the JSON response. Nulls are written as empty cells.
+func formatValue(value any) string {
+	b, err := json.Marshal(value)
+	if err != nil || string(b) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		return s
+	}
+	return string(b)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return formatFloat(*value)
+}
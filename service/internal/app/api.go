@@ -21,16 +21,22 @@ import (
 	"io"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/dirodriguezm/xmatch/service/internal/api"
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
 	"github.com/dirodriguezm/xmatch/service/internal/config"
 	"github.com/dirodriguezm/xmatch/service/internal/repository"
 	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+	"github.com/dirodriguezm/xmatch/service/internal/search/jobs"
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/gaia"
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/httpclient"
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/neowise"
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/ztfdr"
 	"github.com/dirodriguezm/xmatch/service/internal/search/metadata"
+	"github.com/dirodriguezm/xmatch/service/internal/search/tap"
 
 	_ "github.com/mattn/go-sqlite3"
 
@@ -82,18 +88,31 @@ func ServiceRepository(db *sql.DB) conesearch.Repository {
 	return repository.New(db)
 }
 
-func ConesearchService(repo conesearch.Repository) (*conesearch.ConesearchService, error) {
+// ConesearchCacheOptions returns the options of the conesearch caches described by the config
+func ConesearchCacheOptions(cfg config.CacheConfig) []conesearch.ConesearchOption {
+	return []conesearch.ConesearchOption{
+		conesearch.WithPixelCache(max(cfg.PixelCacheSize, 0)),
+		conesearch.WithResponseCache(max(cfg.ResponseCacheSize, 0)),
+	}
+}
+
+func ConesearchService(repo conesearch.Repository, opts ...conesearch.ConesearchOption) (*conesearch.ConesearchService, error) {
 	ctx := context.Background()
 	catalogs, err := repo.GetCatalogs(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("could not find catalogs in DB when creating conesearch service: %w", err)
 	}
 
-	con, err := conesearch.NewConesearchService(
+	err = catalog.RegisterGenericCatalogs(ctx, repo, catalogs)
+	if err != nil {
+		return nil, fmt.Errorf("could not register generic catalogs: %w", err)
+	}
+
+	con, err := conesearch.NewConesearchService(append([]conesearch.ConesearchOption{
 		conesearch.WithScheme(healpix.Nest),
 		conesearch.WithRepository(repo),
 		conesearch.WithCatalogs(catalogs),
-	)
+	}, opts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("could not create ConesearchService: %w", err)
 	}
@@ -108,23 +127,40 @@ func MetadataService(repo conesearch.Repository) (*metadata.MetadataService, err
 	return service, nil
 }
 
-func LightcurveService(cfg config.Config, conesearchService *conesearch.ConesearchService) (*lightcurve.LightcurveService, error) {
-	neowiseFilter := lightcurve.DummyLightcurveFilter
-	if cfg.Service.LightcurveServiceConfig.NeowiseConfig.UseIdFilter || cfg.Service.LightcurveServiceConfig.NeowiseConfig.UseCntrFilter {
-		neowiseFilter = neowise.Filter
+func TapService(repo conesearch.Repository) (*tap.TapService, error) {
+	catalogs, err := repo.GetCatalogs(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("could not find catalogs in DB when creating TAP service: %w", err)
 	}
-	ztfFilter := lightcurve.DummyLightcurveFilter
-	if cfg.Service.LightcurveServiceConfig.ZtfDrConfig.UseIdFilter {
-		ztfFilter = ztfdr.Filter
+
+	service, err := tap.NewTapService(repo, catalogs)
+	if err != nil {
+		return nil, fmt.Errorf("could not create TapService: %w", err)
 	}
-	sources := []lightcurve.Source{
-		{Catalog: "neowise", Client: neowise.NewNeowiseClient(), Filter: neowiseFilter},
-		{Catalog: "ztf", Client: ztfdr.NewZtfDrClient(), Filter: ztfFilter},
+	return service, nil
+}
+
+func LightcurveRepository(db *sql.DB) lightcurve.Repository {
+	return repository.New(db)
+}
+
+func LightcurveService(cfg config.Config, conesearchService *conesearch.ConesearchService, repo lightcurve.Repository) (*lightcurve.LightcurveService, error) {
+	sources := make([]lightcurve.Source, 0, len(cfg.Service.LightcurveServiceConfig.Sources))
+	for _, sourceConfig := range cfg.Service.LightcurveServiceConfig.Sources {
+		if !sourceConfig.IsEnabled() {
+			continue
+		}
+		source, err := LightcurveSource(sourceConfig)
+		if err != nil {
+			return nil, fmt.Errorf("could not create LightcurveService: %w", err)
+		}
+		sources = append(sources, source)
 	}
 
 	service, err := lightcurve.New(
 		sources,
 		conesearchService,
+		repo,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("could not create LightcurveService: %w", err)
@@ -132,6 +168,105 @@ func LightcurveService(cfg config.Config, conesearchService *conesearch.Conesear
 	return service, nil
 }
 
-func API(conesearchService *conesearch.ConesearchService, metadataService *metadata.MetadataService, lightcurveService *lightcurve.LightcurveService, cfg config.ServiceConfig, getenv func(string) string) (*api.API, error) {
-	return api.New(conesearchService, metadataService, lightcurveService, cfg, getenv)
+// LightcurveSource creates the client of a lightcurve source from its configuration
+func LightcurveSource(cfg config.LightcurveSourceConfig) (lightcurve.Source, error) {
+	httpClient, err := LightcurveHTTPClient(cfg)
+	if err != nil {
+		return lightcurve.Source{}, err
+	}
+	filter := strings.ToLower(cfg.Filter)
+	if filter != "" && filter != "none" && filter != "id" {
+		return lightcurve.Source{}, fmt.Errorf("unknown filter %q for lightcurve source %s", cfg.Filter, cfg.Type)
+	}
+
+	var source lightcurve.Source
+	switch strings.ToLower(cfg.Type) {
+	case "neowise":
+		opts := []neowise.NeowiseClientOption{neowise.WithHTTPClient(httpClient)}
+		if cfg.Url != "" {
+			opts = append(opts, neowise.WithURL(cfg.Url))
+		}
+		client, err := neowise.NewNeowiseClient(opts...)
+		if err != nil {
+			return lightcurve.Source{}, err
+		}
+		source = lightcurve.Source{Catalog: "neowise", Client: client, Filter: neowise.Filter}
+	case "ztf_dr":
+		opts := []ztfdr.ZtfDrClientOption{ztfdr.WithHTTPClient(httpClient)}
+		if cfg.Url != "" {
+			opts = append(opts, ztfdr.WithURL(cfg.Url))
+		}
+		client, err := ztfdr.NewZtfDrClient(opts...)
+		if err != nil {
+			return lightcurve.Source{}, err
+		}
+		source = lightcurve.Source{Catalog: "ztf", Client: client, Filter: ztfdr.Filter}
+	case "gaia_dr3":
+		opts := []gaia.GaiaClientOption{gaia.WithHTTPClient(httpClient)}
+		if cfg.Url != "" {
+			opts = append(opts, gaia.WithURL(cfg.Url))
+		}
+		client, err := gaia.NewGaiaClient(opts...)
+		if err != nil {
+			return lightcurve.Source{}, err
+		}
+		source = lightcurve.Source{Catalog: "gaia", Client: client, Filter: gaia.Filter}
+	default:
+		return lightcurve.Source{}, fmt.Errorf("unknown lightcurve source type %q", cfg.Type)
+	}
+
+	if filter != "id" {
+		source.Filter = lightcurve.DummyLightcurveFilter
+	}
+	if cfg.Catalog != "" {
+		source.Catalog = strings.ToLower(cfg.Catalog)
+	}
+	return source, nil
+}
+
+// LightcurveHTTPClient creates the http client a lightcurve source makes its requests with
+func LightcurveHTTPClient(cfg config.LightcurveSourceConfig) (*httpclient.Client, error) {
+	opts := []httpclient.Option{httpclient.WithTimeout(time.Duration(cfg.Timeout) * time.Second)}
+	if cfg.Retries != 0 {
+		opts = append(opts, httpclient.WithRetries(cfg.Retries, time.Duration(cfg.RetryBackoff)*time.Millisecond))
+	}
+	if cfg.BreakerThreshold != 0 {
+		opts = append(opts, httpclient.WithCircuitBreaker(cfg.BreakerThreshold, time.Duration(cfg.BreakerCooldown)*time.Second))
+	}
+	if cfg.MaxConcurrency != 0 {
+		opts = append(opts, httpclient.WithMaxConcurrency(cfg.MaxConcurrency))
+	}
+	if cfg.UserAgent != "" {
+		opts = append(opts, httpclient.WithUserAgent(cfg.UserAgent))
+	}
+	return httpclient.New(strings.ToLower(cfg.Type), opts...)
+}
+
+func JobsRepository(db *sql.DB) jobs.Repository {
+	return repository.New(db)
+}
+
+func JobsService(repo jobs.Repository, conesearchService *conesearch.ConesearchService, cfg config.ServiceConfig) (*jobs.JobsService, error) {
+	opts := []jobs.JobsOption{
+		jobs.WithRepository(repo),
+		jobs.WithSearcher(conesearchService),
+		jobs.WithBulkSettings(cfg.BulkChunkSize, cfg.MaxBulkConcurrency),
+		jobs.WithWorkers(cfg.Jobs.Workers),
+		jobs.WithQueueSize(cfg.Jobs.QueueSize),
+		jobs.WithExpiration(time.Duration(cfg.Jobs.Expiration) * time.Second),
+		jobs.WithCleanupInterval(time.Duration(cfg.Jobs.CleanupInterval) * time.Second),
+	}
+	if cfg.Jobs.Directory != "" {
+		opts = append(opts, jobs.WithDirectory(cfg.Jobs.Directory))
+	}
+
+	service, err := jobs.NewJobsService(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create JobsService: %w", err)
+	}
+	return service, nil
+}
+
+func API(conesearchService *conesearch.ConesearchService, metadataService *metadata.MetadataService, lightcurveService *lightcurve.LightcurveService, tapService *tap.TapService, jobsService *jobs.JobsService, cfg config.ServiceConfig, getenv func(string) string) (*api.API, error) {
+	return api.New(conesearchService, metadataService, lightcurveService, tapService, jobsService, cfg, getenv)
 }
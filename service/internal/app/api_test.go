@@ -5,42 +5,44 @@ import (
 	"testing"
 
 	"github.com/dirodriguezm/xmatch/service/internal/config"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
 	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/gaia"
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/ztfdr"
 	"github.com/stretchr/testify/require"
 )
 
-func TestLightcurveService_AlwaysAddsZtfDrClient(t *testing.T) {
-	cfg := config.Config{
+func lightcurveConfig(sources ...config.LightcurveSourceConfig) config.Config {
+	return config.Config{
 		Service: config.ServiceConfig{
-			LightcurveServiceConfig: config.LightcurveServiceConfig{
-				NeowiseConfig: config.NeowiseConfig{},
-				ZtfDrConfig:   config.ZtfDrConfig{UseIdFilter: false},
-			},
+			LightcurveServiceConfig: config.LightcurveServiceConfig{Sources: sources},
 		},
 	}
+}
+
+func TestLightcurveService_AddsDefaultSources(t *testing.T) {
+	cfg, err := Config(func(string) string { return "" })
+	require.NoError(t, err)
 
-	service, err := LightcurveService(cfg, &conesearch.ConesearchService{})
+	service, err := LightcurveService(cfg, &conesearch.ConesearchService{}, repository.New(nil))
 	require.NoError(t, err)
 
 	sources := reflect.ValueOf(service).Elem().FieldByName("sources")
-	require.Equal(t, 2, sources.Len())
+	require.Equal(t, 2, sources.Len(), "gaia_dr3 is disabled by default")
+	require.Equal(t, "neowise", sources.Index(0).FieldByName("Catalog").String())
 	require.Equal(t, "ztf", sources.Index(1).FieldByName("Catalog").String())
 	require.Equal(t, reflect.ValueOf(lightcurve.DummyLightcurveFilter).Pointer(), sources.Index(1).FieldByName("Filter").Pointer())
+	require.NotContains(t, service.Catalogs(), "gaia")
 }
 
 func TestLightcurveService_AddsZtfDrFilterWhenEnabled(t *testing.T) {
-	cfg := config.Config{
-		Service: config.ServiceConfig{
-			LightcurveServiceConfig: config.LightcurveServiceConfig{
-				NeowiseConfig: config.NeowiseConfig{},
-				ZtfDrConfig:   config.ZtfDrConfig{UseIdFilter: true},
-			},
-		},
-	}
+	cfg := lightcurveConfig(
+		config.LightcurveSourceConfig{Type: "neowise"},
+		config.LightcurveSourceConfig{Type: "ztf_dr", Filter: "id"},
+	)
 
-	service, err := LightcurveService(cfg, &conesearch.ConesearchService{})
+	service, err := LightcurveService(cfg, &conesearch.ConesearchService{}, repository.New(nil))
 	require.NoError(t, err)
 
 	sources := reflect.ValueOf(service).Elem().FieldByName("sources")
@@ -48,3 +50,41 @@ func TestLightcurveService_AddsZtfDrFilterWhenEnabled(t *testing.T) {
 	require.Equal(t, "ztf", sources.Index(1).FieldByName("Catalog").String())
 	require.Equal(t, reflect.ValueOf(ztfdr.Filter).Pointer(), sources.Index(1).FieldByName("Filter").Pointer())
 }
+
+func TestLightcurveService_ConfiguredSources(t *testing.T) {
+	disabled := false
+	cfg := lightcurveConfig(
+		config.LightcurveSourceConfig{Type: "neowise", Enabled: &disabled},
+		config.LightcurveSourceConfig{Type: "ztf_dr", Url: "http://localhost:8080/light_curve/", Timeout: 5},
+		config.LightcurveSourceConfig{Type: "ztf_dr", Catalog: "ZTF-mirror", Filter: "id"},
+		config.LightcurveSourceConfig{Type: "gaia_dr3", Url: "http://localhost:8081", Filter: "id"},
+	)
+
+	service, err := LightcurveService(cfg, &conesearch.ConesearchService{}, repository.New(nil))
+	require.NoError(t, err)
+
+	sources := reflect.ValueOf(service).Elem().FieldByName("sources")
+	require.Equal(t, 3, sources.Len(), "the disabled source is skipped")
+	require.Equal(t, "ztf", sources.Index(0).FieldByName("Catalog").String())
+	require.Equal(t, "ztf-mirror", sources.Index(1).FieldByName("Catalog").String())
+	require.Equal(t, reflect.ValueOf(ztfdr.Filter).Pointer(), sources.Index(1).FieldByName("Filter").Pointer())
+	require.Equal(t, "gaia", sources.Index(2).FieldByName("Catalog").String())
+	require.Equal(t, reflect.ValueOf(gaia.Filter).Pointer(), sources.Index(2).FieldByName("Filter").Pointer())
+}
+
+func TestLightcurveService_InvalidSources(t *testing.T) {
+	testCases := map[string]config.LightcurveSourceConfig{
+		"unknown type":     {Type: "gaia"},
+		"unknown filter":   {Type: "neowise", Filter: "cntr"},
+		"invalid url":      {Type: "ztf_dr", Url: "not a url"},
+		"negative timeout": {Type: "neowise", Timeout: -1},
+		"negative retries": {Type: "neowise", Retries: -1},
+		"negative breaker": {Type: "ztf_dr", BreakerThreshold: -1},
+		"negative limit":   {Type: "ztf_dr", MaxConcurrency: -2},
+	}
+
+	for name, sourceConfig := range testCases {
+		_, err := LightcurveService(lightcurveConfig(sourceConfig), &conesearch.ConesearchService{}, repository.New(nil))
+		require.Error(t, err, name)
+	}
+}
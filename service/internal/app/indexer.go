@@ -7,13 +7,13 @@ import (
 	"strings"
 
 	"github.com/dirodriguezm/xmatch/service/internal/actor"
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
 	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/indexer"
 	mastercat_indexer "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/indexer/mastercat"
 	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/indexer/metadata"
 	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/reader"
 	reader_factory "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/reader/factory"
 	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/source"
-	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/writer"
 	parquet_writer "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/writer/parquet"
 	sqlite_writer "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/writer/sqlite"
 	"github.com/dirodriguezm/xmatch/service/internal/config"
@@ -21,10 +21,6 @@ import (
 	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
 )
 
-const ALLWISE = "allwise"
-const GAIA = "gaia"
-const EROSITA = "erosita"
-
 func Config(getenv func(string) string) (config.Config, error) {
 	return config.Load(getenv)
 }
@@ -54,6 +50,31 @@ func CatalogRegister(ctx context.Context, repo conesearch.Repository, srcConfig
 	return indexer.NewCatalogRegister(ctx, repo, srcConfig)
 }
 
+// GenericCatalog registers the source catalog as a generic catalog when its
+// configuration describes a schema
+func GenericCatalog(srcConfig config.SourceConfig) error {
+	if srcConfig.Schema == nil {
+		return nil
+	}
+
+	columns := make([]repository.GenericColumn, len(srcConfig.Schema.Columns))
+	for i, col := range srcConfig.Schema.Columns {
+		columns[i] = repository.GenericColumn{Name: col.Name, Type: col.Type}
+	}
+
+	err := catalog.RegisterGeneric(repository.GenericSchema{
+		Catalog:   srcConfig.CatalogName,
+		IdColumn:  srcConfig.Schema.IdColumn,
+		RaColumn:  srcConfig.Schema.RaColumn,
+		DecColumn: srcConfig.Schema.DecColumn,
+		Columns:   columns,
+	})
+	if err != nil {
+		return fmt.Errorf("could not register generic catalog: %w", err)
+	}
+	return nil
+}
+
 func Source(cfg config.SourceConfig) (*source.Source, error) {
 	return source.NewSource(cfg)
 }
@@ -75,55 +96,35 @@ func MastercatWriter(ctx context.Context, cfg config.Config, repo conesearch.Rep
 }
 
 func MetadataWriter(ctx context.Context, cfg config.Config, repo conesearch.Repository, src *source.Source) (*actor.Actor, error) {
+	desc, ok := catalog.Lookup(cfg.CatalogIndexer.Source.CatalogName)
+	if !ok || desc.BulkInsert == nil {
+		return nil, fmt.Errorf("Unknown catalog %s", cfg.CatalogIndexer.Source.CatalogName)
+	}
+
 	switch cfg.CatalogIndexer.MetadataWriter.Type {
 	case "parquet":
-		var w writer.Writer
-		var err error
-		switch strings.ToLower(cfg.CatalogIndexer.Source.CatalogName) {
-		case ALLWISE:
-			w, err = parquet_writer.New[repository.Allwise](cfg.CatalogIndexer.MetadataWriter, ctx)
-		case GAIA:
-			w, err = parquet_writer.New[repository.Gaia](cfg.CatalogIndexer.MetadataWriter, ctx)
-		case EROSITA:
-			w, err = parquet_writer.New[repository.Erosita](cfg.CatalogIndexer.MetadataWriter, ctx)
-		default:
-			err = fmt.Errorf("Unknown catalog %s", cfg.CatalogIndexer.Source.CatalogName)
-		}
+		w, err := desc.NewMetadataParquetWriter(cfg.CatalogIndexer.MetadataWriter, ctx)
 		if err != nil {
 			return nil, err
 		}
 		return actor.New("metadata writer", cfg.CatalogIndexer.ChannelSize, w.Write, w.Stop, nil, ctx), nil
 	case "sqlite":
-		switch strings.ToLower(cfg.CatalogIndexer.Source.CatalogName) {
-		case ALLWISE:
-			w := sqlite_writer.New(repo, ctx, repo.BulkInsertAllwise)
-			return actor.New("metadata writer", cfg.CatalogIndexer.ChannelSize, w.Write, w.Stop, nil, ctx), nil
-		case GAIA:
-			w := sqlite_writer.New(repo, ctx, repo.BulkInsertGaia)
-			return actor.New("metadata writer", cfg.CatalogIndexer.ChannelSize, w.Write, w.Stop, nil, ctx), nil
-		case EROSITA:
-			w := sqlite_writer.New(repo, ctx, repo.BulkInsertErosita)
-			return actor.New("metadata writer", cfg.CatalogIndexer.ChannelSize, w.Write, w.Stop, nil, ctx), nil
-		default:
-			return nil, fmt.Errorf("Unknown catalog %s", cfg.CatalogIndexer.Source.CatalogName)
+		bulkInsert := func(ctx context.Context, db *sql.DB, rows []any) error {
+			return desc.BulkInsert(repo, ctx, db, rows)
 		}
+		w := sqlite_writer.New(repo, ctx, bulkInsert)
+		return actor.New("metadata writer", cfg.CatalogIndexer.ChannelSize, w.Write, w.Stop, nil, ctx), nil
 	default:
 		return nil, fmt.Errorf("Unknown Metadata Writer Type: %s", cfg.CatalogIndexer.MetadataWriter.Type)
 	}
 }
 
 func MastercatIndexer(cfg config.CatalogIndexerConfig, writer *actor.Actor, ctx context.Context) (*actor.Actor, error) {
+	if _, ok := catalog.Lookup(cfg.Source.CatalogName); !ok {
+		return nil, fmt.Errorf("Unknown catalog %s", cfg.Source.CatalogName)
+	}
 	fillMastercat := func(schema repository.InputSchema, ipix int64) repository.Mastercat {
-		switch cfg.Source.CatalogName {
-		case ALLWISE:
-			return repository.AllwiseInputSchema.FillMastercat(schema.(repository.AllwiseInputSchema), ipix)
-		case GAIA:
-			return repository.GaiaInputSchema.FillMastercat(schema.(repository.GaiaInputSchema), ipix)
-		case EROSITA:
-			return repository.ErositaInputSchema.FillMastercat(schema.(repository.ErositaInputSchema), ipix)
-		default:
-			panic("Catalog not supported")
-		}
+		return schema.FillMastercat(ipix)
 	}
 
 	ind, err := mastercat_indexer.New(cfg.Indexer, fillMastercat)
@@ -135,25 +136,16 @@ func MastercatIndexer(cfg config.CatalogIndexerConfig, writer *actor.Actor, ctx
 
 func MetadataIndexer(cfg config.CatalogIndexerConfig, writer *actor.Actor, ctx context.Context) *actor.Actor {
 	fillMetadata := func(schema repository.InputSchema) repository.Metadata {
-		switch cfg.Source.CatalogName {
-		case ALLWISE:
-			return repository.AllwiseInputSchema.FillMetadata(schema.(repository.AllwiseInputSchema))
-		case GAIA:
-			return repository.GaiaInputSchema.FillMetadata(schema.(repository.GaiaInputSchema))
-		case EROSITA:
-			return repository.ErositaInputSchema.FillMetadata(schema.(repository.ErositaInputSchema))
-		default:
-			panic("Catalog not supported")
-		}
+		return schema.FillMetadata()
 	}
 	ind := metadata.New(fillMetadata)
 	return actor.New("metadata indexer", cfg.ChannelSize, ind.Index, nil, []*actor.Actor{writer}, ctx)
 }
 
+// Reader reads the source into the given indexers, a nil indexer doesn't receive the rows
 func Reader(
 	src *source.Source,
 	cfg config.ReaderConfig,
-	srcConfig config.SourceConfig,
 	mastercatIndexer *actor.Actor,
 	metadataIndexer *actor.Actor,
 ) (reader.SourceReader, error) {
@@ -165,10 +157,17 @@ func Reader(
 	sourceReader := reader.SourceReader{
 		Reader:    r,
 		BatchSize: cfg.BatchSize,
-		Receivers: []*actor.Actor{mastercatIndexer},
 	}
-	if srcConfig.Metadata {
-		sourceReader.Receivers = append(sourceReader.Receivers, metadataIndexer)
+	for _, receiver := range []*actor.Actor{mastercatIndexer, metadataIndexer} {
+		if receiver != nil {
+			sourceReader.Receivers = append(sourceReader.Receivers, receiver)
+		}
 	}
 	return sourceReader, nil
 }
+
+// DetectionsSource tells if the source holds detections of objects already indexed in the mastercat
+func DetectionsSource(srcConfig config.SourceConfig) bool {
+	desc, ok := catalog.Lookup(srcConfig.CatalogName)
+	return ok && desc.Detections
+}
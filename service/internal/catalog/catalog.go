@@ -0,0 +1,279 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package catalog keeps the registry of the catalogs known to the service.
+//
+// Every part of the service that depends on the catalog being processed (the
+// indexer, the readers, the search services and the lightcurve sources) looks
+// it up here, so supporting a new catalog takes one registration in
+// catalogs.go plus the migration and queries of its metadata table.
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/reader"
+	parquet_reader "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/reader/parquet"
+	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/source"
+	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/writer"
+	parquet_writer "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/writer/parquet"
+	"github.com/dirodriguezm/xmatch/service/internal/config"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+)
+
+// Repository has the queries that read and write the metadata tables of the catalogs
+type Repository interface {
+	BulkInsertAllwise(context.Context, *sql.DB, []any) error
+	BulkInsertGaia(context.Context, *sql.DB, []any) error
+	BulkInsertErosita(context.Context, *sql.DB, []any) error
+	BulkInsertXwaveDetection(context.Context, *sql.DB, []any) error
+	GetAllwise(context.Context, string) (repository.GetAllwiseRow, error)
+	GetGaia(context.Context, string) (repository.GetGaiaRow, error)
+	GetErosita(context.Context, string) (repository.GetErositaRow, error)
+	BulkGetAllwise(context.Context, []string) ([]repository.BulkGetAllwiseRow, error)
+	BulkGetGaia(context.Context, []string) ([]repository.BulkGetGaiaRow, error)
+	BulkGetErosita(context.Context, []string) ([]repository.BulkGetErositaRow, error)
+	GetAllwiseFromPixels(context.Context, []int64) ([]repository.GetAllwiseFromPixelsRow, error)
+	GetGaiaFromPixels(context.Context, []int64) ([]repository.GetGaiaFromPixelsRow, error)
+	GetErositaFromPixels(context.Context, []int64) ([]repository.GetErositaFromPixelsRow, error)
+	GetAllwiseBetweenPixels(context.Context, repository.GetAllwiseBetweenPixelsParams) ([]repository.GetAllwiseBetweenPixelsRow, error)
+	GetGaiaBetweenPixels(context.Context, repository.GetGaiaBetweenPixelsParams) ([]repository.GetGaiaBetweenPixelsRow, error)
+	GetErositaBetweenPixels(context.Context, repository.GetErositaBetweenPixelsParams) ([]repository.GetErositaBetweenPixelsRow, error)
+	GetGenericSchema(context.Context, string) (repository.GenericSchema, error)
+	BulkInsertGeneric(context.Context, *sql.DB, repository.GenericSchema, []any) error
+	GetGeneric(context.Context, repository.GenericSchema, string) (repository.GenericRow, error)
+	BulkGetGeneric(context.Context, repository.GenericSchema, []string) ([]repository.GenericRow, error)
+	GetGenericFromPixels(context.Context, repository.GenericSchema, []int64) ([]repository.GenericRow, error)
+	GetGenericBetweenPixels(context.Context, repository.GenericSchema, int64, int64) ([]repository.GenericRow, error)
+}
+
+// CatalogDescriptor describes how the service reads, indexes and searches a catalog.
+//
+// Only Name is required. Catalogs without InputSchema can't be indexed from their
+// own files, and catalogs without the metadata functions are only available in
+// the mastercat.
+type CatalogDescriptor struct {
+	// Name is the value stored in mastercat.cat, and the one used in requests
+	Name string
+	// DisplayName is the name returned by the metadata rows of the catalog
+	DisplayName string
+
+	// InputSchema is an empty row of the source files of the catalog
+	InputSchema repository.InputSchema
+	// NewParquetReader reads source files of the catalog in parquet format
+	NewParquetReader func(src *source.Source, batchSize int) (reader.Reader, error)
+	// NewMetadataParquetWriter writes the metadata of the catalog in parquet format
+	NewMetadataParquetWriter func(cfg config.WriterConfig, ctx context.Context) (writer.Writer, error)
+
+	// BulkInsert inserts metadata rows in the database
+	BulkInsert func(repo Repository, ctx context.Context, db *sql.DB, rows []any) error
+	// Get returns the metadata of one object
+	Get func(ctx context.Context, repo Repository, id string) (any, error)
+	// BulkGet returns the metadata of several objects, as a slice of the catalog rows
+	BulkGet func(ctx context.Context, repo Repository, ids []string) (any, error)
+	// FromPixels returns the metadata of the objects in the given pixels
+	FromPixels func(ctx context.Context, repo Repository, pixels []int64) ([]repository.MetadataWithCoordinates, error)
+	// BetweenPixels works like FromPixels for the pixels from start to stop, excluded,
+	// so long ranges of pixels don't have to be listed
+	BetweenPixels func(ctx context.Context, repo Repository, start, stop int64) ([]repository.MetadataWithCoordinates, error)
+	// ToMetadata converts a row returned by FromPixels to the metadata model of the catalog
+	ToMetadata func(repository.MetadataWithCoordinates) repository.Metadata
+	// Metadata is an empty row of the metadata model returned by ToMetadata
+	Metadata repository.Metadata
+
+	// Generic is the schema of catalogs without Go types, which readers use to build their rows
+	Generic *repository.GenericSchema
+	// Columns describe the metadata columns published with ADQL, besides the id of the objects
+	Columns []Column
+
+	// LightcurveSource is the name of the lightcurve source of the catalog, if it has another name
+	LightcurveSource string
+	// Detections tells the source files hold per-epoch detections of objects already
+	// in the mastercat. They are written by the metadata writer and skip the mastercat.
+	Detections bool
+}
+
+// Column is a metadata column of a catalog, described with VOTable metadata
+type Column struct {
+	Name        string
+	Description string
+	// Datatype is a VOTable datatype, like char, long or double
+	Datatype string
+	Unit     string
+	Ucd      string
+}
+
+// HasMetadata tells if the metadata of the catalog can be searched
+func (d CatalogDescriptor) HasMetadata() bool {
+	return d.Get != nil && d.BulkGet != nil
+}
+
+// NewInputSchema returns a pointer to a new empty row of the source files of the catalog
+func (d CatalogDescriptor) NewInputSchema() any {
+	return reflect.New(reflect.TypeOf(d.InputSchema)).Interface()
+}
+
+// BulkGetRows works like BulkGet, but returns each row as an element of the slice
+func (d CatalogDescriptor) BulkGetRows(ctx context.Context, repo Repository, ids []string) ([]any, error) {
+	result, err := d.BulkGet(ctx, repo, ids)
+	if err != nil {
+		return nil, err
+	}
+	rows := reflect.ValueOf(result)
+	items := make([]any, rows.Len())
+	for i := range items {
+		items[i] = rows.Index(i).Interface()
+	}
+	return items, nil
+}
+
+var registry []CatalogDescriptor
+
+// Register adds a catalog to the registry.
+//
+// It panics if the name is empty or already registered, as registrations
+// are only made while initializing the package.
+func Register(d CatalogDescriptor) {
+	if d.Name == "" {
+		panic("catalog name can't be empty")
+	}
+	if _, ok := Lookup(d.Name); ok {
+		panic(fmt.Sprintf("catalog %s already registered", d.Name))
+	}
+	d.Name = strings.ToLower(d.Name)
+	registry = append(registry, d)
+}
+
+// Lookup returns the catalog with the given name, ignoring case
+func Lookup(name string) (CatalogDescriptor, bool) {
+	for _, d := range registry {
+		if strings.EqualFold(d.Name, name) {
+			return d, true
+		}
+	}
+	return CatalogDescriptor{}, false
+}
+
+// LookupDisplayName returns the catalog with the given display name, ignoring case
+func LookupDisplayName(displayName string) (CatalogDescriptor, bool) {
+	for _, d := range registry {
+		if d.DisplayName != "" && strings.EqualFold(d.DisplayName, displayName) {
+			return d, true
+		}
+	}
+	return CatalogDescriptor{}, false
+}
+
+// LookupLightcurveSource returns the catalog whose lightcurves come from the given source
+func LookupLightcurveSource(lightcurveSource string) (CatalogDescriptor, bool) {
+	for _, d := range registry {
+		if d.LightcurveSource != "" && strings.EqualFold(d.LightcurveSource, lightcurveSource) {
+			return d, true
+		}
+	}
+	return CatalogDescriptor{}, false
+}
+
+// All returns the registered catalogs in registration order
+func All() []CatalogDescriptor {
+	result := make([]CatalogDescriptor, len(registry))
+	copy(result, registry)
+	return result
+}
+
+// Names returns the names of the registered catalogs in registration order.
+// Detection catalogs are left out, as their objects are searched in the other catalogs.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for _, d := range registry {
+		if !d.Detections {
+			names = append(names, d.Name)
+		}
+	}
+	return names
+}
+
+func parquetReader[T repository.InputSchema]() func(*source.Source, int) (reader.Reader, error) {
+	return func(src *source.Source, batchSize int) (reader.Reader, error) {
+		return parquet_reader.NewParquetReader(src, parquet_reader.WithParquetBatchSize[T](batchSize))
+	}
+}
+
+func parquetWriter[T any]() func(config.WriterConfig, context.Context) (writer.Writer, error) {
+	return func(cfg config.WriterConfig, ctx context.Context) (writer.Writer, error) {
+		return parquet_writer.New[T](cfg, ctx)
+	}
+}
+
+func get[T any](query func(Repository, context.Context, string) (T, error)) func(context.Context, Repository, string) (any, error) {
+	return func(ctx context.Context, repo Repository, id string) (any, error) {
+		result, err := query(repo, ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
+
+func bulkGet[T any](query func(Repository, context.Context, []string) ([]T, error)) func(context.Context, Repository, []string) (any, error) {
+	return func(ctx context.Context, repo Repository, ids []string) (any, error) {
+		result, err := query(repo, ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
+
+func fromPixels[T repository.MetadataWithCoordinates](
+	query func(Repository, context.Context, []int64) ([]T, error),
+) func(context.Context, Repository, []int64) ([]repository.MetadataWithCoordinates, error) {
+	return func(ctx context.Context, repo Repository, pixels []int64) ([]repository.MetadataWithCoordinates, error) {
+		rows, err := query(repo, ctx, pixels)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]repository.MetadataWithCoordinates, len(rows))
+		for i := range rows {
+			result[i] = rows[i]
+		}
+		return result, nil
+	}
+}
+
+func betweenPixels[T repository.MetadataWithCoordinates](
+	query func(Repository, context.Context, int64, int64) ([]T, error),
+) func(context.Context, Repository, int64, int64) ([]repository.MetadataWithCoordinates, error) {
+	return func(ctx context.Context, repo Repository, start, stop int64) ([]repository.MetadataWithCoordinates, error) {
+		rows, err := query(repo, ctx, start, stop)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]repository.MetadataWithCoordinates, len(rows))
+		for i := range rows {
+			result[i] = rows[i]
+		}
+		return result, nil
+	}
+}
+
+func toMetadata[T repository.MetadataWithCoordinates](convert func(T) repository.Metadata) func(repository.MetadataWithCoordinates) repository.Metadata {
+	return func(row repository.MetadataWithCoordinates) repository.Metadata {
+		return convert(row.(T))
+	}
+}
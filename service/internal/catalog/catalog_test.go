@@ -0,0 +1,94 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package catalog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	require.Equal(t, []string{"allwise", "vlass", "ztf", "gaia", "erosita"}, catalog.Names())
+
+	desc, ok := catalog.Lookup("AllWISE")
+	require.True(t, ok)
+	require.Equal(t, "allwise", desc.Name)
+	require.True(t, desc.HasMetadata())
+
+	desc, ok = catalog.Lookup("vlass")
+	require.True(t, ok)
+	require.False(t, desc.HasMetadata())
+
+	_, ok = catalog.Lookup("GAIA/DR3")
+	require.False(t, ok, "display names are not catalog names")
+	desc, ok = catalog.LookupDisplayName("GAIA/DR3")
+	require.True(t, ok)
+	require.Equal(t, "gaia", desc.Name)
+
+	desc, ok = catalog.LookupLightcurveSource("neowise")
+	require.True(t, ok)
+	require.Equal(t, "allwise", desc.Name)
+
+	desc, ok = catalog.Lookup("xwave")
+	require.True(t, ok)
+	require.True(t, desc.Detections)
+	require.False(t, desc.HasMetadata())
+
+	_, ok = catalog.Lookup("unknown")
+	require.False(t, ok)
+}
+
+func TestRegister_Duplicate(t *testing.T) {
+	require.Panics(t, func() { catalog.Register(catalog.CatalogDescriptor{Name: "AllWISE"}) })
+	require.Panics(t, func() { catalog.Register(catalog.CatalogDescriptor{}) })
+}
+
+func TestCatalogDescriptor_NewInputSchema(t *testing.T) {
+	desc, _ := catalog.Lookup("gaia")
+	schema := desc.NewInputSchema()
+	require.IsType(t, &repository.GaiaInputSchema{}, schema)
+}
+
+func TestCatalogDescriptor_Queries(t *testing.T) {
+	repo := conesearch.NewMockRepository(t)
+	repo.On("GetAllwise", mock.Anything, "allwise1").Return(repository.GetAllwiseRow{ID: "allwise1"}, nil)
+	repo.On("BulkGetAllwise", mock.Anything, []string{"allwise1", "allwise2"}).
+		Return([]repository.BulkGetAllwiseRow{{ID: "allwise1"}, {ID: "allwise2"}}, nil)
+	repo.On("GetAllwiseFromPixels", mock.Anything, []int64{1}).
+		Return([]repository.GetAllwiseFromPixelsRow{{ID: "allwise1", Ra: 1, Dec: 2}}, nil)
+
+	desc, _ := catalog.Lookup("allwise")
+	ctx := context.Background()
+
+	result, err := desc.Get(ctx, repo, "allwise1")
+	require.NoError(t, err)
+	require.Equal(t, repository.GetAllwiseRow{ID: "allwise1"}, result)
+
+	rows, err := desc.BulkGetRows(ctx, repo, []string{"allwise1", "allwise2"})
+	require.NoError(t, err)
+	require.Equal(t, []any{repository.BulkGetAllwiseRow{ID: "allwise1"}, repository.BulkGetAllwiseRow{ID: "allwise2"}}, rows)
+
+	objects, err := desc.FromPixels(ctx, repo, []int64{1})
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	require.Equal(t, repository.Allwise{ID: "allwise1"}, desc.ToMetadata(objects[0]))
+}
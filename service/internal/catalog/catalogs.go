@@ -0,0 +1,303 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package catalog
+
+import (
+	"context"
+	"slices"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+)
+
+func init() {
+	Register(CatalogDescriptor{
+		Name:                     "allwise",
+		DisplayName:              "AllWISE",
+		InputSchema:              repository.AllwiseInputSchema{},
+		NewParquetReader:         parquetReader[repository.AllwiseInputSchema](),
+		NewMetadataParquetWriter: parquetWriter[repository.Allwise](),
+		BulkInsert:               Repository.BulkInsertAllwise,
+		Get:                      get(Repository.GetAllwise),
+		BulkGet:                  bulkGet(Repository.BulkGetAllwise),
+		FromPixels:               fromPixels(Repository.GetAllwiseFromPixels),
+		BetweenPixels:            betweenPixels(allwiseBetweenPixels),
+		ToMetadata:               toMetadata(allwiseFromPixelsRow),
+		Metadata:                 repository.Allwise{},
+		Columns: slices.Concat(
+			[]Column{{Name: "cntr", Description: "Unique identification number of the source", Datatype: "long", Ucd: "meta.id"}},
+			magnitudeColumns("W1", "w1mpro", "w1sigmpro", "em.IR.3-4um"),
+			magnitudeColumns("W2", "w2mpro", "w2sigmpro", "em.IR.4-8um"),
+			magnitudeColumns("W3", "w3mpro", "w3sigmpro", "em.IR.8-15um"),
+			magnitudeColumns("W4", "w4mpro", "w4sigmpro", "em.IR.15-30um"),
+			magnitudeColumns("2MASS J", "j_m_2mass", "j_msig_2mass", "em.IR.J"),
+			magnitudeColumns("2MASS H", "h_m_2mass", "h_msig_2mass", "em.IR.H"),
+			magnitudeColumns("2MASS K", "k_m_2mass", "k_msig_2mass", "em.IR.K"),
+		),
+		LightcurveSource: "neowise",
+	})
+	// vlass and ztf objects are indexed in the mastercat, but their metadata is not stored yet
+	Register(CatalogDescriptor{Name: "vlass"})
+	Register(CatalogDescriptor{Name: "ztf"})
+	Register(CatalogDescriptor{
+		Name:                     "gaia",
+		DisplayName:              "GAIA/DR3",
+		InputSchema:              repository.GaiaInputSchema{},
+		NewParquetReader:         parquetReader[repository.GaiaInputSchema](),
+		NewMetadataParquetWriter: parquetWriter[repository.Gaia](),
+		BulkInsert:               Repository.BulkInsertGaia,
+		Get:                      get(Repository.GetGaia),
+		BulkGet:                  bulkGet(Repository.BulkGetGaia),
+		FromPixels:               fromPixels(Repository.GetGaiaFromPixels),
+		BetweenPixels:            betweenPixels(gaiaBetweenPixels),
+		ToMetadata:               toMetadata(gaiaFromPixelsRow),
+		Metadata:                 repository.Gaia{},
+		Columns: slices.Concat(
+			gaiaBandColumns("G", "phot_g_mean", "em.opt"),
+			gaiaBandColumns("BP", "phot_bp_mean", "em.opt.B"),
+			gaiaBandColumns("RP", "phot_rp_mean", "em.opt.R"),
+		),
+	})
+	Register(CatalogDescriptor{
+		Name:                     "erosita",
+		DisplayName:              "eROSITA",
+		InputSchema:              repository.ErositaInputSchema{},
+		NewParquetReader:         parquetReader[repository.ErositaInputSchema](),
+		NewMetadataParquetWriter: parquetWriter[repository.Erosita](),
+		BulkInsert:               Repository.BulkInsertErosita,
+		Get:                      get(Repository.GetErosita),
+		BulkGet:                  bulkGet(Repository.BulkGetErosita),
+		FromPixels:               fromPixels(Repository.GetErositaFromPixels),
+		BetweenPixels:            betweenPixels(erositaBetweenPixels),
+		ToMetadata:               toMetadata(erositaFromPixelsRow),
+		Metadata:                 repository.Erosita{},
+		Columns: []Column{
+			{Name: "detuid", Description: "Unique detection id", Datatype: "char", Ucd: "meta.id"},
+			{Name: "mjd", Description: "Epoch of the observations", Datatype: "double", Unit: "d", Ucd: "time.epoch"},
+			{Name: "ext", Description: "Source extent", Datatype: "double", Unit: "arcsec", Ucd: "phys.angSize"},
+			{Name: "det_like_0", Description: "Detection likelihood", Datatype: "double", Ucd: "stat.likelihood"},
+			{Name: "ml_flux_1", Description: "Flux in the 0.2-2.3 keV band", Datatype: "double", Unit: "erg/cm2/s", Ucd: "phot.flux;em.X-ray"},
+			{Name: "ml_flux_err_1", Description: "Flux error in the 0.2-2.3 keV band", Datatype: "double", Unit: "erg/cm2/s", Ucd: "stat.error;phot.flux;em.X-ray"},
+		},
+	})
+	// x-wave detections are our own photometry of the objects of the other catalogs
+	Register(CatalogDescriptor{
+		Name:                     "xwave",
+		DisplayName:              "x-wave",
+		InputSchema:              repository.XwaveInputSchema{},
+		NewParquetReader:         parquetReader[repository.XwaveInputSchema](),
+		NewMetadataParquetWriter: parquetWriter[repository.XwaveDetection](),
+		BulkInsert:               Repository.BulkInsertXwaveDetection,
+		Detections:               true,
+	})
+}
+
+func magnitudeColumns(band, column, errColumn, ucd string) []Column {
+	return []Column{
+		{Name: column, Description: band + " magnitude", Datatype: "double", Unit: "mag", Ucd: "phot.mag;" + ucd},
+		{Name: errColumn, Description: band + " magnitude error", Datatype: "double", Unit: "mag", Ucd: "stat.error;phot.mag;" + ucd},
+	}
+}
+
+func gaiaBandColumns(band, column, ucd string) []Column {
+	return []Column{
+		{Name: column + "_flux", Description: band + " mean flux", Datatype: "double", Unit: "e-/s", Ucd: "phot.flux;" + ucd},
+		{Name: column + "_flux_error", Description: band + " mean flux error", Datatype: "double", Unit: "e-/s", Ucd: "stat.error;phot.flux;" + ucd},
+		{Name: column + "_mag", Description: band + " mean magnitude", Datatype: "double", Unit: "mag", Ucd: "phot.mag;" + ucd},
+	}
+}
+
+// allwiseBetweenPixels returns the rows of a range of pixels as the rows of FromPixels, which ToMetadata converts
+func allwiseBetweenPixels(repo Repository, ctx context.Context, start, stop int64) ([]repository.GetAllwiseFromPixelsRow, error) {
+	rows, err := repo.GetAllwiseBetweenPixels(ctx, repository.GetAllwiseBetweenPixelsParams{Start: start, Stop: stop})
+	result := make([]repository.GetAllwiseFromPixelsRow, len(rows))
+	for i := range rows {
+		result[i] = repository.GetAllwiseFromPixelsRow(rows[i])
+	}
+	return result, err
+}
+
+// gaiaBetweenPixels returns the rows of a range of pixels as the rows of FromPixels, which ToMetadata converts
+func gaiaBetweenPixels(repo Repository, ctx context.Context, start, stop int64) ([]repository.GetGaiaFromPixelsRow, error) {
+	rows, err := repo.GetGaiaBetweenPixels(ctx, repository.GetGaiaBetweenPixelsParams{Start: start, Stop: stop})
+	result := make([]repository.GetGaiaFromPixelsRow, len(rows))
+	for i := range rows {
+		result[i] = repository.GetGaiaFromPixelsRow(rows[i])
+	}
+	return result, err
+}
+
+// erositaBetweenPixels returns the rows of a range of pixels as the rows of FromPixels, which ToMetadata converts
+func erositaBetweenPixels(repo Repository, ctx context.Context, start, stop int64) ([]repository.GetErositaFromPixelsRow, error) {
+	rows, err := repo.GetErositaBetweenPixels(ctx, repository.GetErositaBetweenPixelsParams{Start: start, Stop: stop})
+	result := make([]repository.GetErositaFromPixelsRow, len(rows))
+	for i := range rows {
+		result[i] = repository.GetErositaFromPixelsRow(rows[i])
+	}
+	return result, err
+}
+
+func allwiseFromPixelsRow(obj repository.GetAllwiseFromPixelsRow) repository.Metadata {
+	return repository.Allwise{
+		ID:         obj.ID,
+		Cntr:       obj.Cntr,
+		W1mpro:     obj.W1mpro,
+		W1sigmpro:  obj.W1sigmpro,
+		W2mpro:     obj.W2mpro,
+		W2sigmpro:  obj.W2sigmpro,
+		W3mpro:     obj.W3mpro,
+		W3sigmpro:  obj.W3sigmpro,
+		W4mpro:     obj.W4mpro,
+		W4sigmpro:  obj.W4sigmpro,
+		JM2mass:    obj.JM2mass,
+		JMsig2mass: obj.JMsig2mass,
+		HM2mass:    obj.HM2mass,
+		HMsig2mass: obj.HMsig2mass,
+		KM2mass:    obj.KM2mass,
+		KMsig2mass: obj.KMsig2mass,
+	}
+}
+
+func gaiaFromPixelsRow(obj repository.GetGaiaFromPixelsRow) repository.Metadata {
+	return repository.Gaia{
+		ID:                  obj.ID,
+		PhotGMeanFlux:       obj.PhotGMeanFlux,
+		PhotGMeanFluxError:  obj.PhotGMeanFluxError,
+		PhotGMeanMag:        obj.PhotGMeanMag,
+		PhotBpMeanFlux:      obj.PhotBpMeanFlux,
+		PhotBpMeanFluxError: obj.PhotBpMeanFluxError,
+		PhotBpMeanMag:       obj.PhotBpMeanMag,
+		PhotRpMeanFlux:      obj.PhotRpMeanFlux,
+		PhotRpMeanFluxError: obj.PhotRpMeanFluxError,
+		PhotRpMeanMag:       obj.PhotRpMeanMag,
+	}
+}
+
+func erositaFromPixelsRow(obj repository.GetErositaFromPixelsRow) repository.Metadata {
+	return repository.Erosita{
+		ID:             obj.ID,
+		Detuid:         obj.Detuid,
+		Skytile:        obj.Skytile,
+		IDSrc:          obj.IDSrc,
+		Uid:            obj.Uid,
+		UidHard:        obj.UidHard,
+		IDCluster:      obj.IDCluster,
+		Ra:             obj.Ra,
+		Dec:            obj.Dec,
+		RaLowerr:       obj.RaLowerr,
+		RaUperr:        obj.RaUperr,
+		DecLowerr:      obj.DecLowerr,
+		DecUperr:       obj.DecUperr,
+		PosErr:         obj.PosErr,
+		Mjd:            obj.Mjd,
+		MjdMin:         obj.MjdMin,
+		MjdMax:         obj.MjdMax,
+		Ext:            obj.Ext,
+		ExtErr:         obj.ExtErr,
+		ExtLike:        obj.ExtLike,
+		DetLike0:       obj.DetLike0,
+		MlCts1:         obj.MlCts1,
+		MlCtsErr1:      obj.MlCtsErr1,
+		MlRate1:        obj.MlRate1,
+		MlRateErr1:     obj.MlRateErr1,
+		MlFlux1:        obj.MlFlux1,
+		MlFluxErr1:     obj.MlFluxErr1,
+		MlBkg1:         obj.MlBkg1,
+		MlExp1:         obj.MlExp1,
+		ApeBkg1:        obj.ApeBkg1,
+		ApeRadius1:     obj.ApeRadius1,
+		ApePois1:       obj.ApePois1,
+		DetLikeP1:      obj.DetLikeP1,
+		MlCtsP1:        obj.MlCtsP1,
+		MlCtsErrP1:     obj.MlCtsErrP1,
+		MlRateP1:       obj.MlRateP1,
+		MlRateErrP1:    obj.MlRateErrP1,
+		MlFluxP1:       obj.MlFluxP1,
+		MlFluxErrP1:    obj.MlFluxErrP1,
+		MlBkgP1:        obj.MlBkgP1,
+		MlExpP1:        obj.MlExpP1,
+		ApeBkgP1:       obj.ApeBkgP1,
+		ApeRadiusP1:    obj.ApeRadiusP1,
+		ApePoisP1:      obj.ApePoisP1,
+		DetLikeP2:      obj.DetLikeP2,
+		MlCtsP2:        obj.MlCtsP2,
+		MlCtsErrP2:     obj.MlCtsErrP2,
+		MlRateP2:       obj.MlRateP2,
+		MlRateErrP2:    obj.MlRateErrP2,
+		MlFluxP2:       obj.MlFluxP2,
+		MlFluxErrP2:    obj.MlFluxErrP2,
+		MlBkgP2:        obj.MlBkgP2,
+		MlExpP2:        obj.MlExpP2,
+		ApeBkgP2:       obj.ApeBkgP2,
+		ApeRadiusP2:    obj.ApeRadiusP2,
+		ApePoisP2:      obj.ApePoisP2,
+		DetLikeP3:      obj.DetLikeP3,
+		MlCtsP3:        obj.MlCtsP3,
+		MlCtsErrP3:     obj.MlCtsErrP3,
+		MlRateP3:       obj.MlRateP3,
+		MlRateErrP3:    obj.MlRateErrP3,
+		MlFluxP3:       obj.MlFluxP3,
+		MlFluxErrP3:    obj.MlFluxErrP3,
+		MlBkgP3:        obj.MlBkgP3,
+		MlExpP3:        obj.MlExpP3,
+		ApeBkgP3:       obj.ApeBkgP3,
+		ApeRadiusP3:    obj.ApeRadiusP3,
+		ApePoisP3:      obj.ApePoisP3,
+		DetLikeP4:      obj.DetLikeP4,
+		MlCtsP4:        obj.MlCtsP4,
+		MlCtsErrP4:     obj.MlCtsErrP4,
+		MlRateP4:       obj.MlRateP4,
+		MlRateErrP4:    obj.MlRateErrP4,
+		MlFluxP4:       obj.MlFluxP4,
+		MlFluxErrP4:    obj.MlFluxErrP4,
+		MlBkgP4:        obj.MlBkgP4,
+		MlExpP4:        obj.MlExpP4,
+		ApeBkgP4:       obj.ApeBkgP4,
+		ApeRadiusP4:    obj.ApeRadiusP4,
+		ApePoisP4:      obj.ApePoisP4,
+		DetLikeP5:      obj.DetLikeP5,
+		MlCtsP5:        obj.MlCtsP5,
+		MlCtsErrP5:     obj.MlCtsErrP5,
+		MlRateP5:       obj.MlRateP5,
+		MlRateErrP5:    obj.MlRateErrP5,
+		MlFluxP5:       obj.MlFluxP5,
+		MlFluxErrP5:    obj.MlFluxErrP5,
+		MlBkgP5:        obj.MlBkgP5,
+		MlExpP5:        obj.MlExpP5,
+		ApeBkgP5:       obj.ApeBkgP5,
+		ApeRadiusP5:    obj.ApeRadiusP5,
+		ApePoisP5:      obj.ApePoisP5,
+		DetLikeP6:      obj.DetLikeP6,
+		MlCtsP6:        obj.MlCtsP6,
+		MlCtsErrP6:     obj.MlCtsErrP6,
+		MlRateP6:       obj.MlRateP6,
+		MlRateErrP6:    obj.MlRateErrP6,
+		MlFluxP6:       obj.MlFluxP6,
+		MlFluxErrP6:    obj.MlFluxErrP6,
+		MlBkgP6:        obj.MlBkgP6,
+		MlExpP6:        obj.MlExpP6,
+		ApeBkgP6:       obj.ApeBkgP6,
+		ApeRadiusP6:    obj.ApeRadiusP6,
+		ApePoisP6:      obj.ApePoisP6,
+		FlagSpSnr:      obj.FlagSpSnr,
+		FlagSpBps:      obj.FlagSpBps,
+		FlagSpScl:      obj.FlagSpScl,
+		FlagSpLga:      obj.FlagSpLga,
+		FlagSpGcCons:   obj.FlagSpGcCons,
+		FlagNoRadecErr: obj.FlagNoRadecErr,
+		FlagNoExtErr:   obj.FlagNoExtErr,
+		FlagNoCtsErr:   obj.FlagNoCtsErr,
+		FlagOpt:        obj.FlagOpt,
+	}
+}
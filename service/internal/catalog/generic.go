@@ -0,0 +1,132 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/reader"
+	parquet_reader "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/reader/parquet"
+	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/source"
+	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/writer"
+	parquet_writer "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/writer/parquet"
+	"github.com/dirodriguezm/xmatch/service/internal/config"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+)
+
+// Generic describes a catalog without Go types, whose rows are read, stored
+// and searched through the columns of its schema
+func Generic(schema repository.GenericSchema) CatalogDescriptor {
+	return CatalogDescriptor{
+		Name:        strings.ToLower(schema.Catalog),
+		DisplayName: schema.Catalog,
+		Generic:     &schema,
+		NewParquetReader: func(src *source.Source, batchSize int) (reader.Reader, error) {
+			return parquet_reader.NewGenericParquetReader(src, schema, batchSize)
+		},
+		NewMetadataParquetWriter: func(cfg config.WriterConfig, ctx context.Context) (writer.Writer, error) {
+			return parquet_writer.NewGeneric(cfg, schema, ctx)
+		},
+		BulkInsert: func(repo Repository, ctx context.Context, db *sql.DB, rows []any) error {
+			return repo.BulkInsertGeneric(ctx, db, schema, rows)
+		},
+		Get: func(ctx context.Context, repo Repository, id string) (any, error) {
+			return repo.GetGeneric(ctx, schema, id)
+		},
+		BulkGet: func(ctx context.Context, repo Repository, ids []string) (any, error) {
+			return repo.BulkGetGeneric(ctx, schema, ids)
+		},
+		FromPixels: fromPixels(func(repo Repository, ctx context.Context, pixels []int64) ([]repository.GenericRow, error) {
+			return repo.GetGenericFromPixels(ctx, schema, pixels)
+		}),
+		BetweenPixels: betweenPixels(func(repo Repository, ctx context.Context, start, stop int64) ([]repository.GenericRow, error) {
+			return repo.GetGenericBetweenPixels(ctx, schema, start, stop)
+		}),
+		ToMetadata: func(row repository.MetadataWithCoordinates) repository.Metadata {
+			return row
+		},
+		Columns: genericColumns(schema),
+	}
+}
+
+func genericColumns(schema repository.GenericSchema) []Column {
+	datatypes := map[string]string{
+		repository.GenericString: "char",
+		repository.GenericDouble: "double",
+		repository.GenericLong:   "long",
+	}
+	columns := make([]Column, len(schema.Columns))
+	for i, column := range schema.Columns {
+		columns[i] = Column{Name: column.Name, Datatype: datatypes[column.Type]}
+	}
+	return columns
+}
+
+// RegisterGeneric adds a generic catalog to the registry. It replaces a catalog with
+// the same name if it is generic or only available in the mastercat, like vlass.
+func RegisterGeneric(schema repository.GenericSchema) error {
+	if err := schema.Validate(); err != nil {
+		return err
+	}
+	for i, d := range registry {
+		if !strings.EqualFold(d.Name, schema.Catalog) {
+			continue
+		}
+		if d.Generic == nil && (d.HasMetadata() || d.InputSchema != nil) {
+			return fmt.Errorf("catalog %s is already registered and can't be generic", d.Name)
+		}
+		registry[i] = Generic(schema)
+		return nil
+	}
+	registry = append(registry, Generic(schema))
+	return nil
+}
+
+// RegisterGenericCatalogs registers the catalogs of the database without metadata in
+// the service as generic catalogs, described by the columns of their metadata tables.
+//
+// Catalogs without a metadata table, or named after a table of the service, are only
+// available in the mastercat.
+func RegisterGenericCatalogs(ctx context.Context, repo Repository, catalogs []repository.Catalog) error {
+	for _, c := range catalogs {
+		desc, known := Lookup(c.Name)
+		if known && (desc.HasMetadata() || desc.InputSchema != nil) {
+			continue
+		}
+
+		var schema repository.GenericSchema
+		err := repository.GenericSchema{Catalog: c.Name}.Validate()
+		if err == nil {
+			schema, err = repo.GetGenericSchema(ctx, c.Name)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) && !errors.Is(err, repository.ErrReservedTable) {
+				return fmt.Errorf("could not describe catalog %s: %w", c.Name, err)
+			}
+		}
+		if err != nil {
+			if !known {
+				registry = append(registry, CatalogDescriptor{Name: strings.ToLower(c.Name)})
+			}
+			continue
+		}
+		if err := RegisterGeneric(schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package catalog_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch/test_helpers"
+	"github.com/dirodriguezm/xmatch/service/internal/testutils"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+func genericTestDB(t *testing.T) (*sql.DB, *repository.Queries) {
+	t.Helper()
+	rootPath, err := testutils.FindRootModulePath(5)
+	require.NoError(t, err)
+
+	dbFile := filepath.Join(t.TempDir(), "test.db")
+	require.NoError(t, test_helpers.Migrate(dbFile, rootPath))
+
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s", dbFile))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db, repository.New(db)
+}
+
+func genericTestSchema(name string) repository.GenericSchema {
+	return repository.GenericSchema{
+		Catalog:   name,
+		IdColumn:  "source_id",
+		RaColumn:  "RA",
+		DecColumn: "DEC",
+		Columns: []repository.GenericColumn{
+			{Name: "flux", Type: repository.GenericDouble},
+			{Name: "nobs", Type: repository.GenericLong},
+			{Name: "class", Type: repository.GenericString},
+		},
+	}
+}
+
+func TestGeneric_Queries(t *testing.T) {
+	ctx := context.Background()
+	db, repo := genericTestDB(t)
+
+	schema := genericTestSchema("survey")
+	require.NoError(t, catalog.RegisterGeneric(schema))
+	desc, ok := catalog.Lookup("Survey")
+	require.True(t, ok)
+	require.True(t, desc.HasMetadata())
+	require.Equal(t, []catalog.Column{
+		{Name: "flux", Datatype: "double"},
+		{Name: "nobs", Datatype: "long"},
+		{Name: "class", Datatype: "char"},
+	}, desc.Columns, "the columns of the schema are published with ADQL")
+
+	rows := make([]any, 3)
+	for i := range rows {
+		row, err := schema.NewRow(func(column string) (any, bool) {
+			values := map[string]any{
+				"source_id": fmt.Sprintf("s-%d", i),
+				"RA":        float64(i),
+				"DEC":       float64(i),
+				"flux":      fmt.Sprintf("%d.5", i),
+				"nobs":      int64(i),
+				"class":     nil,
+			}
+			v, ok := values[column]
+			return v, ok
+		})
+		require.NoError(t, err)
+		require.NoError(t, repo.InsertObject(ctx, repository.InsertObjectParams{
+			ID: row.ID, Ra: row.Ra, Dec: row.Dec, Ipix: int64(i), Cat: "survey",
+		}))
+		rows[i] = row.FillMetadata()
+	}
+	require.NoError(t, desc.BulkInsert(repo, ctx, db, rows))
+
+	result, err := desc.Get(ctx, repo, "s-1")
+	require.NoError(t, err)
+	row := result.(repository.GenericRow)
+	require.Equal(t, "s-1", row.GetId())
+	require.Equal(t, []any{1.5, int64(1), nil}, row.Values)
+
+	result, err = desc.BulkGet(ctx, repo, []string{"s-0", "s-2"})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+
+	found, err := desc.FromPixels(ctx, repo, []int64{1, 2})
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	ra, dec := found[0].GetCoordinates()
+	require.Equal(t, 1.0, ra)
+	require.Equal(t, 1.0, dec)
+	b, err := json.Marshal(desc.ToMetadata(found[0]))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"s-1","ra":1,"dec":1,"flux":1.5,"nobs":1,"class":null}`, string(b))
+}
+
+func TestRegisterGenericCatalogs(t *testing.T) {
+	ctx := context.Background()
+	db, repo := genericTestDB(t)
+
+	schema := genericTestSchema("described")
+	row, err := schema.NewRow(func(column string) (any, bool) {
+		return map[string]any{"source_id": "d-0", "RA": 1.0, "DEC": 2.0}[column], true
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.BulkInsertGeneric(ctx, db, schema, []any{row}))
+
+	catalogs := []repository.Catalog{{Name: "allwise"}, {Name: "described"}, {Name: "positions"}, {Name: "jobs"}}
+	require.NoError(t, catalog.RegisterGenericCatalogs(ctx, repo, catalogs))
+
+	desc, ok := catalog.Lookup("described")
+	require.True(t, ok)
+	require.NotNil(t, desc.Generic)
+	require.Equal(t, schema.Columns, desc.Generic.Columns)
+
+	desc, ok = catalog.Lookup("positions")
+	require.True(t, ok, "catalogs without a metadata table are only in the mastercat")
+	require.False(t, desc.HasMetadata())
+
+	desc, ok = catalog.Lookup("jobs")
+	require.True(t, ok, "catalogs named after a table of the service are only in the mastercat")
+	require.False(t, desc.HasMetadata())
+
+	desc, ok = catalog.Lookup("allwise")
+	require.True(t, ok)
+	require.Nil(t, desc.Generic)
+}
+
+func TestBulkInsertGeneric_ReservedTables(t *testing.T) {
+	ctx := context.Background()
+	db, repo := genericTestDB(t)
+
+	for _, name := range []string{"jobs", "xwave_detection", "Allwise"} {
+		err := repo.BulkInsertGeneric(ctx, db, genericTestSchema(name), []any{})
+		require.ErrorIs(t, err, repository.ErrReservedTable, name)
+	}
+
+	require.NoError(t, repo.BulkInsertGeneric(ctx, db, genericTestSchema("survey"), []any{}))
+	require.NoError(t, repo.BulkInsertGeneric(ctx, db, genericTestSchema("Survey"), []any{}), "generic tables can be written again")
+}
+
+func TestRegisterGeneric_Errors(t *testing.T) {
+	require.ErrorContains(t, catalog.RegisterGeneric(genericTestSchema("allwise")), "can't be generic")
+	require.Error(t, catalog.RegisterGeneric(genericTestSchema("mastercat")))
+	require.Error(t, catalog.RegisterGeneric(genericTestSchema("drop table")))
+
+	schema := genericTestSchema("invalid")
+	schema.Columns = append(schema.Columns, repository.GenericColumn{Name: "flux", Type: repository.GenericDouble})
+	require.Error(t, catalog.RegisterGeneric(schema))
+}
@@ -22,6 +22,7 @@ import (
 	"slices"
 	"strconv"
 
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
 	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/source"
 	"github.com/dirodriguezm/xmatch/service/internal/repository"
 )
@@ -35,6 +36,7 @@ type CsvReader struct {
 	currentReader     *csv.Reader
 	src               *source.Source
 	batchSize         int
+	schema            *repository.GenericSchema
 	opts              []CsvReaderOption
 }
 
@@ -58,6 +60,19 @@ func NewCsvReader(src *source.Source, opts ...CsvReaderOption) (*CsvReader, erro
 	return r, nil
 }
 
+// ReadHeader returns the header of the current file, reading it from the first line
+// if it was not given or read already
+func (r *CsvReader) ReadHeader() ([]string, error) {
+	if r.Header == nil {
+		header, err := r.currentReader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("could not read header from csv: %w", err)
+		}
+		r.Header = header
+	}
+	return r.Header, nil
+}
+
 func (r *CsvReader) ReadSingleFile(currentReader *csv.Reader, catalogName string) ([]repository.InputSchema, error) {
 	rows := make([]repository.InputSchema, 0)
 
@@ -78,7 +93,10 @@ func (r *CsvReader) ReadSingleFile(currentReader *csv.Reader, catalogName string
 
 	// Transform data into the correct schema
 	for _, record := range records {
-		row := r.createInputSchema(catalogName, record)
+		row, err := r.createInputSchema(catalogName, record)
+		if err != nil {
+			return nil, err
+		}
 		rows = append(rows, row)
 	}
 
@@ -131,7 +149,10 @@ func (r *CsvReader) ReadBatchSingleFile(currentReader *csv.Reader, batchSize int
 			return nil, err
 		}
 
-		row := r.createInputSchema(catalogName, record)
+		row, err := r.createInputSchema(catalogName, record)
+		if err != nil {
+			return nil, err
+		}
 		rows = append(rows, row)
 	}
 
@@ -184,30 +205,48 @@ func (r *CsvReader) ReadBatch() ([]repository.InputSchema, error) {
 	return rows, nil
 }
 
-func (r *CsvReader) createInputSchema(catalogName string, record []string) repository.InputSchema {
-	switch catalogName {
-	case "allwise":
-		schema := repository.AllwiseInputSchema{}
-		err := fillStructFromStrings(&schema, record)
-		if err != nil {
-			panic(err)
+// createInputSchema converts a record to the input schema of the catalog.
+// A generic schema given with WithGenericSchema takes precedence over the registered catalogs.
+func (r *CsvReader) createInputSchema(catalogName string, record []string) (repository.InputSchema, error) {
+	if r.schema != nil {
+		return r.createGenericRow(*r.schema, record)
+	}
+
+	desc, ok := catalog.Lookup(catalogName)
+	if !ok || (desc.InputSchema == nil && desc.Generic == nil) {
+		schema := TestSchema{}
+		if err := fillStructFromStrings(&schema, record); err != nil {
+			return nil, err
 		}
-		return schema
-	case "gaia":
-		schema := repository.GaiaInputSchema{}
-		err := fillStructFromStrings(&schema, record)
-		if err != nil {
-			panic(err)
+		return schema, nil
+	}
+
+	if desc.Generic != nil {
+		return r.createGenericRow(*desc.Generic, record)
+	}
+
+	schema := desc.NewInputSchema()
+	if err := fillStructFromStrings(schema, record); err != nil {
+		return nil, err
+	}
+	return reflect.ValueOf(schema).Elem().Interface().(repository.InputSchema), nil
+}
+
+func (r *CsvReader) createGenericRow(schema repository.GenericSchema, record []string) (repository.InputSchema, error) {
+	row, err := schema.NewRow(func(column string) (any, bool) {
+		i := slices.Index(r.Header, column)
+		if i < 0 || i >= len(record) {
+			return nil, false
 		}
-		return schema
-	default:
-		schema := TestSchema{}
-		err := fillStructFromStrings(&schema, record)
-		if err != nil {
-			panic(err)
+		if slices.Contains(nullValues, record[i]) {
+			return nil, true
 		}
-		return schema
+		return record[i], true
+	})
+	if err != nil {
+		return nil, err
 	}
+	return row, nil
 }
 
 func fillStructFromStrings(s any, values []string) error {
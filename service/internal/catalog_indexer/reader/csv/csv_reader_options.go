@@ -14,6 +14,8 @@
 
 package csv_reader
 
+import "github.com/dirodriguezm/xmatch/service/internal/repository"
+
 type CsvReaderOption func(r *CsvReader)
 
 func WithHeader(header []string) CsvReaderOption {
@@ -45,3 +47,11 @@ func WithComment(comment string) CsvReaderOption {
 		}
 	}
 }
+
+// WithGenericSchema reads every record with the given schema,
+// instead of the schema registered for the catalog of the source
+func WithGenericSchema(schema repository.GenericSchema) CsvReaderOption {
+	return func(r *CsvReader) {
+		r.schema = &schema
+	}
+}
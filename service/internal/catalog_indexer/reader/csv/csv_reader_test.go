@@ -18,6 +18,7 @@ import (
 	"io"
 	"testing"
 
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
 	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/source"
 	"github.com/dirodriguezm/xmatch/service/internal/config"
 	"github.com/dirodriguezm/xmatch/service/internal/repository"
@@ -261,3 +262,77 @@ o3,3,3
 
 	require.Equal(t, expectedOids, receivedOids)
 }
+
+func TestReadGeneric(t *testing.T) {
+	csv := `name,ra_deg,dec_deg,mag,kind
+g1,1.5,-1.5,12.25,star
+g2,2.5,-2.5,,galaxy
+`
+	err := catalog.RegisterGeneric(repository.GenericSchema{
+		Catalog:   "generic_csv",
+		IdColumn:  "name",
+		RaColumn:  "ra_deg",
+		DecColumn: "dec_deg",
+		Columns: []repository.GenericColumn{
+			{Name: "mag", Type: repository.GenericDouble},
+			{Name: "kind", Type: repository.GenericString},
+		},
+	})
+	require.NoError(t, err)
+
+	source, err := source.NewSource(config.SourceConfig{
+		Url:         "buffer:" + csv,
+		Type:        "csv",
+		CatalogName: "generic_csv",
+		Nside:       18,
+		Metadata:    true,
+	})
+	require.NoError(t, err)
+
+	csvReader, err := NewCsvReader(source, WithCsvBatchSize(2))
+	require.NoError(t, err)
+
+	rows, err := csvReader.Read()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	row := rows[1].(repository.GenericRow)
+	require.Equal(t, "g2", row.GetId())
+	ra, dec := row.GetCoordinates()
+	require.Equal(t, 2.5, ra)
+	require.Equal(t, -2.5, dec)
+	require.Equal(t, []any{nil, "galaxy"}, row.Values)
+	require.Equal(t, []any{12.25, "star"}, rows[0].(repository.GenericRow).Values)
+}
+
+func TestReadWithGenericSchema(t *testing.T) {
+	csv := `name,ra_deg,dec_deg,kind
+g1,1.5,-1.5,star
+g2,2.5,,galaxy
+`
+	source, err := source.NewSource(config.SourceConfig{
+		Url:         "buffer:" + csv,
+		Type:        "csv",
+		CatalogName: "not_registered",
+		Nside:       18,
+	})
+	require.NoError(t, err)
+
+	schema := repository.GenericSchema{
+		Catalog:   "not_registered",
+		IdColumn:  "name",
+		RaColumn:  "ra_deg",
+		DecColumn: "dec_deg",
+		Columns:   []repository.GenericColumn{{Name: "kind", Type: repository.GenericString}},
+	}
+	csvReader, err := NewCsvReader(source, WithGenericSchema(schema))
+	require.NoError(t, err)
+
+	header, err := csvReader.ReadHeader()
+	require.NoError(t, err)
+	require.Equal(t, []string{"name", "ra_deg", "dec_deg", "kind"}, header)
+
+	// the second row has no declination
+	_, err = csvReader.Read()
+	require.ErrorContains(t, err, "coordinates can't be null")
+}
@@ -18,13 +18,12 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
 	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/reader"
 	csv_reader "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/reader/csv"
 	fits_reader "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/reader/fits"
-	parquet_reader "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/reader/parquet"
 	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/source"
 	"github.com/dirodriguezm/xmatch/service/internal/config"
-	"github.com/dirodriguezm/xmatch/service/internal/repository"
 )
 
 func ReaderFactory(
@@ -54,23 +53,17 @@ func ReaderFactory(
 }
 
 func parquetFactory(src *source.Source, cfg config.ReaderConfig) (reader.Reader, error) {
-	switch strings.ToLower(src.CatalogName) {
-	case "allwise":
-		return parquet_reader.NewParquetReader(
-			src,
-			parquet_reader.WithParquetBatchSize[repository.AllwiseInputSchema](cfg.BatchSize),
-		)
-	case "gaia":
-		return parquet_reader.NewParquetReader(
-			src,
-			parquet_reader.WithParquetBatchSize[repository.GaiaInputSchema](cfg.BatchSize),
-		)
-	default:
+	desc, ok := catalog.Lookup(src.CatalogName)
+	if !ok || desc.NewParquetReader == nil {
 		return nil, fmt.Errorf("Schema not found for catalog %s", src.CatalogName)
 	}
+	return desc.NewParquetReader(src, cfg.BatchSize)
 }
 
 func fitsFactory(src *source.Source, cfg config.ReaderConfig) (reader.Reader, error) {
+	if desc, ok := catalog.Lookup(src.CatalogName); ok && desc.Generic != nil {
+		return nil, fmt.Errorf("Generic catalogs can only be read from csv or parquet files")
+	}
 	fitsReader, err := fits_reader.NewFitsReader(src, fits_reader.WithBatchSize(cfg.BatchSize))
 	if err != nil {
 		return nil, err
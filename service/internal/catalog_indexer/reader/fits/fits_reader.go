@@ -18,8 +18,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
 
 	"codeberg.org/astrogo/fitsio"
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
 	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/source"
 	"github.com/dirodriguezm/xmatch/service/internal/repository"
 )
@@ -156,22 +158,8 @@ func (r *FitsReader) ReadBatchSingleFile(rowIterator *fitsio.Rows, size int, nam
 }
 
 func (r *FitsReader) createInputSchema(name string, rowIterator *fitsio.Rows) repository.InputSchema {
-	switch name {
-	case "allwise":
-		schema := repository.AllwiseInputSchema{}
-		err := rowIterator.Scan(&schema)
-		if err != nil {
-			panic(err)
-		}
-		return schema
-	case "erosita":
-		schema := repository.ErositaInputSchema{}
-		err := rowIterator.Scan(&schema)
-		if err != nil {
-			panic(err)
-		}
-		return schema
-	default:
+	desc, ok := catalog.Lookup(name)
+	if !ok || desc.InputSchema == nil {
 		schema := TestSchema{}
 		err := rowIterator.Scan(&schema)
 		if err != nil {
@@ -179,6 +167,13 @@ func (r *FitsReader) createInputSchema(name string, rowIterator *fitsio.Rows) re
 		}
 		return schema
 	}
+
+	schema := desc.NewInputSchema()
+	err := rowIterator.Scan(schema)
+	if err != nil {
+		panic(err)
+	}
+	return reflect.ValueOf(schema).Elem().Interface().(repository.InputSchema)
 }
 
 func (r *FitsReader) Close() error {
@@ -0,0 +1,216 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet_reader
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/source"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	preader "github.com/xitongsys/parquet-go/reader"
+	psource "github.com/xitongsys/parquet-go/source"
+)
+
+// GenericParquetReader reads the rows of a generic catalog, column by column,
+// so the files don't need a Go struct describing them
+type GenericParquetReader struct {
+	schema               repository.GenericSchema
+	currentParquetReader *preader.ParquetReader
+	currentFileReader    psource.ParquetFile
+	currentFileName      string
+	remaining            int64
+	src                  *source.Source
+	batchSize            int
+}
+
+func NewGenericParquetReader(src *source.Source, schema repository.GenericSchema, batchSize int) (*GenericParquetReader, error) {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	r := &GenericParquetReader{schema: schema, src: src, batchSize: batchSize}
+	if err := r.nextFile(); err != nil {
+		return nil, fmt.Errorf("could not get next source: %w", err)
+	}
+	return r, nil
+}
+
+func (r *GenericParquetReader) Read() ([]repository.InputSchema, error) {
+	rows := make([]repository.InputSchema, 0, r.remaining)
+	for {
+		batch, err := r.ReadBatch()
+		rows = append(rows, batch...)
+		if err != nil {
+			return rows, err
+		}
+	}
+}
+
+// ReadBatch reads up to batchSize rows of the current file.
+// Returns io.EOF with the last rows of the last file.
+func (r *GenericParquetReader) ReadBatch() ([]repository.InputSchema, error) {
+	rows, err := r.readRows(min(int64(r.batchSize), r.remaining))
+	if err != nil {
+		return nil, fmt.Errorf("Error while reading batch from current parquet reader: %s. %w", r.currentFileName, err)
+	}
+	if r.remaining > 0 {
+		return rows, nil
+	}
+
+	// the current file is done, so we move to the next one
+	if err := r.nextFile(); err != nil {
+		if err == io.EOF {
+			return rows, io.EOF
+		}
+		return rows, fmt.Errorf("Error getting the next file from the Source: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *GenericParquetReader) readRows(n int64) ([]repository.InputSchema, error) {
+	if n == 0 {
+		return []repository.InputSchema{}, nil
+	}
+
+	names := []string{r.schema.IdColumn, r.schema.RaColumn, r.schema.DecColumn}
+	for _, column := range r.schema.Columns {
+		names = append(names, column.Name)
+	}
+	root := r.currentParquetReader.SchemaHandler.GetRootExName()
+	columns := make(map[string][]any, len(names))
+	for _, name := range names {
+		if _, ok := columns[name]; ok {
+			continue
+		}
+		values, _, _, err := r.currentParquetReader.ReadColumnByPath(common.PathToStr([]string{root, name}), n)
+		if err != nil {
+			return nil, fmt.Errorf("column %s not found in source: %w", name, err)
+		}
+		if int64(len(values)) != n {
+			return nil, fmt.Errorf("column %s not found in source", name)
+		}
+		columns[name] = values
+	}
+	r.remaining -= n
+
+	rows := make([]repository.InputSchema, n)
+	for i := range rows {
+		row, err := r.schema.NewRow(func(column string) (any, bool) {
+			values, ok := columns[column]
+			if !ok {
+				return nil, false
+			}
+			return values[i], true
+		})
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// nextFile opens the next file of the source, closing the current one
+func (r *GenericParquetReader) nextFile() error {
+	nextReader, err := r.src.Next()
+	if err != nil {
+		return err
+	}
+	nextFileName := nextReader.(*os.File).Name()
+	nextReader.(*os.File).Close()
+
+	fr, err := local.NewLocalFileReader(nextFileName)
+	if err != nil {
+		return fmt.Errorf("Could not create NewLocalFileReader\n%w", err)
+	}
+	pr, err := preader.NewParquetColumnReader(fr, 1)
+	if err != nil {
+		fr.Close()
+		return fmt.Errorf("Could not create NewParquetColumnReader\n%w", err)
+	}
+
+	if err := r.Close(); err != nil {
+		return err
+	}
+	r.currentFileReader = fr
+	r.currentParquetReader = pr
+	r.currentFileName = nextFileName
+	r.remaining = pr.GetNumRows()
+	return nil
+}
+
+func (r *GenericParquetReader) Close() error {
+	if r.currentParquetReader == nil {
+		return nil
+	}
+	r.currentParquetReader.ReadStop()
+	return r.currentFileReader.Close()
+}
+
+// ParquetColumns lists the top level columns of a parquet file, with the generic type
+// their values are read as. Nested columns are skipped.
+func ParquetColumns(fileName string) ([]repository.GenericColumn, error) {
+	fr, err := local.NewLocalFileReader(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Could not create NewLocalFileReader\n%w", err)
+	}
+	defer fr.Close()
+	pr, err := preader.NewParquetColumnReader(fr, 1)
+	if err != nil {
+		return nil, fmt.Errorf("Could not create NewParquetColumnReader\n%w", err)
+	}
+	defer pr.ReadStop()
+
+	elements := pr.SchemaHandler.SchemaElements
+	infos := pr.SchemaHandler.Infos
+	columns := make([]repository.GenericColumn, 0, len(elements))
+	// the first element is the root of the schema
+	for i := 1; i < len(elements); {
+		if elements[i].GetNumChildren() > 0 {
+			i = skipSchemaElement(elements, i)
+			continue
+		}
+		// the names of the elements are converted to Go names, so the names of the file are taken from the infos
+		columns = append(columns, repository.GenericColumn{Name: infos[i].ExName, Type: genericType(elements[i].GetType())})
+		i++
+	}
+	return columns, nil
+}
+
+// skipSchemaElement returns the index of the element after i and all of its descendants
+func skipSchemaElement(elements []*parquet.SchemaElement, i int) int {
+	children := elements[i].GetNumChildren()
+	i++
+	for range children {
+		i = skipSchemaElement(elements, i)
+	}
+	return i
+}
+
+func genericType(t parquet.Type) string {
+	switch t {
+	case parquet.Type_FLOAT, parquet.Type_DOUBLE:
+		return repository.GenericDouble
+	case parquet.Type_INT32, parquet.Type_INT64:
+		return repository.GenericLong
+	default:
+		return repository.GenericString
+	}
+}
@@ -249,3 +249,88 @@ func TestReadParquet_read_batch_larger_than_rows(t *testing.T) {
 	require.Equal(t, 2, batches)
 	require.Equal(t, expectedOids, receivedOids)
 }
+
+type GenericWrite struct {
+	SourceId string   `parquet:"name=source_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	RaDeg    float64  `parquet:"name=ra_deg, type=DOUBLE"`
+	DecDeg   float64  `parquet:"name=dec_deg, type=DOUBLE"`
+	Mag      *float64 `parquet:"name=mag, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Nobs     int64    `parquet:"name=nobs, type=INT64"`
+	Kind     string   `parquet:"name=kind, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+func TestReadGenericParquet(t *testing.T) {
+	parquetFile := filepath.Join(t.TempDir(), "generic.parquet")
+	fw, err := local.NewLocalFileWriter(parquetFile)
+	require.NoError(t, err)
+	pw, err := writer.NewParquetWriter(fw, new(GenericWrite), 1)
+	require.NoError(t, err)
+	for i := range 5 {
+		obj := GenericWrite{SourceId: fmt.Sprintf("g%d", i), RaDeg: float64(i), DecDeg: float64(-i), Nobs: int64(i), Kind: "star"}
+		if i%2 == 0 {
+			mag := float64(i) + 0.5
+			obj.Mag = &mag
+		}
+		require.NoError(t, pw.Write(obj))
+	}
+	require.NoError(t, pw.WriteStop())
+	fw.Close()
+
+	schema := repository.GenericSchema{
+		Catalog:   "test",
+		IdColumn:  "source_id",
+		RaColumn:  "ra_deg",
+		DecColumn: "dec_deg",
+		Columns: []repository.GenericColumn{
+			{Name: "mag", Type: repository.GenericDouble},
+			{Name: "nobs", Type: repository.GenericLong},
+			{Name: "kind", Type: repository.GenericString},
+		},
+	}
+	src, err := source.NewSource(config.SourceConfig{
+		Url:         "file:" + parquetFile,
+		Type:        "parquet",
+		CatalogName: "test",
+		Nside:       18,
+	})
+	require.NoError(t, err)
+
+	parquetReader, err := NewGenericParquetReader(src, schema, 2)
+	require.NoError(t, err)
+
+	rows, err := parquetReader.ReadBatch()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	rows, err = parquetReader.Read()
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	require.Len(t, rows, 3)
+	row := rows[0].(repository.GenericRow)
+	require.Equal(t, "g2", row.GetId())
+	ra, dec := row.GetCoordinates()
+	require.Equal(t, 2.0, ra)
+	require.Equal(t, -2.0, dec)
+	require.Equal(t, []any{2.5, int64(2), "star"}, row.Values)
+	require.Equal(t, []any{nil, int64(3), "star"}, rows[1].(repository.GenericRow).Values)
+
+	columns, err := ParquetColumns(parquetFile)
+	require.NoError(t, err)
+	require.Equal(t, []repository.GenericColumn{
+		{Name: "source_id", Type: repository.GenericString},
+		{Name: "ra_deg", Type: repository.GenericDouble},
+		{Name: "dec_deg", Type: repository.GenericDouble},
+		{Name: "mag", Type: repository.GenericDouble},
+		{Name: "nobs", Type: repository.GenericLong},
+		{Name: "kind", Type: repository.GenericString},
+	}, columns)
+
+	schema.Columns = append(schema.Columns, repository.GenericColumn{Name: "missing", Type: repository.GenericDouble})
+	src, err = source.NewSource(config.SourceConfig{Url: "file:" + parquetFile, Type: "parquet", CatalogName: "test", Nside: 18})
+	require.NoError(t, err)
+	parquetReader, err = NewGenericParquetReader(src, schema, 2)
+	require.NoError(t, err)
+	_, err = parquetReader.ReadBatch()
+	require.ErrorContains(t, err, "column missing not found in source")
+}
@@ -0,0 +1,124 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet_writer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/dirodriguezm/xmatch/service/internal/actor"
+	"github.com/dirodriguezm/xmatch/service/internal/config"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	pwriter "github.com/xitongsys/parquet-go/writer"
+)
+
+// GenericParquetWriter writes the rows of a generic catalog, with a parquet
+// schema created from the columns of the catalog
+type GenericParquetWriter struct {
+	parquetWriter *pwriter.JSONWriter
+	pfile         *os.File
+}
+
+func NewGeneric(cfg config.WriterConfig, schema repository.GenericSchema, ctx context.Context) (*GenericParquetWriter, error) {
+	slog.Debug("Creating new GenericParquetWriter")
+
+	jsonSchema, err := genericJSONSchema(schema)
+	if err != nil {
+		return nil, fmt.Errorf("GenericParquetWriter could not create schema %w", err)
+	}
+
+	file, err := os.Create(cfg.OutputFile)
+	if err != nil {
+		return nil, fmt.Errorf("GenericParquetWriter could not create file %s\n%w", cfg.OutputFile, err)
+	}
+
+	parquetWriter, err := pwriter.NewJSONWriterFromWriter(jsonSchema, file, 1)
+	if err != nil {
+		return nil, fmt.Errorf("GenericParquetWriter could not create writer %w", err)
+	}
+
+	return &GenericParquetWriter{parquetWriter: parquetWriter, pfile: file}, nil
+}
+
+func (w *GenericParquetWriter) Write(a *actor.Actor, msg actor.Message) {
+	slog.Debug("GenericParquetWriter received message")
+	if msg.Error != nil {
+		slog.Error("GenericParquetWriter received error message")
+		panic(msg.Error)
+	}
+
+	for i := range msg.Rows {
+		row := msg.Rows[i].(repository.GenericRow)
+		if row.ID == "" {
+			continue // skip empty objects
+		}
+		obj, err := json.Marshal(row)
+		if err != nil {
+			panic(fmt.Errorf("GenericParquetWriter could not encode object %v\n%w", row, err))
+		}
+		if err := w.parquetWriter.Write(string(obj)); err != nil {
+			panic(fmt.Errorf("GenericParquetWriter could not write object %v\n%w", row, err))
+		}
+	}
+}
+
+func (w *GenericParquetWriter) Stop(a *actor.Actor) {
+	if err := w.parquetWriter.WriteStop(); err != nil {
+		panic(fmt.Errorf("GenericParquetWriter could not stop. Error: %w", err))
+	}
+	if err := w.pfile.Close(); err != nil {
+		panic(fmt.Errorf("GenericParquetWriter could not close parquet file %w", err))
+	}
+}
+
+// genericJSONSchema describes the rows of the catalog with the JSON schema format of parquet-go
+func genericJSONSchema(schema repository.GenericSchema) (string, error) {
+	if err := schema.Validate(); err != nil {
+		return "", err
+	}
+
+	type field struct {
+		Tag string
+	}
+	fields := []field{
+		{Tag: "name=id, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"},
+		{Tag: "name=ra, type=DOUBLE, repetitiontype=REQUIRED"},
+		{Tag: "name=dec, type=DOUBLE, repetitiontype=REQUIRED"},
+	}
+	for _, column := range schema.Columns {
+		var tag string
+		switch column.Type {
+		case repository.GenericDouble:
+			tag = "type=DOUBLE"
+		case repository.GenericLong:
+			tag = "type=INT64"
+		default:
+			tag = "type=BYTE_ARRAY, convertedtype=UTF8"
+		}
+		fields = append(fields, field{Tag: fmt.Sprintf("name=%s, %s, repetitiontype=OPTIONAL", column.Name, tag)})
+	}
+
+	jsonSchema, err := json.Marshal(struct {
+		Tag    string
+		Fields []field
+	}{
+		Tag:    "name=parquet_go_root, repetitiontype=REQUIRED",
+		Fields: fields,
+	})
+	return string(jsonSchema), err
+}
@@ -0,0 +1,68 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet_writer
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/dirodriguezm/xmatch/service/internal/actor"
+	"github.com/dirodriguezm/xmatch/service/internal/config"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+type genericRead struct {
+	Id   string   `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Ra   float64  `parquet:"name=ra, type=DOUBLE"`
+	Dec  float64  `parquet:"name=dec, type=DOUBLE"`
+	Mag  *float64 `parquet:"name=mag, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Nobs *int64   `parquet:"name=nobs, type=INT64, repetitiontype=OPTIONAL"`
+	Kind *string  `parquet:"name=kind, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+}
+
+func TestWriteGeneric(t *testing.T) {
+	columns := []repository.GenericColumn{
+		{Name: "mag", Type: repository.GenericDouble},
+		{Name: "nobs", Type: repository.GenericLong},
+		{Name: "kind", Type: repository.GenericString},
+	}
+	schema := repository.GenericSchema{Catalog: "test", IdColumn: "oid", RaColumn: "ra", DecColumn: "dec", Columns: columns}
+	outputFile := path.Join(t.TempDir(), "output.parquet")
+
+	w, err := NewGeneric(config.WriterConfig{OutputFile: outputFile}, schema, context.Background())
+	require.NoError(t, err)
+
+	rows := []any{
+		repository.GenericRow{ID: "o1", Ra: 1, Dec: 2, Columns: columns, Values: []any{12.5, int64(3), "star"}},
+		repository.GenericRow{ID: "o2", Ra: 3, Dec: 4, Columns: columns, Values: []any{nil, nil, nil}},
+		repository.GenericRow{},
+	}
+	w.Write(nil, actor.Message{Error: nil, Rows: rows})
+	require.NoError(t, w.parquetWriter.WriteStop(), "can't stop writer")
+	w.pfile.Close()
+
+	readRows := read_helper[genericRead](t, outputFile)
+	require.Len(t, readRows, 2)
+	require.Equal(t, "o1", readRows[0].Id)
+	require.Equal(t, 2.0, readRows[0].Dec)
+	require.Equal(t, 12.5, *readRows[0].Mag)
+	require.Equal(t, int64(3), *readRows[0].Nobs)
+	require.Equal(t, "star", *readRows[0].Kind)
+	require.Equal(t, "o2", readRows[1].Id)
+	require.Nil(t, readRows[1].Mag)
+	require.Nil(t, readRows[1].Kind)
+}
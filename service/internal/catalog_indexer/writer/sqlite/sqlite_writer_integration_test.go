@@ -143,3 +143,28 @@ func TestReceive(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, objects, 2)
 }
+
+func TestReceive_XwaveDetections(t *testing.T) {
+	ctx := context.Background()
+	w := sqlite_writer.New(repo, ctx, repo.BulkInsertXwaveDetection)
+
+	input := repository.XwaveInputSchema{ObjectID: "1", Mjd: 60000.5, Band: "g", Mag: 15.2, MagErr: 0.1}
+	w.Write(nil, actor.Message{
+		Rows:  []any{input.FillMetadata(), repository.XwaveDetection{ObjectID: "2", Mjd: 60001, Band: "r", Mag: 14, MagErr: 0.2}},
+		Error: nil,
+	})
+
+	// check the database
+	detections, err := repository.New(repo.GetDbInstance()).BulkGetXwaveDetections(ctx, []string{"1", "3"})
+	require.NoError(t, err)
+	require.Equal(t, []repository.XwaveDetection{{ObjectID: "1", Mjd: 60000.5, Band: "g", Mag: 15.2, MagErr: 0.1}}, detections)
+
+	// ingesting a detection again replaces it
+	w.Write(nil, actor.Message{
+		Rows:  []any{repository.XwaveDetection{ObjectID: "1", Mjd: 60000.5, Band: "g", Mag: 15.3, MagErr: 0.1}},
+		Error: nil,
+	})
+	detections, err = repository.New(repo.GetDbInstance()).BulkGetXwaveDetections(ctx, []string{"1"})
+	require.NoError(t, err)
+	require.Equal(t, []repository.XwaveDetection{{ObjectID: "1", Mjd: 60000.5, Band: "g", Mag: 15.3, MagErr: 0.1}}, detections)
+}
@@ -60,6 +60,22 @@ type SourceConfig struct {
 	CatalogName string `yaml:"catalog_name"`
 	Nside       int    `yaml:"nside"`
 	Metadata    bool   `yaml:"metadata"`
+
+	// Schema describes a generic catalog, read without a Go input schema
+	Schema *GenericSchemaConfig `yaml:"schema"`
+}
+
+type GenericSchemaConfig struct {
+	IdColumn  string                `yaml:"id_column"`
+	RaColumn  string                `yaml:"ra_column"`
+	DecColumn string                `yaml:"dec_column"`
+	Columns   []GenericColumnConfig `yaml:"columns"`
+}
+
+type GenericColumnConfig struct {
+	Name string `yaml:"name"`
+	// one of string, double or long
+	Type string `yaml:"type"`
 }
 
 type ReaderConfig struct {
@@ -91,6 +107,35 @@ type ServiceConfig struct {
 	BulkChunkSize           int                     `yaml:"bulk_chunk_size"`
 	MaxBulkConcurrency      int                     `yaml:"max_bulk_concurrency"`
 	LightcurveServiceConfig LightcurveServiceConfig `yaml:"lightcurve_service"`
+	Cache                   CacheConfig             `yaml:"cache"`
+	Jobs                    JobsConfig              `yaml:"jobs"`
+
+	// seconds a request can take before it is cancelled, a zero or negative timeout disables the deadline
+	RequestTimeout int `yaml:"request_timeout"`
+	// seconds a bulk request can take before it is cancelled, a zero or negative timeout disables the deadline
+	BulkRequestTimeout int `yaml:"bulk_request_timeout"`
+}
+
+type CacheConfig struct {
+	// number of objects of the searched pixels kept in memory, a zero or negative size disables the pixel cache
+	PixelCacheSize int `yaml:"pixel_cache_size"`
+	// number of objects of the conesearch responses kept in memory, a zero or negative size disables the response cache
+	ResponseCacheSize int `yaml:"response_cache_size"`
+	// seconds between checks of the index version, a zero or negative interval disables the invalidation
+	IndexRefreshInterval int `yaml:"index_refresh_interval"`
+}
+
+type JobsConfig struct {
+	// where the input and result files of the jobs are kept, a temporary directory when empty
+	Directory string `yaml:"directory"`
+	// number of jobs executed at the same time
+	Workers int `yaml:"workers"`
+	// number of jobs that can wait to be executed
+	QueueSize int `yaml:"queue_size"`
+	// seconds a job and its results are kept after it was submitted
+	Expiration int `yaml:"expiration"`
+	// seconds between removals of expired jobs
+	CleanupInterval int `yaml:"cleanup_interval"`
 }
 
 type DatabaseConfig struct {
@@ -98,17 +143,41 @@ type DatabaseConfig struct {
 }
 
 type LightcurveServiceConfig struct {
-	NeowiseConfig NeowiseConfig `yaml:"neowise"`
-	ZtfDrConfig   ZtfDrConfig   `yaml:"ztf_dr"`
+	// Sources are the external lightcurve services queried by the lightcurve endpoint
+	Sources []LightcurveSourceConfig `yaml:"sources"`
 }
 
-type NeowiseConfig struct {
-	UseIdFilter   bool `yaml:"use_id_filter"`
-	UseCntrFilter bool `yaml:"use_cntr_filter"`
+type LightcurveSourceConfig struct {
+	// one of neowise, ztf_dr or gaia_dr3
+	Type string `yaml:"type"`
+	// catalog the source is selected with in requests, neowise, ztf or gaia when empty
+	Catalog string `yaml:"catalog"`
+	// base url of the service, the public one when empty. For gaia_dr3 it can be
+	// a local mirror serving the TAP and datalink paths of the Gaia archive
+	Url string `yaml:"url"`
+	// seconds a request to the service can take, zero disables the limit
+	Timeout int `yaml:"timeout"`
+	// times a request that times out or fails with a 5xx status is retried, zero disables retries
+	Retries int `yaml:"retries"`
+	// milliseconds before the first retry, each following retry waits twice as long
+	RetryBackoff int `yaml:"retry_backoff"`
+	// consecutive failed requests after which the service isn't called for
+	// breaker_cooldown seconds, zero disables the circuit breaker
+	BreakerThreshold int `yaml:"breaker_threshold"`
+	BreakerCooldown  int `yaml:"breaker_cooldown"`
+	// requests to the service in flight at the same time, zero disables the limit
+	MaxConcurrency int `yaml:"max_concurrency"`
+	// User-Agent header of the requests, xmatch-service when empty
+	UserAgent string `yaml:"user_agent"`
+	// one of none or id, id keeps the detections of the objects found by the conesearch
+	Filter string `yaml:"filter"`
+	// the source is enabled unless enabled is false
+	Enabled *bool `yaml:"enabled"`
 }
 
-type ZtfDrConfig struct {
-	UseIdFilter bool `yaml:"use_id_filter"`
+// IsEnabled tells if the source is enabled
+func (c LightcurveSourceConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
 }
 
 func Load(getEnv func(string) string) (Config, error) {
@@ -172,6 +241,40 @@ func LoadFile(path string) (Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return Config{}, fmt.Errorf("parsing config file: %w", err)
 	}
+	if err := checkRemovedKeys(data); err != nil {
+		return Config{}, fmt.Errorf("parsing config file: %w", err)
+	}
 
 	return cfg, nil
 }
+
+// removedLightcurveConfig holds the lightcurve settings that were replaced by the list of sources
+type removedLightcurveConfig struct {
+	Service struct {
+		LightcurveService struct {
+			Neowise map[string]any `yaml:"neowise"`
+			ZtfDr   map[string]any `yaml:"ztf_dr"`
+		} `yaml:"lightcurve_service"`
+	} `yaml:"service"`
+}
+
+// checkRemovedKeys fails for settings that are no longer read, so they are not silently ignored
+func checkRemovedKeys(data []byte) error {
+	var removed removedLightcurveConfig
+	if err := yaml.Unmarshal(data, &removed); err != nil {
+		return err
+	}
+	for name, settings := range map[string]map[string]any{
+		"neowise": removed.Service.LightcurveService.Neowise,
+		"ztf_dr":  removed.Service.LightcurveService.ZtfDr,
+	} {
+		if settings != nil {
+			return fmt.Errorf(
+				"service.lightcurve_service.%s was replaced by service.lightcurve_service.sources: "+
+					"add a source with type %s, and filter: id instead of use_id_filter or use_cntr_filter",
+				name, name,
+			)
+		}
+	}
+	return nil
+}
@@ -61,6 +61,81 @@ catalog_indexer:
 				require.Equal(t, WriterConfig{Type: "sqlite"}, cfg.CatalogIndexer.IndexerWriter)
 			},
 		},
+		{
+			name: "catalog indexer generic schema",
+			input: `
+catalog_indexer:
+  source:
+    url: "file:test.csv"
+    catalog_name: "generic"
+    schema:
+      id_column: "name"
+      ra_column: "RA"
+      dec_column: "DEC"
+      columns:
+        - name: "mag"
+          type: "double"
+`,
+			validate: func(t *testing.T, cfg Config) {
+				require.Equal(t, &GenericSchemaConfig{
+					IdColumn:  "name",
+					RaColumn:  "RA",
+					DecColumn: "DEC",
+					Columns:   []GenericColumnConfig{{Name: "mag", Type: "double"}},
+				}, cfg.CatalogIndexer.Source.Schema)
+			},
+		},
+		{
+			name: "service cache",
+			input: `
+service:
+  cache:
+    pixel_cache_size: 10
+    response_cache_size: -1
+    index_refresh_interval: 5
+`,
+			validate: func(t *testing.T, cfg Config) {
+				require.Equal(t, CacheConfig{
+					PixelCacheSize:       10,
+					ResponseCacheSize:    -1,
+					IndexRefreshInterval: 5,
+				}, cfg.Service.Cache)
+			},
+		},
+		{
+			name: "lightcurve sources",
+			input: `
+service:
+  lightcurve_service:
+    sources:
+      - type: ztf_dr
+        url: "http://localhost:8080/light_curve/"
+        timeout: 5
+        retries: 3
+        retry_backoff: 100
+        breaker_threshold: 4
+        breaker_cooldown: 30
+        max_concurrency: 2
+        user_agent: "xmatch-test"
+        filter: id
+      - type: neowise
+        enabled: false
+`,
+			validate: func(t *testing.T, cfg Config) {
+				sources := cfg.Service.LightcurveServiceConfig.Sources
+				require.Len(t, sources, 2)
+				require.Equal(t, "http://localhost:8080/light_curve/", sources[0].Url)
+				require.Equal(t, 5, sources[0].Timeout)
+				require.Equal(t, 3, sources[0].Retries)
+				require.Equal(t, 100, sources[0].RetryBackoff)
+				require.Equal(t, 4, sources[0].BreakerThreshold)
+				require.Equal(t, 30, sources[0].BreakerCooldown)
+				require.Equal(t, 2, sources[0].MaxConcurrency)
+				require.Equal(t, "xmatch-test", sources[0].UserAgent)
+				require.True(t, sources[0].IsEnabled())
+				require.False(t, sources[1].IsEnabled())
+			},
+		},
 		{
 			name:  "catalog indexer with empty config",
 			input: "",
@@ -87,6 +162,31 @@ catalog_indexer:
 	}
 }
 
+func TestLoadFile_RemovedKeys(t *testing.T) {
+	testCases := map[string]string{
+		"neowise cntr filter": `
+service:
+  lightcurve_service:
+    neowise:
+      use_cntr_filter: true
+`,
+		"ztf id filter": `
+service:
+  lightcurve_service:
+    ztf_dr:
+      use_id_filter: false
+`,
+	}
+
+	for name, input := range testCases {
+		configPath := filepath.Join(t.TempDir(), "config.yml")
+		require.NoError(t, os.WriteFile(configPath, []byte(input), 0644))
+
+		_, err := LoadFile(configPath)
+		require.ErrorContains(t, err, "was replaced by service.lightcurve_service.sources", name)
+	}
+}
+
 func TestLoad(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -186,6 +286,23 @@ func TestLoadDefaultConfigFallsBackToEmbeddedConfig(t *testing.T) {
 	require.Equal(t, "file:dev.db", cfg.CatalogIndexer.Database.Url)
 }
 
+func TestLoad_KeepsDefaultTimeoutsAndCacheSizes(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("service:\n  request_timeout: 0\n"), 0644))
+
+	cfg, err := Load(func(key string) string {
+		if key == "CONFIG_PATH" {
+			return configPath
+		}
+		return ""
+	})
+	require.NoError(t, err)
+	require.Equal(t, 30, cfg.Service.RequestTimeout)
+	require.Equal(t, 300, cfg.Service.BulkRequestTimeout)
+	require.Positive(t, cfg.Service.Cache.PixelCacheSize)
+	require.Positive(t, cfg.Service.Cache.ResponseCacheSize)
+}
+
 func Test_mergeConfig(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -0,0 +1 @@
+ALTER TABLE mastercat DROP COLUMN pos_err;
@@ -0,0 +1 @@
+ALTER TABLE mastercat ADD COLUMN pos_err double precision;
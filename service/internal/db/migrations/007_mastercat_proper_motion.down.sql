@@ -0,0 +1,4 @@
+ALTER TABLE mastercat DROP COLUMN ref_epoch;
+ALTER TABLE mastercat DROP COLUMN parallax;
+ALTER TABLE mastercat DROP COLUMN pmdec;
+ALTER TABLE mastercat DROP COLUMN pmra;
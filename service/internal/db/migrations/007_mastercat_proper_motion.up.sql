@@ -0,0 +1,4 @@
+ALTER TABLE mastercat ADD COLUMN pmra double precision;
+ALTER TABLE mastercat ADD COLUMN pmdec double precision;
+ALTER TABLE mastercat ADD COLUMN parallax double precision;
+ALTER TABLE mastercat ADD COLUMN ref_epoch double precision;
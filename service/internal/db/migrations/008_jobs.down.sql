@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS jobs_expires_at;
+DROP INDEX IF EXISTS jobs_phase;
+DROP TABLE IF EXISTS jobs;
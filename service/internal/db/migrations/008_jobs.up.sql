@@ -0,0 +1,18 @@
+CREATE TABLE jobs (
+    id text not null PRIMARY KEY,
+    phase text not null,
+    radius double precision not null,
+    catalog text not null,
+    nneighbor integer not null,
+    result_format text not null,
+    total integer not null,
+    progress integer not null default 0,
+    error text,
+    created_at integer not null,
+    started_at integer,
+    finished_at integer,
+    expires_at integer not null
+);
+
+CREATE INDEX jobs_phase ON jobs (phase);
+CREATE INDEX jobs_expires_at ON jobs (expires_at);
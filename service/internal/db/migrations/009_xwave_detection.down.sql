@@ -0,0 +1 @@
+DROP TABLE IF EXISTS xwave_detection;
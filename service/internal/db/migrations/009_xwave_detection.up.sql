@@ -0,0 +1,8 @@
+CREATE TABLE xwave_detection (
+    object_id text not null,
+    mjd double precision not null,
+    band text not null,
+    mag double precision not null,
+    mag_err double precision not null,
+    PRIMARY KEY (object_id, band, mjd)
+);
@@ -0,0 +1,2 @@
+DROP INDEX mastercat_ipix_idx;
+CREATE INDEX mastercat_ipix_cat_id_idx ON mastercat (ipix, cat, id);
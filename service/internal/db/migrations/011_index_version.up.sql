@@ -0,0 +1,5 @@
+CREATE TABLE index_version (
+    id integer primary key check (id = 0),
+    version integer not null
+);
+INSERT INTO index_version (id, version) VALUES (0, 0);
@@ -3,6 +3,42 @@ SELECT *
 FROM mastercat 
 WHERE ipix IN (sqlc.slice(ipix));
 
+-- name: FindObjectsBetweenPixels :many
+SELECT *
+FROM mastercat
+WHERE ipix >= sqlc.arg(start) AND ipix < sqlc.arg(stop);
+
+-- name: FindObjectsOfCatalogs :many
+SELECT *
+FROM mastercat
+WHERE ipix IN (sqlc.slice(ipix)) AND cat IN (sqlc.slice(cat));
+
+-- name: FindObjectsOfCatalogsBetweenPixels :many
+SELECT *
+FROM mastercat
+WHERE ipix >= sqlc.arg(start) AND ipix < sqlc.arg(stop) AND cat IN (sqlc.slice(cat));
+
+-- name: FindObjectsOfCatalogsInPixelRange :many
+SELECT *
+FROM mastercat
+WHERE ipix >= sqlc.arg(start) AND ipix < sqlc.arg(stop) AND cat IN (sqlc.slice(cat))
+AND (ipix, cat, id) > (sqlc.arg(after_ipix), sqlc.arg(after_cat), sqlc.arg(after_id))
+ORDER BY ipix, cat, id
+LIMIT sqlc.arg(limit);
+
+-- name: FindObjectsById :many
+SELECT *
+FROM mastercat
+WHERE id = ?;
+
+-- name: FindObjectsInPixelRange :many
+SELECT *
+FROM mastercat
+WHERE ipix >= sqlc.arg(start) AND ipix < sqlc.arg(stop)
+AND (ipix, cat, id) > (sqlc.arg(after_ipix), sqlc.arg(after_cat), sqlc.arg(after_id))
+ORDER BY ipix, cat, id
+LIMIT sqlc.arg(limit);
+
 -- name: GetObjectsFromCatalog :many
 SELECT * 
 FROM mastercat 
@@ -11,15 +47,25 @@ AND cat = ?;
 
 -- name: InsertObject :exec
 INSERT INTO mastercat (
-	id, ipix, ra, dec, cat
+	id, ipix, ra, dec, cat, pos_err, pmra, pmdec, parallax, ref_epoch
 ) VALUES (
-	?, ?, ?, ?, ?
+	?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 );
 
 -- name: GetAllObjects :many
 SELECT *
 FROM mastercat;
 
+-- name: GetIndexVersion :one
+SELECT version
+FROM index_version
+WHERE id = 0;
+
+-- name: BumpIndexVersion :exec
+UPDATE index_version
+SET version = version + 1
+WHERE id = 0;
+
 -- name: GetCatalogs :many
 SELECT *
 FROM catalogs;
@@ -59,8 +105,14 @@ DELETE FROM allwise;
 -- name: RemoveAllCatalogs :exec
 DELETE FROM catalogs;
 
+-- name: GetAllwiseBetweenPixels :many
+SELECT allwise.*, mastercat.ra, mastercat.dec, mastercat.pos_err, mastercat.pmra, mastercat.pmdec, mastercat.ref_epoch
+FROM allwise
+JOIN mastercat ON mastercat.id = allwise.id
+WHERE mastercat.ipix >= sqlc.arg(start) AND mastercat.ipix < sqlc.arg(stop);
+
 -- name: GetAllwiseFromPixels :many
-SELECT allwise.*, mastercat.ra, mastercat.dec
+SELECT allwise.*, mastercat.ra, mastercat.dec, mastercat.pos_err, mastercat.pmra, mastercat.pmdec, mastercat.ref_epoch
 FROM allwise 
 JOIN mastercat ON mastercat.id = allwise.id
 WHERE mastercat.ipix IN (sqlc.slice(ipix));
@@ -96,8 +148,14 @@ WHERE gaia.id IN (sqlc.slice(id));
 -- name: RemoveAllGaia :exec
 DELETE FROM gaia;
 
+-- name: GetGaiaBetweenPixels :many
+SELECT gaia.*, mastercat.ra, mastercat.dec, mastercat.pos_err, mastercat.pmra, mastercat.pmdec, mastercat.ref_epoch
+FROM gaia
+JOIN mastercat ON mastercat.id = gaia.id
+WHERE mastercat.ipix >= sqlc.arg(start) AND mastercat.ipix < sqlc.arg(stop);
+
 -- name: GetGaiaFromPixels :many
-SELECT gaia.*, mastercat.ra, mastercat.dec
+SELECT gaia.*, mastercat.ra, mastercat.dec, mastercat.pos_err, mastercat.pmra, mastercat.pmdec, mastercat.ref_epoch
 FROM gaia 
 JOIN mastercat ON mastercat.id = gaia.id
 WHERE mastercat.ipix IN (sqlc.slice(ipix));
@@ -144,8 +202,71 @@ WHERE erosita.id IN (sqlc.slice(id));
 -- name: RemoveAllErosita :exec
 DELETE FROM erosita;
 
+-- name: GetErositaBetweenPixels :many
+SELECT erosita.*, mastercat.ra, mastercat.dec, mastercat.pos_err, mastercat.pmra, mastercat.pmdec, mastercat.ref_epoch
+FROM erosita
+JOIN mastercat ON mastercat.id = erosita.id
+WHERE mastercat.ipix >= sqlc.arg(start) AND mastercat.ipix < sqlc.arg(stop);
+
 -- name: GetErositaFromPixels :many
-SELECT erosita.*, mastercat.ra, mastercat.dec
+SELECT erosita.*, mastercat.ra, mastercat.dec, mastercat.pos_err, mastercat.pmra, mastercat.pmdec, mastercat.ref_epoch
 FROM erosita 
 JOIN mastercat ON mastercat.id = erosita.id
 WHERE mastercat.ipix IN (sqlc.slice(ipix));
+
+-- name: InsertJob :exec
+INSERT INTO jobs (
+	id, phase, radius, catalog, nneighbor, result_format, total, created_at, expires_at
+) VALUES (
+	?, ?, ?, ?, ?, ?, ?, ?, ?
+);
+
+-- name: GetJob :one
+SELECT *
+FROM jobs
+WHERE id = ?;
+
+-- name: GetJobsByPhase :many
+SELECT *
+FROM jobs
+WHERE phase = ?;
+
+-- name: GetExpiredJobs :many
+SELECT *
+FROM jobs
+WHERE expires_at <= ?;
+
+-- name: StartJob :exec
+UPDATE jobs
+SET phase = ?, started_at = ?
+WHERE id = ?;
+
+-- name: UpdateJobProgress :exec
+UPDATE jobs
+SET progress = ?
+WHERE id = ?;
+
+-- name: FinishJob :exec
+UPDATE jobs
+SET phase = ?, error = ?, finished_at = ?
+WHERE id = ?;
+
+-- name: DeleteJob :exec
+DELETE FROM jobs
+WHERE id = ?;
+
+-- name: InsertXwaveDetection :exec
+INSERT OR REPLACE INTO xwave_detection (
+	object_id, mjd, band, mag, mag_err
+) VALUES (
+	?, ?, ?, ?, ?
+);
+
+-- name: BulkGetXwaveDetections :many
+SELECT *
+FROM xwave_detection
+WHERE object_id IN (sqlc.slice(object_id))
+ORDER BY object_id, mjd;
+
+-- name: RemoveAllXwaveDetections :exec
+DELETE FROM xwave_detection;
@@ -170,6 +170,20 @@ func (m GetAllwiseFromPixelsRow) GetCatalog() string {
 	return "AllWISE"
 }
 
+func (m GetAllwiseFromPixelsRow) GetPositionalError() (float64, bool) {
+	return positionalError(m.PosErr)
+}
+
+func (m GetAllwiseFromPixelsRow) GetProperMotion() (float64, float64, float64, bool) {
+	return properMotion(m.Pmra, m.Pmdec, m.RefEpoch)
+}
+
+func (m GetAllwiseFromPixelsRow) WithCoordinates(ra, dec float64) MetadataWithCoordinates {
+	m.Ra = ra
+	m.Dec = dec
+	return m
+}
+
 func (q *Queries) InsertAllwiseWithoutParams(ctx context.Context, arg Allwise) error {
 	_, err := q.db.ExecContext(ctx, insertAllwise,
 		arg.ID,
@@ -82,3 +82,19 @@ func (q *Queries) BulkInsertErosita(ctx context.Context, db *sql.DB, arg []any)
 	}
 	return tx.Commit()
 }
+
+func (q *Queries) BulkInsertXwaveDetection(ctx context.Context, db *sql.DB, arg []any) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := q.WithTx(tx)
+	for i := range arg {
+		err = qtx.InsertXwaveDetectionWithoutParams(ctx, arg[i].(XwaveDetection))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
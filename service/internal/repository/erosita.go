@@ -249,12 +249,14 @@ func (schema ErositaInputSchema) FillMetadata() Metadata {
 
 func (schema ErositaInputSchema) FillMastercat(ipix int64) Mastercat {
 	ra, dec := schema.GetCoordinates()
+	posErr := float64(schema.POS_ERR)
 	return Mastercat{
-		ID:   schema.GetId(),
-		Ipix: ipix,
-		Ra:   ra,
-		Dec:  dec,
-		Cat:  "erosita",
+		ID:     schema.GetId(),
+		Ipix:   ipix,
+		Ra:     ra,
+		Dec:    dec,
+		Cat:    "erosita",
+		PosErr: &posErr,
 	}
 }
 
@@ -274,16 +276,28 @@ func (m GetErositaFromPixelsRow) GetId() string {
 	return m.ID
 }
 
+// GetCoordinates returns the position of the source in the mastercat,
+// which is the one searched and propagated with the proper motion
 func (m GetErositaFromPixelsRow) GetCoordinates() (float64, float64) {
-	ra := 0.0
-	dec := 0.0
-	if m.Ra.Valid {
-		ra = m.Ra.Float64
-	}
-	if m.Dec.Valid {
-		dec = m.Dec.Float64
-	}
-	return ra, dec
+	return m.Ra_2, m.Dec_2
+}
+
+func (m GetErositaFromPixelsRow) GetCatalog() string {
+	return "eROSITA"
+}
+
+func (m GetErositaFromPixelsRow) GetPositionalError() (float64, bool) {
+	return positionalError(m.PosErr_2)
+}
+
+func (m GetErositaFromPixelsRow) GetProperMotion() (float64, float64, float64, bool) {
+	return properMotion(m.Pmra, m.Pmdec, m.RefEpoch)
+}
+
+func (m GetErositaFromPixelsRow) WithCoordinates(ra, dec float64) MetadataWithCoordinates {
+	m.Ra_2 = ra
+	m.Dec_2 = dec
+	return m
 }
 
 func (q *Queries) InsertErositaWithoutParams(ctx context.Context, arg Erosita) error {
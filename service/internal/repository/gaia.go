@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"math"
 )
 
 type GaiaInputSchema struct {
@@ -185,15 +186,30 @@ func (schema GaiaInputSchema) FillMetadata() Metadata {
 
 func (schema GaiaInputSchema) FillMastercat(ipix int64) Mastercat {
 	ra, dec := schema.GetCoordinates()
+	// ra_error and dec_error are given in mas, the mastercat stores a single circular error in arcsec
+	posErr := math.Sqrt((float64(schema.RAError)*float64(schema.RAError)+float64(schema.DecError)*float64(schema.DecError))/2) / 1000
 	return Mastercat{
-		ID:   schema.GetId(),
-		Ipix: ipix,
-		Ra:   ra,
-		Dec:  dec,
-		Cat:  "gaia",
+		ID:       schema.GetId(),
+		Ipix:     ipix,
+		Ra:       ra,
+		Dec:      dec,
+		Cat:      "gaia",
+		PosErr:   &posErr,
+		Pmra:     finiteOrNil(schema.PMRA),
+		Pmdec:    finiteOrNil(schema.PMDec),
+		Parallax: finiteOrNil(schema.Parallax),
+		RefEpoch: finiteOrNil(schema.RefEpoch),
 	}
 }
 
+// finiteOrNil returns nil for the NaN values used by Gaia for missing astrometry
+func finiteOrNil(value float64) *float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+	return &value
+}
+
 func (gaia Gaia) GetId() string {
 	return gaia.ID
 }
@@ -235,3 +251,17 @@ func (m GetGaiaFromPixelsRow) GetCoordinates() (float64, float64) {
 func (m GetGaiaFromPixelsRow) GetCatalog() string {
 	return "GAIA/DR3"
 }
+
+func (m GetGaiaFromPixelsRow) GetPositionalError() (float64, bool) {
+	return positionalError(m.PosErr)
+}
+
+func (m GetGaiaFromPixelsRow) GetProperMotion() (float64, float64, float64, bool) {
+	return properMotion(m.Pmra, m.Pmdec, m.RefEpoch)
+}
+
+func (m GetGaiaFromPixelsRow) WithCoordinates(ra, dec float64) MetadataWithCoordinates {
+	m.Ra = ra
+	m.Dec = dec
+	return m
+}
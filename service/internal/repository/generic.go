@@ -0,0 +1,542 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Types of the metadata columns of generic catalogs
+const (
+	GenericString = "string"
+	GenericDouble = "double"
+	GenericLong   = "long"
+)
+
+var genericIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Tables of the service that can't be used by generic catalogs, checked without a database.
+// Other tables of the database are reserved by checkGenericTable.
+var reservedTables = []string{"mastercat", "catalogs", "schema_migrations"}
+
+// ErrReservedTable is returned when a generic catalog is named after a table of the service
+var ErrReservedTable = errors.New("the name is used by the service")
+
+// GenericColumn is a metadata column of a generic catalog
+type GenericColumn struct {
+	Name string
+	Type string
+}
+
+// GenericSchema describes a catalog that is read and stored without Go types.
+//
+// IdColumn, RaColumn and DecColumn are the columns of the source files holding the
+// id and position of each object, and are only needed while indexing. The metadata
+// table of the catalog has an id column and one column for each of Columns.
+type GenericSchema struct {
+	Catalog   string
+	IdColumn  string
+	RaColumn  string
+	DecColumn string
+	Columns   []GenericColumn
+}
+
+// Validate checks that the catalog and columns can be used as SQL identifiers
+func (s GenericSchema) Validate() error {
+	if !genericIdentifier.MatchString(s.Catalog) {
+		return fmt.Errorf("invalid catalog name %q: names must contain only letters, digits and underscores", s.Catalog)
+	}
+	if slices.Contains(reservedTables, strings.ToLower(s.Catalog)) {
+		return fmt.Errorf("invalid catalog name %q: the name is used by the service", s.Catalog)
+	}
+
+	seen := map[string]bool{"id": true, "ra": true, "dec": true}
+	for _, column := range s.Columns {
+		if !genericIdentifier.MatchString(column.Name) {
+			return fmt.Errorf("invalid column name %q: names must contain only letters, digits and underscores", column.Name)
+		}
+		if seen[strings.ToLower(column.Name)] {
+			return fmt.Errorf("invalid column name %q: the column is repeated or used by the service", column.Name)
+		}
+		seen[strings.ToLower(column.Name)] = true
+
+		switch column.Type {
+		case GenericString, GenericDouble, GenericLong:
+		default:
+			return fmt.Errorf("invalid type %q of column %s: allowed types are %s, %s and %s", column.Type, column.Name, GenericString, GenericDouble, GenericLong)
+		}
+	}
+	return nil
+}
+
+// Value converts a value read from a source file to the type of the column.
+//
+// Strings are parsed, numbers are converted between integers and floats, and
+// NaN is stored as null.
+func (c GenericColumn) Value(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch c.Type {
+	case GenericString:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		return fmt.Sprint(value), nil
+	case GenericDouble:
+		var f float64
+		switch v := value.(type) {
+		case string:
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", c.Name, err)
+			}
+			f = parsed
+		case float64:
+			f = v
+		case float32:
+			f = float64(v)
+		case int64:
+			f = float64(v)
+		case int32:
+			f = float64(v)
+		case int:
+			f = float64(v)
+		default:
+			return nil, fmt.Errorf("column %s: can't convert %T to %s", c.Name, value, c.Type)
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, nil
+		}
+		return f, nil
+	case GenericLong:
+		switch v := value.(type) {
+		case string:
+			parsed, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", c.Name, err)
+			}
+			return parsed, nil
+		case int64:
+			return v, nil
+		case int32:
+			return int64(v), nil
+		case int:
+			return int64(v), nil
+		default:
+			return nil, fmt.Errorf("column %s: can't convert %T to %s", c.Name, value, c.Type)
+		}
+	default:
+		return nil, fmt.Errorf("column %s: unknown type %s", c.Name, c.Type)
+	}
+}
+
+// NewRow builds a row from the values of a source file, found by column name with value
+func (s GenericSchema) NewRow(value func(column string) (any, bool)) (GenericRow, error) {
+	find := func(column string) (any, error) {
+		v, ok := value(column)
+		if !ok {
+			return nil, fmt.Errorf("column %s not found in source", column)
+		}
+		return v, nil
+	}
+
+	id, err := find(s.IdColumn)
+	if err != nil {
+		return GenericRow{}, err
+	}
+	if id == nil {
+		return GenericRow{}, fmt.Errorf("column %s: id can't be null", s.IdColumn)
+	}
+	row := GenericRow{Catalog: s.Catalog, ID: fmt.Sprint(id), Columns: s.Columns, Values: make([]any, len(s.Columns))}
+
+	for _, position := range []struct {
+		column string
+		dest   *float64
+	}{{s.RaColumn, &row.Ra}, {s.DecColumn, &row.Dec}} {
+		v, err := find(position.column)
+		if err != nil {
+			return GenericRow{}, err
+		}
+		coordinate, err := GenericColumn{Name: position.column, Type: GenericDouble}.Value(v)
+		if err != nil {
+			return GenericRow{}, err
+		}
+		if coordinate == nil {
+			return GenericRow{}, fmt.Errorf("column %s: coordinates can't be null", position.column)
+		}
+		*position.dest = coordinate.(float64)
+	}
+
+	for i, column := range s.Columns {
+		v, err := find(column.Name)
+		if err != nil {
+			return GenericRow{}, err
+		}
+		if row.Values[i], err = column.Value(v); err != nil {
+			return GenericRow{}, err
+		}
+	}
+	return row, nil
+}
+
+// GenericRow is an object of a generic catalog, with one value for each
+// metadata column of the schema. Null values are stored as nil.
+type GenericRow struct {
+	Catalog string
+	ID      string
+	Ra      float64
+	Dec     float64
+	PosErr  *float64
+	Columns []GenericColumn
+	Values  []any
+}
+
+func (row GenericRow) GetId() string {
+	return row.ID
+}
+
+func (row GenericRow) GetCoordinates() (float64, float64) {
+	return row.Ra, row.Dec
+}
+
+func (row GenericRow) GetCatalog() string {
+	return row.Catalog
+}
+
+func (row GenericRow) GetPositionalError() (float64, bool) {
+	return positionalError(row.PosErr)
+}
+
+func (row GenericRow) FillMastercat(ipix int64) Mastercat {
+	return Mastercat{
+		ID:     row.ID,
+		Ipix:   ipix,
+		Ra:     row.Ra,
+		Dec:    row.Dec,
+		Cat:    row.Catalog,
+		PosErr: row.PosErr,
+	}
+}
+
+func (row GenericRow) FillMetadata() Metadata {
+	return row
+}
+
+// MarshalJSON writes the row as an object with the id, the position and
+// the metadata columns, in the order of the schema
+func (row GenericRow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	write := func(name string, value any) error {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(encoded)
+		return nil
+	}
+
+	if err := write("id", row.ID); err != nil {
+		return nil, err
+	}
+	if err := write("ra", row.Ra); err != nil {
+		return nil, err
+	}
+	if err := write("dec", row.Dec); err != nil {
+		return nil, err
+	}
+	for i, column := range row.Columns {
+		if err := write(column.Name, row.Values[i]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// GetGenericSchema describes the metadata table of a catalog from its columns.
+// It returns sql.ErrNoRows when the table doesn't exist.
+func (q *Queries) GetGenericSchema(ctx context.Context, catalog string) (GenericSchema, error) {
+	schema := GenericSchema{Catalog: catalog}
+	if err := schema.Validate(); err != nil {
+		return GenericSchema{}, err
+	}
+	if err := checkGenericTable(ctx, q.db, catalog); err != nil {
+		return GenericSchema{}, err
+	}
+
+	rows, err := q.db.QueryContext(ctx, fmt.Sprintf(`PRAGMA table_info(%q)`, catalog))
+	if err != nil {
+		return GenericSchema{}, err
+	}
+	defer rows.Close()
+
+	found := false
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    int
+			dflt       sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &dflt, &pk); err != nil {
+			return GenericSchema{}, err
+		}
+		found = true
+		if strings.EqualFold(name, "id") {
+			continue
+		}
+		schema.Columns = append(schema.Columns, GenericColumn{Name: name, Type: genericType(columnType)})
+	}
+	if err := rows.Err(); err != nil {
+		return GenericSchema{}, err
+	}
+	if !found {
+		return GenericSchema{}, sql.ErrNoRows
+	}
+	return schema, nil
+}
+
+// BulkInsertGeneric inserts the rows of a generic catalog, creating its metadata table if needed
+func (q *Queries) BulkInsertGeneric(ctx context.Context, db *sql.DB, schema GenericSchema, arg []any) error {
+	if err := schema.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkGenericTable(ctx, tx, schema.Catalog); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, genericCreateTable(schema)); err != nil {
+		return fmt.Errorf("could not create table %s: %w", schema.Catalog, err)
+	}
+	stmt, err := tx.PrepareContext(ctx, genericInsert(schema))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i := range arg {
+		row := arg[i].(GenericRow)
+		values := append([]any{row.ID}, row.Values...)
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetGeneric returns the metadata of one object of a generic catalog
+func (q *Queries) GetGeneric(ctx context.Context, schema GenericSchema, id string) (GenericRow, error) {
+	rows, err := q.queryGeneric(ctx, schema, "t.id = ?", []any{id})
+	if err != nil {
+		return GenericRow{}, err
+	}
+	if len(rows) == 0 {
+		return GenericRow{}, sql.ErrNoRows
+	}
+	return rows[0], nil
+}
+
+// BulkGetGeneric returns the metadata of several objects of a generic catalog
+func (q *Queries) BulkGetGeneric(ctx context.Context, schema GenericSchema, ids []string) ([]GenericRow, error) {
+	args := make([]any, len(ids))
+	for i := range ids {
+		args[i] = ids[i]
+	}
+	return q.queryGeneric(ctx, schema, "t.id IN ("+placeholders(len(ids))+")", args)
+}
+
+// GetGenericFromPixels returns the metadata of the objects of a generic catalog in the given pixels
+func (q *Queries) GetGenericFromPixels(ctx context.Context, schema GenericSchema, ipix []int64) ([]GenericRow, error) {
+	args := make([]any, len(ipix))
+	for i := range ipix {
+		args[i] = ipix[i]
+	}
+	return q.queryGeneric(ctx, schema, "mastercat.ipix IN ("+placeholders(len(ipix))+")", args)
+}
+
+// GetGenericBetweenPixels returns the metadata of the objects of a generic catalog in the pixels from start to stop, excluded
+func (q *Queries) GetGenericBetweenPixels(ctx context.Context, schema GenericSchema, start, stop int64) ([]GenericRow, error) {
+	return q.queryGeneric(ctx, schema, "mastercat.ipix >= ? AND mastercat.ipix < ?", []any{start, stop})
+}
+
+func (q *Queries) queryGeneric(ctx context.Context, schema GenericSchema, where string, args []any) ([]GenericRow, error) {
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return []GenericRow{}, nil
+	}
+
+	columns := []string{"t.id", "mastercat.ra", "mastercat.dec", "mastercat.pos_err"}
+	for _, column := range schema.Columns {
+		columns = append(columns, fmt.Sprintf("t.%q", column.Name))
+	}
+	query := fmt.Sprintf(
+		"SELECT %s FROM %q AS t JOIN mastercat ON mastercat.id = t.id AND mastercat.cat = ? WHERE %s",
+		strings.Join(columns, ", "),
+		schema.Catalog,
+		where,
+	)
+
+	rows, err := q.db.QueryContext(ctx, query, append([]any{schema.Catalog}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []GenericRow{}
+	for rows.Next() {
+		row := GenericRow{Catalog: schema.Catalog, Columns: schema.Columns}
+		dest := []any{&row.ID, &row.Ra, &row.Dec, &row.PosErr}
+		values := make([]any, len(schema.Columns))
+		for i, column := range schema.Columns {
+			switch column.Type {
+			case GenericDouble:
+				values[i] = new(sql.NullFloat64)
+			case GenericLong:
+				values[i] = new(sql.NullInt64)
+			default:
+				values[i] = new(sql.NullString)
+			}
+		}
+		if err := rows.Scan(append(dest, values...)...); err != nil {
+			return nil, err
+		}
+
+		row.Values = make([]any, len(values))
+		for i := range values {
+			switch v := values[i].(type) {
+			case *sql.NullFloat64:
+				if v.Valid {
+					row.Values[i] = v.Float64
+				}
+			case *sql.NullInt64:
+				if v.Valid {
+					row.Values[i] = v.Int64
+				}
+			case *sql.NullString:
+				if v.Valid {
+					row.Values[i] = v.String
+				}
+			}
+		}
+		items = append(items, row)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// checkGenericTable fails with ErrReservedTable when a table or view named like the catalog
+// exists and was not created for a generic catalog, like the jobs or xwave_detection tables
+func checkGenericTable(ctx context.Context, db DBTX, catalog string) error {
+	var definition sql.NullString
+	err := db.QueryRowContext(
+		ctx,
+		"SELECT sql FROM sqlite_master WHERE type IN ('table', 'view') AND name = ? COLLATE NOCASE",
+		catalog,
+	).Scan(&definition)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not find table %s: %w", catalog, err)
+	}
+	prefix := fmt.Sprintf("CREATE TABLE %q (id TEXT PRIMARY KEY", catalog)
+	if !strings.HasPrefix(strings.ToLower(definition.String), strings.ToLower(prefix)) {
+		return fmt.Errorf("invalid catalog name %q: %w", catalog, ErrReservedTable)
+	}
+	return nil
+}
+
+func genericCreateTable(schema GenericSchema) string {
+	columns := []string{"id TEXT PRIMARY KEY"}
+	for _, column := range schema.Columns {
+		columns = append(columns, fmt.Sprintf("%q %s", column.Name, sqliteType(column.Type)))
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %q (%s)", schema.Catalog, strings.Join(columns, ", "))
+}
+
+func genericInsert(schema GenericSchema) string {
+	columns := []string{"id"}
+	for _, column := range schema.Columns {
+		columns = append(columns, fmt.Sprintf("%q", column.Name))
+	}
+	return fmt.Sprintf(
+		"INSERT INTO %q (%s) VALUES (%s)",
+		schema.Catalog,
+		strings.Join(columns, ", "),
+		placeholders(len(columns)),
+	)
+}
+
+func sqliteType(columnType string) string {
+	switch columnType {
+	case GenericDouble:
+		return "REAL"
+	case GenericLong:
+		return "INTEGER"
+	default:
+		return "TEXT"
+	}
+}
+
+func genericType(sqliteType string) string {
+	switch strings.ToUpper(sqliteType) {
+	case "REAL", "DOUBLE", "FLOAT":
+		return GenericDouble
+	case "INTEGER", "INT", "BIGINT":
+		return GenericLong
+	default:
+		return GenericString
+	}
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -9,6 +9,19 @@ func (q *Queries) InsertMastercat(ctx context.Context, arg Mastercat) error {
 		arg.Ra,
 		arg.Dec,
 		arg.Cat,
+		arg.PosErr,
+		arg.Pmra,
+		arg.Pmdec,
+		arg.Parallax,
+		arg.RefEpoch,
 	)
 	return err
 }
+
+// GetProperMotion returns the proper motion of the object in mas/yr and the
+// Julian year of its recorded coordinates.
+//
+// The boolean is false when the proper motion was not recorded for the object.
+func (m Mastercat) GetProperMotion() (float64, float64, float64, bool) {
+	return properMotion(m.Pmra, m.Pmdec, m.RefEpoch)
+}
@@ -23,3 +23,39 @@ type MetadataWithCoordinates interface {
 	Metadata
 	GetCoordinates() (float64, float64)
 }
+
+// MetadataWithPositionalError is implemented by metadata rows that know
+// the positional error of the source, in arcsec.
+//
+// The boolean is false when the positional error was not recorded for the source.
+type MetadataWithPositionalError interface {
+	GetPositionalError() (float64, bool)
+}
+
+func positionalError(posErr *float64) (float64, bool) {
+	if posErr == nil {
+		return 0, false
+	}
+	return *posErr, true
+}
+
+// MetadataWithProperMotion is implemented by metadata rows that know
+// the proper motion of the source.
+//
+// pmra (including the cos(dec) factor) and pmdec are given in mas/yr,
+// and refEpoch is the Julian year of the recorded coordinates.
+// The boolean is false when the proper motion was not recorded for the source.
+//
+// WithCoordinates returns a copy of the row placed at the given coordinates.
+type MetadataWithProperMotion interface {
+	MetadataWithCoordinates
+	GetProperMotion() (pmra, pmdec, refEpoch float64, ok bool)
+	WithCoordinates(ra, dec float64) MetadataWithCoordinates
+}
+
+func properMotion(pmra, pmdec, refEpoch *float64) (float64, float64, float64, bool) {
+	if pmra == nil || pmdec == nil || refEpoch == nil {
+		return 0, 0, 0, false
+	}
+	return *pmra, *pmdec, *refEpoch, true
+}
@@ -157,10 +157,44 @@ type Gaia struct {
 	PhotRpMeanMag       NullFloat64 `json:"phot_rp_mean_mag" parquet:"name=phot_rp_mean_mag, type=DOUBLE"`
 }
 
+type IndexVersion struct {
+	ID      int64
+	Version int64
+}
+
+type Job struct {
+	ID           string
+	Phase        string
+	Radius       float64
+	Catalog      string
+	Nneighbor    int64
+	ResultFormat string
+	Total        int64
+	Progress     int64
+	Error        NullString
+	CreatedAt    int64
+	StartedAt    NullInt64
+	FinishedAt   NullInt64
+	ExpiresAt    int64
+}
+
 type Mastercat struct {
-	ID   string  `json:"id" parquet:"name=id, type=BYTE_ARRAY"`
-	Ipix int64   `json:"ipix" parquet:"name=ipix, type=INT64"`
-	Ra   float64 `json:"ra" parquet:"name=ra, type=DOUBLE"`
-	Dec  float64 `json:"dec" parquet:"name=dec, type=DOUBLE"`
-	Cat  string  `json:"cat" parquet:"name=cat, type=BYTE_ARRAY"`
+	ID       string   `json:"id" parquet:"name=id, type=BYTE_ARRAY"`
+	Ipix     int64    `json:"ipix" parquet:"name=ipix, type=INT64"`
+	Ra       float64  `json:"ra" parquet:"name=ra, type=DOUBLE"`
+	Dec      float64  `json:"dec" parquet:"name=dec, type=DOUBLE"`
+	Cat      string   `json:"cat" parquet:"name=cat, type=BYTE_ARRAY"`
+	PosErr   *float64 `json:"pos_err,omitempty" parquet:"name=pos_err, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmra     *float64 `json:"pmra,omitempty" parquet:"name=pmra, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmdec    *float64 `json:"pmdec,omitempty" parquet:"name=pmdec, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Parallax *float64 `json:"parallax,omitempty" parquet:"name=parallax, type=DOUBLE, repetitiontype=OPTIONAL"`
+	RefEpoch *float64 `json:"ref_epoch,omitempty" parquet:"name=ref_epoch, type=DOUBLE, repetitiontype=OPTIONAL"`
+}
+
+type XwaveDetection struct {
+	ObjectID string  `json:"object_id" parquet:"name=object_id, type=BYTE_ARRAY"`
+	Mjd      float64 `json:"mjd" parquet:"name=mjd, type=DOUBLE"`
+	Band     string  `json:"band" parquet:"name=band, type=BYTE_ARRAY"`
+	Mag      float64 `json:"mag" parquet:"name=mag, type=DOUBLE"`
+	MagErr   float64 `json:"mag_err" parquet:"name=mag_err, type=DOUBLE"`
 }
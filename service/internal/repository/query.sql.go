@@ -432,8 +432,75 @@ func (q *Queries) BulkGetGaia(ctx context.Context, id []string) ([]BulkGetGaiaRo
 	return items, nil
 }
 
+const bulkGetXwaveDetections = `-- name: BulkGetXwaveDetections :many
+SELECT object_id, mjd, band, mag, mag_err
+FROM xwave_detection
+WHERE object_id IN (/*SLICE:object_id*/?)
+ORDER BY object_id, mjd
+`
+
+func (q *Queries) BulkGetXwaveDetections(ctx context.Context, objectID []string) ([]XwaveDetection, error) {
+	query := bulkGetXwaveDetections
+	var queryParams []interface{}
+	if len(objectID) > 0 {
+		for _, v := range objectID {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:object_id*/?", strings.Repeat(",?", len(objectID))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:object_id*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []XwaveDetection
+	for rows.Next() {
+		var i XwaveDetection
+		if err := rows.Scan(
+			&i.ObjectID,
+			&i.Mjd,
+			&i.Band,
+			&i.Mag,
+			&i.MagErr,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const bumpIndexVersion = `-- name: BumpIndexVersion :exec
+UPDATE index_version
+SET version = version + 1
+WHERE id = 0
+`
+
+func (q *Queries) BumpIndexVersion(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, bumpIndexVersion)
+	return err
+}
+
+const deleteJob = `-- name: DeleteJob :exec
+DELETE FROM jobs
+WHERE id = ?
+`
+
+func (q *Queries) DeleteJob(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteJob, id)
+	return err
+}
+
 const findObjects = `-- name: FindObjects :many
-SELECT id, ipix, ra, dec, cat
+SELECT id, ipix, ra, dec, cat, pos_err, pmra, pmdec, parallax, ref_epoch
 FROM mastercat 
 WHERE ipix IN (/*SLICE:ipix*/?)
 `
@@ -463,6 +530,345 @@ func (q *Queries) FindObjects(ctx context.Context, ipix []int64) ([]Mastercat, e
 			&i.Ra,
 			&i.Dec,
 			&i.Cat,
+			&i.PosErr,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.Parallax,
+			&i.RefEpoch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findObjectsBetweenPixels = `-- name: FindObjectsBetweenPixels :many
+SELECT id, ipix, ra, dec, cat, pos_err, pmra, pmdec, parallax, ref_epoch
+FROM mastercat
+WHERE ipix >= ?1 AND ipix < ?2
+`
+
+type FindObjectsBetweenPixelsParams struct {
+	Start int64 `json:"start"`
+	Stop  int64 `json:"stop"`
+}
+
+func (q *Queries) FindObjectsBetweenPixels(ctx context.Context, arg FindObjectsBetweenPixelsParams) ([]Mastercat, error) {
+	rows, err := q.db.QueryContext(ctx, findObjectsBetweenPixels, arg.Start, arg.Stop)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Mastercat
+	for rows.Next() {
+		var i Mastercat
+		if err := rows.Scan(
+			&i.ID,
+			&i.Ipix,
+			&i.Ra,
+			&i.Dec,
+			&i.Cat,
+			&i.PosErr,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.Parallax,
+			&i.RefEpoch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findObjectsById = `-- name: FindObjectsById :many
+SELECT id, ipix, ra, dec, cat, pos_err, pmra, pmdec, parallax, ref_epoch
+FROM mastercat
+WHERE id = ?
+`
+
+func (q *Queries) FindObjectsById(ctx context.Context, id string) ([]Mastercat, error) {
+	rows, err := q.db.QueryContext(ctx, findObjectsById, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Mastercat
+	for rows.Next() {
+		var i Mastercat
+		if err := rows.Scan(
+			&i.ID,
+			&i.Ipix,
+			&i.Ra,
+			&i.Dec,
+			&i.Cat,
+			&i.PosErr,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.Parallax,
+			&i.RefEpoch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findObjectsInPixelRange = `-- name: FindObjectsInPixelRange :many
+SELECT id, ipix, ra, dec, cat, pos_err, pmra, pmdec, parallax, ref_epoch
+FROM mastercat
+WHERE ipix >= ?1 AND ipix < ?2
+AND (ipix, cat, id) > (?3, ?4, ?5)
+ORDER BY ipix, cat, id
+LIMIT ?6
+`
+
+type FindObjectsInPixelRangeParams struct {
+	Start     int64  `json:"start"`
+	Stop      int64  `json:"stop"`
+	AfterIpix int64  `json:"after_ipix"`
+	AfterCat  string `json:"after_cat"`
+	AfterID   string `json:"after_id"`
+	Limit     int64  `json:"limit"`
+}
+
+func (q *Queries) FindObjectsInPixelRange(ctx context.Context, arg FindObjectsInPixelRangeParams) ([]Mastercat, error) {
+	rows, err := q.db.QueryContext(ctx, findObjectsInPixelRange,
+		arg.Start,
+		arg.Stop,
+		arg.AfterIpix,
+		arg.AfterCat,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Mastercat
+	for rows.Next() {
+		var i Mastercat
+		if err := rows.Scan(
+			&i.ID,
+			&i.Ipix,
+			&i.Ra,
+			&i.Dec,
+			&i.Cat,
+			&i.PosErr,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.Parallax,
+			&i.RefEpoch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findObjectsOfCatalogs = `-- name: FindObjectsOfCatalogs :many
+SELECT id, ipix, ra, dec, cat, pos_err, pmra, pmdec, parallax, ref_epoch
+FROM mastercat
+WHERE ipix IN (/*SLICE:ipix*/?) AND cat IN (/*SLICE:cat*/?)
+`
+
+type FindObjectsOfCatalogsParams struct {
+	Ipix []int64  `json:"ipix"`
+	Cat  []string `json:"cat"`
+}
+
+func (q *Queries) FindObjectsOfCatalogs(ctx context.Context, arg FindObjectsOfCatalogsParams) ([]Mastercat, error) {
+	query := findObjectsOfCatalogs
+	var queryParams []interface{}
+	if len(arg.Ipix) > 0 {
+		for _, v := range arg.Ipix {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ipix*/?", strings.Repeat(",?", len(arg.Ipix))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ipix*/?", "NULL", 1)
+	}
+	if len(arg.Cat) > 0 {
+		for _, v := range arg.Cat {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:cat*/?", strings.Repeat(",?", len(arg.Cat))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:cat*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Mastercat
+	for rows.Next() {
+		var i Mastercat
+		if err := rows.Scan(
+			&i.ID,
+			&i.Ipix,
+			&i.Ra,
+			&i.Dec,
+			&i.Cat,
+			&i.PosErr,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.Parallax,
+			&i.RefEpoch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findObjectsOfCatalogsBetweenPixels = `-- name: FindObjectsOfCatalogsBetweenPixels :many
+SELECT id, ipix, ra, dec, cat, pos_err, pmra, pmdec, parallax, ref_epoch
+FROM mastercat
+WHERE ipix >= ? AND ipix < ? AND cat IN (/*SLICE:cat*/?)
+`
+
+type FindObjectsOfCatalogsBetweenPixelsParams struct {
+	Start int64    `json:"start"`
+	Stop  int64    `json:"stop"`
+	Cat   []string `json:"cat"`
+}
+
+func (q *Queries) FindObjectsOfCatalogsBetweenPixels(ctx context.Context, arg FindObjectsOfCatalogsBetweenPixelsParams) ([]Mastercat, error) {
+	query := findObjectsOfCatalogsBetweenPixels
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.Start)
+	queryParams = append(queryParams, arg.Stop)
+	if len(arg.Cat) > 0 {
+		for _, v := range arg.Cat {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:cat*/?", strings.Repeat(",?", len(arg.Cat))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:cat*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Mastercat
+	for rows.Next() {
+		var i Mastercat
+		if err := rows.Scan(
+			&i.ID,
+			&i.Ipix,
+			&i.Ra,
+			&i.Dec,
+			&i.Cat,
+			&i.PosErr,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.Parallax,
+			&i.RefEpoch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findObjectsOfCatalogsInPixelRange = `-- name: FindObjectsOfCatalogsInPixelRange :many
+SELECT id, ipix, ra, dec, cat, pos_err, pmra, pmdec, parallax, ref_epoch
+FROM mastercat
+WHERE ipix >= ? AND ipix < ? AND cat IN (/*SLICE:cat*/?)
+AND (ipix, cat, id) > (?, ?, ?)
+ORDER BY ipix, cat, id
+LIMIT ?
+`
+
+type FindObjectsOfCatalogsInPixelRangeParams struct {
+	Start     int64    `json:"start"`
+	Stop      int64    `json:"stop"`
+	Cat       []string `json:"cat"`
+	AfterIpix int64    `json:"after_ipix"`
+	AfterCat  string   `json:"after_cat"`
+	AfterID   string   `json:"after_id"`
+	Limit     int64    `json:"limit"`
+}
+
+func (q *Queries) FindObjectsOfCatalogsInPixelRange(ctx context.Context, arg FindObjectsOfCatalogsInPixelRangeParams) ([]Mastercat, error) {
+	query := findObjectsOfCatalogsInPixelRange
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.Start)
+	queryParams = append(queryParams, arg.Stop)
+	if len(arg.Cat) > 0 {
+		for _, v := range arg.Cat {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:cat*/?", strings.Repeat(",?", len(arg.Cat))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:cat*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.AfterIpix)
+	queryParams = append(queryParams, arg.AfterCat)
+	queryParams = append(queryParams, arg.AfterID)
+	queryParams = append(queryParams, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Mastercat
+	for rows.Next() {
+		var i Mastercat
+		if err := rows.Scan(
+			&i.ID,
+			&i.Ipix,
+			&i.Ra,
+			&i.Dec,
+			&i.Cat,
+			&i.PosErr,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.Parallax,
+			&i.RefEpoch,
 		); err != nil {
 			return nil, err
 		}
@@ -477,8 +883,31 @@ func (q *Queries) FindObjects(ctx context.Context, ipix []int64) ([]Mastercat, e
 	return items, nil
 }
 
+const finishJob = `-- name: FinishJob :exec
+UPDATE jobs
+SET phase = ?, error = ?, finished_at = ?
+WHERE id = ?
+`
+
+type FinishJobParams struct {
+	Phase      string
+	Error      NullString
+	FinishedAt NullInt64
+	ID         string
+}
+
+func (q *Queries) FinishJob(ctx context.Context, arg FinishJobParams) error {
+	_, err := q.db.ExecContext(ctx, finishJob,
+		arg.Phase,
+		arg.Error,
+		arg.FinishedAt,
+		arg.ID,
+	)
+	return err
+}
+
 const getAllObjects = `-- name: GetAllObjects :many
-SELECT id, ipix, ra, dec, cat
+SELECT id, ipix, ra, dec, cat, pos_err, pmra, pmdec, parallax, ref_epoch
 FROM mastercat
 `
 
@@ -497,6 +926,11 @@ func (q *Queries) GetAllObjects(ctx context.Context) ([]Mastercat, error) {
 			&i.Ra,
 			&i.Dec,
 			&i.Cat,
+			&i.PosErr,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.Parallax,
+			&i.RefEpoch,
 		); err != nil {
 			return nil, err
 		}
@@ -565,8 +999,91 @@ func (q *Queries) GetAllwise(ctx context.Context, id string) (GetAllwiseRow, err
 	return i, err
 }
 
+const getAllwiseBetweenPixels = `-- name: GetAllwiseBetweenPixels :many
+SELECT allwise.id, allwise.cntr, allwise.w1mpro, allwise.w1sigmpro, allwise.w2mpro, allwise.w2sigmpro, allwise.w3mpro, allwise.w3sigmpro, allwise.w4mpro, allwise.w4sigmpro, allwise.j_m_2mass, allwise.j_msig_2mass, allwise.h_m_2mass, allwise.h_msig_2mass, allwise.k_m_2mass, allwise.k_msig_2mass, mastercat.ra, mastercat.dec, mastercat.pos_err, mastercat.pmra, mastercat.pmdec, mastercat.ref_epoch
+FROM allwise
+JOIN mastercat ON mastercat.id = allwise.id
+WHERE mastercat.ipix >= ?1 AND mastercat.ipix < ?2
+`
+
+type GetAllwiseBetweenPixelsRow struct {
+	ID         string      `json:"id" parquet:"name=source_id, type=BYTE_ARRAY"`
+	Cntr       int64       `json:"cntr" parquet:"name=cntr, type=INT64"`
+	W1mpro     NullFloat64 `json:"w1mpro" parquet:"name=w1mpro, type=DOUBLE"`
+	W1sigmpro  NullFloat64 `json:"w1sigmpro" parquet:"name=w1sigmpro, type=DOUBLE"`
+	W2mpro     NullFloat64 `json:"w2mpro" parquet:"name=w2mpro, type=DOUBLE"`
+	W2sigmpro  NullFloat64 `json:"w2sigmpro" parquet:"name=w2sigmpro, type=DOUBLE"`
+	W3mpro     NullFloat64 `json:"w3mpro" parquet:"name=w3mpro, type=DOUBLE"`
+	W3sigmpro  NullFloat64 `json:"w3sigmpro" parquet:"name=w3sigmpro, type=DOUBLE"`
+	W4mpro     NullFloat64 `json:"w4mpro" parquet:"name=w4mpro, type=DOUBLE"`
+	W4sigmpro  NullFloat64 `json:"w4sigmpro" parquet:"name=w4sigmpro, type=DOUBLE"`
+	JM2mass    NullFloat64 `json:"j_m_2mass" parquet:"name=j_m_2mass, type=DOUBLE"`
+	JMsig2mass NullFloat64 `json:"j_msig_2mass" parquet:"name=j_msig_2mass, type=DOUBLE"`
+	HM2mass    NullFloat64 `json:"h_m_2mass" parquet:"name=h_m_2mass, type=DOUBLE"`
+	HMsig2mass NullFloat64 `json:"h_msig_2mass" parquet:"name=h_msig_2mass, type=DOUBLE"`
+	KM2mass    NullFloat64 `json:"k_m_2mass" parquet:"name=k_m_2mass, type=DOUBLE"`
+	KMsig2mass NullFloat64 `json:"k_msig_2mass" parquet:"name=k_msig_2mass, type=DOUBLE"`
+	Ra         float64     `json:"ra" parquet:"name=ra, type=DOUBLE"`
+	Dec        float64     `json:"dec" parquet:"name=dec, type=DOUBLE"`
+	PosErr     *float64    `json:"pos_err,omitempty" parquet:"name=pos_err, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmra       *float64    `json:"pmra,omitempty" parquet:"name=pmra, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmdec      *float64    `json:"pmdec,omitempty" parquet:"name=pmdec, type=DOUBLE, repetitiontype=OPTIONAL"`
+	RefEpoch   *float64    `json:"ref_epoch,omitempty" parquet:"name=ref_epoch, type=DOUBLE, repetitiontype=OPTIONAL"`
+}
+
+type GetAllwiseBetweenPixelsParams struct {
+	Start int64 `json:"start"`
+	Stop  int64 `json:"stop"`
+}
+
+func (q *Queries) GetAllwiseBetweenPixels(ctx context.Context, arg GetAllwiseBetweenPixelsParams) ([]GetAllwiseBetweenPixelsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllwiseBetweenPixels, arg.Start, arg.Stop)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllwiseBetweenPixelsRow
+	for rows.Next() {
+		var i GetAllwiseBetweenPixelsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Cntr,
+			&i.W1mpro,
+			&i.W1sigmpro,
+			&i.W2mpro,
+			&i.W2sigmpro,
+			&i.W3mpro,
+			&i.W3sigmpro,
+			&i.W4mpro,
+			&i.W4sigmpro,
+			&i.JM2mass,
+			&i.JMsig2mass,
+			&i.HM2mass,
+			&i.HMsig2mass,
+			&i.KM2mass,
+			&i.KMsig2mass,
+			&i.Ra,
+			&i.Dec,
+			&i.PosErr,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.RefEpoch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAllwiseFromPixels = `-- name: GetAllwiseFromPixels :many
-SELECT allwise.id, allwise.cntr, allwise.w1mpro, allwise.w1sigmpro, allwise.w2mpro, allwise.w2sigmpro, allwise.w3mpro, allwise.w3sigmpro, allwise.w4mpro, allwise.w4sigmpro, allwise.j_m_2mass, allwise.j_msig_2mass, allwise.h_m_2mass, allwise.h_msig_2mass, allwise.k_m_2mass, allwise.k_msig_2mass, mastercat.ra, mastercat.dec
+SELECT allwise.id, allwise.cntr, allwise.w1mpro, allwise.w1sigmpro, allwise.w2mpro, allwise.w2sigmpro, allwise.w3mpro, allwise.w3sigmpro, allwise.w4mpro, allwise.w4sigmpro, allwise.j_m_2mass, allwise.j_msig_2mass, allwise.h_m_2mass, allwise.h_msig_2mass, allwise.k_m_2mass, allwise.k_msig_2mass, mastercat.ra, mastercat.dec, mastercat.pos_err, mastercat.pmra, mastercat.pmdec, mastercat.ref_epoch
 FROM allwise 
 JOIN mastercat ON mastercat.id = allwise.id
 WHERE mastercat.ipix IN (/*SLICE:ipix*/?)
@@ -591,6 +1108,10 @@ type GetAllwiseFromPixelsRow struct {
 	KMsig2mass NullFloat64 `json:"k_msig_2mass" parquet:"name=k_msig_2mass, type=DOUBLE"`
 	Ra         float64     `json:"ra" parquet:"name=ra, type=DOUBLE"`
 	Dec        float64     `json:"dec" parquet:"name=dec, type=DOUBLE"`
+	PosErr     *float64    `json:"pos_err,omitempty" parquet:"name=pos_err, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmra       *float64    `json:"pmra,omitempty" parquet:"name=pmra, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmdec      *float64    `json:"pmdec,omitempty" parquet:"name=pmdec, type=DOUBLE, repetitiontype=OPTIONAL"`
+	RefEpoch   *float64    `json:"ref_epoch,omitempty" parquet:"name=ref_epoch, type=DOUBLE, repetitiontype=OPTIONAL"`
 }
 
 func (q *Queries) GetAllwiseFromPixels(ctx context.Context, ipix []int64) ([]GetAllwiseFromPixelsRow, error) {
@@ -631,6 +1152,10 @@ func (q *Queries) GetAllwiseFromPixels(ctx context.Context, ipix []int64) ([]Get
 			&i.KMsig2mass,
 			&i.Ra,
 			&i.Dec,
+			&i.PosErr,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.RefEpoch,
 		); err != nil {
 			return nil, err
 		}
@@ -921,8 +1446,285 @@ func (q *Queries) GetErosita(ctx context.Context, id string) (GetErositaRow, err
 	return i, err
 }
 
+const getErositaBetweenPixels = `-- name: GetErositaBetweenPixels :many
+SELECT erosita.id, erosita.detuid, erosita.skytile, erosita.id_src, erosita.uid, erosita.uid_hard, erosita.id_cluster, erosita.ra, erosita.dec, erosita.ra_lowerr, erosita.ra_uperr, erosita.dec_lowerr, erosita.dec_uperr, erosita.pos_err, erosita.mjd, erosita.mjd_min, erosita.mjd_max, erosita.ext, erosita.ext_err, erosita.ext_like, erosita.det_like_0, erosita.ml_cts_1, erosita.ml_cts_err_1, erosita.ml_rate_1, erosita.ml_rate_err_1, erosita.ml_flux_1, erosita.ml_flux_err_1, erosita.ml_bkg_1, erosita.ml_exp_1, erosita.ape_bkg_1, erosita.ape_radius_1, erosita.ape_pois_1, erosita.det_like_p1, erosita.ml_cts_p1, erosita.ml_cts_err_p1, erosita.ml_rate_p1, erosita.ml_rate_err_p1, erosita.ml_flux_p1, erosita.ml_flux_err_p1, erosita.ml_bkg_p1, erosita.ml_exp_p1, erosita.ape_bkg_p1, erosita.ape_radius_p1, erosita.ape_pois_p1, erosita.det_like_p2, erosita.ml_cts_p2, erosita.ml_cts_err_p2, erosita.ml_rate_p2, erosita.ml_rate_err_p2, erosita.ml_flux_p2, erosita.ml_flux_err_p2, erosita.ml_bkg_p2, erosita.ml_exp_p2, erosita.ape_bkg_p2, erosita.ape_radius_p2, erosita.ape_pois_p2, erosita.det_like_p3, erosita.ml_cts_p3, erosita.ml_cts_err_p3, erosita.ml_rate_p3, erosita.ml_rate_err_p3, erosita.ml_flux_p3, erosita.ml_flux_err_p3, erosita.ml_bkg_p3, erosita.ml_exp_p3, erosita.ape_bkg_p3, erosita.ape_radius_p3, erosita.ape_pois_p3, erosita.det_like_p4, erosita.ml_cts_p4, erosita.ml_cts_err_p4, erosita.ml_rate_p4, erosita.ml_rate_err_p4, erosita.ml_flux_p4, erosita.ml_flux_err_p4, erosita.ml_bkg_p4, erosita.ml_exp_p4, erosita.ape_bkg_p4, erosita.ape_radius_p4, erosita.ape_pois_p4, erosita.det_like_p5, erosita.ml_cts_p5, erosita.ml_cts_err_p5, erosita.ml_rate_p5, erosita.ml_rate_err_p5, erosita.ml_flux_p5, erosita.ml_flux_err_p5, erosita.ml_bkg_p5, erosita.ml_exp_p5, erosita.ape_bkg_p5, erosita.ape_radius_p5, erosita.ape_pois_p5, erosita.det_like_p6, erosita.ml_cts_p6, erosita.ml_cts_err_p6, erosita.ml_rate_p6, erosita.ml_rate_err_p6, erosita.ml_flux_p6, erosita.ml_flux_err_p6, erosita.ml_bkg_p6, erosita.ml_exp_p6, erosita.ape_bkg_p6, erosita.ape_radius_p6, erosita.ape_pois_p6, erosita.flag_sp_snr, erosita.flag_sp_bps, erosita.flag_sp_scl, erosita.flag_sp_lga, erosita.flag_sp_gc_cons, erosita.flag_no_radec_err, erosita.flag_no_ext_err, erosita.flag_no_cts_err, erosita.flag_opt, mastercat.ra, mastercat.dec, mastercat.pos_err, mastercat.pmra, mastercat.pmdec, mastercat.ref_epoch
+FROM erosita
+JOIN mastercat ON mastercat.id = erosita.id
+WHERE mastercat.ipix >= ?1 AND mastercat.ipix < ?2
+`
+
+type GetErositaBetweenPixelsRow struct {
+	ID             string      `json:"id" parquet:"name=IAUNAME, type=BYTE_ARRAY"`
+	Detuid         NullString  `parquet:"name=DETUID, type=BYTE_ARRAY"`
+	Skytile        NullInt64   `parquet:"name=SKYTILE, type=INT32"`
+	IDSrc          NullInt64   `parquet:"name=ID_SRC, type=INT32"`
+	Uid            NullInt64   `parquet:"name=UID, type=INT64"`
+	UidHard        NullInt64   `parquet:"name=UID_Hard, type=INT64"`
+	IDCluster      NullInt64   `parquet:"name=ID_CLUSTER, type=INT32"`
+	Ra             NullFloat64 `parquet:"name=RA, type=DOUBLE"`
+	Dec            NullFloat64 `parquet:"name=DEC, type=DOUBLE"`
+	RaLowerr       NullFloat64 `parquet:"name=RA_LOWERR, type=FLOAT"`
+	RaUperr        NullFloat64 `parquet:"name=RA_UPERR, type=FLOAT"`
+	DecLowerr      NullFloat64 `parquet:"name=DEC_LOWERR, type=FLOAT"`
+	DecUperr       NullFloat64 `parquet:"name=DEC_UPERR, type=FLOAT"`
+	PosErr         NullFloat64 `parquet:"name=POS_ERR, type=FLOAT"`
+	Mjd            NullFloat64 `json:"mjd" parquet:"name=MJD, type=FLOAT"`
+	MjdMin         NullFloat64 `parquet:"name=MJD_MIN, type=FLOAT"`
+	MjdMax         NullFloat64 `parquet:"name=MJD_MAX, type=FLOAT"`
+	Ext            NullFloat64 `parquet:"name=EXT, type=FLOAT"`
+	ExtErr         NullFloat64 `parquet:"name=EXT_ERR, type=FLOAT"`
+	ExtLike        NullFloat64 `parquet:"name=EXT_LIKE, type=FLOAT"`
+	DetLike0       NullFloat64 `parquet:"name=DET_LIKE_0, type=FLOAT"`
+	MlCts1         NullFloat64 `parquet:"name=ML_CTS_1, type=FLOAT"`
+	MlCtsErr1      NullFloat64 `parquet:"name=ML_CTS_ERR_1, type=FLOAT"`
+	MlRate1        NullFloat64 `parquet:"name=ML_RATE_1, type=FLOAT"`
+	MlRateErr1     NullFloat64 `parquet:"name=ML_RATE_ERR_1, type=FLOAT"`
+	MlFlux1        NullFloat64 `json:"ml_flux_1" parquet:"name=ML_FLUX_1, type=FLOAT"`
+	MlFluxErr1     NullFloat64 `parquet:"name=ML_FLUX_ERR_1, type=FLOAT"`
+	MlBkg1         NullFloat64 `parquet:"name=ML_BKG_1, type=FLOAT"`
+	MlExp1         NullFloat64 `parquet:"name=ML_EXP_1, type=FLOAT"`
+	ApeBkg1        NullFloat64 `parquet:"name=APE_BKG_1, type=FLOAT"`
+	ApeRadius1     NullFloat64 `parquet:"name=APE_RADIUS_1, type=FLOAT"`
+	ApePois1       NullFloat64 `parquet:"name=APE_POIS_1, type=FLOAT"`
+	DetLikeP1      NullFloat64 `parquet:"name=DET_LIKE_P1, type=FLOAT"`
+	MlCtsP1        NullFloat64 `parquet:"name=ML_CTS_P1, type=FLOAT"`
+	MlCtsErrP1     NullFloat64 `parquet:"name=ML_CTS_ERR_P1, type=FLOAT"`
+	MlRateP1       NullFloat64 `parquet:"name=ML_RATE_P1, type=FLOAT"`
+	MlRateErrP1    NullFloat64 `parquet:"name=ML_RATE_ERR_P1, type=FLOAT"`
+	MlFluxP1       NullFloat64 `parquet:"name=ML_FLUX_P1, type=FLOAT"`
+	MlFluxErrP1    NullFloat64 `parquet:"name=ML_FLUX_ERR_P1, type=FLOAT"`
+	MlBkgP1        NullFloat64 `parquet:"name=ML_BKG_P1, type=FLOAT"`
+	MlExpP1        NullFloat64 `parquet:"name=ML_EXP_P1, type=FLOAT"`
+	ApeBkgP1       NullFloat64 `parquet:"name=APE_BKG_P1, type=FLOAT"`
+	ApeRadiusP1    NullFloat64 `parquet:"name=APE_RADIUS_P1, type=FLOAT"`
+	ApePoisP1      NullFloat64 `parquet:"name=APE_POIS_P1, type=FLOAT"`
+	DetLikeP2      NullFloat64 `parquet:"name=DET_LIKE_P2, type=FLOAT"`
+	MlCtsP2        NullFloat64 `parquet:"name=ML_CTS_P2, type=FLOAT"`
+	MlCtsErrP2     NullFloat64 `parquet:"name=ML_CTS_ERR_P2, type=FLOAT"`
+	MlRateP2       NullFloat64 `parquet:"name=ML_RATE_P2, type=FLOAT"`
+	MlRateErrP2    NullFloat64 `parquet:"name=ML_RATE_ERR_P2, type=FLOAT"`
+	MlFluxP2       NullFloat64 `parquet:"name=ML_FLUX_P2, type=FLOAT"`
+	MlFluxErrP2    NullFloat64 `parquet:"name=ML_FLUX_ERR_P2, type=FLOAT"`
+	MlBkgP2        NullFloat64 `parquet:"name=ML_BKG_P2, type=FLOAT"`
+	MlExpP2        NullFloat64 `parquet:"name=ML_EXP_P2, type=FLOAT"`
+	ApeBkgP2       NullFloat64 `parquet:"name=APE_BKG_P2, type=FLOAT"`
+	ApeRadiusP2    NullFloat64 `parquet:"name=APE_RADIUS_P2, type=FLOAT"`
+	ApePoisP2      NullFloat64 `parquet:"name=APE_POIS_P2, type=FLOAT"`
+	DetLikeP3      NullFloat64 `parquet:"name=DET_LIKE_P3, type=FLOAT"`
+	MlCtsP3        NullFloat64 `parquet:"name=ML_CTS_P3, type=FLOAT"`
+	MlCtsErrP3     NullFloat64 `parquet:"name=ML_CTS_ERR_P3, type=FLOAT"`
+	MlRateP3       NullFloat64 `parquet:"name=ML_RATE_P3, type=FLOAT"`
+	MlRateErrP3    NullFloat64 `parquet:"name=ML_RATE_ERR_P3, type=FLOAT"`
+	MlFluxP3       NullFloat64 `parquet:"name=ML_FLUX_P3, type=FLOAT"`
+	MlFluxErrP3    NullFloat64 `parquet:"name=ML_FLUX_ERR_P3, type=FLOAT"`
+	MlBkgP3        NullFloat64 `parquet:"name=ML_BKG_P3, type=FLOAT"`
+	MlExpP3        NullFloat64 `parquet:"name=ML_EXP_P3, type=FLOAT"`
+	ApeBkgP3       NullFloat64 `parquet:"name=APE_BKG_P3, type=FLOAT"`
+	ApeRadiusP3    NullFloat64 `parquet:"name=APE_RADIUS_P3, type=FLOAT"`
+	ApePoisP3      NullFloat64 `parquet:"name=APE_POIS_P3, type=FLOAT"`
+	DetLikeP4      NullFloat64 `parquet:"name=DET_LIKE_P4, type=FLOAT"`
+	MlCtsP4        NullFloat64 `parquet:"name=ML_CTS_P4, type=FLOAT"`
+	MlCtsErrP4     NullFloat64 `parquet:"name=ML_CTS_ERR_P4, type=FLOAT"`
+	MlRateP4       NullFloat64 `parquet:"name=ML_RATE_P4, type=FLOAT"`
+	MlRateErrP4    NullFloat64 `parquet:"name=ML_RATE_ERR_P4, type=FLOAT"`
+	MlFluxP4       NullFloat64 `parquet:"name=ML_FLUX_P4, type=FLOAT"`
+	MlFluxErrP4    NullFloat64 `parquet:"name=ML_FLUX_ERR_P4, type=FLOAT"`
+	MlBkgP4        NullFloat64 `parquet:"name=ML_BKG_P4, type=FLOAT"`
+	MlExpP4        NullFloat64 `parquet:"name=ML_EXP_P4, type=FLOAT"`
+	ApeBkgP4       NullFloat64 `parquet:"name=APE_BKG_P4, type=FLOAT"`
+	ApeRadiusP4    NullFloat64 `parquet:"name=APE_RADIUS_P4, type=FLOAT"`
+	ApePoisP4      NullFloat64 `parquet:"name=APE_POIS_P4, type=FLOAT"`
+	DetLikeP5      NullFloat64 `parquet:"name=DET_LIKE_P5, type=FLOAT"`
+	MlCtsP5        NullFloat64 `parquet:"name=ML_CTS_P5, type=FLOAT"`
+	MlCtsErrP5     NullFloat64 `parquet:"name=ML_CTS_ERR_P5, type=FLOAT"`
+	MlRateP5       NullFloat64 `parquet:"name=ML_RATE_P5, type=FLOAT"`
+	MlRateErrP5    NullFloat64 `parquet:"name=ML_RATE_ERR_P5, type=FLOAT"`
+	MlFluxP5       NullFloat64 `parquet:"name=ML_FLUX_P5, type=FLOAT"`
+	MlFluxErrP5    NullFloat64 `parquet:"name=ML_FLUX_ERR_P5, type=FLOAT"`
+	MlBkgP5        NullFloat64 `parquet:"name=ML_BKG_P5, type=FLOAT"`
+	MlExpP5        NullFloat64 `parquet:"name=ML_EXP_P5, type=FLOAT"`
+	ApeBkgP5       NullFloat64 `parquet:"name=APE_BKG_P5, type=FLOAT"`
+	ApeRadiusP5    NullFloat64 `parquet:"name=APE_RADIUS_P5, type=FLOAT"`
+	ApePoisP5      NullFloat64 `parquet:"name=APE_POIS_P5, type=FLOAT"`
+	DetLikeP6      NullFloat64 `parquet:"name=DET_LIKE_P6, type=FLOAT"`
+	MlCtsP6        NullFloat64 `parquet:"name=ML_CTS_P6, type=FLOAT"`
+	MlCtsErrP6     NullFloat64 `parquet:"name=ML_CTS_ERR_P6, type=FLOAT"`
+	MlRateP6       NullFloat64 `parquet:"name=ML_RATE_P6, type=FLOAT"`
+	MlRateErrP6    NullFloat64 `parquet:"name=ML_RATE_ERR_P6, type=FLOAT"`
+	MlFluxP6       NullFloat64 `parquet:"name=ML_FLUX_P6, type=FLOAT"`
+	MlFluxErrP6    NullFloat64 `parquet:"name=ML_FLUX_ERR_P6, type=FLOAT"`
+	MlBkgP6        NullFloat64 `parquet:"name=ML_BKG_P6, type=FLOAT"`
+	MlExpP6        NullFloat64 `parquet:"name=ML_EXP_P6, type=FLOAT"`
+	ApeBkgP6       NullFloat64 `parquet:"name=APE_BKG_P6, type=FLOAT"`
+	ApeRadiusP6    NullFloat64 `parquet:"name=APE_RADIUS_P6, type=FLOAT"`
+	ApePoisP6      NullFloat64 `parquet:"name=APE_POIS_P6, type=FLOAT"`
+	FlagSpSnr      NullInt64   `parquet:"name=FLAG_SP_SNR, type=INT32"`
+	FlagSpBps      NullInt64   `parquet:"name=FLAG_SP_BPS, type=INT32"`
+	FlagSpScl      NullInt64   `parquet:"name=FLAG_SP_SCL, type=INT32"`
+	FlagSpLga      NullInt64   `parquet:"name=FLAG_SP_LGA, type=INT32"`
+	FlagSpGcCons   NullInt64   `parquet:"name=FLAG_SP_GC_CONS, type=INT32"`
+	FlagNoRadecErr NullInt64   `parquet:"name=FLAG_NO_RADEC_ERR, type=INT32"`
+	FlagNoExtErr   NullInt64   `parquet:"name=FLAG_NO_EXT_ERR, type=INT32"`
+	FlagNoCtsErr   NullInt64   `parquet:"name=FLAG_NO_CTS_ERR, type=INT32"`
+	FlagOpt        NullInt64   `parquet:"name=FLAG_OPT, type=INT32"`
+	Ra_2           float64     `json:"ra" parquet:"name=ra, type=DOUBLE"`
+	Dec_2          float64     `json:"dec" parquet:"name=dec, type=DOUBLE"`
+	PosErr_2       *float64    `json:"pos_err,omitempty" parquet:"name=pos_err, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmra           *float64    `json:"pmra,omitempty" parquet:"name=pmra, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmdec          *float64    `json:"pmdec,omitempty" parquet:"name=pmdec, type=DOUBLE, repetitiontype=OPTIONAL"`
+	RefEpoch       *float64    `json:"ref_epoch,omitempty" parquet:"name=ref_epoch, type=DOUBLE, repetitiontype=OPTIONAL"`
+}
+
+type GetErositaBetweenPixelsParams struct {
+	Start int64 `json:"start"`
+	Stop  int64 `json:"stop"`
+}
+
+func (q *Queries) GetErositaBetweenPixels(ctx context.Context, arg GetErositaBetweenPixelsParams) ([]GetErositaBetweenPixelsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getErositaBetweenPixels, arg.Start, arg.Stop)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetErositaBetweenPixelsRow
+	for rows.Next() {
+		var i GetErositaBetweenPixelsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Detuid,
+			&i.Skytile,
+			&i.IDSrc,
+			&i.Uid,
+			&i.UidHard,
+			&i.IDCluster,
+			&i.Ra,
+			&i.Dec,
+			&i.RaLowerr,
+			&i.RaUperr,
+			&i.DecLowerr,
+			&i.DecUperr,
+			&i.PosErr,
+			&i.Mjd,
+			&i.MjdMin,
+			&i.MjdMax,
+			&i.Ext,
+			&i.ExtErr,
+			&i.ExtLike,
+			&i.DetLike0,
+			&i.MlCts1,
+			&i.MlCtsErr1,
+			&i.MlRate1,
+			&i.MlRateErr1,
+			&i.MlFlux1,
+			&i.MlFluxErr1,
+			&i.MlBkg1,
+			&i.MlExp1,
+			&i.ApeBkg1,
+			&i.ApeRadius1,
+			&i.ApePois1,
+			&i.DetLikeP1,
+			&i.MlCtsP1,
+			&i.MlCtsErrP1,
+			&i.MlRateP1,
+			&i.MlRateErrP1,
+			&i.MlFluxP1,
+			&i.MlFluxErrP1,
+			&i.MlBkgP1,
+			&i.MlExpP1,
+			&i.ApeBkgP1,
+			&i.ApeRadiusP1,
+			&i.ApePoisP1,
+			&i.DetLikeP2,
+			&i.MlCtsP2,
+			&i.MlCtsErrP2,
+			&i.MlRateP2,
+			&i.MlRateErrP2,
+			&i.MlFluxP2,
+			&i.MlFluxErrP2,
+			&i.MlBkgP2,
+			&i.MlExpP2,
+			&i.ApeBkgP2,
+			&i.ApeRadiusP2,
+			&i.ApePoisP2,
+			&i.DetLikeP3,
+			&i.MlCtsP3,
+			&i.MlCtsErrP3,
+			&i.MlRateP3,
+			&i.MlRateErrP3,
+			&i.MlFluxP3,
+			&i.MlFluxErrP3,
+			&i.MlBkgP3,
+			&i.MlExpP3,
+			&i.ApeBkgP3,
+			&i.ApeRadiusP3,
+			&i.ApePoisP3,
+			&i.DetLikeP4,
+			&i.MlCtsP4,
+			&i.MlCtsErrP4,
+			&i.MlRateP4,
+			&i.MlRateErrP4,
+			&i.MlFluxP4,
+			&i.MlFluxErrP4,
+			&i.MlBkgP4,
+			&i.MlExpP4,
+			&i.ApeBkgP4,
+			&i.ApeRadiusP4,
+			&i.ApePoisP4,
+			&i.DetLikeP5,
+			&i.MlCtsP5,
+			&i.MlCtsErrP5,
+			&i.MlRateP5,
+			&i.MlRateErrP5,
+			&i.MlFluxP5,
+			&i.MlFluxErrP5,
+			&i.MlBkgP5,
+			&i.MlExpP5,
+			&i.ApeBkgP5,
+			&i.ApeRadiusP5,
+			&i.ApePoisP5,
+			&i.DetLikeP6,
+			&i.MlCtsP6,
+			&i.MlCtsErrP6,
+			&i.MlRateP6,
+			&i.MlRateErrP6,
+			&i.MlFluxP6,
+			&i.MlFluxErrP6,
+			&i.MlBkgP6,
+			&i.MlExpP6,
+			&i.ApeBkgP6,
+			&i.ApeRadiusP6,
+			&i.ApePoisP6,
+			&i.FlagSpSnr,
+			&i.FlagSpBps,
+			&i.FlagSpScl,
+			&i.FlagSpLga,
+			&i.FlagSpGcCons,
+			&i.FlagNoRadecErr,
+			&i.FlagNoExtErr,
+			&i.FlagNoCtsErr,
+			&i.FlagOpt,
+			&i.Ra_2,
+			&i.Dec_2,
+			&i.PosErr_2,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.RefEpoch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getErositaFromPixels = `-- name: GetErositaFromPixels :many
-SELECT erosita.id, erosita.detuid, erosita.skytile, erosita.id_src, erosita.uid, erosita.uid_hard, erosita.id_cluster, erosita.ra, erosita.dec, erosita.ra_lowerr, erosita.ra_uperr, erosita.dec_lowerr, erosita.dec_uperr, erosita.pos_err, erosita.mjd, erosita.mjd_min, erosita.mjd_max, erosita.ext, erosita.ext_err, erosita.ext_like, erosita.det_like_0, erosita.ml_cts_1, erosita.ml_cts_err_1, erosita.ml_rate_1, erosita.ml_rate_err_1, erosita.ml_flux_1, erosita.ml_flux_err_1, erosita.ml_bkg_1, erosita.ml_exp_1, erosita.ape_bkg_1, erosita.ape_radius_1, erosita.ape_pois_1, erosita.det_like_p1, erosita.ml_cts_p1, erosita.ml_cts_err_p1, erosita.ml_rate_p1, erosita.ml_rate_err_p1, erosita.ml_flux_p1, erosita.ml_flux_err_p1, erosita.ml_bkg_p1, erosita.ml_exp_p1, erosita.ape_bkg_p1, erosita.ape_radius_p1, erosita.ape_pois_p1, erosita.det_like_p2, erosita.ml_cts_p2, erosita.ml_cts_err_p2, erosita.ml_rate_p2, erosita.ml_rate_err_p2, erosita.ml_flux_p2, erosita.ml_flux_err_p2, erosita.ml_bkg_p2, erosita.ml_exp_p2, erosita.ape_bkg_p2, erosita.ape_radius_p2, erosita.ape_pois_p2, erosita.det_like_p3, erosita.ml_cts_p3, erosita.ml_cts_err_p3, erosita.ml_rate_p3, erosita.ml_rate_err_p3, erosita.ml_flux_p3, erosita.ml_flux_err_p3, erosita.ml_bkg_p3, erosita.ml_exp_p3, erosita.ape_bkg_p3, erosita.ape_radius_p3, erosita.ape_pois_p3, erosita.det_like_p4, erosita.ml_cts_p4, erosita.ml_cts_err_p4, erosita.ml_rate_p4, erosita.ml_rate_err_p4, erosita.ml_flux_p4, erosita.ml_flux_err_p4, erosita.ml_bkg_p4, erosita.ml_exp_p4, erosita.ape_bkg_p4, erosita.ape_radius_p4, erosita.ape_pois_p4, erosita.det_like_p5, erosita.ml_cts_p5, erosita.ml_cts_err_p5, erosita.ml_rate_p5, erosita.ml_rate_err_p5, erosita.ml_flux_p5, erosita.ml_flux_err_p5, erosita.ml_bkg_p5, erosita.ml_exp_p5, erosita.ape_bkg_p5, erosita.ape_radius_p5, erosita.ape_pois_p5, erosita.det_like_p6, erosita.ml_cts_p6, erosita.ml_cts_err_p6, erosita.ml_rate_p6, erosita.ml_rate_err_p6, erosita.ml_flux_p6, erosita.ml_flux_err_p6, erosita.ml_bkg_p6, erosita.ml_exp_p6, erosita.ape_bkg_p6, erosita.ape_radius_p6, erosita.ape_pois_p6, erosita.flag_sp_snr, erosita.flag_sp_bps, erosita.flag_sp_scl, erosita.flag_sp_lga, erosita.flag_sp_gc_cons, erosita.flag_no_radec_err, erosita.flag_no_ext_err, erosita.flag_no_cts_err, erosita.flag_opt, mastercat.ra, mastercat.dec
+SELECT erosita.id, erosita.detuid, erosita.skytile, erosita.id_src, erosita.uid, erosita.uid_hard, erosita.id_cluster, erosita.ra, erosita.dec, erosita.ra_lowerr, erosita.ra_uperr, erosita.dec_lowerr, erosita.dec_uperr, erosita.pos_err, erosita.mjd, erosita.mjd_min, erosita.mjd_max, erosita.ext, erosita.ext_err, erosita.ext_like, erosita.det_like_0, erosita.ml_cts_1, erosita.ml_cts_err_1, erosita.ml_rate_1, erosita.ml_rate_err_1, erosita.ml_flux_1, erosita.ml_flux_err_1, erosita.ml_bkg_1, erosita.ml_exp_1, erosita.ape_bkg_1, erosita.ape_radius_1, erosita.ape_pois_1, erosita.det_like_p1, erosita.ml_cts_p1, erosita.ml_cts_err_p1, erosita.ml_rate_p1, erosita.ml_rate_err_p1, erosita.ml_flux_p1, erosita.ml_flux_err_p1, erosita.ml_bkg_p1, erosita.ml_exp_p1, erosita.ape_bkg_p1, erosita.ape_radius_p1, erosita.ape_pois_p1, erosita.det_like_p2, erosita.ml_cts_p2, erosita.ml_cts_err_p2, erosita.ml_rate_p2, erosita.ml_rate_err_p2, erosita.ml_flux_p2, erosita.ml_flux_err_p2, erosita.ml_bkg_p2, erosita.ml_exp_p2, erosita.ape_bkg_p2, erosita.ape_radius_p2, erosita.ape_pois_p2, erosita.det_like_p3, erosita.ml_cts_p3, erosita.ml_cts_err_p3, erosita.ml_rate_p3, erosita.ml_rate_err_p3, erosita.ml_flux_p3, erosita.ml_flux_err_p3, erosita.ml_bkg_p3, erosita.ml_exp_p3, erosita.ape_bkg_p3, erosita.ape_radius_p3, erosita.ape_pois_p3, erosita.det_like_p4, erosita.ml_cts_p4, erosita.ml_cts_err_p4, erosita.ml_rate_p4, erosita.ml_rate_err_p4, erosita.ml_flux_p4, erosita.ml_flux_err_p4, erosita.ml_bkg_p4, erosita.ml_exp_p4, erosita.ape_bkg_p4, erosita.ape_radius_p4, erosita.ape_pois_p4, erosita.det_like_p5, erosita.ml_cts_p5, erosita.ml_cts_err_p5, erosita.ml_rate_p5, erosita.ml_rate_err_p5, erosita.ml_flux_p5, erosita.ml_flux_err_p5, erosita.ml_bkg_p5, erosita.ml_exp_p5, erosita.ape_bkg_p5, erosita.ape_radius_p5, erosita.ape_pois_p5, erosita.det_like_p6, erosita.ml_cts_p6, erosita.ml_cts_err_p6, erosita.ml_rate_p6, erosita.ml_rate_err_p6, erosita.ml_flux_p6, erosita.ml_flux_err_p6, erosita.ml_bkg_p6, erosita.ml_exp_p6, erosita.ape_bkg_p6, erosita.ape_radius_p6, erosita.ape_pois_p6, erosita.flag_sp_snr, erosita.flag_sp_bps, erosita.flag_sp_scl, erosita.flag_sp_lga, erosita.flag_sp_gc_cons, erosita.flag_no_radec_err, erosita.flag_no_ext_err, erosita.flag_no_cts_err, erosita.flag_opt, mastercat.ra, mastercat.dec, mastercat.pos_err, mastercat.pmra, mastercat.pmdec, mastercat.ref_epoch
 FROM erosita 
 JOIN mastercat ON mastercat.id = erosita.id
 WHERE mastercat.ipix IN (/*SLICE:ipix*/?)
@@ -1044,6 +1846,10 @@ type GetErositaFromPixelsRow struct {
 	FlagOpt        NullInt64   `parquet:"name=FLAG_OPT, type=INT32"`
 	Ra_2           float64     `json:"ra" parquet:"name=ra, type=DOUBLE"`
 	Dec_2          float64     `json:"dec" parquet:"name=dec, type=DOUBLE"`
+	PosErr_2       *float64    `json:"pos_err,omitempty" parquet:"name=pos_err, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmra           *float64    `json:"pmra,omitempty" parquet:"name=pmra, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmdec          *float64    `json:"pmdec,omitempty" parquet:"name=pmdec, type=DOUBLE, repetitiontype=OPTIONAL"`
+	RefEpoch       *float64    `json:"ref_epoch,omitempty" parquet:"name=ref_epoch, type=DOUBLE, repetitiontype=OPTIONAL"`
 }
 
 func (q *Queries) GetErositaFromPixels(ctx context.Context, ipix []int64) ([]GetErositaFromPixelsRow, error) {
@@ -1181,6 +1987,53 @@ func (q *Queries) GetErositaFromPixels(ctx context.Context, ipix []int64) ([]Get
 			&i.FlagOpt,
 			&i.Ra_2,
 			&i.Dec_2,
+			&i.PosErr_2,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.RefEpoch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExpiredJobs = `-- name: GetExpiredJobs :many
+SELECT id, phase, radius, catalog, nneighbor, result_format, total, progress, error, created_at, started_at, finished_at, expires_at
+FROM jobs
+WHERE expires_at <= ?
+`
+
+func (q *Queries) GetExpiredJobs(ctx context.Context, expiresAt int64) ([]Job, error) {
+	rows, err := q.db.QueryContext(ctx, getExpiredJobs, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Job
+	for rows.Next() {
+		var i Job
+		if err := rows.Scan(
+			&i.ID,
+			&i.Phase,
+			&i.Radius,
+			&i.Catalog,
+			&i.Nneighbor,
+			&i.ResultFormat,
+			&i.Total,
+			&i.Progress,
+			&i.Error,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.ExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1237,8 +2090,79 @@ func (q *Queries) GetGaia(ctx context.Context, id string) (GetGaiaRow, error) {
 	return i, err
 }
 
+const getGaiaBetweenPixels = `-- name: GetGaiaBetweenPixels :many
+SELECT gaia.id, gaia.phot_g_mean_flux, gaia.phot_g_mean_flux_error, gaia.phot_g_mean_mag, gaia.phot_bp_mean_flux, gaia.phot_bp_mean_flux_error, gaia.phot_bp_mean_mag, gaia.phot_rp_mean_flux, gaia.phot_rp_mean_flux_error, gaia.phot_rp_mean_mag, mastercat.ra, mastercat.dec, mastercat.pos_err, mastercat.pmra, mastercat.pmdec, mastercat.ref_epoch
+FROM gaia
+JOIN mastercat ON mastercat.id = gaia.id
+WHERE mastercat.ipix >= ?1 AND mastercat.ipix < ?2
+`
+
+type GetGaiaBetweenPixelsRow struct {
+	ID                  string      `json:"id" parquet:"name=id, type=BYTE_ARRAY"`
+	PhotGMeanFlux       NullFloat64 `json:"phot_g_mean_flux" parquet:"name=phot_g_mean_flux, type=DOUBLE"`
+	PhotGMeanFluxError  NullFloat64 `json:"phot_g_mean_flux_error" parquet:"name=phot_g_mean_flux_error, type=DOUBLE"`
+	PhotGMeanMag        NullFloat64 `json:"phot_g_mean_mag" parquet:"name=phot_g_mean_mag, type=DOUBLE"`
+	PhotBpMeanFlux      NullFloat64 `json:"phot_bp_mean_flux" parquet:"name=phot_bp_mean_flux, type=DOUBLE"`
+	PhotBpMeanFluxError NullFloat64 `json:"phot_bp_mean_flux_error" parquet:"name=phot_bp_mean_flux_error, type=DOUBLE"`
+	PhotBpMeanMag       NullFloat64 `json:"phot_bp_mean_mag" parquet:"name=phot_bp_mean_mag, type=DOUBLE"`
+	PhotRpMeanFlux      NullFloat64 `json:"phot_rp_mean_flux" parquet:"name=phot_rp_mean_flux, type=DOUBLE"`
+	PhotRpMeanFluxError NullFloat64 `json:"phot_rp_mean_flux_error" parquet:"name=phot_rp_mean_flux_error, type=DOUBLE"`
+	PhotRpMeanMag       NullFloat64 `json:"phot_rp_mean_mag" parquet:"name=phot_rp_mean_mag, type=DOUBLE"`
+	Ra                  float64     `json:"ra" parquet:"name=ra, type=DOUBLE"`
+	Dec                 float64     `json:"dec" parquet:"name=dec, type=DOUBLE"`
+	PosErr              *float64    `json:"pos_err,omitempty" parquet:"name=pos_err, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmra                *float64    `json:"pmra,omitempty" parquet:"name=pmra, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmdec               *float64    `json:"pmdec,omitempty" parquet:"name=pmdec, type=DOUBLE, repetitiontype=OPTIONAL"`
+	RefEpoch            *float64    `json:"ref_epoch,omitempty" parquet:"name=ref_epoch, type=DOUBLE, repetitiontype=OPTIONAL"`
+}
+
+type GetGaiaBetweenPixelsParams struct {
+	Start int64 `json:"start"`
+	Stop  int64 `json:"stop"`
+}
+
+func (q *Queries) GetGaiaBetweenPixels(ctx context.Context, arg GetGaiaBetweenPixelsParams) ([]GetGaiaBetweenPixelsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getGaiaBetweenPixels, arg.Start, arg.Stop)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGaiaBetweenPixelsRow
+	for rows.Next() {
+		var i GetGaiaBetweenPixelsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PhotGMeanFlux,
+			&i.PhotGMeanFluxError,
+			&i.PhotGMeanMag,
+			&i.PhotBpMeanFlux,
+			&i.PhotBpMeanFluxError,
+			&i.PhotBpMeanMag,
+			&i.PhotRpMeanFlux,
+			&i.PhotRpMeanFluxError,
+			&i.PhotRpMeanMag,
+			&i.Ra,
+			&i.Dec,
+			&i.PosErr,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.RefEpoch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getGaiaFromPixels = `-- name: GetGaiaFromPixels :many
-SELECT gaia.id, gaia.phot_g_mean_flux, gaia.phot_g_mean_flux_error, gaia.phot_g_mean_mag, gaia.phot_bp_mean_flux, gaia.phot_bp_mean_flux_error, gaia.phot_bp_mean_mag, gaia.phot_rp_mean_flux, gaia.phot_rp_mean_flux_error, gaia.phot_rp_mean_mag, mastercat.ra, mastercat.dec
+SELECT gaia.id, gaia.phot_g_mean_flux, gaia.phot_g_mean_flux_error, gaia.phot_g_mean_mag, gaia.phot_bp_mean_flux, gaia.phot_bp_mean_flux_error, gaia.phot_bp_mean_mag, gaia.phot_rp_mean_flux, gaia.phot_rp_mean_flux_error, gaia.phot_rp_mean_mag, mastercat.ra, mastercat.dec, mastercat.pos_err, mastercat.pmra, mastercat.pmdec, mastercat.ref_epoch
 FROM gaia 
 JOIN mastercat ON mastercat.id = gaia.id
 WHERE mastercat.ipix IN (/*SLICE:ipix*/?)
@@ -1257,6 +2181,10 @@ type GetGaiaFromPixelsRow struct {
 	PhotRpMeanMag       NullFloat64 `json:"phot_rp_mean_mag" parquet:"name=phot_rp_mean_mag, type=DOUBLE"`
 	Ra                  float64     `json:"ra" parquet:"name=ra, type=DOUBLE"`
 	Dec                 float64     `json:"dec" parquet:"name=dec, type=DOUBLE"`
+	PosErr              *float64    `json:"pos_err,omitempty" parquet:"name=pos_err, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmra                *float64    `json:"pmra,omitempty" parquet:"name=pmra, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmdec               *float64    `json:"pmdec,omitempty" parquet:"name=pmdec, type=DOUBLE, repetitiontype=OPTIONAL"`
+	RefEpoch            *float64    `json:"ref_epoch,omitempty" parquet:"name=ref_epoch, type=DOUBLE, repetitiontype=OPTIONAL"`
 }
 
 func (q *Queries) GetGaiaFromPixels(ctx context.Context, ipix []int64) ([]GetGaiaFromPixelsRow, error) {
@@ -1291,6 +2219,93 @@ func (q *Queries) GetGaiaFromPixels(ctx context.Context, ipix []int64) ([]GetGai
 			&i.PhotRpMeanMag,
 			&i.Ra,
 			&i.Dec,
+			&i.PosErr,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.RefEpoch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getIndexVersion = `-- name: GetIndexVersion :one
+SELECT version
+FROM index_version
+WHERE id = 0
+`
+
+func (q *Queries) GetIndexVersion(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getIndexVersion)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
+
+const getJob = `-- name: GetJob :one
+SELECT id, phase, radius, catalog, nneighbor, result_format, total, progress, error, created_at, started_at, finished_at, expires_at
+FROM jobs
+WHERE id = ?
+`
+
+func (q *Queries) GetJob(ctx context.Context, id string) (Job, error) {
+	row := q.db.QueryRowContext(ctx, getJob, id)
+	var i Job
+	err := row.Scan(
+		&i.ID,
+		&i.Phase,
+		&i.Radius,
+		&i.Catalog,
+		&i.Nneighbor,
+		&i.ResultFormat,
+		&i.Total,
+		&i.Progress,
+		&i.Error,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getJobsByPhase = `-- name: GetJobsByPhase :many
+SELECT id, phase, radius, catalog, nneighbor, result_format, total, progress, error, created_at, started_at, finished_at, expires_at
+FROM jobs
+WHERE phase = ?
+`
+
+func (q *Queries) GetJobsByPhase(ctx context.Context, phase string) ([]Job, error) {
+	rows, err := q.db.QueryContext(ctx, getJobsByPhase, phase)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Job
+	for rows.Next() {
+		var i Job
+		if err := rows.Scan(
+			&i.ID,
+			&i.Phase,
+			&i.Radius,
+			&i.Catalog,
+			&i.Nneighbor,
+			&i.ResultFormat,
+			&i.Total,
+			&i.Progress,
+			&i.Error,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.ExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1306,7 +2321,7 @@ func (q *Queries) GetGaiaFromPixels(ctx context.Context, ipix []int64) ([]GetGai
 }
 
 const getObjectsFromCatalog = `-- name: GetObjectsFromCatalog :many
-SELECT id, ipix, ra, dec, cat 
+SELECT id, ipix, ra, dec, cat, pos_err, pmra, pmdec, parallax, ref_epoch 
 FROM mastercat 
 WHERE ipix IN (/*SLICE:ipix*/?)
 AND cat = ?
@@ -1343,6 +2358,11 @@ func (q *Queries) GetObjectsFromCatalog(ctx context.Context, arg GetObjectsFromC
 			&i.Ra,
 			&i.Dec,
 			&i.Cat,
+			&i.PosErr,
+			&i.Pmra,
+			&i.Pmdec,
+			&i.Parallax,
+			&i.RefEpoch,
 		); err != nil {
 			return nil, err
 		}
@@ -1733,20 +2753,60 @@ func (q *Queries) InsertGaia(ctx context.Context, arg InsertGaiaParams) error {
 	return err
 }
 
+const insertJob = `-- name: InsertJob :exec
+INSERT INTO jobs (
+	id, phase, radius, catalog, nneighbor, result_format, total, created_at, expires_at
+) VALUES (
+	?, ?, ?, ?, ?, ?, ?, ?, ?
+)
+`
+
+type InsertJobParams struct {
+	ID           string
+	Phase        string
+	Radius       float64
+	Catalog      string
+	Nneighbor    int64
+	ResultFormat string
+	Total        int64
+	CreatedAt    int64
+	ExpiresAt    int64
+}
+
+func (q *Queries) InsertJob(ctx context.Context, arg InsertJobParams) error {
+	_, err := q.db.ExecContext(ctx, insertJob,
+		arg.ID,
+		arg.Phase,
+		arg.Radius,
+		arg.Catalog,
+		arg.Nneighbor,
+		arg.ResultFormat,
+		arg.Total,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
 const insertObject = `-- name: InsertObject :exec
 INSERT INTO mastercat (
-	id, ipix, ra, dec, cat
+	id, ipix, ra, dec, cat, pos_err, pmra, pmdec, parallax, ref_epoch
 ) VALUES (
-	?, ?, ?, ?, ?
+	?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
 `
 
 type InsertObjectParams struct {
-	ID   string  `json:"id" parquet:"name=id, type=BYTE_ARRAY"`
-	Ipix int64   `json:"ipix" parquet:"name=ipix, type=INT64"`
-	Ra   float64 `json:"ra" parquet:"name=ra, type=DOUBLE"`
-	Dec  float64 `json:"dec" parquet:"name=dec, type=DOUBLE"`
-	Cat  string  `json:"cat" parquet:"name=cat, type=BYTE_ARRAY"`
+	ID       string   `json:"id" parquet:"name=id, type=BYTE_ARRAY"`
+	Ipix     int64    `json:"ipix" parquet:"name=ipix, type=INT64"`
+	Ra       float64  `json:"ra" parquet:"name=ra, type=DOUBLE"`
+	Dec      float64  `json:"dec" parquet:"name=dec, type=DOUBLE"`
+	Cat      string   `json:"cat" parquet:"name=cat, type=BYTE_ARRAY"`
+	PosErr   *float64 `json:"pos_err,omitempty" parquet:"name=pos_err, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmra     *float64 `json:"pmra,omitempty" parquet:"name=pmra, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Pmdec    *float64 `json:"pmdec,omitempty" parquet:"name=pmdec, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Parallax *float64 `json:"parallax,omitempty" parquet:"name=parallax, type=DOUBLE, repetitiontype=OPTIONAL"`
+	RefEpoch *float64 `json:"ref_epoch,omitempty" parquet:"name=ref_epoch, type=DOUBLE, repetitiontype=OPTIONAL"`
 }
 
 func (q *Queries) InsertObject(ctx context.Context, arg InsertObjectParams) error {
@@ -1756,6 +2816,38 @@ func (q *Queries) InsertObject(ctx context.Context, arg InsertObjectParams) erro
 		arg.Ra,
 		arg.Dec,
 		arg.Cat,
+		arg.PosErr,
+		arg.Pmra,
+		arg.Pmdec,
+		arg.Parallax,
+		arg.RefEpoch,
+	)
+	return err
+}
+
+const insertXwaveDetection = `-- name: InsertXwaveDetection :exec
+INSERT OR REPLACE INTO xwave_detection (
+	object_id, mjd, band, mag, mag_err
+) VALUES (
+	?, ?, ?, ?, ?
+)
+`
+
+type InsertXwaveDetectionParams struct {
+	ObjectID string
+	Mjd      float64
+	Band     string
+	Mag      float64
+	MagErr   float64
+}
+
+func (q *Queries) InsertXwaveDetection(ctx context.Context, arg InsertXwaveDetectionParams) error {
+	_, err := q.db.ExecContext(ctx, insertXwaveDetection,
+		arg.ObjectID,
+		arg.Mjd,
+		arg.Band,
+		arg.Mag,
+		arg.MagErr,
 	)
 	return err
 }
@@ -1804,3 +2896,45 @@ func (q *Queries) RemoveAllObjects(ctx context.Context) error {
 	_, err := q.db.ExecContext(ctx, removeAllObjects)
 	return err
 }
+
+const removeAllXwaveDetections = `-- name: RemoveAllXwaveDetections :exec
+DELETE FROM xwave_detection
+`
+
+func (q *Queries) RemoveAllXwaveDetections(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, removeAllXwaveDetections)
+	return err
+}
+
+const startJob = `-- name: StartJob :exec
+UPDATE jobs
+SET phase = ?, started_at = ?
+WHERE id = ?
+`
+
+type StartJobParams struct {
+	Phase     string
+	StartedAt NullInt64
+	ID        string
+}
+
+func (q *Queries) StartJob(ctx context.Context, arg StartJobParams) error {
+	_, err := q.db.ExecContext(ctx, startJob, arg.Phase, arg.StartedAt, arg.ID)
+	return err
+}
+
+const updateJobProgress = `-- name: UpdateJobProgress :exec
+UPDATE jobs
+SET progress = ?
+WHERE id = ?
+`
+
+type UpdateJobProgressParams struct {
+	Progress int64
+	ID       string
+}
+
+func (q *Queries) UpdateJobProgress(ctx context.Context, arg UpdateJobProgressParams) error {
+	_, err := q.db.ExecContext(ctx, updateJobProgress, arg.Progress, arg.ID)
+	return err
+}
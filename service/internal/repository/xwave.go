@@ -0,0 +1,67 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import "context"
+
+// XwaveInputSchema is a row of the x-wave photometry files.
+// Each row is one epoch of an object already indexed in the mastercat.
+type XwaveInputSchema struct {
+	ObjectID string  `json:"object_id" parquet:"name=object_id, type=BYTE_ARRAY"`
+	Mjd      float64 `json:"mjd" parquet:"name=mjd, type=DOUBLE"`
+	Band     string  `json:"band" parquet:"name=band, type=BYTE_ARRAY"`
+	Mag      float64 `json:"mag" parquet:"name=mag, type=DOUBLE"`
+	MagErr   float64 `json:"mag_err" parquet:"name=mag_err, type=DOUBLE"`
+}
+
+func (schema XwaveInputSchema) GetId() string {
+	return schema.ObjectID
+}
+
+// GetCoordinates returns zero, as detections take the position of their mastercat object
+func (schema XwaveInputSchema) GetCoordinates() (float64, float64) {
+	return 0, 0
+}
+
+func (schema XwaveInputSchema) FillMetadata() Metadata {
+	return XwaveDetection{
+		ObjectID: schema.ObjectID,
+		Mjd:      schema.Mjd,
+		Band:     schema.Band,
+		Mag:      schema.Mag,
+		MagErr:   schema.MagErr,
+	}
+}
+
+// FillMastercat is only there to satisfy InputSchema, x-wave detections are not indexed in the mastercat
+func (schema XwaveInputSchema) FillMastercat(ipix int64) Mastercat {
+	return Mastercat{
+		ID:   schema.ObjectID,
+		Ipix: ipix,
+		Cat:  "xwave",
+	}
+}
+
+func (d XwaveDetection) GetId() string {
+	return d.ObjectID
+}
+
+func (d XwaveDetection) GetCatalog() string {
+	return "x-wave"
+}
+
+func (q *Queries) InsertXwaveDetectionWithoutParams(ctx context.Context, arg XwaveDetection) error {
+	return q.InsertXwaveDetection(ctx, InsertXwaveDetectionParams(arg))
+}
@@ -0,0 +1,67 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import "math"
+
+// BayesFactor returns the Budavári & Szalay (2008) Bayes factor for the hypothesis
+// that two detections with circular gaussian positional errors belong to the same source.
+//
+// The separation and both positional errors are given in arcsec.
+// Returns false when the errors are both zero, since the factor is undefined.
+func BayesFactor(separation, sigma1, sigma2 float64) (float64, bool) {
+	psi := arcsecToRadians(separation)
+	s1 := arcsecToRadians(sigma1)
+	s2 := arcsecToRadians(sigma2)
+
+	sigmaSquared := s1*s1 + s2*s2
+	if sigmaSquared <= 0 {
+		return 0, false
+	}
+	return 2 / sigmaSquared * math.Exp(-psi*psi/(2*sigmaSquared)), true
+}
+
+// MatchProbability returns the posterior probability of a match given its Bayes factor and prior
+func MatchProbability(bayesFactor, prior float64) float64 {
+	if bayesFactor <= 0 || prior <= 0 {
+		return 0
+	}
+	return 1 / (1 + (1-prior)/(bayesFactor*prior))
+}
+
+// matchPrior estimates the prior probability of a match from the surface density of
+// the candidates found inside the search cone.
+//
+// This is the N_match / (N_1 * N_2) prior from Budavári & Szalay, assuming the
+// catalog has the same density as the cone over the whole sky.
+func matchPrior(radius float64, ncandidates int) float64 {
+	coneArea := 2 * math.Pi * (1 - math.Cos(arcsecToRadians(radius)))
+	return min(coneArea/(4*math.Pi*float64(max(ncandidates, 1))), 1)
+}
+
+// scoreMatch computes the Bayes factor and match probability of a candidate
+//
+// Both values are nil when the candidate has no positional error recorded.
+func scoreMatch(separation, radius, posErr float64, candidatePosErr *float64, ncandidates int) (*float64, *float64) {
+	if candidatePosErr == nil {
+		return nil, nil
+	}
+	bayesFactor, ok := BayesFactor(separation, posErr, *candidatePosErr)
+	if !ok {
+		return nil, nil
+	}
+	probability := MatchProbability(bayesFactor, matchPrior(radius, ncandidates))
+	return &bayesFactor, &probability
+}
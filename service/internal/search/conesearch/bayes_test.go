@@ -0,0 +1,107 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+
+	"github.com/dirodriguezm/healpix"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBayesFactor(t *testing.T) {
+	// at zero separation the factor is 2 / (sigma1^2 + sigma2^2)
+	bf, ok := BayesFactor(0, 1, 1)
+	require.True(t, ok)
+	sigma := arcsecToRadians(1)
+	require.InDelta(t, 1/(sigma*sigma), bf, 1e-6*bf)
+
+	// the factor decreases with separation
+	closer, _ := BayesFactor(0.5, 1, 1)
+	farther, _ := BayesFactor(2, 1, 1)
+	require.Greater(t, closer, farther)
+
+	_, ok = BayesFactor(1, 0, 0)
+	require.False(t, ok)
+}
+
+func TestMatchProbability(t *testing.T) {
+	require.Equal(t, 0.5, MatchProbability(1, 0.5))
+	require.Zero(t, MatchProbability(0, 0.5))
+	require.Greater(t, MatchProbability(1e12, 1e-9), 0.99)
+	require.Less(t, MatchProbability(1, 1e-9), 0.01)
+	require.False(t, math.IsNaN(MatchProbability(math.Inf(1), 1e-9)))
+}
+
+func TestProbabilisticConesearch(t *testing.T) {
+	posErr := 0.1
+	objects := []repository.Mastercat{
+		{ID: "A", Ra: 1, Dec: 1, Cat: "vlass", PosErr: &posErr},
+		{ID: "B", Ra: 1.0002, Dec: 1, Cat: "vlass", PosErr: &posErr},
+		{ID: "C", Ra: 1.0001, Dec: 1, Cat: "vlass"},
+	}
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return(objects, nil)
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	result, err := service.ProbabilisticConesearch(context.Background(), 1, 1, 2, 0.1, 10, "all")
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	require.Len(t, result, 1)
+	require.Len(t, result[0].Data, 3)
+	scores := make(map[string]MastercatExtended)
+	for _, m := range result[0].Data {
+		scores[m.ID] = m
+	}
+	require.NotNil(t, scores["A"].Probability)
+	require.NotNil(t, scores["B"].Probability)
+	require.Nil(t, scores["C"].Probability, "objects without positional error should not be scored")
+	require.Nil(t, scores["C"].BayesFactor)
+	require.Greater(t, *scores["A"].Probability, *scores["B"].Probability)
+	require.Greater(t, *scores["A"].BayesFactor, *scores["B"].BayesFactor)
+
+	_, err = service.ProbabilisticConesearch(context.Background(), 1, 1, 2, -1, 10, "all")
+	require.ErrorAs(t, err, &ValidationError{})
+}
+
+func TestFindMetadataByProbabilisticConesearch(t *testing.T) {
+	posErr := 0.1
+	objects := []repository.GetAllwiseFromPixelsRow{
+		{ID: "A", Ra: 1, Dec: 1, PosErr: &posErr},
+		{ID: "B", Ra: 10, Dec: 10, PosErr: &posErr},
+	}
+	repo := &MockRepository{}
+	repo.On("GetAllwiseFromPixels", mock.Anything, mock.Anything).Return(objects, nil)
+	catalogs := []repository.Catalog{{Name: "allwise", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	result, err := service.FindMetadataByProbabilisticConesearch(context.Background(), 1, 1, 1, 0.1, 1, "allwise")
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	require.Len(t, result, 1)
+	require.Equal(t, "A", result[0].Data[0].GetId())
+	require.NotNil(t, result[0].Data[0].Probability)
+	require.Greater(t, *result[0].Data[0].Probability, 0.9)
+}
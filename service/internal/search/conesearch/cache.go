@@ -0,0 +1,363 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+
+	"github.com/dirodriguezm/healpix"
+)
+
+// mastercatCache is the catalog name used to cache the mastercat objects of a pixel,
+// since FindObjects returns the objects of every catalog at once
+const mastercatCache = "mastercat"
+
+// CacheStats holds the usage of a cache since the service was created.
+// Size is the number of objects the cache can hold, and Objects the number it holds.
+type CacheStats struct {
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	Entries int   `json:"entries"`
+	Objects int   `json:"objects"`
+	Size    int   `json:"size"`
+}
+
+// ConesearchCacheStats holds the usage of every cache of the ConesearchService
+type ConesearchCacheStats struct {
+	Pixels            CacheStats `json:"pixels"`
+	MetadataPixels    CacheStats `json:"metadata_pixels"`
+	Responses         CacheStats `json:"responses"`
+	MetadataResponses CacheStats `json:"metadata_responses"`
+	Invalidations     int64      `json:"invalidations"`
+	IndexVersion      int64      `json:"index_version"`
+}
+
+// lruCache is a cache bounded by the weight of its entries, which evicts the least recently
+// used entries when it is full. A cache of size zero is disabled: it stores nothing and
+// counts no hits or misses.
+type lruCache[K comparable, V any] struct {
+	size    int
+	used    int
+	weigh   func(V) int
+	mu      sync.Mutex
+	order   *list.List
+	entries map[K]*list.Element
+	hits    atomic.Int64
+	misses  atomic.Int64
+}
+
+type lruEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	weight int
+}
+
+func newLRUCache[K comparable, V any](size int, weigh func(V) int) *lruCache[K, V] {
+	return &lruCache[K, V]{
+		size:    max(size, 0),
+		weigh:   weigh,
+		order:   list.New(),
+		entries: make(map[K]*list.Element),
+	}
+}
+
+// objectsWeight weighs the objects of a pixel. Empty pixels weigh one, since they are cached too.
+func objectsWeight[V any](objects []V) int {
+	return max(len(objects), 1)
+}
+
+// mastercatWeight weighs a response by the objects of all its results
+func mastercatWeight(results []MastercatResult) int {
+	weight := 0
+	for _, result := range results {
+		weight += len(result.Data)
+	}
+	return max(weight, 1)
+}
+
+// metadataWeight weighs a response by the objects of all its results
+func metadataWeight(results []MetadataResult) int {
+	weight := 0
+	for _, result := range results {
+		weight += len(result.Data)
+	}
+	return max(weight, 1)
+}
+
+func (l *lruCache[K, V]) enabled() bool {
+	return l.size > 0
+}
+
+func (l *lruCache[K, V]) get(key K) (V, bool) {
+	var zero V
+	if !l.enabled() {
+		return zero, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	elem, ok := l.entries[key]
+	if !ok {
+		l.misses.Add(1)
+		return zero, false
+	}
+	l.hits.Add(1)
+	l.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry[K, V]).value, true
+}
+
+func (l *lruCache[K, V]) add(key K, value V) {
+	if !l.enabled() {
+		return
+	}
+
+	weight := max(l.weigh(value), 1)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if weight > l.size {
+		// values heavier than the whole cache are not kept
+		if elem, ok := l.entries[key]; ok {
+			l.remove(elem)
+		}
+		return
+	}
+	if elem, ok := l.entries[key]; ok {
+		entry := elem.Value.(*lruEntry[K, V])
+		l.used += weight - entry.weight
+		entry.value, entry.weight = value, weight
+		l.order.MoveToFront(elem)
+	} else {
+		l.entries[key] = l.order.PushFront(&lruEntry[K, V]{key: key, value: value, weight: weight})
+		l.used += weight
+	}
+	for l.used > l.size {
+		l.remove(l.order.Back())
+	}
+}
+
+func (l *lruCache[K, V]) remove(elem *list.Element) {
+	entry := l.order.Remove(elem).(*lruEntry[K, V])
+	delete(l.entries, entry.key)
+	l.used -= entry.weight
+}
+
+func (l *lruCache[K, V]) purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.order.Init()
+	clear(l.entries)
+	l.used = 0
+}
+
+func (l *lruCache[K, V]) stats() CacheStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return CacheStats{
+		Hits:    l.hits.Load(),
+		Misses:  l.misses.Load(),
+		Entries: l.order.Len(),
+		Objects: l.used,
+		Size:    l.size,
+	}
+}
+
+// pixelKey identifies the objects of a catalog found in a pixel of a HEALPix order,
+// as they were in the version of the index they were found at
+type pixelKey struct {
+	version int64
+	catalog string
+	order   int64
+	pixel   int64
+}
+
+// responseKey identifies a search by its arguments and the version of the index it was made at
+type responseKey struct {
+	version   int64
+	search    string
+	ra, dec   float64
+	radius    float64
+	nneighbor int
+	catalog   string
+}
+
+// cachedPixels returns the cached values of the pixels, and the pixels that still have to be queried
+func cachedPixels[V any](cache *lruCache[pixelKey, []V], version int64, catalogName string, order int64, pixels []int64) ([]V, []int64) {
+	if !cache.enabled() {
+		return nil, pixels
+	}
+
+	values := make([]V, 0)
+	missing := make([]int64, 0)
+	for _, pixel := range pixels {
+		v, ok := cache.get(pixelKey{version: version, catalog: catalogName, order: order, pixel: pixel})
+		if !ok {
+			missing = append(missing, pixel)
+			continue
+		}
+		values = append(values, v...)
+	}
+	return values, missing
+}
+
+// cachePixels stores the values found in the queried pixels, grouped by the pixel of each value.
+// Pixels without values are cached too, so empty regions are not queried again.
+// Nothing is cached if a value does not belong to any of the queried pixels.
+func cachePixels[V any](cache *lruCache[pixelKey, []V], version int64, catalogName string, order int64, pixels []int64, values []V, pixelOf func(V) int64) {
+	if !cache.enabled() {
+		return
+	}
+
+	byPixel := make(map[int64][]V, len(pixels))
+	for _, pixel := range pixels {
+		byPixel[pixel] = make([]V, 0)
+	}
+	for _, v := range values {
+		pixel := pixelOf(v)
+		if _, ok := byPixel[pixel]; !ok {
+			return
+		}
+		byPixel[pixel] = append(byPixel[pixel], v)
+	}
+	for pixel, v := range byPixel {
+		cache.add(pixelKey{version: version, catalog: catalogName, order: order, pixel: pixel}, v)
+	}
+}
+
+// findObjectsInPixels returns the mastercat objects of the pixels of an order,
+// querying the repository only for the pixels missing from the cache
+func (c *ConesearchService) findObjectsInPixels(ctx context.Context, order int64, pixels []int64) ([]repository.Mastercat, error) {
+	version := c.indexVersion.Load()
+	objects, missing := cachedPixels(c.pixelCache, version, mastercatCache, order, pixels)
+	if len(missing) == 0 {
+		return objects, nil
+	}
+
+	var found []repository.Mastercat
+	var err error
+	if catalogs := c.catalogsAt(order); catalogs != nil {
+		found, err = c.repository.FindObjectsOfCatalogs(ctx, repository.FindObjectsOfCatalogsParams{Ipix: missing, Cat: catalogs})
+	} else {
+		found, err = c.repository.FindObjects(ctx, missing)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cachePixels(c.pixelCache, version, mastercatCache, order, missing, found, func(obj repository.Mastercat) int64 { return obj.Ipix })
+	return append(objects, found...), nil
+}
+
+// findMetadataInPixels returns the metadata of a catalog in the pixels of an order,
+// querying the repository only for the pixels missing from the cache.
+//
+// Metadata rows don't hold their pixel, so it is computed again from the coordinates,
+// the same way the indexer does.
+func (c *ConesearchService) findMetadataInPixels(
+	ctx context.Context,
+	desc catalog.CatalogDescriptor,
+	order int64,
+	mapper *healpix.HEALPixMapper,
+	pixels []int64,
+) ([]repository.MetadataWithCoordinates, error) {
+	version := c.indexVersion.Load()
+	objects, missing := cachedPixels(c.metadataCache, version, strings.ToLower(desc.Name), order, pixels)
+	if len(missing) == 0 {
+		return objects, nil
+	}
+
+	found, err := desc.FromPixels(ctx, c.repository, missing)
+	if err != nil {
+		return nil, err
+	}
+	cachePixels(c.metadataCache, version, strings.ToLower(desc.Name), order, missing, found, func(obj repository.MetadataWithCoordinates) int64 {
+		return mapper.PixelAt(healpix.RADec(obj.GetCoordinates()))
+	})
+	return append(objects, found...), nil
+}
+
+// cachedResponse returns the result of an identical search, or runs the search and caches its result
+func cachedResponse[T any](cache *lruCache[responseKey, []T], key responseKey, search func() ([]T, error)) ([]T, error) {
+	if result, ok := cache.get(key); ok {
+		return slices.Clone(result), nil
+	}
+
+	result, err := search()
+	if err != nil {
+		return nil, err
+	}
+	cache.add(key, slices.Clone(result))
+	return result, nil
+}
+
+// InvalidateCache removes every cached pixel and response.
+// Searches made after it query the repository again.
+func (c *ConesearchService) InvalidateCache() {
+	c.pixelCache.purge()
+	c.metadataCache.purge()
+	c.mastercatResponses.purge()
+	c.metadataResponses.purge()
+	c.invalidations.Add(1)
+}
+
+// CacheStats returns the hits, misses and size of the caches of the service
+func (c *ConesearchService) CacheStats() ConesearchCacheStats {
+	return ConesearchCacheStats{
+		Pixels:            c.pixelCache.stats(),
+		MetadataPixels:    c.metadataCache.stats(),
+		Responses:         c.mastercatResponses.stats(),
+		MetadataResponses: c.metadataResponses.stats(),
+		Invalidations:     c.invalidations.Load(),
+		IndexVersion:      c.indexVersion.Load(),
+	}
+}
+
+// WatchIndexVersion invalidates the caches whenever the version of the index changes,
+// which the indexer bumps once it has written every object of a catalog.
+// It polls the version every interval until the context is done.
+//
+// Cached pixels and responses belong to the version they were found at, so what a
+// search caches while a catalog is being indexed is not used after the version changes.
+func (c *ConesearchService) WatchIndexVersion(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			version, err := c.repository.GetIndexVersion(ctx)
+			if err != nil {
+				slog.Error("could not get the index version while watching the index", "error", err)
+				continue
+			}
+			if version == c.indexVersion.Load() {
+				continue
+			}
+			slog.Info("Index version changed, invalidating conesearch cache", "version", version)
+			c.indexVersion.Store(version)
+			c.InvalidateCache()
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+
+	"github.com/dirodriguezm/healpix"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache(t *testing.T) {
+	// each value weighs as much as its number
+	cache := newLRUCache[string](3, func(v int) int { return v })
+	cache.add("a", 1)
+	cache.add("b", 2)
+
+	v, ok := cache.get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, v)
+
+	// b is the least recently used entry
+	cache.add("c", 1)
+	_, ok = cache.get("b")
+	require.False(t, ok)
+	_, ok = cache.get("c")
+	require.True(t, ok)
+
+	require.Equal(t, CacheStats{Hits: 2, Misses: 1, Entries: 2, Objects: 2, Size: 3}, cache.stats())
+
+	// values heavier than the cache are not kept, and don't evict the others
+	cache.add("d", 4)
+	_, ok = cache.get("d")
+	require.False(t, ok)
+	require.Equal(t, 2, cache.stats().Entries)
+
+	cache.purge()
+	_, ok = cache.get("a")
+	require.False(t, ok)
+	require.Equal(t, 0, cache.stats().Entries)
+	require.Equal(t, 0, cache.stats().Objects)
+}
+
+func TestLRUCache_ObjectsWeight(t *testing.T) {
+	cache := newLRUCache[string](3, objectsWeight[string])
+	cache.add("empty", []string{})
+	cache.add("full", []string{"a", "b"})
+	require.Equal(t, 3, cache.stats().Objects, "empty pixels weigh one object")
+
+	cache.add("other", []string{"c"})
+	_, ok := cache.get("empty")
+	require.False(t, ok)
+	require.Equal(t, 3, cache.stats().Objects)
+}
+
+func TestLRUCache_Disabled(t *testing.T) {
+	cache := newLRUCache[string](0, func(int) int { return 1 })
+	cache.add("a", 1)
+	_, ok := cache.get("a")
+	require.False(t, ok)
+	require.Equal(t, CacheStats{}, cache.stats())
+}
+
+func pixelAt(t *testing.T, order int, ra, dec float64) int64 {
+	t.Helper()
+	mapper, err := healpix.NewHEALPixMapper(order, healpix.Nest)
+	require.NoError(t, err)
+	return mapper.PixelAt(healpix.RADec(ra, dec))
+}
+
+func TestConesearch_PixelCache(t *testing.T) {
+	objects := []repository.Mastercat{{ID: "A", Ra: 1, Dec: 1, Cat: "vlass", Ipix: pixelAt(t, 18, 1, 1)}}
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return(objects, nil).Once()
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs), WithPixelCache(100))
+	require.NoError(t, err)
+
+	// the second search has other arguments, but the same pixels
+	result, err := service.Conesearch(context.Background(), 1, 1, 1, 1, "all")
+	require.NoError(t, err)
+	require.Equal(t, "A", result[0].Data[0].ID)
+	result, err = service.Conesearch(context.Background(), 1, 1, 1, 2, "vlass")
+	require.NoError(t, err)
+	require.Equal(t, "A", result[0].Data[0].ID)
+
+	repo.AssertExpectations(t)
+	stats := service.CacheStats()
+	require.NotZero(t, stats.Pixels.Hits)
+	require.NotZero(t, stats.Pixels.Misses)
+	require.Equal(t, CacheStats{}, stats.Responses)
+}
+
+func TestConesearch_MetadataPixelCache(t *testing.T) {
+	objects := []repository.GetAllwiseFromPixelsRow{{ID: "A", Ra: 1, Dec: 1}}
+	repo := &MockRepository{}
+	repo.On("GetAllwiseFromPixels", mock.Anything, mock.Anything).Return(objects, nil).Once()
+	catalogs := []repository.Catalog{{Name: "allwise", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs), WithPixelCache(100))
+	require.NoError(t, err)
+
+	for _, nneighbor := range []int{1, 2} {
+		result, err := service.FindMetadataByConesearch(context.Background(), 1, 1, 1, nneighbor, "allwise")
+		require.NoError(t, err)
+		require.Equal(t, "A", result[0].Data[0].GetId())
+	}
+	repo.AssertExpectations(t)
+	require.NotZero(t, service.CacheStats().MetadataPixels.Hits)
+}
+
+func TestConesearch_ResponseCache(t *testing.T) {
+	objects := []repository.Mastercat{{ID: "A", Ra: 1, Dec: 1, Cat: "vlass"}}
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return(objects, nil).Once()
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs), WithResponseCache(10))
+	require.NoError(t, err)
+
+	first, err := service.Conesearch(context.Background(), 1, 1, 1, 1, "all")
+	require.NoError(t, err)
+	second, err := service.Conesearch(context.Background(), 1, 1, 1, 1, "all")
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+
+	repo.AssertExpectations(t)
+	require.Equal(t, CacheStats{Hits: 1, Misses: 1, Entries: 1, Objects: 1, Size: 10}, service.CacheStats().Responses)
+}
+
+func TestConesearch_InvalidateCache(t *testing.T) {
+	objects := []repository.Mastercat{{ID: "A", Ra: 1, Dec: 1, Cat: "vlass", Ipix: pixelAt(t, 18, 1, 1)}}
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return(objects, nil).Twice()
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(
+		WithScheme(healpix.Nest),
+		WithRepository(repo),
+		WithCatalogs(catalogs),
+		WithPixelCache(100),
+		WithResponseCache(10),
+	)
+	require.NoError(t, err)
+
+	_, err = service.Conesearch(context.Background(), 1, 1, 1, 1, "all")
+	require.NoError(t, err)
+	service.InvalidateCache()
+	_, err = service.Conesearch(context.Background(), 1, 1, 1, 1, "all")
+	require.NoError(t, err)
+
+	repo.AssertExpectations(t)
+	require.Equal(t, int64(1), service.CacheStats().Invalidations)
+}
+
+func TestConesearch_IndexVersion(t *testing.T) {
+	objects := []repository.Mastercat{{ID: "A", Ra: 1, Dec: 1, Cat: "vlass", Ipix: pixelAt(t, 18, 1, 1)}}
+	repo := &MockRepository{}
+	// the pixel is empty while the catalog is being indexed
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return([]repository.Mastercat{}, nil).Once()
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return(objects, nil).Once()
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(
+		WithScheme(healpix.Nest),
+		WithRepository(repo),
+		WithCatalogs(catalogs),
+		WithPixelCache(100),
+		WithResponseCache(10),
+	)
+	require.NoError(t, err)
+
+	result, err := service.Conesearch(context.Background(), 1, 1, 1, 1, "all")
+	require.NoError(t, err)
+	require.Empty(t, result)
+
+	// the cached miss belongs to the previous version, even before the caches are purged
+	service.indexVersion.Store(1)
+	result, err = service.Conesearch(context.Background(), 1, 1, 1, 1, "all")
+	require.NoError(t, err)
+	require.Equal(t, "A", result[0].Data[0].ID)
+	repo.AssertExpectations(t)
+}
+
+func TestConesearch_WatchIndexVersion(t *testing.T) {
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	repo := &MockRepository{}
+	// the version known by the service doesn't invalidate the cache
+	repo.On("GetIndexVersion", mock.Anything).Return(int64(0), nil).Once()
+	repo.On("GetIndexVersion", mock.Anything).Return(int64(1), nil)
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs), WithPixelCache(100))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		service.WatchIndexVersion(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		return service.CacheStats().Invalidations > 0
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	// only the finished indexing invalidates the cache
+	require.Equal(t, int64(1), service.CacheStats().Invalidations)
+	require.Equal(t, int64(1), service.CacheStats().IndexVersion)
+}
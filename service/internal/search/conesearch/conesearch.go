@@ -15,69 +15,92 @@
 package conesearch
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"maps"
 	"math"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/dirodriguezm/xmatch/service/internal/assertions"
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
 	"github.com/dirodriguezm/xmatch/service/internal/repository"
 	"github.com/dirodriguezm/xmatch/service/internal/search/knn"
 
 	"github.com/dirodriguezm/healpix"
 )
 
-type indexedResult struct {
-	index  int
-	result knn.KnnResult[repository.Mastercat]
-}
+// Largest number of pixels sent in a single query, kept well below
+// the SQLite limit of host parameters per statement
+const maxPixelsPerQuery = 10000
+
+// Pixel ranges at least this long are queried by their bounds, instead of pixel by pixel
+const minQueriedPixelRange = 4096
 
 type Repository interface {
+	catalog.Repository
 	FindObjects(context.Context, []int64) ([]repository.Mastercat, error)
+	FindObjectsBetweenPixels(context.Context, repository.FindObjectsBetweenPixelsParams) ([]repository.Mastercat, error)
+	FindObjectsInPixelRange(context.Context, repository.FindObjectsInPixelRangeParams) ([]repository.Mastercat, error)
+	FindObjectsOfCatalogs(context.Context, repository.FindObjectsOfCatalogsParams) ([]repository.Mastercat, error)
+	FindObjectsOfCatalogsBetweenPixels(context.Context, repository.FindObjectsOfCatalogsBetweenPixelsParams) ([]repository.Mastercat, error)
+	FindObjectsOfCatalogsInPixelRange(context.Context, repository.FindObjectsOfCatalogsInPixelRangeParams) ([]repository.Mastercat, error)
+	FindObjectsById(context.Context, string) ([]repository.Mastercat, error)
 	InsertMastercat(context.Context, repository.Mastercat) error
 	GetAllObjects(context.Context) ([]repository.Mastercat, error)
 	GetCatalogs(context.Context) ([]repository.Catalog, error)
+	GetIndexVersion(context.Context) (int64, error)
+	BumpIndexVersion(context.Context) error
 	InsertCatalog(context.Context, repository.InsertCatalogParams) error
 	GetDbInstance() *sql.DB
 	InsertAllwiseWithoutParams(context.Context, repository.Allwise) error
-	GetAllwise(context.Context, string) (repository.GetAllwiseRow, error)
-	GetGaia(context.Context, string) (repository.GetGaiaRow, error)
-	BulkInsertAllwise(context.Context, *sql.DB, []any) error
-	BulkInsertGaia(context.Context, *sql.DB, []any) error
 	BulkInsertObject(context.Context, *sql.DB, []any) error
 	RemoveAllObjects(context.Context) error
-	BulkGetAllwise(context.Context, []string) ([]repository.BulkGetAllwiseRow, error)
-	BulkGetGaia(context.Context, []string) ([]repository.BulkGetGaiaRow, error)
-	GetAllwiseFromPixels(context.Context, []int64) ([]repository.GetAllwiseFromPixelsRow, error)
-	GetGaiaFromPixels(context.Context, []int64) ([]repository.GetGaiaFromPixelsRow, error)
 	InsertErositaWithoutParams(context.Context, repository.Erosita) error
-	GetErosita(context.Context, string) (repository.GetErositaRow, error)
-	BulkInsertErosita(context.Context, *sql.DB, []any) error
-	BulkGetErosita(context.Context, []string) ([]repository.BulkGetErositaRow, error)
-	GetErositaFromPixels(context.Context, []int64) ([]repository.GetErositaFromPixelsRow, error)
 }
 
 type ConesearchService struct {
-	Scheme     healpix.OrderingScheme
-	Resolution int
-	Catalogs   []repository.Catalog
-	repository Repository
-	mappers    map[int64]*healpix.HEALPixMapper
-	ctx        context.Context
+	Scheme          healpix.OrderingScheme
+	Resolution      int
+	Catalogs        []repository.Catalog
+	MaxProperMotion float64
+	ReferenceEpoch  float64
+	repository      Repository
+	mappers         map[int64]*healpix.HEALPixMapper
+	// catalogOrders holds the HEALPix orders each catalog was indexed at, by lowercase name
+	catalogOrders map[string][]int64
+	// orderCatalogs holds the catalogs indexed at each order, when the service has more than one
+	orderCatalogs map[int64][]string
+
+	pixelCache         *lruCache[pixelKey, []repository.Mastercat]
+	metadataCache      *lruCache[pixelKey, []repository.MetadataWithCoordinates]
+	mastercatResponses *lruCache[responseKey, []MastercatResult]
+	metadataResponses  *lruCache[responseKey, []MetadataResult]
+	invalidations      atomic.Int64
+	// indexVersion is the version of the index the cached pixels and responses belong to
+	indexVersion atomic.Int64
 }
 
 func NewConesearchService(options ...ConesearchOption) (*ConesearchService, error) {
-	ctx := context.Background()
 	service := &ConesearchService{
-		Scheme:     healpix.Nest,
-		Resolution: 4,
-		Catalogs:   []repository.Catalog{},
-		repository: nil,
-		mappers:    map[int64]*healpix.HEALPixMapper{},
-		ctx:        ctx,
+		Scheme:          healpix.Nest,
+		Resolution:      4,
+		Catalogs:        []repository.Catalog{},
+		MaxProperMotion: defaultMaxProperMotion,
+		ReferenceEpoch:  defaultReferenceEpoch,
+		repository:      nil,
+		mappers:         map[int64]*healpix.HEALPixMapper{},
+		catalogOrders:   map[string][]int64{},
+
+		pixelCache:         newLRUCache[pixelKey](0, objectsWeight[repository.Mastercat]),
+		metadataCache:      newLRUCache[pixelKey](0, objectsWeight[repository.MetadataWithCoordinates]),
+		mastercatResponses: newLRUCache[responseKey](0, mastercatWeight),
+		metadataResponses:  newLRUCache[responseKey](0, metadataWeight),
 	}
 	for _, opt := range options {
 		err := opt(service)
@@ -94,6 +117,8 @@ func NewConesearchService(options ...ConesearchOption) (*ConesearchService, erro
 	if err != nil {
 		return nil, err
 	}
+	service.catalogOrders = catalogOrders(service.Catalogs)
+	service.orderCatalogs = orderCatalogs(service.Catalogs, service.mappers)
 
 	slog.Debug("Created new ConesearchService", "scheme", service.Scheme, "catalogs", service.Catalogs, "resolution", service.Resolution)
 	return service, nil
@@ -122,28 +147,217 @@ func createServiceMappers(catalogs []repository.Catalog, scheme healpix.Ordering
 	return mappers, nil
 }
 
-func (c *ConesearchService) Conesearch(ra, dec, radius float64, nneighbor int, catalog string) ([]MastercatResult, error) {
+// catalogOrders groups the orders of the catalogs table by catalog.
+// A catalog can be indexed more than once, at different orders.
+func catalogOrders(catalogs []repository.Catalog) map[string][]int64 {
+	orders := make(map[string][]int64)
+	for _, cat := range catalogs {
+		name := strings.ToLower(cat.Name)
+		if !slices.Contains(orders[name], cat.Nside) {
+			orders[name] = append(orders[name], cat.Nside)
+		}
+	}
+	return orders
+}
+
+// orderCatalogs groups the catalogs of the catalogs table by order. It returns nil when
+// the service has a single order, since every object of the mastercat is then given at it.
+func orderCatalogs(catalogs []repository.Catalog, mappers map[int64]*healpix.HEALPixMapper) map[int64][]string {
+	if len(mappers) < 2 {
+		return nil
+	}
+	catalogs = slices.Clone(catalogs)
+	slices.SortFunc(catalogs, func(a, b repository.Catalog) int { return strings.Compare(a.Name, b.Name) })
+
+	orders := make(map[int64][]string)
+	for _, cat := range catalogs {
+		if !slices.Contains(orders[cat.Nside], cat.Name) {
+			orders[cat.Nside] = append(orders[cat.Nside], cat.Name)
+		}
+	}
+	return orders
+}
+
+// catalogsAt returns the catalogs whose pixels are given at the order, so the objects of
+// other catalogs are left out by the queries of the order: their pixel ids point somewhere
+// else in the sky. It returns nil when every catalog of the mastercat is searched.
+func (c *ConesearchService) catalogsAt(order int64) []string {
+	if c.orderCatalogs == nil {
+		return nil
+	}
+	return c.orderCatalogs[order]
+}
+
+// searchMappers returns the mappers whose pixels are searched for the requested catalog,
+// so a catalog is only queried with pixel ids of its own order
+func (c *ConesearchService) searchMappers(catalogName string) map[int64]*healpix.HEALPixMapper {
+	orders, ok := c.catalogOrders[strings.ToLower(catalogName)]
+	if catalogName == "all" || !ok {
+		return c.mappers
+	}
+	mappers := make(map[int64]*healpix.HEALPixMapper, len(orders))
+	for _, order := range orders {
+		mappers[order] = c.mappers[order]
+	}
+	return mappers
+}
+
+func (c *ConesearchService) Conesearch(ctx context.Context, ra, dec, radius float64, nneighbor int, catalog string) ([]MastercatResult, error) {
 	if err := ValidateArguments(ra, dec, radius, nneighbor, catalog); err != nil {
 		return nil, err
 	}
 
-	radius_radians := arcsecToRadians(radius)
-	point := healpix.RADec(float64(ra), float64(dec))
-	objects := make([]repository.Mastercat, 0)
-	for _, v := range c.mappers {
-		pixelRanges := v.QueryDiscInclusive(point, radius_radians, c.Resolution)
-		pixelList := pixelRangeToList(pixelRanges)
-		objs, err := c.getObjects(pixelList, catalog)
+	key := responseKey{version: c.indexVersion.Load(), search: "conesearch", ra: ra, dec: dec, radius: radius, nneighbor: nneighbor, catalog: catalog}
+	return cachedResponse(c.mastercatResponses, key, func() ([]MastercatResult, error) {
+		objects, err := findObjects(ctx, healpix.RADec(float64(ra), float64(dec)), arcsecToRadians(radius), c, catalog)
 		if err != nil {
 			return nil, err
 		}
-		objects = append(objects, objs...)
+
+		return ResultFromKnn(knn.NearestNeighborSearch(objects, ra, dec, radius, nneighbor), 0), nil
+	})
+}
+
+// ProbabilisticConesearch works like Conesearch, but also scores each neighbor
+// with the Bayes factor and match probability of being the same source as the target.
+//
+// posErr is the positional error of the target in arcsec. Neighbors without
+// a recorded positional error are returned without score.
+func (c *ConesearchService) ProbabilisticConesearch(
+	ctx context.Context,
+	ra, dec, radius, posErr float64,
+	nneighbor int,
+	catalog string,
+) ([]MastercatResult, error) {
+	if err := ValidateArguments(ra, dec, radius, nneighbor, catalog); err != nil {
+		return nil, err
+	}
+	if err := ValidatePosErr(posErr); err != nil {
+		return nil, err
+	}
+
+	objects, err := findObjects(ctx, healpix.RADec(float64(ra), float64(dec)), arcsecToRadians(radius), c, catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	// every candidate inside the cone is needed to estimate the prior of each catalog
+	neighbors := knn.NearestNeighborSearch(objects, ra, dec, radius, max(len(objects), 1))
+	ncandidates := make(map[string]int)
+	for _, m := range neighbors.Data {
+		ncandidates[m.Cat]++
+	}
+	neighbors.Data = neighbors.Data[:min(nneighbor, len(neighbors.Data))]
+	neighbors.Distance = neighbors.Distance[:len(neighbors.Data)]
+
+	result := ResultFromKnn(neighbors, 0)
+	for i := range result {
+		for j := range result[i].Data {
+			m := &result[i].Data[j]
+			m.BayesFactor, m.Probability = scoreMatch(m.Distance, radius, posErr, m.PosErr, ncandidates[m.Cat])
+		}
+	}
+	return result, nil
+}
+
+func findObjects(
+	ctx context.Context,
+	point healpix.Pointing,
+	radius_radians float64,
+	c *ConesearchService,
+	catalog string,
+) ([]repository.Mastercat, error) {
+	objects := make([]repository.Mastercat, 0)
+	for order, v := range c.searchMappers(catalog) {
+		pixelList, longRanges := splitPixelRanges(v.QueryDiscInclusive(point, radius_radians, c.Resolution))
+		for pixels := range slices.Chunk(pixelList, maxPixelsPerQuery) {
+			objs, err := c.getObjects(ctx, order, pixels, catalog)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, objs...)
+		}
+		for _, r := range longRanges {
+			objs, err := c.getObjectsInRange(ctx, order, r, catalog)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, objs...)
+		}
 	}
+	return objects, nil
+}
+
+// findObjectsInChunk works like findObjects for every position of a chunk, but queries
+// the union of their pixels only once. Neighboring positions share most of their pixels,
+// so this avoids fetching the same objects again for each position.
+//
+// The objects of each pixel are then handed back to every position whose cone covers it.
+// Each position is searched with its own radius, in arcsec.
+func findObjectsInChunk(
+	ctx context.Context,
+	ra, dec, radius []float64,
+	c *ConesearchService,
+	catalog string,
+) ([][]repository.Mastercat, error) {
+	objects := make([][]repository.Mastercat, len(ra))
+	for order, v := range c.searchMappers(catalog) {
+		pointPixels := make([][]int64, len(ra))
+		pointRanges := make([][]healpix.PixelRange, len(ra))
+		union := make(map[int64][]repository.Mastercat)
+		longRanges := make([]healpix.PixelRange, 0)
+		for i := range ra {
+			pointPixels[i], pointRanges[i] = splitPixelRanges(v.QueryDiscInclusive(healpix.RADec(ra[i], dec[i]), arcsecToRadians(radius[i]), c.Resolution))
+			for _, pixel := range pointPixels[i] {
+				union[pixel] = nil
+			}
+			longRanges = append(longRanges, pointRanges[i]...)
+		}
+
+		pixelList := slices.Sorted(maps.Keys(union))
+		for pixels := range slices.Chunk(pixelList, maxPixelsPerQuery) {
+			objs, err := c.getObjects(ctx, order, pixels, catalog)
+			if err != nil {
+				return nil, err
+			}
+			for _, obj := range objs {
+				union[obj.Ipix] = append(union[obj.Ipix], obj)
+			}
+		}
 
-	return ResultFromKnn(knn.NearestNeighborSearch(objects, ra, dec, radius, nneighbor), 0), nil
+		// long ranges of overlapping cones are merged, so their pixels are queried only once
+		inRanges := make([]repository.Mastercat, 0)
+		for _, r := range mergeRanges(longRanges) {
+			objs, err := c.getObjectsInRange(ctx, order, r, catalog)
+			if err != nil {
+				return nil, err
+			}
+			inRanges = append(inRanges, objs...)
+		}
+		slices.SortFunc(inRanges, func(a, b repository.Mastercat) int { return cmp.Compare(a.Ipix, b.Ipix) })
+
+		for i := range ra {
+			for _, pixel := range pointPixels[i] {
+				objects[i] = append(objects[i], union[pixel]...)
+			}
+			for _, r := range pointRanges[i] {
+				start, _ := slices.BinarySearchFunc(inRanges, r.Start, func(obj repository.Mastercat, pixel int64) int {
+					return cmp.Compare(obj.Ipix, pixel)
+				})
+				for _, obj := range inRanges[start:] {
+					if obj.Ipix >= r.Stop {
+						break
+					}
+					objects[i] = append(objects[i], obj)
+				}
+			}
+		}
+	}
+	return objects, nil
 }
 
 func (c *ConesearchService) FindMetadataByConesearch(
+	ctx context.Context,
 	ra, dec, radius float64,
 	nneighbor int,
 	catalog string,
@@ -152,127 +366,273 @@ func (c *ConesearchService) FindMetadataByConesearch(
 		return nil, err
 	}
 
-	objects, err := findMetadata(healpix.RADec(float64(ra), float64(dec)), arcsecToRadians(radius), c, catalog)
+	key := responseKey{version: c.indexVersion.Load(), search: "metadata", ra: ra, dec: dec, radius: radius, nneighbor: nneighbor, catalog: catalog}
+	return cachedResponse(c.metadataResponses, key, func() ([]MetadataResult, error) {
+		found, err := findMetadata(ctx, healpix.RADec(float64(ra), float64(dec)), arcsecToRadians(radius), c, catalog)
+		if err != nil {
+			return nil, fmt.Errorf("could not find metadata: %w", err)
+		}
+
+		result := make([]MetadataResult, 0, len(found))
+		for _, f := range found {
+			result = append(result, ResultFromKnnMetadata(knn.NearestNeighborSearchForMetadata(f.objects, ra, dec, radius, nneighbor, f.catalog))...)
+		}
+		return result, nil
+	})
+}
+
+// FindMetadataByProbabilisticConesearch works like FindMetadataByConesearch, but also scores
+// each neighbor with the Bayes factor and match probability of being the same source as the target.
+//
+// posErr is the positional error of the target in arcsec. Neighbors without
+// a recorded positional error are returned without score.
+func (c *ConesearchService) FindMetadataByProbabilisticConesearch(
+	ctx context.Context,
+	ra, dec, radius, posErr float64,
+	nneighbor int,
+	catalog string,
+) ([]MetadataResult, error) {
+	if err := ValidateArguments(ra, dec, radius, nneighbor, catalog); err != nil {
+		return nil, err
+	}
+	if err := ValidatePosErr(posErr); err != nil {
+		return nil, err
+	}
+
+	found, err := findMetadata(ctx, healpix.RADec(float64(ra), float64(dec)), arcsecToRadians(radius), c, catalog)
 	if err != nil {
-		return nil, fmt.Errorf("could not find allwise metadata: %w", err)
+		return nil, fmt.Errorf("could not find metadata: %w", err)
+	}
+
+	result := make([]MetadataResult, 0, len(found))
+	for _, f := range found {
+		// the knn result only keeps the metadata, so positional errors are looked up by id
+		posErrs := make(map[string]*float64)
+		for _, obj := range f.objects {
+			if withPosErr, ok := obj.(repository.MetadataWithPositionalError); ok {
+				if e, ok := withPosErr.GetPositionalError(); ok {
+					posErrs[obj.GetId()] = &e
+				}
+			}
+		}
+
+		// every candidate inside the cone is needed to estimate the prior of the catalog
+		neighbors := knn.NearestNeighborSearchForMetadata(f.objects, ra, dec, radius, max(len(f.objects), 1), f.catalog)
+		ncandidates := len(neighbors.Data)
+		neighbors.Data = neighbors.Data[:min(nneighbor, len(neighbors.Data))]
+		neighbors.Distance = neighbors.Distance[:len(neighbors.Data)]
+
+		catalogResult := ResultFromKnnMetadata(neighbors)
+		for i := range catalogResult {
+			for j := range catalogResult[i].Data {
+				m := &catalogResult[i].Data[j]
+				m.BayesFactor, m.Probability = scoreMatch(m.Distance, radius, posErr, posErrs[m.GetId()], ncandidates)
+			}
+		}
+		result = append(result, catalogResult...)
 	}
+	return result, nil
+}
 
-	return ResultFromKnnMetadata(knn.NearestNeighborSearchForMetadata(objects, ra, dec, radius, nneighbor, catalog)), nil
+// catalogMetadata holds the metadata found in a single catalog
+type catalogMetadata struct {
+	catalog string
+	objects []repository.MetadataWithCoordinates
 }
 
+// findMetadata searches the metadata of each requested catalog inside the cone.
+// The catalogs are kept apart, so each one can run its own nearest neighbor search.
 func findMetadata(
+	ctx context.Context,
 	point healpix.Pointing,
 	radius_radians float64,
 	c *ConesearchService,
-	catalog string,
-) ([]repository.MetadataWithCoordinates, error) {
-	objects := make([]repository.MetadataWithCoordinates, 0)
-	for _, v := range c.mappers {
-		pixelRanges := v.QueryDiscInclusive(point, radius_radians, c.Resolution)
-		pixelList := pixelRangeToList(pixelRanges)
-		objs, err := c.getMetadata(pixelList, catalog)
-		if err != nil {
-			return nil, err
+	catalogName string,
+) ([]catalogMetadata, error) {
+	descs, err := c.metadataCatalogs(catalogName)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]catalogMetadata, len(descs))
+	for i, desc := range descs {
+		found[i] = catalogMetadata{catalog: desc.Name, objects: make([]repository.MetadataWithCoordinates, 0)}
+		for order, v := range c.searchMappers(desc.Name) {
+			pixelList, longRanges := splitPixelRanges(v.QueryDiscInclusive(point, radius_radians, c.Resolution))
+			for pixels := range slices.Chunk(pixelList, maxPixelsPerQuery) {
+				objs, err := c.findMetadataInPixels(ctx, desc, order, v, pixels)
+				if err != nil {
+					return nil, err
+				}
+				found[i].objects = append(found[i].objects, objs...)
+			}
+			for _, r := range longRanges {
+				objs, err := desc.BetweenPixels(ctx, c.repository, r.Start, r.Stop)
+				if err != nil {
+					return nil, err
+				}
+				found[i].objects = append(found[i].objects, objs...)
+			}
 		}
-		objects = append(objects, objs...)
 	}
-	return objects, nil
+	return found, nil
 }
 
+// BulkConesearch runs a conesearch for each pair of coordinates.
+//
+// The radius and nneighbor hold either a single value used for every position,
+// or one value for each position.
+//
+// Each neighbor is returned as its own result, with the index of the coordinates
+// it belongs to. Results are ordered by index, and by distance within an index.
 func (c *ConesearchService) BulkConesearch(
+	ctx context.Context,
 	ra, dec []float64,
-	radius float64,
-	nneighbor int,
+	radius []float64,
+	nneighbor []int,
 	catalog string,
 	chunkSize int,
 	maxBulkConcurrency int,
 ) ([]MastercatResult, error) {
-	if err := ValidateBulkArguments(ra, dec, radius, nneighbor, catalog); err != nil {
+	resultsByIndex := make([][]MastercatResult, len(ra))
+	err := c.BulkConesearchStream(ctx, ra, dec, radius, nneighbor, catalog, chunkSize, maxBulkConcurrency, func(results []MastercatResult) error {
+		for _, r := range results {
+			resultsByIndex[r.Index] = append(resultsByIndex[r.Index], r)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	radius_radians := arcsecToRadians(radius)
+	return slices.Concat(resultsByIndex...), nil
+}
+
+// BulkConesearchStream works like BulkConesearch, but hands the results of each chunk
+// of coordinates to emit as soon as the chunk is done, instead of keeping them in memory.
+//
+// Chunks may finish in any order, but emit is never called concurrently and receives
+// every result of an index at once. The search stops at the first error, either
+// from the repository or from emit, or when the context is done.
+func (c *ConesearchService) BulkConesearchStream(
+	ctx context.Context,
+	ra, dec []float64,
+	radius []float64,
+	nneighbor []int,
+	catalog string,
+	chunkSize int,
+	maxBulkConcurrency int,
+	emit func([]MastercatResult) error,
+) error {
+	if err := ValidateBulkArguments(ra, dec, radius, nneighbor, catalog); err != nil {
+		return err
+	}
+
+	radius = perPosition(radius, len(ra))
+	nneighbor = perPosition(nneighbor, len(ra))
 	numChunks := (len(ra) + chunkSize - 1) / chunkSize
-	resultsChan := make(chan indexedResult, numChunks)
 	errChan := make(chan error, numChunks)
 	var wg sync.WaitGroup
+	var emitMutex sync.Mutex
+	failed := false
+
+	// the first error, or the end of the request, stops the chunks that didn't start yet
+	stopped := func() bool {
+		emitMutex.Lock()
+		defer emitMutex.Unlock()
+		return failed || ctx.Err() != nil
+	}
+	fail := func(err error) {
+		failed = true
+		errChan <- err
+	}
 
 	sem := make(chan struct{}, maxBulkConcurrency)
 
-	for _, v := range c.mappers {
-		for i := 0; i < len(ra); i += chunkSize {
-			wg.Add(1)
-
-			end := min(i+chunkSize, len(ra))
-			chunkRa := ra[i:end]
-			chunkDec := dec[i:end]
-
-			go func(chunkRa, chunkDec []float64, baseIndex int) {
-				sem <- struct{}{}
-
-				defer func() {
-					<-sem
-					wg.Done()
-				}()
-
-				for j := range chunkRa {
-					point := healpix.RADec(chunkRa[j], chunkDec[j])
-					pixelRange := v.QueryDiscInclusive(point, radius_radians, c.Resolution)
-					pixelList := pixelRangeToList(pixelRange)
-					objs, err := c.getObjects(pixelList, catalog)
-					if err != nil {
-						errChan <- err
-						break
-					}
+	for i := 0; i < len(ra); i += chunkSize {
+		wg.Add(1)
 
-					resultsChan <- indexedResult{
-						index:  baseIndex + j,
-						result: knn.NearestNeighborSearch(objs, chunkRa[j], chunkDec[j], radius, nneighbor),
-					}
-				}
+		end := min(i+chunkSize, len(ra))
+		chunkRa := ra[i:end]
+		chunkDec := dec[i:end]
+		chunkRadius := radius[i:end]
+		chunkNneighbor := nneighbor[i:end]
 
-			}(chunkRa, chunkDec, i)
-		}
-	}
+		go func(chunkRa, chunkDec, chunkRadius []float64, chunkNneighbor []int, baseIndex int) {
+			sem <- struct{}{}
 
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-		close(errChan)
-	}()
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
 
-	resultsByIndex := make([][]MastercatResult, len(ra))
-	for indexed := range resultsChan {
-		resultsByIndex[indexed.index] = ResultFromKnn(indexed.result, indexed.index)
+			if stopped() {
+				return
+			}
+
+			objs, err := findObjectsInChunk(ctx, chunkRa, chunkDec, chunkRadius, c, catalog)
+			if err != nil {
+				emitMutex.Lock()
+				fail(err)
+				emitMutex.Unlock()
+				return
+			}
+
+			results := make([]MastercatResult, 0, len(chunkRa))
+			for j := range chunkRa {
+				neighbors := knn.NearestNeighborSearch(objs[j], chunkRa[j], chunkDec[j], chunkRadius[j], chunkNneighbor[j])
+				results = append(results, uniqueNeighbors(neighbors, baseIndex+j)...)
+			}
+
+			emitMutex.Lock()
+			defer emitMutex.Unlock()
+			if failed {
+				return
+			}
+			if err := emit(results); err != nil {
+				fail(err)
+			}
+		}(chunkRa, chunkDec, chunkRadius, chunkNneighbor, i)
 	}
+
+	wg.Wait()
+	close(errChan)
 	for err := range errChan {
-		return nil, err
+		return err
 	}
+	return ctx.Err()
+}
 
-	uniqueObjects := make([]MastercatResult, 0)
-	seenIDs := make(map[int]map[string]bool)
-	for i := range resultsByIndex {
-		if resultsByIndex[i] == nil {
-			resultsByIndex[i] = []MastercatResult{}
-		}
-		if seenIDs[i] == nil {
-			seenIDs[i] = make(map[string]bool)
-		}
-		for _, mastercatResult := range resultsByIndex[i] {
-			for j := range mastercatResult.Data {
-				id := mastercatResult.Data[j].ID
-				if !seenIDs[i][id] {
-					seenIDs[i][id] = true
-					uniqueObjects = append(uniqueObjects, MastercatResult{
-						Catalog: mastercatResult.Catalog,
-						Data:    []MastercatExtended{mastercatResult.Data[j]},
-						Index:   i,
-					})
-				}
-			}
+// uniqueNeighbors splits the neighbors of a single index into one result each,
+// skipping ids that were already found through another mapper
+func uniqueNeighbors(neighbors knn.KnnResult[repository.Mastercat], index int) []MastercatResult {
+	result := make([]MastercatResult, 0, len(neighbors.Data))
+	seenIDs := make(map[string]bool)
+	for i, m := range neighbors.Data {
+		if seenIDs[m.ID] {
+			continue
 		}
+		seenIDs[m.ID] = true
+		result = append(result, MastercatResult{
+			Catalog: m.Cat,
+			Data:    []MastercatExtended{{Mastercat: m, Distance: neighbors.Distance[i]}},
+			Index:   index,
+		})
 	}
-	return uniqueObjects, nil
+	return result
+}
+
+// perPosition returns one value for each of n positions,
+// repeating the value when a single one was given
+func perPosition[T any](values []T, n int) []T {
+	if len(values) != 1 {
+		return values
+	}
+	result := make([]T, n)
+	for i := range result {
+		result[i] = values[0]
+	}
+	return result
 }
 
 func arcsecToRadians(arcsec float64) float64 {
@@ -289,8 +649,30 @@ func pixelRangeToList(pixelRanges []healpix.PixelRange) []int64 {
 	return result
 }
 
-func (c *ConesearchService) getObjects(pixelList []int64, catalog string) ([]repository.Mastercat, error) {
-	objects, err := c.repository.FindObjects(c.ctx, pixelList)
+// splitPixelRanges returns the pixels of the short ranges, which are queried by id and cached,
+// and the long ranges, which are queried by their bounds so their pixels are never listed
+func splitPixelRanges(pixelRanges []healpix.PixelRange) ([]int64, []healpix.PixelRange) {
+	short := make([]healpix.PixelRange, 0, len(pixelRanges))
+	long := make([]healpix.PixelRange, 0)
+	for _, r := range pixelRanges {
+		if r.Stop-r.Start >= minQueriedPixelRange {
+			long = append(long, r)
+		} else {
+			short = append(short, r)
+		}
+	}
+	return pixelRangeToList(short), long
+}
+
+// getObjectsInRange works like getObjects for every pixel of a range, without the pixel cache
+func (c *ConesearchService) getObjectsInRange(ctx context.Context, order int64, r healpix.PixelRange, catalog string) ([]repository.Mastercat, error) {
+	var objects []repository.Mastercat
+	var err error
+	if catalogs := c.catalogsAt(order); catalogs != nil {
+		objects, err = c.repository.FindObjectsOfCatalogsBetweenPixels(ctx, repository.FindObjectsOfCatalogsBetweenPixelsParams{Start: r.Start, Stop: r.Stop, Cat: catalogs})
+	} else {
+		objects, err = c.repository.FindObjectsBetweenPixels(ctx, repository.FindObjectsBetweenPixelsParams{Start: r.Start, Stop: r.Stop})
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -300,70 +682,57 @@ func (c *ConesearchService) getObjects(pixelList []int64, catalog string) ([]rep
 	return objects, nil
 }
 
-func (c *ConesearchService) getMetadata(pixelList []int64, catalog string) ([]repository.MetadataWithCoordinates, error) {
-	objects := make([]repository.MetadataWithCoordinates, 0)
+func (c *ConesearchService) getObjects(ctx context.Context, order int64, pixelList []int64, catalog string) ([]repository.Mastercat, error) {
+	objects, err := c.findObjectsInPixels(ctx, order, pixelList)
+	if err != nil {
+		return nil, err
+	}
+	if catalog != "all" {
+		return filterByCatalog(objects, catalog), nil
+	}
+	return objects, nil
+}
 
-	switch catalog {
-	case "all":
-		objects, err := c.getAllwiseMetadata(objects, pixelList)
-		if err != nil {
-			return nil, err
+// metadataCatalogs returns the catalogs whose metadata is searched for the requested catalog.
+// For all, those are the catalogs of the service with metadata, in the order of the service catalogs.
+func (c *ConesearchService) metadataCatalogs(catalogName string) ([]catalog.CatalogDescriptor, error) {
+	if catalogName != "all" {
+		desc, ok := catalog.Lookup(catalogName)
+		if !ok || desc.FromPixels == nil {
+			return nil, NewValidationError("Metadata search not available for catalog", catalogName, "catalog")
 		}
+		return []catalog.CatalogDescriptor{desc}, nil
+	}
 
-		objects, err = c.getGaiaMetadata(objects, pixelList)
-		if err != nil {
-			return nil, err
-		}
-		return objects, nil
-	case "allwise":
-		objects, err := c.getAllwiseMetadata(objects, pixelList)
-		if err != nil {
-			return nil, err
+	descs := make([]catalog.CatalogDescriptor, 0, len(c.Catalogs))
+	for _, cat := range c.Catalogs {
+		desc, ok := catalog.Lookup(cat.Name)
+		if !ok || desc.FromPixels == nil {
+			continue
 		}
-		return objects, nil
-	case "gaia":
-		objects, err := c.getGaiaMetadata(objects, pixelList)
-		if err != nil {
-			return nil, err
+		if slices.ContainsFunc(descs, func(d catalog.CatalogDescriptor) bool { return d.Name == desc.Name }) {
+			continue
 		}
-		return objects, nil
-	default:
-		return nil, fmt.Errorf("catalog %s not supported", catalog)
+		descs = append(descs, desc)
 	}
+	return descs, nil
 }
 
-func (c *ConesearchService) getGaiaMetadata(
-	objects []repository.MetadataWithCoordinates,
-	pixelList []int64,
-) ([]repository.MetadataWithCoordinates, error) {
-	objectsCopy := make([]repository.MetadataWithCoordinates, len(objects))
-	copy(objectsCopy, objects)
-
-	gaia, err := c.repository.GetGaiaFromPixels(c.ctx, pixelList)
+func (c *ConesearchService) getMetadata(ctx context.Context, pixelList []int64, catalogName string) ([]repository.MetadataWithCoordinates, error) {
+	descs, err := c.metadataCatalogs(catalogName)
 	if err != nil {
 		return nil, err
 	}
-	for _, g := range gaia {
-		objectsCopy = append(objectsCopy, g)
-	}
-	return objectsCopy, nil
-}
 
-func (c *ConesearchService) getAllwiseMetadata(
-	objects []repository.MetadataWithCoordinates,
-	pixelList []int64,
-) ([]repository.MetadataWithCoordinates, error) {
-	objectsCopy := make([]repository.MetadataWithCoordinates, len(objects))
-	copy(objectsCopy, objects)
-
-	allwise, err := c.repository.GetAllwiseFromPixels(c.ctx, pixelList)
-	if err != nil {
-		return nil, err
-	}
-	for _, aw := range allwise {
-		objectsCopy = append(objectsCopy, aw)
+	objects := make([]repository.MetadataWithCoordinates, 0)
+	for _, desc := range descs {
+		rows, err := desc.FromPixels(ctx, c.repository, pixelList)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, rows...)
 	}
-	return objectsCopy, nil
+	return objects, nil
 }
 
 func filterByCatalog(objects []repository.Mastercat, catalog string) []repository.Mastercat {
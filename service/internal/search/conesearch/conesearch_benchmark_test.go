@@ -0,0 +1,129 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch/test_helpers"
+	"github.com/dirodriguezm/xmatch/service/internal/search/knn"
+	"github.com/dirodriguezm/xmatch/service/internal/testutils"
+
+	"github.com/dirodriguezm/healpix"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	benchmarkObjects   = 20000
+	benchmarkPositions = 5000
+	benchmarkChunkSize = 500
+	// every object and position falls in a field of this many degrees
+	benchmarkField  = 0.05
+	benchmarkRadius = 5.0
+)
+
+// benchmarkService creates a service backed by a SQLite database filled with random objects
+func benchmarkService(b *testing.B) *ConesearchService {
+	b.Helper()
+	rootPath, err := testutils.FindRootModulePath(5)
+	if err != nil {
+		b.Fatal(err)
+	}
+	dbFile := filepath.Join(b.TempDir(), "bench.db")
+	if err := test_helpers.Migrate(dbFile, rootPath); err != nil {
+		b.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_journal_mode=WAL&_sync=NORMAL&_busy_timeout=5000", dbFile))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { db.Close() })
+
+	mapper, err := healpix.NewHEALPixMapper(18, healpix.Nest)
+	if err != nil {
+		b.Fatal(err)
+	}
+	random := rand.New(rand.NewSource(1))
+	objects := make([]any, benchmarkObjects)
+	for i := range objects {
+		ra, dec := 10+random.Float64()*benchmarkField, 10+random.Float64()*benchmarkField
+		objects[i] = repository.Mastercat{
+			ID:   fmt.Sprintf("bench-%d", i),
+			Ra:   ra,
+			Dec:  dec,
+			Cat:  "vlass",
+			Ipix: mapper.PixelAt(healpix.RADec(ra, dec)),
+		}
+	}
+	repo := repository.New(db)
+	if err := repo.BulkInsertObject(context.Background(), db, objects); err != nil {
+		b.Fatal(err)
+	}
+
+	service, err := NewConesearchService(
+		WithScheme(healpix.Nest),
+		WithRepository(repo),
+		WithCatalogs([]repository.Catalog{{Name: "vlass", Nside: 18}}),
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return service
+}
+
+func benchmarkPositionList() ([]float64, []float64) {
+	random := rand.New(rand.NewSource(2))
+	ra := make([]float64, benchmarkPositions)
+	dec := make([]float64, benchmarkPositions)
+	for i := range ra {
+		ra[i], dec[i] = 10+random.Float64()*benchmarkField, 10+random.Float64()*benchmarkField
+	}
+	return ra, dec
+}
+
+// BenchmarkBulkConesearch compares querying the pixels of each position on its own
+// with querying the union of the pixels of a chunk of positions at once
+func BenchmarkBulkConesearch(b *testing.B) {
+	service := benchmarkService(b)
+	ra, dec := benchmarkPositionList()
+	ctx := context.Background()
+
+	b.Run("per position", func(b *testing.B) {
+		for b.Loop() {
+			for i := range ra {
+				objs, err := findObjects(ctx, healpix.RADec(ra[i], dec[i]), arcsecToRadians(benchmarkRadius), service, "all")
+				if err != nil {
+					b.Fatal(err)
+				}
+				uniqueNeighbors(knn.NearestNeighborSearch(objs, ra[i], dec[i], benchmarkRadius, 1), i)
+			}
+		}
+	})
+
+	b.Run("per chunk", func(b *testing.B) {
+		for b.Loop() {
+			_, err := service.BulkConesearch(ctx, ra, dec, []float64{benchmarkRadius}, []int{1}, "all", benchmarkChunkSize, 1)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
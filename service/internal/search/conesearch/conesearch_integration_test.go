@@ -70,8 +70,9 @@ service:
   bulk_chunk_size: 500
   max_bulk_concurrency: 1
   lightcurve_service:
-    neowise:
-      use_cntr_filter: true
+    sources:
+      - type: neowise
+        filter: id
 `, dbFile)
 	err = os.WriteFile(configPath, []byte(config), 0644)
 	if err != nil {
@@ -149,7 +150,7 @@ func TestConesearch(t *testing.T) {
 		}
 	}
 
-	result, err := service.Conesearch(0, 0, 1, 10, "all")
+	result, err := service.Conesearch(context.Background(), 0, 0, 1, 10, "all")
 	if err != nil {
 		t.Error(err)
 	}
@@ -207,7 +208,7 @@ func TestConesearch_WithMetadata(t *testing.T) {
 		}
 	}
 
-	result, err := service.FindMetadataByConesearch(0, 0, 1, 10, "allwise")
+	result, err := service.FindMetadataByConesearch(context.Background(), 0, 0, 1, 10, "allwise")
 	if err != nil {
 		t.Error(err)
 	}
@@ -278,7 +279,7 @@ func TestBulkConesearch(t *testing.T) {
 
 	// test bulk conesearch
 	for _, tc := range testCases {
-		result, err := service.BulkConesearch(tc.ra, tc.dec, tc.radius, tc.nneighbor, "all", 1, 1)
+		result, err := service.BulkConesearch(context.Background(), tc.ra, tc.dec, []float64{tc.radius}, []int{tc.nneighbor}, "all", 1, 1)
 		if err != nil {
 			t.Error(err)
 		}
@@ -299,10 +300,11 @@ func TestBulkConesearch(t *testing.T) {
 	// test that coordinates with no matches are properly handled
 	t.Run("coordinates with no matches return empty results", func(t *testing.T) {
 		noMatchResult, err := service.BulkConesearch(
+			context.Background(),
 			[]float64{100, 200}, // coordinates with no objects nearby
 			[]float64{50, 60},
-			1,
-			10,
+			[]float64{1},
+			[]int{10},
 			"all",
 			1,
 			1,
@@ -314,10 +316,11 @@ func TestBulkConesearch(t *testing.T) {
 	// test that Index field is correctly set
 	t.Run("index field is correctly set for matched coordinates", func(t *testing.T) {
 		multiMatchResult, err := service.BulkConesearch(
+			context.Background(),
 			[]float64{0, 10}, // first matches A, second matches B
 			[]float64{0, 10},
-			1,
-			10,
+			[]float64{1},
+			[]int{10},
 			"all",
 			1,
 			1,
@@ -338,10 +341,11 @@ func TestBulkConesearch(t *testing.T) {
 	// test that non-matching coordinates in the middle are handled correctly
 	t.Run("non-matching coordinates in the middle preserve index correctness", func(t *testing.T) {
 		middleNoMatchResult, err := service.BulkConesearch(
+			context.Background(),
 			[]float64{0, 50, 10, 60},
 			[]float64{0, 50, 10, 60},
-			1,
-			10,
+			[]float64{1},
+			[]int{10},
 			"all",
 			1,
 			1,
@@ -15,7 +15,9 @@
 package conesearch
 
 import (
+	"context"
 	"errors"
+	"slices"
 	"testing"
 
 	"github.com/dirodriguezm/xmatch/service/internal/repository"
@@ -37,7 +39,7 @@ func TestConesearch(t *testing.T) {
 	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
 	require.NoError(t, err)
 
-	result, err := service.Conesearch(1, 1, 1, 1, "all")
+	result, err := service.Conesearch(context.Background(), 1, 1, 1, 1, "all")
 	require.NoError(t, err)
 	repo.AssertExpectations(t)
 
@@ -52,7 +54,7 @@ func TestConesearch_WithRepositoryError(t *testing.T) {
 	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
 	require.NoError(t, err)
 
-	_, err = service.Conesearch(1, 1, 1, 1, "all")
+	_, err = service.Conesearch(context.Background(), 1, 1, 1, 1, "all")
 	repo.AssertExpectations(t)
 	if assert.Error(t, err) {
 		require.Equal(t, errors.New("Test error"), err)
@@ -60,22 +62,35 @@ func TestConesearch_WithRepositoryError(t *testing.T) {
 }
 
 func TestConesearch_WithMultipleMappers(t *testing.T) {
-	vlassObjects := []repository.Mastercat{
+	// the pixels of both orders find every object, but each catalog is only
+	// queried with the pixels of the order it was indexed at
+	objects := []repository.Mastercat{
 		{ID: "A", Ra: 1, Dec: 1, Cat: "vlass"},
 		{ID: "B", Ra: 10, Dec: 10, Cat: "vlass"},
-	}
-	ztfObjects := []repository.Mastercat{
 		{ID: "ZTFA", Ra: 1, Dec: 1, Cat: "ztf"},
 	}
+	queried := make([][]string, 0)
 	repo := &MockRepository{}
-	repo.On("FindObjects", mock.Anything, mock.Anything).Return(vlassObjects, nil).Once()
-	repo.On("FindObjects", mock.Anything, mock.Anything).Return(ztfObjects, nil).Once()
+	repo.On("FindObjectsOfCatalogs", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, params repository.FindObjectsOfCatalogsParams) ([]repository.Mastercat, error) {
+			queried = append(queried, params.Cat)
+			found := make([]repository.Mastercat, 0)
+			for _, obj := range objects {
+				if slices.Contains(params.Cat, obj.Cat) {
+					found = append(found, obj)
+				}
+			}
+			return found, nil
+		},
+	).Twice()
 	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}, {Name: "ztf", Nside: 12}}
 	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
 	require.NoError(t, err)
 
-	result, err := service.Conesearch(1, 1, 1, 2, "all")
+	result, err := service.Conesearch(context.Background(), 1, 1, 1, 3, "all")
+	require.NoError(t, err)
 	repo.AssertExpectations(t)
+	require.ElementsMatch(t, [][]string{{"vlass"}, {"ztf"}}, queried)
 
 	// both objects in the result should be in the same coordinates, but different catalog
 	require.Len(t, result, 2)
@@ -87,14 +102,49 @@ func TestConesearch_WithMultipleMappers(t *testing.T) {
 			cats[i] = result[i].Data[j].Cat
 		}
 	}
-	require.Subset(t, ids, []string{"A", "ZTFA"})
-	require.Subset(t, cats, []string{"vlass", "ztf"})
+	require.ElementsMatch(t, []string{"A", "ZTFA"}, ids)
+	require.ElementsMatch(t, []string{"vlass", "ztf"}, cats)
+}
+
+func TestConesearch_CatalogOrder(t *testing.T) {
+	repo := &MockRepository{}
+	repo.On("FindObjectsOfCatalogs", mock.Anything, mock.MatchedBy(func(params repository.FindObjectsOfCatalogsParams) bool {
+		return slices.Equal(params.Cat, []string{"ztf"})
+	})).Return([]repository.Mastercat{{ID: "ZTFA", Ra: 1, Dec: 1, Cat: "ztf"}}, nil).Once()
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}, {Name: "ztf", Nside: 12}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	// ztf is only searched with the pixels of its own order, so a single query is made
+	result, err := service.Conesearch(context.Background(), 1, 1, 1, 1, "ztf")
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	require.Len(t, result, 1)
+	require.Equal(t, "ZTFA", result[0].Data[0].ID)
+}
+
+func TestConesearch_LongPixelRanges(t *testing.T) {
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return([]repository.Mastercat{}, nil)
+	repo.On("FindObjectsBetweenPixels", mock.Anything, mock.MatchedBy(func(params repository.FindObjectsBetweenPixelsParams) bool {
+		return params.Stop-params.Start >= minQueriedPixelRange
+	})).Return([]repository.Mastercat{{ID: "A", Ra: 1, Dec: 1, Cat: "vlass"}, {ID: "ZTFA", Ra: 1, Dec: 1, Cat: "ztf"}}, nil)
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	// a large cone covers whole blocks of pixels, which are queried by their bounds
+	result, err := service.Conesearch(context.Background(), 1, 1, 600, 1, "vlass")
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	require.Len(t, result, 1)
+	require.Equal(t, "A", result[0].Data[0].ID)
 }
 
 func TestBulkConesearch(t *testing.T) {
 	objects := []repository.Mastercat{
-		{ID: "A", Ra: 1, Dec: 1, Cat: "vlass"},
-		{ID: "B", Ra: 10, Dec: 10, Cat: "vlass"},
+		{ID: "A", Ra: 1, Dec: 1, Cat: "vlass", Ipix: pixelAt(t, 18, 1, 1)},
+		{ID: "B", Ra: 10, Dec: 10, Cat: "vlass", Ipix: pixelAt(t, 18, 10, 10)},
 	}
 	repo := &MockRepository{}
 	repo.On("FindObjects", mock.Anything, mock.Anything).Return(objects, nil)
@@ -118,7 +168,7 @@ func TestBulkConesearch(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		result, err := service.BulkConesearch(tc.ra, tc.dec, tc.radius, tc.nneighbor, "all", 2, 1)
+		result, err := service.BulkConesearch(context.Background(), tc.ra, tc.dec, []float64{tc.radius}, []int{tc.nneighbor}, "all", 2, 1)
 		require.NoError(t, err)
 		repo.AssertExpectations(t)
 
@@ -131,6 +181,111 @@ func TestBulkConesearch(t *testing.T) {
 	}
 }
 
+func TestBulkConesearch_SingleQueryPerChunk(t *testing.T) {
+	objects := []repository.Mastercat{
+		{ID: "A", Ra: 1, Dec: 1, Cat: "vlass", Ipix: pixelAt(t, 18, 1, 1)},
+		{ID: "B", Ra: 1.0001, Dec: 1, Cat: "vlass", Ipix: pixelAt(t, 18, 1.0001, 1)},
+		{ID: "C", Ra: 10, Dec: 10, Cat: "vlass", Ipix: pixelAt(t, 18, 10, 10)},
+	}
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return(objects, nil).Once()
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	// every position is in the same chunk, so the repository is queried once
+	// and each position only gets the objects of its own pixels
+	ra := []float64{1, 1.0001, 10, 20}
+	dec := []float64{1, 1, 10, 20}
+	result, err := service.BulkConesearch(context.Background(), ra, dec, []float64{0.1}, []int{10}, "all", len(ra), 1)
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	ids := make(map[int][]string)
+	for _, r := range result {
+		ids[r.Index] = append(ids[r.Index], r.Data[0].ID)
+	}
+	require.Equal(t, map[int][]string{0: {"A"}, 1: {"B"}, 2: {"C"}}, ids)
+}
+
+func TestBulkConesearch_LongPixelRanges(t *testing.T) {
+	objects := []repository.Mastercat{
+		{ID: "A", Ra: 1, Dec: 1, Cat: "vlass", Ipix: pixelAt(t, 18, 1, 1)},
+		{ID: "B", Ra: 1.01, Dec: 1, Cat: "vlass", Ipix: pixelAt(t, 18, 1.01, 1)},
+	}
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.MatchedBy(func(pixels []int64) bool {
+		return len(pixels) <= maxPixelsPerQuery
+	})).Return([]repository.Mastercat{}, nil)
+	repo.On("FindObjectsBetweenPixels", mock.Anything, mock.MatchedBy(func(params repository.FindObjectsBetweenPixelsParams) bool {
+		return params.Stop-params.Start >= minQueriedPixelRange
+	})).Return(objects, nil)
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	// large cones cover whole blocks of pixels, which are queried by their bounds
+	// and handed back to the positions whose blocks hold each object
+	ra := []float64{1, 1.01}
+	dec := []float64{1, 1}
+	result, err := service.BulkConesearch(context.Background(), ra, dec, []float64{600, 1}, []int{1}, "all", len(ra), 1)
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	ids := make(map[int][]string)
+	for _, r := range result {
+		ids[r.Index] = append(ids[r.Index], r.Data[0].ID)
+	}
+	require.Equal(t, map[int][]string{0: {"A"}}, ids)
+}
+
+func TestBulkConesearch_PerPositionArguments(t *testing.T) {
+	// B is 1.8 arcsec away from A
+	objects := []repository.Mastercat{
+		{ID: "A", Ra: 1, Dec: 1, Cat: "vlass", Ipix: pixelAt(t, 18, 1, 1)},
+		{ID: "B", Ra: 1.0005, Dec: 1, Cat: "vlass", Ipix: pixelAt(t, 18, 1.0005, 1)},
+	}
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return(objects, nil)
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	ra := []float64{1, 1, 1}
+	dec := []float64{1, 1, 1}
+	radius := []float64{1, 3, 3}
+	nneighbor := []int{10, 10, 1}
+	result, err := service.BulkConesearch(context.Background(), ra, dec, radius, nneighbor, "all", 2, 1)
+	require.NoError(t, err)
+
+	ids := make(map[int][]string)
+	for _, r := range result {
+		ids[r.Index] = append(ids[r.Index], r.Data[0].ID)
+	}
+	require.Equal(t, map[int][]string{0: {"A"}, 1: {"A", "B"}, 2: {"A"}}, ids)
+}
+
+func TestBulkConesearch_PerPositionValidation(t *testing.T) {
+	testCases := map[string]struct {
+		radius    []float64
+		nneighbor []int
+		field     string
+	}{
+		"radius length":    {[]float64{1, 1}, []int{1}, "radius"},
+		"missing radius":   {nil, []int{1}, "radius"},
+		"invalid radius":   {[]float64{1, 1, -1}, []int{1}, "radius"},
+		"nneighbor length": {[]float64{1}, []int{1, 1}, "nneighbor"},
+		"invalid neighbor": {[]float64{1}, []int{1, 0, 1}, "nneighbor"},
+	}
+
+	for name, tc := range testCases {
+		err := ValidateBulkArguments([]float64{1, 2, 3}, []float64{1, 2, 3}, tc.radius, tc.nneighbor, "all")
+		var validationErr ValidationError
+		require.ErrorAs(t, err, &validationErr, name)
+		require.Equal(t, tc.field, validationErr.Field, name)
+	}
+}
+
 func TestBulkConesearch_WithRepositoryError(t *testing.T) {
 	repo := &MockRepository{}
 	repo.On("FindObjects", mock.Anything, mock.Anything).Return(nil, errors.New("repository error"))
@@ -138,12 +293,60 @@ func TestBulkConesearch_WithRepositoryError(t *testing.T) {
 	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
 	require.NoError(t, err)
 
-	_, err = service.BulkConesearch([]float64{1, 10}, []float64{1, 10}, 1, 100, "all", 2, 1)
+	_, err = service.BulkConesearch(context.Background(), []float64{1, 10}, []float64{1, 10}, []float64{1}, []int{100}, "all", 2, 1)
 	repo.AssertExpectations(t)
 	require.Error(t, err)
 	require.Equal(t, "repository error", err.Error())
 }
 
+func TestBulkConesearchStream(t *testing.T) {
+	objects := []repository.Mastercat{
+		{ID: "A", Ra: 1, Dec: 1, Cat: "vlass", Ipix: pixelAt(t, 18, 1, 1)},
+		{ID: "B", Ra: 10, Dec: 10, Cat: "vlass", Ipix: pixelAt(t, 18, 10, 10)},
+	}
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return(objects, nil)
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	ra := []float64{1, 10, 1, 20, 10}
+	dec := []float64{1, 10, 1, 20, 10}
+	batches := make([][]MastercatResult, 0)
+	err = service.BulkConesearchStream(context.Background(), ra, dec, []float64{1}, []int{100}, "all", 2, 2, func(results []MastercatResult) error {
+		batches = append(batches, results)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// one batch per chunk of coordinates, without a result for the empty cone
+	require.Len(t, batches, 3)
+	ids := make(map[int]string)
+	for _, batch := range batches {
+		for _, r := range batch {
+			require.Len(t, r.Data, 1)
+			ids[r.Index] = r.Data[0].ID
+		}
+	}
+	require.Equal(t, map[int]string{0: "A", 1: "B", 2: "A", 4: "B"}, ids)
+}
+
+func TestBulkConesearchStream_WithEmitError(t *testing.T) {
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return([]repository.Mastercat{{ID: "A", Ra: 1, Dec: 1, Cat: "vlass", Ipix: pixelAt(t, 18, 1, 1)}}, nil)
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	calls := 0
+	err = service.BulkConesearchStream(context.Background(), []float64{1, 1, 1}, []float64{1, 1, 1}, []float64{1}, []int{1}, "all", 1, 1, func(results []MastercatResult) error {
+		calls++
+		return errors.New("client disconnected")
+	})
+	require.EqualError(t, err, "client disconnected")
+	require.Equal(t, 1, calls, "no chunk is emitted after an error")
+}
+
 func TestConesearch_WithMetadata(t *testing.T) {
 	objects := []repository.GetAllwiseFromPixelsRow{
 		{ID: "A", Ra: 1, Dec: 1},
@@ -155,7 +358,7 @@ func TestConesearch_WithMetadata(t *testing.T) {
 	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
 	require.NoError(t, err)
 
-	result, err := service.FindMetadataByConesearch(1, 1, 1, 1, "allwise")
+	result, err := service.FindMetadataByConesearch(context.Background(), 1, 1, 1, 1, "allwise")
 	require.NoError(t, err)
 	repo.AssertExpectations(t)
 
@@ -163,6 +366,74 @@ func TestConesearch_WithMetadata(t *testing.T) {
 	require.Equal(t, result[0].Data[0].GetId(), "A")
 }
 
+func TestConesearch_WithMetadataInLongPixelRanges(t *testing.T) {
+	repo := &MockRepository{}
+	repo.On("GetAllwiseFromPixels", mock.Anything, mock.Anything).Return([]repository.GetAllwiseFromPixelsRow{}, nil)
+	repo.On("GetAllwiseBetweenPixels", mock.Anything, mock.MatchedBy(func(params repository.GetAllwiseBetweenPixelsParams) bool {
+		return params.Stop-params.Start >= minQueriedPixelRange
+	})).Return([]repository.GetAllwiseBetweenPixelsRow{{ID: "A", Ra: 1, Dec: 1}}, nil)
+	catalogs := []repository.Catalog{{Name: "allwise", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	result, err := service.FindMetadataByConesearch(context.Background(), 1, 1, 600, 1, "allwise")
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	require.Len(t, result, 1)
+	require.Equal(t, "A", result[0].Data[0].GetId())
+}
+
+func TestConesearch_WithErositaMetadata(t *testing.T) {
+	objects := []repository.GetErositaFromPixelsRow{
+		{ID: "A", Ra_2: 1, Dec_2: 1},
+		{ID: "B", Ra_2: 10, Dec_2: 10},
+	}
+	repo := &MockRepository{}
+	repo.On("GetErositaFromPixels", mock.Anything, mock.Anything).Return(objects, nil)
+	catalogs := []repository.Catalog{{Name: "erosita", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	result, err := service.FindMetadataByConesearch(context.Background(), 1, 1, 1, 1, "erosita")
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	require.Len(t, result, 1)
+	require.Equal(t, "eROSITA", result[0].Catalog)
+	require.Equal(t, "A", result[0].Data[0].GetId())
+	require.IsType(t, repository.Erosita{}, result[0].Data[0].Metadata)
+}
+
+func TestConesearch_WithMetadataOfAllCatalogs(t *testing.T) {
+	allwise := []repository.GetAllwiseFromPixelsRow{
+		{ID: "A1", Ra: 1, Dec: 1},
+		{ID: "A2", Ra: 1.0001, Dec: 1},
+		{ID: "A3", Ra: 1.0002, Dec: 1},
+	}
+	erosita := []repository.GetErositaFromPixelsRow{{ID: "E", Ra_2: 1.0003, Dec_2: 1}}
+	repo := &MockRepository{}
+	repo.On("GetAllwiseFromPixels", mock.Anything, mock.Anything).Return(allwise, nil)
+	repo.On("GetErositaFromPixels", mock.Anything, mock.Anything).Return(erosita, nil)
+	// gaia isn't a catalog of the service, so its metadata is not searched
+	catalogs := []repository.Catalog{{Name: "erosita", Nside: 18}, {Name: "vlass", Nside: 18}, {Name: "allwise", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	result, err := service.FindMetadataByConesearch(context.Background(), 1, 1, 5, 2, "all")
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	// each catalog gets its own neighbors, in the order of the service catalogs
+	require.Len(t, result, 2)
+	require.Equal(t, "eROSITA", result[0].Catalog)
+	require.Len(t, result[0].Data, 1)
+	require.Equal(t, "AllWISE", result[1].Catalog)
+	require.Len(t, result[1].Data, 2)
+	require.Equal(t, "A1", result[1].Data[0].GetId())
+	require.Equal(t, "A2", result[1].Data[1].GetId())
+}
+
 func FuzzConesearch(f *testing.F) {
 	objects := []repository.Mastercat{
 		{ID: "A", Ra: 1, Dec: 1, Cat: "vlass"},
@@ -176,9 +447,38 @@ func FuzzConesearch(f *testing.F) {
 
 	f.Add(float64(1), float64(1), float64(1), int(1))
 	f.Fuzz(func(t *testing.T, ra float64, dec float64, radius float64, nneighbor int) {
-		_, err := service.Conesearch(ra, dec, radius, nneighbor, "all")
+		_, err := service.Conesearch(context.Background(), ra, dec, radius, nneighbor, "all")
 		if err == nil {
 			repo.AssertExpectations(t)
 		}
 	})
 }
+
+func TestConesearch_UsesRequestContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "request")
+	repo := &MockRepository{}
+	repo.On("FindObjects", ctx, mock.Anything).Return([]repository.Mastercat{{ID: "A", Ra: 1, Dec: 1, Cat: "vlass"}}, nil).Once()
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	result, err := service.Conesearch(ctx, 1, 1, 1, 1, "all")
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestBulkConesearch_CancelledContext(t *testing.T) {
+	repo := &MockRepository{}
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = service.BulkConesearch(ctx, []float64{1, 2}, []float64{1, 2}, []float64{1}, []int{1}, "all", 1, 1)
+	require.ErrorIs(t, err, context.Canceled)
+	repo.AssertNotCalled(t, "FindObjects", mock.Anything, mock.Anything)
+}
@@ -18,10 +18,9 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"slices"
-	"strings"
 
 	"github.com/dirodriguezm/healpix"
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
 	"github.com/dirodriguezm/xmatch/service/internal/repository"
 )
 
@@ -49,11 +48,9 @@ func WithResolution(res int) ConesearchOption {
 
 func WithCatalogs(catalogs []repository.Catalog) ConesearchOption {
 	return func(service *ConesearchService) error {
-		allowed := []string{"vlass", "allwise", "ztf", "gaia", "erosita"}
 		for i := range catalogs {
-			catName := strings.ToLower(catalogs[i].Name)
-			if !slices.Contains(allowed, catName) {
-				msg := fmt.Sprintf("specified catalog not available, please use one of %s", allowed)
+			if _, ok := catalog.Lookup(catalogs[i].Name); !ok {
+				msg := fmt.Sprintf("specified catalog not available, please use one of %s", catalog.Names())
 				return errors.New(msg)
 			}
 		}
@@ -69,6 +66,56 @@ func WithRepository(repository Repository) ConesearchOption {
 	}
 }
 
+// WithMaxProperMotion sets the largest total proper motion, in mas/yr, expected
+// for any indexed object. It bounds how much the search disc is widened
+// when propagating objects to another epoch.
+func WithMaxProperMotion(pm float64) ConesearchOption {
+	return func(service *ConesearchService) error {
+		if pm < 0 {
+			return errors.New("max proper motion can't be negative")
+		}
+		service.MaxProperMotion = pm
+		return nil
+	}
+}
+
+// WithReferenceEpoch sets the Julian year of the indexed coordinates
+func WithReferenceEpoch(epoch float64) ConesearchOption {
+	return func(service *ConesearchService) error {
+		if err := ValidateEpoch(epoch); err != nil {
+			return err
+		}
+		service.ReferenceEpoch = epoch
+		return nil
+	}
+}
+
+// WithPixelCache keeps up to size objects of the pixels searched in memory, and as many objects of metadata,
+// so hot pixels are not queried again. Empty pixels count as one object. A size of zero disables the cache.
+func WithPixelCache(size int) ConesearchOption {
+	return func(service *ConesearchService) error {
+		if size < 0 {
+			return errors.New("pixel cache size can't be negative")
+		}
+		service.pixelCache = newLRUCache[pixelKey](size, objectsWeight[repository.Mastercat])
+		service.metadataCache = newLRUCache[pixelKey](size, objectsWeight[repository.MetadataWithCoordinates])
+		return nil
+	}
+}
+
+// WithResponseCache keeps the results of conesearches in memory, up to size objects among them,
+// so identical requests are answered without searching again. A size of zero disables the cache.
+func WithResponseCache(size int) ConesearchOption {
+	return func(service *ConesearchService) error {
+		if size < 0 {
+			return errors.New("response cache size can't be negative")
+		}
+		service.mastercatResponses = newLRUCache[responseKey](size, mastercatWeight)
+		service.metadataResponses = newLRUCache[responseKey](size, metadataWeight)
+		return nil
+	}
+}
+
 func isPowerOfTwo(n int) bool {
 	if n <= 0 {
 		return false
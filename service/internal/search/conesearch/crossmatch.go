@@ -0,0 +1,232 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/knn"
+
+	"github.com/dirodriguezm/healpix"
+)
+
+// Number of standard deviations of the input positional error that the
+// search radius is widened to when an input row provides one
+const crossmatchPosErrSigma = 3
+
+// CrossmatchInput is a single row of the user table to crossmatch
+//
+// PosErr is the optional positional error of the row, in arcsec.
+// Epoch is the optional Julian year of the row coordinates. When given, candidates with
+// a recorded proper motion are moved to that epoch before measuring their separation.
+type CrossmatchInput struct {
+	ID     string   `json:"id"`
+	Ra     float64  `json:"ra"`
+	Dec    float64  `json:"dec"`
+	PosErr *float64 `json:"pos_err,omitempty"`
+	Epoch  *float64 `json:"epoch,omitempty"`
+}
+
+// CrossmatchResult is a single row of the crossmatch table
+//
+// There is at least one row for each input row and requested catalog.
+// When no counterpart was found, Rank is 0 and both Match and Separation are nil.
+// Otherwise Rank starts at 1 for the closest counterpart and Separation is given in arcsec.
+//
+// When the input row has a positional error, matches with a recorded positional error
+// are also scored with the Bayes factor and match probability.
+type CrossmatchResult struct {
+	Index       int                   `json:"index"`
+	ID          string                `json:"id"`
+	Ra          float64               `json:"ra"`
+	Dec         float64               `json:"dec"`
+	Catalog     string                `json:"catalog"`
+	Rank        int                   `json:"rank"`
+	Separation  *float64              `json:"separation"`
+	BayesFactor *float64              `json:"bayes_factor,omitempty"`
+	Probability *float64              `json:"probability,omitempty"`
+	Match       *repository.Mastercat `json:"match"`
+}
+
+// Crossmatch finds the counterparts of each input row in each of the requested catalogs
+//
+// The result preserves the order of the input rows and of the requested catalogs.
+// Only the best match of each catalog is returned unless allMatches is true,
+// in which case every counterpart inside the radius is returned ordered by separation.
+func (c *ConesearchService) Crossmatch(
+	ctx context.Context,
+	rows []CrossmatchInput,
+	radius float64,
+	catalogs []string,
+	allMatches bool,
+	chunkSize int,
+	maxBulkConcurrency int,
+) ([]CrossmatchResult, error) {
+	if err := ValidateCrossmatchArguments(rows, radius, catalogs); err != nil {
+		return nil, err
+	}
+	catalogs = c.crossmatchCatalogs(catalogs)
+
+	numChunks := (len(rows) + chunkSize - 1) / chunkSize
+	resultsByIndex := make([][]CrossmatchResult, len(rows))
+	errChan := make(chan error, numChunks)
+	var wg sync.WaitGroup
+
+	sem := make(chan struct{}, maxBulkConcurrency)
+
+	for i := 0; i < len(rows); i += chunkSize {
+		wg.Add(1)
+
+		end := min(i+chunkSize, len(rows))
+
+		go func(chunk []CrossmatchInput, baseIndex int) {
+			sem <- struct{}{}
+
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			for j := range chunk {
+				result, err := c.crossmatchRow(ctx, chunk[j], baseIndex+j, radius, catalogs, allMatches)
+				if err != nil {
+					errChan <- err
+					return
+				}
+				// each goroutine owns a disjoint range of indices
+				resultsByIndex[baseIndex+j] = result
+			}
+		}(rows[i:end], i)
+	}
+
+	wg.Wait()
+	close(errChan)
+	for err := range errChan {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]CrossmatchResult, 0, len(rows)*len(catalogs))
+	for i := range resultsByIndex {
+		result = append(result, resultsByIndex[i]...)
+	}
+	return result, nil
+}
+
+func (c *ConesearchService) crossmatchRow(
+	ctx context.Context,
+	row CrossmatchInput,
+	index int,
+	radius float64,
+	catalogs []string,
+	allMatches bool,
+) ([]CrossmatchResult, error) {
+	if row.PosErr != nil {
+		radius = max(radius, crossmatchPosErrSigma*(*row.PosErr))
+	}
+
+	searchRadius := radius
+	if row.Epoch != nil {
+		searchRadius = c.propagationRadius(radius, *row.Epoch)
+	}
+
+	objects, err := findObjects(ctx, healpix.RADec(row.Ra, row.Dec), arcsecToRadians(searchRadius), c, "all")
+	if err != nil {
+		return nil, err
+	}
+	if row.Epoch != nil {
+		objects = propagateObjects(objects, *row.Epoch)
+	}
+
+	result := make([]CrossmatchResult, 0, len(catalogs))
+	for _, catalog := range catalogs {
+		candidates := filterByCatalog(objects, catalog)
+		matches := knn.NearestNeighborSearch(candidates, row.Ra, row.Dec, radius, max(len(candidates), 1))
+		ncandidates := len(matches.Data)
+		if !allMatches {
+			matches.Data = matches.Data[:min(1, ncandidates)]
+		}
+
+		if len(matches.Data) == 0 {
+			result = append(result, CrossmatchResult{
+				Index:   index,
+				ID:      row.ID,
+				Ra:      row.Ra,
+				Dec:     row.Dec,
+				Catalog: catalog,
+			})
+			continue
+		}
+
+		for k := range matches.Data {
+			match := CrossmatchResult{
+				Index:      index,
+				ID:         row.ID,
+				Ra:         row.Ra,
+				Dec:        row.Dec,
+				Catalog:    catalog,
+				Rank:       k + 1,
+				Separation: &matches.Distance[k],
+				Match:      &matches.Data[k],
+			}
+			if row.PosErr != nil {
+				match.BayesFactor, match.Probability = scoreMatch(matches.Distance[k], radius, *row.PosErr, matches.Data[k].PosErr, ncandidates)
+			}
+			result = append(result, match)
+		}
+	}
+	return result, nil
+}
+
+// crossmatchCatalogs expands the requested catalogs into the list of catalogs to match against
+//
+// An empty list or the "all" catalog expands to every catalog known by the service.
+func (c *ConesearchService) crossmatchCatalogs(catalogs []string) []string {
+	result := make([]string, 0, len(catalogs))
+	for _, catalog := range catalogs {
+		catalog = strings.ToLower(catalog)
+		if catalog == "all" {
+			return c.catalogNames()
+		}
+		if !slices.Contains(result, catalog) {
+			result = append(result, catalog)
+		}
+	}
+	if len(result) == 0 {
+		return c.catalogNames()
+	}
+	return result
+}
+
+func (c *ConesearchService) catalogNames() []string {
+	names := make([]string, 0, len(c.Catalogs))
+	for _, catalog := range c.Catalogs {
+		name := strings.ToLower(catalog.Name)
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,178 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+
+	"github.com/dirodriguezm/healpix"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrossmatch(t *testing.T) {
+	objects := []repository.Mastercat{
+		{ID: "A", Ra: 1, Dec: 1, Cat: "vlass"},
+		{ID: "A2", Ra: 1.0001, Dec: 1, Cat: "vlass"},
+		{ID: "W", Ra: 1, Dec: 1.0001, Cat: "allwise"},
+		{ID: "B", Ra: 10, Dec: 10, Cat: "vlass"},
+	}
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return(objects, nil)
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}, {Name: "allwise", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	rows := []CrossmatchInput{
+		{ID: "src-2", Ra: 50, Dec: 50},
+		{ID: "src-1", Ra: 1, Dec: 1},
+		{ID: "src-3", Ra: 10, Dec: 10},
+	}
+
+	t.Run("best match", func(t *testing.T) {
+		result, err := service.Crossmatch(context.Background(), rows, 1, []string{"vlass", "allwise"}, false, 2, 1)
+		require.NoError(t, err)
+
+		type row struct {
+			index   int
+			id      string
+			catalog string
+			rank    int
+			match   string
+		}
+		expected := []row{
+			{0, "src-2", "vlass", 0, ""},
+			{0, "src-2", "allwise", 0, ""},
+			{1, "src-1", "vlass", 1, "A"},
+			{1, "src-1", "allwise", 1, "W"},
+			{2, "src-3", "vlass", 1, "B"},
+			{2, "src-3", "allwise", 0, ""},
+		}
+		require.Len(t, result, len(expected))
+		for i := range expected {
+			require.Equal(t, expected[i].index, result[i].Index, "row %d", i)
+			require.Equal(t, expected[i].id, result[i].ID, "row %d", i)
+			require.Equal(t, expected[i].catalog, result[i].Catalog, "row %d", i)
+			require.Equal(t, expected[i].rank, result[i].Rank, "row %d", i)
+			if expected[i].match == "" {
+				require.Nil(t, result[i].Match, "row %d", i)
+				require.Nil(t, result[i].Separation, "row %d", i)
+				continue
+			}
+			require.Equal(t, expected[i].match, result[i].Match.ID, "row %d", i)
+			require.NotNil(t, result[i].Separation, "row %d", i)
+		}
+	})
+
+	t.Run("all matches", func(t *testing.T) {
+		result, err := service.Crossmatch(context.Background(), rows[1:2], 1, []string{"vlass"}, true, 2, 1)
+		require.NoError(t, err)
+
+		require.Len(t, result, 2)
+		require.Equal(t, "A", result[0].Match.ID)
+		require.Equal(t, 1, result[0].Rank)
+		require.Equal(t, "A2", result[1].Match.ID)
+		require.Equal(t, 2, result[1].Rank)
+		require.Less(t, *result[0].Separation, *result[1].Separation)
+	})
+
+	t.Run("all catalogs", func(t *testing.T) {
+		result, err := service.Crossmatch(context.Background(), rows[1:2], 1, nil, false, 2, 1)
+		require.NoError(t, err)
+
+		require.Len(t, result, 2)
+		require.Equal(t, "vlass", result[0].Catalog)
+		require.Equal(t, "allwise", result[1].Catalog)
+	})
+
+	t.Run("positional error widens the radius", func(t *testing.T) {
+		posErr := 1.0
+		input := []CrossmatchInput{{ID: "src-1", Ra: 1.0005, Dec: 1, PosErr: &posErr}}
+
+		result, err := service.Crossmatch(context.Background(), input, 1, []string{"vlass"}, false, 2, 1)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.NotNil(t, result[0].Match)
+		require.Equal(t, "A2", result[0].Match.ID)
+	})
+
+	t.Run("epoch propagates proper motions", func(t *testing.T) {
+		epoch := 2026.0
+		input := []CrossmatchInput{{ID: "src-1", Ra: 1, Dec: 1, Epoch: &epoch}}
+
+		pmra, pmdec, refEpoch := 0.0, 1000.0, 2016.0
+		moving := []repository.Mastercat{
+			{ID: "M", Ra: 1, Dec: 1 - 10.0/3600, Cat: "vlass", Pmra: &pmra, Pmdec: &pmdec, RefEpoch: &refEpoch},
+		}
+		repo := &MockRepository{}
+		repo.On("FindObjects", mock.Anything, mock.Anything).Return(moving, nil)
+		service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs), WithMaxProperMotion(2000))
+		require.NoError(t, err)
+
+		result, err := service.Crossmatch(context.Background(), input, 1, []string{"vlass"}, false, 1, 1)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.NotNil(t, result[0].Match)
+		require.Equal(t, "M", result[0].Match.ID)
+		require.Less(t, *result[0].Separation, 1e-3)
+	})
+}
+
+func TestCrossmatch_Validation(t *testing.T) {
+	repo := &MockRepository{}
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	negative := -1.0
+	farEpoch := 5000.0
+	testCases := map[string]struct {
+		rows     []CrossmatchInput
+		catalogs []string
+		field    string
+	}{
+		"empty table":     {rows: nil, field: "rows"},
+		"missing id":      {rows: []CrossmatchInput{{Ra: 1, Dec: 1}}, field: "id"},
+		"invalid ra":      {rows: []CrossmatchInput{{ID: "a", Ra: -1, Dec: 1}}, field: "RA"},
+		"invalid pos_err": {rows: []CrossmatchInput{{ID: "a", Ra: 1, Dec: 1, PosErr: &negative}}, field: "pos_err"},
+		"invalid epoch":   {rows: []CrossmatchInput{{ID: "a", Ra: 1, Dec: 1, Epoch: &farEpoch}}, field: "epoch"},
+		"invalid catalog": {rows: []CrossmatchInput{{ID: "a", Ra: 1, Dec: 1}}, catalogs: []string{"nope"}, field: "catalog"},
+	}
+
+	for name, tc := range testCases {
+		_, err := service.Crossmatch(context.Background(), tc.rows, 1, tc.catalogs, false, 1, 1)
+		var validationErr ValidationError
+		require.True(t, errors.As(err, &validationErr), name)
+		require.Equal(t, tc.field, validationErr.Field, name)
+	}
+	repo.AssertNotCalled(t, "FindObjects", mock.Anything, mock.Anything)
+}
+
+func TestCrossmatch_WithRepositoryError(t *testing.T) {
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return(nil, errors.New("repository error"))
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	_, err = service.Crossmatch(context.Background(), []CrossmatchInput{{ID: "a", Ra: 1, Dec: 1}}, 1, nil, false, 1, 1)
+	repo.AssertExpectations(t)
+	require.Error(t, err)
+	require.Equal(t, "repository error", err.Error())
+}
@@ -7,7 +7,9 @@ import (
 
 type MastercatExtended struct {
 	repository.Mastercat
-	Distance float64 `json:"distance"`
+	Distance    float64  `json:"distance"`
+	BayesFactor *float64 `json:"bayes_factor,omitempty"`
+	Probability *float64 `json:"probability,omitempty"`
 }
 
 type MastercatResult struct {
@@ -9,7 +9,9 @@ import (
 
 type MetadataExtended struct {
 	repository.Metadata `json:"-"`
-	Distance            float64 `json:"distance"`
+	Distance            float64  `json:"distance"`
+	BayesFactor         *float64 `json:"bayes_factor,omitempty"`
+	Probability         *float64 `json:"probability,omitempty"`
 }
 
 func (m MetadataExtended) MarshalJSON() ([]byte, error) {
@@ -24,6 +26,12 @@ func (m MetadataExtended) MarshalJSON() ([]byte, error) {
 	}
 
 	metadataMap["distance"] = m.Distance
+	if m.BayesFactor != nil {
+		metadataMap["bayes_factor"] = *m.BayesFactor
+	}
+	if m.Probability != nil {
+		metadataMap["probability"] = *m.Probability
+	}
 	return json.Marshal(metadataMap)
 }
 
@@ -32,17 +40,22 @@ type MetadataResult struct {
 	Data    []MetadataExtended `json:"data"`
 }
 
+// ResultFromKnnMetadata groups the neighbors by catalog. Catalogs are
+// ordered by their nearest neighbor, so the order is stable between searches.
 func ResultFromKnnMetadata(metadata knn.KnnResult[repository.Metadata]) []MetadataResult {
 	result := make([]MetadataResult, 0)
-	grouped := make(map[string][]MetadataExtended)
+	index := make(map[string]int)
 	for i, m := range metadata.Data {
-		grouped[m.GetCatalog()] = append(grouped[m.GetCatalog()], MetadataExtended{
+		j, ok := index[m.GetCatalog()]
+		if !ok {
+			j = len(result)
+			index[m.GetCatalog()] = j
+			result = append(result, MetadataResult{Catalog: m.GetCatalog()})
+		}
+		result[j].Data = append(result[j].Data, MetadataExtended{
 			Metadata: m,
 			Distance: metadata.Distance[i],
 		})
 	}
-	for catalog, data := range grouped {
-		result = append(result, MetadataResult{Catalog: catalog, Data: data})
-	}
 	return result
 }
@@ -243,6 +243,80 @@ func (_c *MockRepository_BulkGetGaia_Call) RunAndReturn(run func(context1 contex
 	return _c
 }
 
+// BulkGetGeneric provides a mock function for the type MockRepository
+func (_mock *MockRepository) BulkGetGeneric(context1 context.Context, genericSchema repository.GenericSchema, strings []string) ([]repository.GenericRow, error) {
+	ret := _mock.Called(context1, genericSchema, strings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkGetGeneric")
+	}
+
+	var r0 []repository.GenericRow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GenericSchema, []string) ([]repository.GenericRow, error)); ok {
+		return returnFunc(context1, genericSchema, strings)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GenericSchema, []string) []repository.GenericRow); ok {
+		r0 = returnFunc(context1, genericSchema, strings)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.GenericRow)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, repository.GenericSchema, []string) error); ok {
+		r1 = returnFunc(context1, genericSchema, strings)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_BulkGetGeneric_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BulkGetGeneric'
+type MockRepository_BulkGetGeneric_Call struct {
+	*mock.Call
+}
+
+// BulkGetGeneric is a helper method to define mock.On call
+//   - context1 context.Context
+//   - genericSchema repository.GenericSchema
+//   - strings []string
+func (_e *MockRepository_Expecter) BulkGetGeneric(context1 interface{}, genericSchema interface{}, strings interface{}) *MockRepository_BulkGetGeneric_Call {
+	return &MockRepository_BulkGetGeneric_Call{Call: _e.mock.On("BulkGetGeneric", context1, genericSchema, strings)}
+}
+
+func (_c *MockRepository_BulkGetGeneric_Call) Run(run func(context1 context.Context, genericSchema repository.GenericSchema, strings []string)) *MockRepository_BulkGetGeneric_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 repository.GenericSchema
+		if args[1] != nil {
+			arg1 = args[1].(repository.GenericSchema)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_BulkGetGeneric_Call) Return(genericRows []repository.GenericRow, err error) *MockRepository_BulkGetGeneric_Call {
+	_c.Call.Return(genericRows, err)
+	return _c
+}
+
+func (_c *MockRepository_BulkGetGeneric_Call) RunAndReturn(run func(context1 context.Context, genericSchema repository.GenericSchema, strings []string) ([]repository.GenericRow, error)) *MockRepository_BulkGetGeneric_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // BulkInsertAllwise provides a mock function for the type MockRepository
 func (_mock *MockRepository) BulkInsertAllwise(context1 context.Context, dB *sql.DB, vs []any) error {
 	ret := _mock.Called(context1, dB, vs)
@@ -432,6 +506,75 @@ func (_c *MockRepository_BulkInsertGaia_Call) RunAndReturn(run func(context1 con
 	return _c
 }
 
+// BulkInsertGeneric provides a mock function for the type MockRepository
+func (_mock *MockRepository) BulkInsertGeneric(context1 context.Context, dB *sql.DB, genericSchema repository.GenericSchema, vs []any) error {
+	ret := _mock.Called(context1, dB, genericSchema, vs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkInsertGeneric")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *sql.DB, repository.GenericSchema, []any) error); ok {
+		r0 = returnFunc(context1, dB, genericSchema, vs)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_BulkInsertGeneric_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BulkInsertGeneric'
+type MockRepository_BulkInsertGeneric_Call struct {
+	*mock.Call
+}
+
+// BulkInsertGeneric is a helper method to define mock.On call
+//   - context1 context.Context
+//   - dB *sql.DB
+//   - genericSchema repository.GenericSchema
+//   - vs []any
+func (_e *MockRepository_Expecter) BulkInsertGeneric(context1 interface{}, dB interface{}, genericSchema interface{}, vs interface{}) *MockRepository_BulkInsertGeneric_Call {
+	return &MockRepository_BulkInsertGeneric_Call{Call: _e.mock.On("BulkInsertGeneric", context1, dB, genericSchema, vs)}
+}
+
+func (_c *MockRepository_BulkInsertGeneric_Call) Run(run func(context1 context.Context, dB *sql.DB, genericSchema repository.GenericSchema, vs []any)) *MockRepository_BulkInsertGeneric_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *sql.DB
+		if args[1] != nil {
+			arg1 = args[1].(*sql.DB)
+		}
+		var arg2 repository.GenericSchema
+		if args[2] != nil {
+			arg2 = args[2].(repository.GenericSchema)
+		}
+		var arg3 []any
+		if args[3] != nil {
+			arg3 = args[3].([]any)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_BulkInsertGeneric_Call) Return(err error) *MockRepository_BulkInsertGeneric_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_BulkInsertGeneric_Call) RunAndReturn(run func(context1 context.Context, dB *sql.DB, genericSchema repository.GenericSchema, vs []any) error) *MockRepository_BulkInsertGeneric_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // BulkInsertObject provides a mock function for the type MockRepository
 func (_mock *MockRepository) BulkInsertObject(context1 context.Context, dB *sql.DB, vs []any) error {
 	ret := _mock.Called(context1, dB, vs)
@@ -495,6 +638,120 @@ func (_c *MockRepository_BulkInsertObject_Call) RunAndReturn(run func(context1 c
 	return _c
 }
 
+// BulkInsertXwaveDetection provides a mock function for the type MockRepository
+func (_mock *MockRepository) BulkInsertXwaveDetection(ctx context.Context, db *sql.DB, arg []any) error {
+	ret := _mock.Called(ctx, db, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkInsertXwaveDetection")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *sql.DB, []any) error); ok {
+		r0 = returnFunc(ctx, db, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_BulkInsertXwaveDetection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BulkInsertXwaveDetection'
+type MockRepository_BulkInsertXwaveDetection_Call struct {
+	*mock.Call
+}
+
+// BulkInsertXwaveDetection is a helper method to define mock.On call
+//   - ctx context.Context
+//   - db *sql.DB
+//   - arg []any
+func (_e *MockRepository_Expecter) BulkInsertXwaveDetection(ctx interface{}, db interface{}, arg interface{}) *MockRepository_BulkInsertXwaveDetection_Call {
+	return &MockRepository_BulkInsertXwaveDetection_Call{Call: _e.mock.On("BulkInsertXwaveDetection", ctx, db, arg)}
+}
+
+func (_c *MockRepository_BulkInsertXwaveDetection_Call) Run(run func(ctx context.Context, db *sql.DB, arg []any)) *MockRepository_BulkInsertXwaveDetection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *sql.DB
+		if args[1] != nil {
+			arg1 = args[1].(*sql.DB)
+		}
+		var arg2 []any
+		if args[2] != nil {
+			arg2 = args[2].([]any)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_BulkInsertXwaveDetection_Call) Return(err error) *MockRepository_BulkInsertXwaveDetection_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_BulkInsertXwaveDetection_Call) RunAndReturn(run func(ctx context.Context, db *sql.DB, arg []any) error) *MockRepository_BulkInsertXwaveDetection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// BumpIndexVersion provides a mock function for the type MockRepository
+func (_mock *MockRepository) BumpIndexVersion(context1 context.Context) error {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BumpIndexVersion")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(context1)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_BumpIndexVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BumpIndexVersion'
+type MockRepository_BumpIndexVersion_Call struct {
+	*mock.Call
+}
+
+// BumpIndexVersion is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockRepository_Expecter) BumpIndexVersion(context1 interface{}) *MockRepository_BumpIndexVersion_Call {
+	return &MockRepository_BumpIndexVersion_Call{Call: _e.mock.On("BumpIndexVersion", context1)}
+}
+
+func (_c *MockRepository_BumpIndexVersion_Call) Run(run func(context1 context.Context)) *MockRepository_BumpIndexVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_BumpIndexVersion_Call) Return(err error) *MockRepository_BumpIndexVersion_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_BumpIndexVersion_Call) RunAndReturn(run func(context1 context.Context) error) *MockRepository_BumpIndexVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindObjects provides a mock function for the type MockRepository
 func (_mock *MockRepository) FindObjects(context1 context.Context, int64s []int64) ([]repository.Mastercat, error) {
 	ret := _mock.Called(context1, int64s)
@@ -563,85 +820,93 @@ func (_c *MockRepository_FindObjects_Call) RunAndReturn(run func(context1 contex
 	return _c
 }
 
-// GetAllObjects provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetAllObjects(context1 context.Context) ([]repository.Mastercat, error) {
-	ret := _mock.Called(context1)
+// FindObjectsBetweenPixels provides a mock function for the type MockRepository
+func (_mock *MockRepository) FindObjectsBetweenPixels(context1 context.Context, findObjectsBetweenPixelsParams repository.FindObjectsBetweenPixelsParams) ([]repository.Mastercat, error) {
+	ret := _mock.Called(context1, findObjectsBetweenPixelsParams)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllObjects")
+		panic("no return value specified for FindObjectsBetweenPixels")
 	}
 
 	var r0 []repository.Mastercat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]repository.Mastercat, error)); ok {
-		return returnFunc(context1)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.FindObjectsBetweenPixelsParams) ([]repository.Mastercat, error)); ok {
+		return returnFunc(context1, findObjectsBetweenPixelsParams)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []repository.Mastercat); ok {
-		r0 = returnFunc(context1)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.FindObjectsBetweenPixelsParams) []repository.Mastercat); ok {
+		r0 = returnFunc(context1, findObjectsBetweenPixelsParams)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]repository.Mastercat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(context1)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, repository.FindObjectsBetweenPixelsParams) error); ok {
+		r1 = returnFunc(context1, findObjectsBetweenPixelsParams)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetAllObjects_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllObjects'
-type MockRepository_GetAllObjects_Call struct {
+// MockRepository_FindObjectsBetweenPixels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindObjectsBetweenPixels'
+type MockRepository_FindObjectsBetweenPixels_Call struct {
 	*mock.Call
 }
 
-// GetAllObjects is a helper method to define mock.On call
+// FindObjectsBetweenPixels is a helper method to define mock.On call
 //   - context1 context.Context
-func (_e *MockRepository_Expecter) GetAllObjects(context1 interface{}) *MockRepository_GetAllObjects_Call {
-	return &MockRepository_GetAllObjects_Call{Call: _e.mock.On("GetAllObjects", context1)}
+//   - findObjectsBetweenPixelsParams repository.FindObjectsBetweenPixelsParams
+func (_e *MockRepository_Expecter) FindObjectsBetweenPixels(context1 interface{}, findObjectsBetweenPixelsParams interface{}) *MockRepository_FindObjectsBetweenPixels_Call {
+	return &MockRepository_FindObjectsBetweenPixels_Call{Call: _e.mock.On("FindObjectsBetweenPixels", context1, findObjectsBetweenPixelsParams)}
 }
 
-func (_c *MockRepository_GetAllObjects_Call) Run(run func(context1 context.Context)) *MockRepository_GetAllObjects_Call {
+func (_c *MockRepository_FindObjectsBetweenPixels_Call) Run(run func(context1 context.Context, findObjectsBetweenPixelsParams repository.FindObjectsBetweenPixelsParams)) *MockRepository_FindObjectsBetweenPixels_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 repository.FindObjectsBetweenPixelsParams
+		if args[1] != nil {
+			arg1 = args[1].(repository.FindObjectsBetweenPixelsParams)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_GetAllObjects_Call) Return(mastercats []repository.Mastercat, err error) *MockRepository_GetAllObjects_Call {
+func (_c *MockRepository_FindObjectsBetweenPixels_Call) Return(mastercats []repository.Mastercat, err error) *MockRepository_FindObjectsBetweenPixels_Call {
 	_c.Call.Return(mastercats, err)
 	return _c
 }
 
-func (_c *MockRepository_GetAllObjects_Call) RunAndReturn(run func(context1 context.Context) ([]repository.Mastercat, error)) *MockRepository_GetAllObjects_Call {
+func (_c *MockRepository_FindObjectsBetweenPixels_Call) RunAndReturn(run func(context1 context.Context, findObjectsBetweenPixelsParams repository.FindObjectsBetweenPixelsParams) ([]repository.Mastercat, error)) *MockRepository_FindObjectsBetweenPixels_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAllwise provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetAllwise(context1 context.Context, s string) (repository.GetAllwiseRow, error) {
+// FindObjectsById provides a mock function for the type MockRepository
+func (_mock *MockRepository) FindObjectsById(context1 context.Context, s string) ([]repository.Mastercat, error) {
 	ret := _mock.Called(context1, s)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllwise")
+		panic("no return value specified for FindObjectsById")
 	}
 
-	var r0 repository.GetAllwiseRow
+	var r0 []repository.Mastercat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (repository.GetAllwiseRow, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]repository.Mastercat, error)); ok {
 		return returnFunc(context1, s)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) repository.GetAllwiseRow); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []repository.Mastercat); ok {
 		r0 = returnFunc(context1, s)
 	} else {
-		r0 = ret.Get(0).(repository.GetAllwiseRow)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.Mastercat)
+		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
 		r1 = returnFunc(context1, s)
@@ -651,19 +916,19 @@ func (_mock *MockRepository) GetAllwise(context1 context.Context, s string) (rep
 	return r0, r1
 }
 
-// MockRepository_GetAllwise_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllwise'
-type MockRepository_GetAllwise_Call struct {
+// MockRepository_FindObjectsById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindObjectsById'
+type MockRepository_FindObjectsById_Call struct {
 	*mock.Call
 }
 
-// GetAllwise is a helper method to define mock.On call
+// FindObjectsById is a helper method to define mock.On call
 //   - context1 context.Context
 //   - s string
-func (_e *MockRepository_Expecter) GetAllwise(context1 interface{}, s interface{}) *MockRepository_GetAllwise_Call {
-	return &MockRepository_GetAllwise_Call{Call: _e.mock.On("GetAllwise", context1, s)}
+func (_e *MockRepository_Expecter) FindObjectsById(context1 interface{}, s interface{}) *MockRepository_FindObjectsById_Call {
+	return &MockRepository_FindObjectsById_Call{Call: _e.mock.On("FindObjectsById", context1, s)}
 }
 
-func (_c *MockRepository_GetAllwise_Call) Run(run func(context1 context.Context, s string)) *MockRepository_GetAllwise_Call {
+func (_c *MockRepository_FindObjectsById_Call) Run(run func(context1 context.Context, s string)) *MockRepository_FindObjectsById_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -681,65 +946,65 @@ func (_c *MockRepository_GetAllwise_Call) Run(run func(context1 context.Context,
 	return _c
 }
 
-func (_c *MockRepository_GetAllwise_Call) Return(getAllwiseRow repository.GetAllwiseRow, err error) *MockRepository_GetAllwise_Call {
-	_c.Call.Return(getAllwiseRow, err)
+func (_c *MockRepository_FindObjectsById_Call) Return(mastercats []repository.Mastercat, err error) *MockRepository_FindObjectsById_Call {
+	_c.Call.Return(mastercats, err)
 	return _c
 }
 
-func (_c *MockRepository_GetAllwise_Call) RunAndReturn(run func(context1 context.Context, s string) (repository.GetAllwiseRow, error)) *MockRepository_GetAllwise_Call {
+func (_c *MockRepository_FindObjectsById_Call) RunAndReturn(run func(context1 context.Context, s string) ([]repository.Mastercat, error)) *MockRepository_FindObjectsById_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAllwiseFromPixels provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetAllwiseFromPixels(context1 context.Context, int64s []int64) ([]repository.GetAllwiseFromPixelsRow, error) {
-	ret := _mock.Called(context1, int64s)
+// FindObjectsInPixelRange provides a mock function for the type MockRepository
+func (_mock *MockRepository) FindObjectsInPixelRange(context1 context.Context, findObjectsInPixelRangeParams repository.FindObjectsInPixelRangeParams) ([]repository.Mastercat, error) {
+	ret := _mock.Called(context1, findObjectsInPixelRangeParams)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllwiseFromPixels")
+		panic("no return value specified for FindObjectsInPixelRange")
 	}
 
-	var r0 []repository.GetAllwiseFromPixelsRow
+	var r0 []repository.Mastercat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) ([]repository.GetAllwiseFromPixelsRow, error)); ok {
-		return returnFunc(context1, int64s)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.FindObjectsInPixelRangeParams) ([]repository.Mastercat, error)); ok {
+		return returnFunc(context1, findObjectsInPixelRangeParams)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) []repository.GetAllwiseFromPixelsRow); ok {
-		r0 = returnFunc(context1, int64s)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.FindObjectsInPixelRangeParams) []repository.Mastercat); ok {
+		r0 = returnFunc(context1, findObjectsInPixelRangeParams)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]repository.GetAllwiseFromPixelsRow)
+			r0 = ret.Get(0).([]repository.Mastercat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
-		r1 = returnFunc(context1, int64s)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, repository.FindObjectsInPixelRangeParams) error); ok {
+		r1 = returnFunc(context1, findObjectsInPixelRangeParams)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetAllwiseFromPixels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllwiseFromPixels'
-type MockRepository_GetAllwiseFromPixels_Call struct {
+// MockRepository_FindObjectsInPixelRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindObjectsInPixelRange'
+type MockRepository_FindObjectsInPixelRange_Call struct {
 	*mock.Call
 }
 
-// GetAllwiseFromPixels is a helper method to define mock.On call
+// FindObjectsInPixelRange is a helper method to define mock.On call
 //   - context1 context.Context
-//   - int64s []int64
-func (_e *MockRepository_Expecter) GetAllwiseFromPixels(context1 interface{}, int64s interface{}) *MockRepository_GetAllwiseFromPixels_Call {
-	return &MockRepository_GetAllwiseFromPixels_Call{Call: _e.mock.On("GetAllwiseFromPixels", context1, int64s)}
+//   - findObjectsInPixelRangeParams repository.FindObjectsInPixelRangeParams
+func (_e *MockRepository_Expecter) FindObjectsInPixelRange(context1 interface{}, findObjectsInPixelRangeParams interface{}) *MockRepository_FindObjectsInPixelRange_Call {
+	return &MockRepository_FindObjectsInPixelRange_Call{Call: _e.mock.On("FindObjectsInPixelRange", context1, findObjectsInPixelRangeParams)}
 }
 
-func (_c *MockRepository_GetAllwiseFromPixels_Call) Run(run func(context1 context.Context, int64s []int64)) *MockRepository_GetAllwiseFromPixels_Call {
+func (_c *MockRepository_FindObjectsInPixelRange_Call) Run(run func(context1 context.Context, findObjectsInPixelRangeParams repository.FindObjectsInPixelRangeParams)) *MockRepository_FindObjectsInPixelRange_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []int64
+		var arg1 repository.FindObjectsInPixelRangeParams
 		if args[1] != nil {
-			arg1 = args[1].([]int64)
+			arg1 = args[1].(repository.FindObjectsInPixelRangeParams)
 		}
 		run(
 			arg0,
@@ -749,171 +1014,201 @@ func (_c *MockRepository_GetAllwiseFromPixels_Call) Run(run func(context1 contex
 	return _c
 }
 
-func (_c *MockRepository_GetAllwiseFromPixels_Call) Return(getAllwiseFromPixelsRows []repository.GetAllwiseFromPixelsRow, err error) *MockRepository_GetAllwiseFromPixels_Call {
-	_c.Call.Return(getAllwiseFromPixelsRows, err)
+func (_c *MockRepository_FindObjectsInPixelRange_Call) Return(mastercats []repository.Mastercat, err error) *MockRepository_FindObjectsInPixelRange_Call {
+	_c.Call.Return(mastercats, err)
 	return _c
 }
 
-func (_c *MockRepository_GetAllwiseFromPixels_Call) RunAndReturn(run func(context1 context.Context, int64s []int64) ([]repository.GetAllwiseFromPixelsRow, error)) *MockRepository_GetAllwiseFromPixels_Call {
+func (_c *MockRepository_FindObjectsInPixelRange_Call) RunAndReturn(run func(context1 context.Context, findObjectsInPixelRangeParams repository.FindObjectsInPixelRangeParams) ([]repository.Mastercat, error)) *MockRepository_FindObjectsInPixelRange_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetCatalogs provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetCatalogs(context1 context.Context) ([]repository.Catalog, error) {
-	ret := _mock.Called(context1)
+// FindObjectsOfCatalogs provides a mock function for the type MockRepository
+func (_mock *MockRepository) FindObjectsOfCatalogs(context1 context.Context, findObjectsOfCatalogsParams repository.FindObjectsOfCatalogsParams) ([]repository.Mastercat, error) {
+	ret := _mock.Called(context1, findObjectsOfCatalogsParams)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCatalogs")
+		panic("no return value specified for FindObjectsOfCatalogs")
 	}
 
-	var r0 []repository.Catalog
+	var r0 []repository.Mastercat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]repository.Catalog, error)); ok {
-		return returnFunc(context1)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.FindObjectsOfCatalogsParams) ([]repository.Mastercat, error)); ok {
+		return returnFunc(context1, findObjectsOfCatalogsParams)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []repository.Catalog); ok {
-		r0 = returnFunc(context1)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.FindObjectsOfCatalogsParams) []repository.Mastercat); ok {
+		r0 = returnFunc(context1, findObjectsOfCatalogsParams)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]repository.Catalog)
+			r0 = ret.Get(0).([]repository.Mastercat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(context1)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, repository.FindObjectsOfCatalogsParams) error); ok {
+		r1 = returnFunc(context1, findObjectsOfCatalogsParams)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetCatalogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCatalogs'
-type MockRepository_GetCatalogs_Call struct {
+// MockRepository_FindObjectsOfCatalogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindObjectsOfCatalogs'
+type MockRepository_FindObjectsOfCatalogs_Call struct {
 	*mock.Call
 }
 
-// GetCatalogs is a helper method to define mock.On call
+// FindObjectsOfCatalogs is a helper method to define mock.On call
 //   - context1 context.Context
-func (_e *MockRepository_Expecter) GetCatalogs(context1 interface{}) *MockRepository_GetCatalogs_Call {
-	return &MockRepository_GetCatalogs_Call{Call: _e.mock.On("GetCatalogs", context1)}
+//   - findObjectsOfCatalogsParams repository.FindObjectsOfCatalogsParams
+func (_e *MockRepository_Expecter) FindObjectsOfCatalogs(context1 interface{}, findObjectsOfCatalogsParams interface{}) *MockRepository_FindObjectsOfCatalogs_Call {
+	return &MockRepository_FindObjectsOfCatalogs_Call{Call: _e.mock.On("FindObjectsOfCatalogs", context1, findObjectsOfCatalogsParams)}
 }
 
-func (_c *MockRepository_GetCatalogs_Call) Run(run func(context1 context.Context)) *MockRepository_GetCatalogs_Call {
+func (_c *MockRepository_FindObjectsOfCatalogs_Call) Run(run func(context1 context.Context, findObjectsOfCatalogsParams repository.FindObjectsOfCatalogsParams)) *MockRepository_FindObjectsOfCatalogs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 repository.FindObjectsOfCatalogsParams
+		if args[1] != nil {
+			arg1 = args[1].(repository.FindObjectsOfCatalogsParams)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_GetCatalogs_Call) Return(catalogs []repository.Catalog, err error) *MockRepository_GetCatalogs_Call {
-	_c.Call.Return(catalogs, err)
+func (_c *MockRepository_FindObjectsOfCatalogs_Call) Return(mastercats []repository.Mastercat, err error) *MockRepository_FindObjectsOfCatalogs_Call {
+	_c.Call.Return(mastercats, err)
 	return _c
 }
 
-func (_c *MockRepository_GetCatalogs_Call) RunAndReturn(run func(context1 context.Context) ([]repository.Catalog, error)) *MockRepository_GetCatalogs_Call {
+func (_c *MockRepository_FindObjectsOfCatalogs_Call) RunAndReturn(run func(context1 context.Context, findObjectsOfCatalogsParams repository.FindObjectsOfCatalogsParams) ([]repository.Mastercat, error)) *MockRepository_FindObjectsOfCatalogs_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetDbInstance provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetDbInstance() *sql.DB {
-	ret := _mock.Called()
+// FindObjectsOfCatalogsBetweenPixels provides a mock function for the type MockRepository
+func (_mock *MockRepository) FindObjectsOfCatalogsBetweenPixels(context1 context.Context, findObjectsOfCatalogsBetweenPixelsParams repository.FindObjectsOfCatalogsBetweenPixelsParams) ([]repository.Mastercat, error) {
+	ret := _mock.Called(context1, findObjectsOfCatalogsBetweenPixelsParams)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetDbInstance")
+		panic("no return value specified for FindObjectsOfCatalogsBetweenPixels")
 	}
 
-	var r0 *sql.DB
-	if returnFunc, ok := ret.Get(0).(func() *sql.DB); ok {
-		r0 = returnFunc()
+	var r0 []repository.Mastercat
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.FindObjectsOfCatalogsBetweenPixelsParams) ([]repository.Mastercat, error)); ok {
+		return returnFunc(context1, findObjectsOfCatalogsBetweenPixelsParams)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.FindObjectsOfCatalogsBetweenPixelsParams) []repository.Mastercat); ok {
+		r0 = returnFunc(context1, findObjectsOfCatalogsBetweenPixelsParams)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*sql.DB)
+			r0 = ret.Get(0).([]repository.Mastercat)
 		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, repository.FindObjectsOfCatalogsBetweenPixelsParams) error); ok {
+		r1 = returnFunc(context1, findObjectsOfCatalogsBetweenPixelsParams)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockRepository_GetDbInstance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDbInstance'
-type MockRepository_GetDbInstance_Call struct {
+// MockRepository_FindObjectsOfCatalogsBetweenPixels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindObjectsOfCatalogsBetweenPixels'
+type MockRepository_FindObjectsOfCatalogsBetweenPixels_Call struct {
 	*mock.Call
 }
 
-// GetDbInstance is a helper method to define mock.On call
-func (_e *MockRepository_Expecter) GetDbInstance() *MockRepository_GetDbInstance_Call {
-	return &MockRepository_GetDbInstance_Call{Call: _e.mock.On("GetDbInstance")}
+// FindObjectsOfCatalogsBetweenPixels is a helper method to define mock.On call
+//   - context1 context.Context
+//   - findObjectsOfCatalogsBetweenPixelsParams repository.FindObjectsOfCatalogsBetweenPixelsParams
+func (_e *MockRepository_Expecter) FindObjectsOfCatalogsBetweenPixels(context1 interface{}, findObjectsOfCatalogsBetweenPixelsParams interface{}) *MockRepository_FindObjectsOfCatalogsBetweenPixels_Call {
+	return &MockRepository_FindObjectsOfCatalogsBetweenPixels_Call{Call: _e.mock.On("FindObjectsOfCatalogsBetweenPixels", context1, findObjectsOfCatalogsBetweenPixelsParams)}
 }
 
-func (_c *MockRepository_GetDbInstance_Call) Run(run func()) *MockRepository_GetDbInstance_Call {
+func (_c *MockRepository_FindObjectsOfCatalogsBetweenPixels_Call) Run(run func(context1 context.Context, findObjectsOfCatalogsBetweenPixelsParams repository.FindObjectsOfCatalogsBetweenPixelsParams)) *MockRepository_FindObjectsOfCatalogsBetweenPixels_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 repository.FindObjectsOfCatalogsBetweenPixelsParams
+		if args[1] != nil {
+			arg1 = args[1].(repository.FindObjectsOfCatalogsBetweenPixelsParams)
+		}
+		run(
+			arg0,
+			arg1,
+		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_GetDbInstance_Call) Return(dB *sql.DB) *MockRepository_GetDbInstance_Call {
-	_c.Call.Return(dB)
+func (_c *MockRepository_FindObjectsOfCatalogsBetweenPixels_Call) Return(mastercats []repository.Mastercat, err error) *MockRepository_FindObjectsOfCatalogsBetweenPixels_Call {
+	_c.Call.Return(mastercats, err)
 	return _c
 }
 
-func (_c *MockRepository_GetDbInstance_Call) RunAndReturn(run func() *sql.DB) *MockRepository_GetDbInstance_Call {
+func (_c *MockRepository_FindObjectsOfCatalogsBetweenPixels_Call) RunAndReturn(run func(context1 context.Context, findObjectsOfCatalogsBetweenPixelsParams repository.FindObjectsOfCatalogsBetweenPixelsParams) ([]repository.Mastercat, error)) *MockRepository_FindObjectsOfCatalogsBetweenPixels_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetErosita provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetErosita(context1 context.Context, s string) (repository.GetErositaRow, error) {
-	ret := _mock.Called(context1, s)
+// FindObjectsOfCatalogsInPixelRange provides a mock function for the type MockRepository
+func (_mock *MockRepository) FindObjectsOfCatalogsInPixelRange(context1 context.Context, findObjectsOfCatalogsInPixelRangeParams repository.FindObjectsOfCatalogsInPixelRangeParams) ([]repository.Mastercat, error) {
+	ret := _mock.Called(context1, findObjectsOfCatalogsInPixelRangeParams)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetErosita")
+		panic("no return value specified for FindObjectsOfCatalogsInPixelRange")
 	}
 
-	var r0 repository.GetErositaRow
+	var r0 []repository.Mastercat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (repository.GetErositaRow, error)); ok {
-		return returnFunc(context1, s)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.FindObjectsOfCatalogsInPixelRangeParams) ([]repository.Mastercat, error)); ok {
+		return returnFunc(context1, findObjectsOfCatalogsInPixelRangeParams)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) repository.GetErositaRow); ok {
-		r0 = returnFunc(context1, s)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.FindObjectsOfCatalogsInPixelRangeParams) []repository.Mastercat); ok {
+		r0 = returnFunc(context1, findObjectsOfCatalogsInPixelRangeParams)
 	} else {
-		r0 = ret.Get(0).(repository.GetErositaRow)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.Mastercat)
+		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(context1, s)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, repository.FindObjectsOfCatalogsInPixelRangeParams) error); ok {
+		r1 = returnFunc(context1, findObjectsOfCatalogsInPixelRangeParams)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetErosita_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetErosita'
-type MockRepository_GetErosita_Call struct {
+// MockRepository_FindObjectsOfCatalogsInPixelRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindObjectsOfCatalogsInPixelRange'
+type MockRepository_FindObjectsOfCatalogsInPixelRange_Call struct {
 	*mock.Call
 }
 
-// GetErosita is a helper method to define mock.On call
+// FindObjectsOfCatalogsInPixelRange is a helper method to define mock.On call
 //   - context1 context.Context
-//   - s string
-func (_e *MockRepository_Expecter) GetErosita(context1 interface{}, s interface{}) *MockRepository_GetErosita_Call {
-	return &MockRepository_GetErosita_Call{Call: _e.mock.On("GetErosita", context1, s)}
+//   - findObjectsOfCatalogsInPixelRangeParams repository.FindObjectsOfCatalogsInPixelRangeParams
+func (_e *MockRepository_Expecter) FindObjectsOfCatalogsInPixelRange(context1 interface{}, findObjectsOfCatalogsInPixelRangeParams interface{}) *MockRepository_FindObjectsOfCatalogsInPixelRange_Call {
+	return &MockRepository_FindObjectsOfCatalogsInPixelRange_Call{Call: _e.mock.On("FindObjectsOfCatalogsInPixelRange", context1, findObjectsOfCatalogsInPixelRangeParams)}
 }
 
-func (_c *MockRepository_GetErosita_Call) Run(run func(context1 context.Context, s string)) *MockRepository_GetErosita_Call {
+func (_c *MockRepository_FindObjectsOfCatalogsInPixelRange_Call) Run(run func(context1 context.Context, findObjectsOfCatalogsInPixelRangeParams repository.FindObjectsOfCatalogsInPixelRangeParams)) *MockRepository_FindObjectsOfCatalogsInPixelRange_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 repository.FindObjectsOfCatalogsInPixelRangeParams
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(repository.FindObjectsOfCatalogsInPixelRangeParams)
 		}
 		run(
 			arg0,
@@ -923,101 +1218,95 @@ func (_c *MockRepository_GetErosita_Call) Run(run func(context1 context.Context,
 	return _c
 }
 
-func (_c *MockRepository_GetErosita_Call) Return(getErositaRow repository.GetErositaRow, err error) *MockRepository_GetErosita_Call {
-	_c.Call.Return(getErositaRow, err)
+func (_c *MockRepository_FindObjectsOfCatalogsInPixelRange_Call) Return(mastercats []repository.Mastercat, err error) *MockRepository_FindObjectsOfCatalogsInPixelRange_Call {
+	_c.Call.Return(mastercats, err)
 	return _c
 }
 
-func (_c *MockRepository_GetErosita_Call) RunAndReturn(run func(context1 context.Context, s string) (repository.GetErositaRow, error)) *MockRepository_GetErosita_Call {
+func (_c *MockRepository_FindObjectsOfCatalogsInPixelRange_Call) RunAndReturn(run func(context1 context.Context, findObjectsOfCatalogsInPixelRangeParams repository.FindObjectsOfCatalogsInPixelRangeParams) ([]repository.Mastercat, error)) *MockRepository_FindObjectsOfCatalogsInPixelRange_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetErositaFromPixels provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetErositaFromPixels(context1 context.Context, int64s []int64) ([]repository.GetErositaFromPixelsRow, error) {
-	ret := _mock.Called(context1, int64s)
+// GetAllObjects provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetAllObjects(context1 context.Context) ([]repository.Mastercat, error) {
+	ret := _mock.Called(context1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetErositaFromPixels")
+		panic("no return value specified for GetAllObjects")
 	}
 
-	var r0 []repository.GetErositaFromPixelsRow
+	var r0 []repository.Mastercat
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) ([]repository.GetErositaFromPixelsRow, error)); ok {
-		return returnFunc(context1, int64s)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]repository.Mastercat, error)); ok {
+		return returnFunc(context1)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) []repository.GetErositaFromPixelsRow); ok {
-		r0 = returnFunc(context1, int64s)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []repository.Mastercat); ok {
+		r0 = returnFunc(context1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]repository.GetErositaFromPixelsRow)
+			r0 = ret.Get(0).([]repository.Mastercat)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
-		r1 = returnFunc(context1, int64s)
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(context1)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetErositaFromPixels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetErositaFromPixels'
-type MockRepository_GetErositaFromPixels_Call struct {
+// MockRepository_GetAllObjects_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllObjects'
+type MockRepository_GetAllObjects_Call struct {
 	*mock.Call
 }
 
-// GetErositaFromPixels is a helper method to define mock.On call
+// GetAllObjects is a helper method to define mock.On call
 //   - context1 context.Context
-//   - int64s []int64
-func (_e *MockRepository_Expecter) GetErositaFromPixels(context1 interface{}, int64s interface{}) *MockRepository_GetErositaFromPixels_Call {
-	return &MockRepository_GetErositaFromPixels_Call{Call: _e.mock.On("GetErositaFromPixels", context1, int64s)}
+func (_e *MockRepository_Expecter) GetAllObjects(context1 interface{}) *MockRepository_GetAllObjects_Call {
+	return &MockRepository_GetAllObjects_Call{Call: _e.mock.On("GetAllObjects", context1)}
 }
 
-func (_c *MockRepository_GetErositaFromPixels_Call) Run(run func(context1 context.Context, int64s []int64)) *MockRepository_GetErositaFromPixels_Call {
+func (_c *MockRepository_GetAllObjects_Call) Run(run func(context1 context.Context)) *MockRepository_GetAllObjects_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []int64
-		if args[1] != nil {
-			arg1 = args[1].([]int64)
-		}
 		run(
 			arg0,
-			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_GetErositaFromPixels_Call) Return(getErositaFromPixelsRows []repository.GetErositaFromPixelsRow, err error) *MockRepository_GetErositaFromPixels_Call {
-	_c.Call.Return(getErositaFromPixelsRows, err)
+func (_c *MockRepository_GetAllObjects_Call) Return(mastercats []repository.Mastercat, err error) *MockRepository_GetAllObjects_Call {
+	_c.Call.Return(mastercats, err)
 	return _c
 }
 
-func (_c *MockRepository_GetErositaFromPixels_Call) RunAndReturn(run func(context1 context.Context, int64s []int64) ([]repository.GetErositaFromPixelsRow, error)) *MockRepository_GetErositaFromPixels_Call {
+func (_c *MockRepository_GetAllObjects_Call) RunAndReturn(run func(context1 context.Context) ([]repository.Mastercat, error)) *MockRepository_GetAllObjects_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetGaia provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetGaia(context1 context.Context, s string) (repository.GetGaiaRow, error) {
+// GetAllwise provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetAllwise(context1 context.Context, s string) (repository.GetAllwiseRow, error) {
 	ret := _mock.Called(context1, s)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetGaia")
+		panic("no return value specified for GetAllwise")
 	}
 
-	var r0 repository.GetGaiaRow
+	var r0 repository.GetAllwiseRow
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (repository.GetGaiaRow, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (repository.GetAllwiseRow, error)); ok {
 		return returnFunc(context1, s)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) repository.GetGaiaRow); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) repository.GetAllwiseRow); ok {
 		r0 = returnFunc(context1, s)
 	} else {
-		r0 = ret.Get(0).(repository.GetGaiaRow)
+		r0 = ret.Get(0).(repository.GetAllwiseRow)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
 		r1 = returnFunc(context1, s)
@@ -1027,19 +1316,19 @@ func (_mock *MockRepository) GetGaia(context1 context.Context, s string) (reposi
 	return r0, r1
 }
 
-// MockRepository_GetGaia_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetGaia'
-type MockRepository_GetGaia_Call struct {
+// MockRepository_GetAllwise_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllwise'
+type MockRepository_GetAllwise_Call struct {
 	*mock.Call
 }
 
-// GetGaia is a helper method to define mock.On call
+// GetAllwise is a helper method to define mock.On call
 //   - context1 context.Context
 //   - s string
-func (_e *MockRepository_Expecter) GetGaia(context1 interface{}, s interface{}) *MockRepository_GetGaia_Call {
-	return &MockRepository_GetGaia_Call{Call: _e.mock.On("GetGaia", context1, s)}
+func (_e *MockRepository_Expecter) GetAllwise(context1 interface{}, s interface{}) *MockRepository_GetAllwise_Call {
+	return &MockRepository_GetAllwise_Call{Call: _e.mock.On("GetAllwise", context1, s)}
 }
 
-func (_c *MockRepository_GetGaia_Call) Run(run func(context1 context.Context, s string)) *MockRepository_GetGaia_Call {
+func (_c *MockRepository_GetAllwise_Call) Run(run func(context1 context.Context, s string)) *MockRepository_GetAllwise_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1057,34 +1346,102 @@ func (_c *MockRepository_GetGaia_Call) Run(run func(context1 context.Context, s
 	return _c
 }
 
-func (_c *MockRepository_GetGaia_Call) Return(getGaiaRow repository.GetGaiaRow, err error) *MockRepository_GetGaia_Call {
-	_c.Call.Return(getGaiaRow, err)
+func (_c *MockRepository_GetAllwise_Call) Return(getAllwiseRow repository.GetAllwiseRow, err error) *MockRepository_GetAllwise_Call {
+	_c.Call.Return(getAllwiseRow, err)
 	return _c
 }
 
-func (_c *MockRepository_GetGaia_Call) RunAndReturn(run func(context1 context.Context, s string) (repository.GetGaiaRow, error)) *MockRepository_GetGaia_Call {
+func (_c *MockRepository_GetAllwise_Call) RunAndReturn(run func(context1 context.Context, s string) (repository.GetAllwiseRow, error)) *MockRepository_GetAllwise_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetGaiaFromPixels provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetGaiaFromPixels(context1 context.Context, int64s []int64) ([]repository.GetGaiaFromPixelsRow, error) {
+// GetAllwiseBetweenPixels provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetAllwiseBetweenPixels(context1 context.Context, getAllwiseBetweenPixelsParams repository.GetAllwiseBetweenPixelsParams) ([]repository.GetAllwiseBetweenPixelsRow, error) {
+	ret := _mock.Called(context1, getAllwiseBetweenPixelsParams)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllwiseBetweenPixels")
+	}
+
+	var r0 []repository.GetAllwiseBetweenPixelsRow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GetAllwiseBetweenPixelsParams) ([]repository.GetAllwiseBetweenPixelsRow, error)); ok {
+		return returnFunc(context1, getAllwiseBetweenPixelsParams)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GetAllwiseBetweenPixelsParams) []repository.GetAllwiseBetweenPixelsRow); ok {
+		r0 = returnFunc(context1, getAllwiseBetweenPixelsParams)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.GetAllwiseBetweenPixelsRow)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, repository.GetAllwiseBetweenPixelsParams) error); ok {
+		r1 = returnFunc(context1, getAllwiseBetweenPixelsParams)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetAllwiseBetweenPixels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllwiseBetweenPixels'
+type MockRepository_GetAllwiseBetweenPixels_Call struct {
+	*mock.Call
+}
+
+// GetAllwiseBetweenPixels is a helper method to define mock.On call
+//   - context1 context.Context
+//   - getAllwiseBetweenPixelsParams repository.GetAllwiseBetweenPixelsParams
+func (_e *MockRepository_Expecter) GetAllwiseBetweenPixels(context1 interface{}, getAllwiseBetweenPixelsParams interface{}) *MockRepository_GetAllwiseBetweenPixels_Call {
+	return &MockRepository_GetAllwiseBetweenPixels_Call{Call: _e.mock.On("GetAllwiseBetweenPixels", context1, getAllwiseBetweenPixelsParams)}
+}
+
+func (_c *MockRepository_GetAllwiseBetweenPixels_Call) Run(run func(context1 context.Context, getAllwiseBetweenPixelsParams repository.GetAllwiseBetweenPixelsParams)) *MockRepository_GetAllwiseBetweenPixels_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 repository.GetAllwiseBetweenPixelsParams
+		if args[1] != nil {
+			arg1 = args[1].(repository.GetAllwiseBetweenPixelsParams)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetAllwiseBetweenPixels_Call) Return(getAllwiseBetweenPixelsRows []repository.GetAllwiseBetweenPixelsRow, err error) *MockRepository_GetAllwiseBetweenPixels_Call {
+	_c.Call.Return(getAllwiseBetweenPixelsRows, err)
+	return _c
+}
+
+func (_c *MockRepository_GetAllwiseBetweenPixels_Call) RunAndReturn(run func(context1 context.Context, getAllwiseBetweenPixelsParams repository.GetAllwiseBetweenPixelsParams) ([]repository.GetAllwiseBetweenPixelsRow, error)) *MockRepository_GetAllwiseBetweenPixels_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllwiseFromPixels provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetAllwiseFromPixels(context1 context.Context, int64s []int64) ([]repository.GetAllwiseFromPixelsRow, error) {
 	ret := _mock.Called(context1, int64s)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetGaiaFromPixels")
+		panic("no return value specified for GetAllwiseFromPixels")
 	}
 
-	var r0 []repository.GetGaiaFromPixelsRow
+	var r0 []repository.GetAllwiseFromPixelsRow
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) ([]repository.GetGaiaFromPixelsRow, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) ([]repository.GetAllwiseFromPixelsRow, error)); ok {
 		return returnFunc(context1, int64s)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) []repository.GetGaiaFromPixelsRow); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) []repository.GetAllwiseFromPixelsRow); ok {
 		r0 = returnFunc(context1, int64s)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]repository.GetGaiaFromPixelsRow)
+			r0 = ret.Get(0).([]repository.GetAllwiseFromPixelsRow)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
@@ -1095,19 +1452,19 @@ func (_mock *MockRepository) GetGaiaFromPixels(context1 context.Context, int64s
 	return r0, r1
 }
 
-// MockRepository_GetGaiaFromPixels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetGaiaFromPixels'
-type MockRepository_GetGaiaFromPixels_Call struct {
+// MockRepository_GetAllwiseFromPixels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllwiseFromPixels'
+type MockRepository_GetAllwiseFromPixels_Call struct {
 	*mock.Call
 }
 
-// GetGaiaFromPixels is a helper method to define mock.On call
+// GetAllwiseFromPixels is a helper method to define mock.On call
 //   - context1 context.Context
 //   - int64s []int64
-func (_e *MockRepository_Expecter) GetGaiaFromPixels(context1 interface{}, int64s interface{}) *MockRepository_GetGaiaFromPixels_Call {
-	return &MockRepository_GetGaiaFromPixels_Call{Call: _e.mock.On("GetGaiaFromPixels", context1, int64s)}
+func (_e *MockRepository_Expecter) GetAllwiseFromPixels(context1 interface{}, int64s interface{}) *MockRepository_GetAllwiseFromPixels_Call {
+	return &MockRepository_GetAllwiseFromPixels_Call{Call: _e.mock.On("GetAllwiseFromPixels", context1, int64s)}
 }
 
-func (_c *MockRepository_GetGaiaFromPixels_Call) Run(run func(context1 context.Context, int64s []int64)) *MockRepository_GetGaiaFromPixels_Call {
+func (_c *MockRepository_GetAllwiseFromPixels_Call) Run(run func(context1 context.Context, int64s []int64)) *MockRepository_GetAllwiseFromPixels_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1125,12 +1482,876 @@ func (_c *MockRepository_GetGaiaFromPixels_Call) Run(run func(context1 context.C
 	return _c
 }
 
-func (_c *MockRepository_GetGaiaFromPixels_Call) Return(getGaiaFromPixelsRows []repository.GetGaiaFromPixelsRow, err error) *MockRepository_GetGaiaFromPixels_Call {
-	_c.Call.Return(getGaiaFromPixelsRows, err)
+func (_c *MockRepository_GetAllwiseFromPixels_Call) Return(getAllwiseFromPixelsRows []repository.GetAllwiseFromPixelsRow, err error) *MockRepository_GetAllwiseFromPixels_Call {
+	_c.Call.Return(getAllwiseFromPixelsRows, err)
 	return _c
 }
 
-func (_c *MockRepository_GetGaiaFromPixels_Call) RunAndReturn(run func(context1 context.Context, int64s []int64) ([]repository.GetGaiaFromPixelsRow, error)) *MockRepository_GetGaiaFromPixels_Call {
+func (_c *MockRepository_GetAllwiseFromPixels_Call) RunAndReturn(run func(context1 context.Context, int64s []int64) ([]repository.GetAllwiseFromPixelsRow, error)) *MockRepository_GetAllwiseFromPixels_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCatalogs provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetCatalogs(context1 context.Context) ([]repository.Catalog, error) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCatalogs")
+	}
+
+	var r0 []repository.Catalog
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]repository.Catalog, error)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []repository.Catalog); ok {
+		r0 = returnFunc(context1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.Catalog)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(context1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetCatalogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCatalogs'
+type MockRepository_GetCatalogs_Call struct {
+	*mock.Call
+}
+
+// GetCatalogs is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockRepository_Expecter) GetCatalogs(context1 interface{}) *MockRepository_GetCatalogs_Call {
+	return &MockRepository_GetCatalogs_Call{Call: _e.mock.On("GetCatalogs", context1)}
+}
+
+func (_c *MockRepository_GetCatalogs_Call) Run(run func(context1 context.Context)) *MockRepository_GetCatalogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetCatalogs_Call) Return(catalogs []repository.Catalog, err error) *MockRepository_GetCatalogs_Call {
+	_c.Call.Return(catalogs, err)
+	return _c
+}
+
+func (_c *MockRepository_GetCatalogs_Call) RunAndReturn(run func(context1 context.Context) ([]repository.Catalog, error)) *MockRepository_GetCatalogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDbInstance provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetDbInstance() *sql.DB {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDbInstance")
+	}
+
+	var r0 *sql.DB
+	if returnFunc, ok := ret.Get(0).(func() *sql.DB); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.DB)
+		}
+	}
+	return r0
+}
+
+// MockRepository_GetDbInstance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDbInstance'
+type MockRepository_GetDbInstance_Call struct {
+	*mock.Call
+}
+
+// GetDbInstance is a helper method to define mock.On call
+func (_e *MockRepository_Expecter) GetDbInstance() *MockRepository_GetDbInstance_Call {
+	return &MockRepository_GetDbInstance_Call{Call: _e.mock.On("GetDbInstance")}
+}
+
+func (_c *MockRepository_GetDbInstance_Call) Run(run func()) *MockRepository_GetDbInstance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetDbInstance_Call) Return(dB *sql.DB) *MockRepository_GetDbInstance_Call {
+	_c.Call.Return(dB)
+	return _c
+}
+
+func (_c *MockRepository_GetDbInstance_Call) RunAndReturn(run func() *sql.DB) *MockRepository_GetDbInstance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetErosita provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetErosita(context1 context.Context, s string) (repository.GetErositaRow, error) {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetErosita")
+	}
+
+	var r0 repository.GetErositaRow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (repository.GetErositaRow, error)); ok {
+		return returnFunc(context1, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) repository.GetErositaRow); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		r0 = ret.Get(0).(repository.GetErositaRow)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(context1, s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetErosita_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetErosita'
+type MockRepository_GetErosita_Call struct {
+	*mock.Call
+}
+
+// GetErosita is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockRepository_Expecter) GetErosita(context1 interface{}, s interface{}) *MockRepository_GetErosita_Call {
+	return &MockRepository_GetErosita_Call{Call: _e.mock.On("GetErosita", context1, s)}
+}
+
+func (_c *MockRepository_GetErosita_Call) Run(run func(context1 context.Context, s string)) *MockRepository_GetErosita_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetErosita_Call) Return(getErositaRow repository.GetErositaRow, err error) *MockRepository_GetErosita_Call {
+	_c.Call.Return(getErositaRow, err)
+	return _c
+}
+
+func (_c *MockRepository_GetErosita_Call) RunAndReturn(run func(context1 context.Context, s string) (repository.GetErositaRow, error)) *MockRepository_GetErosita_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetErositaBetweenPixels provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetErositaBetweenPixels(context1 context.Context, getErositaBetweenPixelsParams repository.GetErositaBetweenPixelsParams) ([]repository.GetErositaBetweenPixelsRow, error) {
+	ret := _mock.Called(context1, getErositaBetweenPixelsParams)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetErositaBetweenPixels")
+	}
+
+	var r0 []repository.GetErositaBetweenPixelsRow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GetErositaBetweenPixelsParams) ([]repository.GetErositaBetweenPixelsRow, error)); ok {
+		return returnFunc(context1, getErositaBetweenPixelsParams)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GetErositaBetweenPixelsParams) []repository.GetErositaBetweenPixelsRow); ok {
+		r0 = returnFunc(context1, getErositaBetweenPixelsParams)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.GetErositaBetweenPixelsRow)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, repository.GetErositaBetweenPixelsParams) error); ok {
+		r1 = returnFunc(context1, getErositaBetweenPixelsParams)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetErositaBetweenPixels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetErositaBetweenPixels'
+type MockRepository_GetErositaBetweenPixels_Call struct {
+	*mock.Call
+}
+
+// GetErositaBetweenPixels is a helper method to define mock.On call
+//   - context1 context.Context
+//   - getErositaBetweenPixelsParams repository.GetErositaBetweenPixelsParams
+func (_e *MockRepository_Expecter) GetErositaBetweenPixels(context1 interface{}, getErositaBetweenPixelsParams interface{}) *MockRepository_GetErositaBetweenPixels_Call {
+	return &MockRepository_GetErositaBetweenPixels_Call{Call: _e.mock.On("GetErositaBetweenPixels", context1, getErositaBetweenPixelsParams)}
+}
+
+func (_c *MockRepository_GetErositaBetweenPixels_Call) Run(run func(context1 context.Context, getErositaBetweenPixelsParams repository.GetErositaBetweenPixelsParams)) *MockRepository_GetErositaBetweenPixels_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 repository.GetErositaBetweenPixelsParams
+		if args[1] != nil {
+			arg1 = args[1].(repository.GetErositaBetweenPixelsParams)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetErositaBetweenPixels_Call) Return(getErositaBetweenPixelsRows []repository.GetErositaBetweenPixelsRow, err error) *MockRepository_GetErositaBetweenPixels_Call {
+	_c.Call.Return(getErositaBetweenPixelsRows, err)
+	return _c
+}
+
+func (_c *MockRepository_GetErositaBetweenPixels_Call) RunAndReturn(run func(context1 context.Context, getErositaBetweenPixelsParams repository.GetErositaBetweenPixelsParams) ([]repository.GetErositaBetweenPixelsRow, error)) *MockRepository_GetErositaBetweenPixels_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetErositaFromPixels provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetErositaFromPixels(context1 context.Context, int64s []int64) ([]repository.GetErositaFromPixelsRow, error) {
+	ret := _mock.Called(context1, int64s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetErositaFromPixels")
+	}
+
+	var r0 []repository.GetErositaFromPixelsRow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) ([]repository.GetErositaFromPixelsRow, error)); ok {
+		return returnFunc(context1, int64s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) []repository.GetErositaFromPixelsRow); ok {
+		r0 = returnFunc(context1, int64s)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.GetErositaFromPixelsRow)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = returnFunc(context1, int64s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetErositaFromPixels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetErositaFromPixels'
+type MockRepository_GetErositaFromPixels_Call struct {
+	*mock.Call
+}
+
+// GetErositaFromPixels is a helper method to define mock.On call
+//   - context1 context.Context
+//   - int64s []int64
+func (_e *MockRepository_Expecter) GetErositaFromPixels(context1 interface{}, int64s interface{}) *MockRepository_GetErositaFromPixels_Call {
+	return &MockRepository_GetErositaFromPixels_Call{Call: _e.mock.On("GetErositaFromPixels", context1, int64s)}
+}
+
+func (_c *MockRepository_GetErositaFromPixels_Call) Run(run func(context1 context.Context, int64s []int64)) *MockRepository_GetErositaFromPixels_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []int64
+		if args[1] != nil {
+			arg1 = args[1].([]int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetErositaFromPixels_Call) Return(getErositaFromPixelsRows []repository.GetErositaFromPixelsRow, err error) *MockRepository_GetErositaFromPixels_Call {
+	_c.Call.Return(getErositaFromPixelsRows, err)
+	return _c
+}
+
+func (_c *MockRepository_GetErositaFromPixels_Call) RunAndReturn(run func(context1 context.Context, int64s []int64) ([]repository.GetErositaFromPixelsRow, error)) *MockRepository_GetErositaFromPixels_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetGaia provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetGaia(context1 context.Context, s string) (repository.GetGaiaRow, error) {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGaia")
+	}
+
+	var r0 repository.GetGaiaRow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (repository.GetGaiaRow, error)); ok {
+		return returnFunc(context1, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) repository.GetGaiaRow); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		r0 = ret.Get(0).(repository.GetGaiaRow)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(context1, s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetGaia_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetGaia'
+type MockRepository_GetGaia_Call struct {
+	*mock.Call
+}
+
+// GetGaia is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockRepository_Expecter) GetGaia(context1 interface{}, s interface{}) *MockRepository_GetGaia_Call {
+	return &MockRepository_GetGaia_Call{Call: _e.mock.On("GetGaia", context1, s)}
+}
+
+func (_c *MockRepository_GetGaia_Call) Run(run func(context1 context.Context, s string)) *MockRepository_GetGaia_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetGaia_Call) Return(getGaiaRow repository.GetGaiaRow, err error) *MockRepository_GetGaia_Call {
+	_c.Call.Return(getGaiaRow, err)
+	return _c
+}
+
+func (_c *MockRepository_GetGaia_Call) RunAndReturn(run func(context1 context.Context, s string) (repository.GetGaiaRow, error)) *MockRepository_GetGaia_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetGaiaBetweenPixels provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetGaiaBetweenPixels(context1 context.Context, getGaiaBetweenPixelsParams repository.GetGaiaBetweenPixelsParams) ([]repository.GetGaiaBetweenPixelsRow, error) {
+	ret := _mock.Called(context1, getGaiaBetweenPixelsParams)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGaiaBetweenPixels")
+	}
+
+	var r0 []repository.GetGaiaBetweenPixelsRow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GetGaiaBetweenPixelsParams) ([]repository.GetGaiaBetweenPixelsRow, error)); ok {
+		return returnFunc(context1, getGaiaBetweenPixelsParams)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GetGaiaBetweenPixelsParams) []repository.GetGaiaBetweenPixelsRow); ok {
+		r0 = returnFunc(context1, getGaiaBetweenPixelsParams)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.GetGaiaBetweenPixelsRow)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, repository.GetGaiaBetweenPixelsParams) error); ok {
+		r1 = returnFunc(context1, getGaiaBetweenPixelsParams)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetGaiaBetweenPixels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetGaiaBetweenPixels'
+type MockRepository_GetGaiaBetweenPixels_Call struct {
+	*mock.Call
+}
+
+// GetGaiaBetweenPixels is a helper method to define mock.On call
+//   - context1 context.Context
+//   - getGaiaBetweenPixelsParams repository.GetGaiaBetweenPixelsParams
+func (_e *MockRepository_Expecter) GetGaiaBetweenPixels(context1 interface{}, getGaiaBetweenPixelsParams interface{}) *MockRepository_GetGaiaBetweenPixels_Call {
+	return &MockRepository_GetGaiaBetweenPixels_Call{Call: _e.mock.On("GetGaiaBetweenPixels", context1, getGaiaBetweenPixelsParams)}
+}
+
+func (_c *MockRepository_GetGaiaBetweenPixels_Call) Run(run func(context1 context.Context, getGaiaBetweenPixelsParams repository.GetGaiaBetweenPixelsParams)) *MockRepository_GetGaiaBetweenPixels_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 repository.GetGaiaBetweenPixelsParams
+		if args[1] != nil {
+			arg1 = args[1].(repository.GetGaiaBetweenPixelsParams)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetGaiaBetweenPixels_Call) Return(getGaiaBetweenPixelsRows []repository.GetGaiaBetweenPixelsRow, err error) *MockRepository_GetGaiaBetweenPixels_Call {
+	_c.Call.Return(getGaiaBetweenPixelsRows, err)
+	return _c
+}
+
+func (_c *MockRepository_GetGaiaBetweenPixels_Call) RunAndReturn(run func(context1 context.Context, getGaiaBetweenPixelsParams repository.GetGaiaBetweenPixelsParams) ([]repository.GetGaiaBetweenPixelsRow, error)) *MockRepository_GetGaiaBetweenPixels_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetGaiaFromPixels provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetGaiaFromPixels(context1 context.Context, int64s []int64) ([]repository.GetGaiaFromPixelsRow, error) {
+	ret := _mock.Called(context1, int64s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGaiaFromPixels")
+	}
+
+	var r0 []repository.GetGaiaFromPixelsRow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) ([]repository.GetGaiaFromPixelsRow, error)); ok {
+		return returnFunc(context1, int64s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) []repository.GetGaiaFromPixelsRow); ok {
+		r0 = returnFunc(context1, int64s)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.GetGaiaFromPixelsRow)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = returnFunc(context1, int64s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetGaiaFromPixels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetGaiaFromPixels'
+type MockRepository_GetGaiaFromPixels_Call struct {
+	*mock.Call
+}
+
+// GetGaiaFromPixels is a helper method to define mock.On call
+//   - context1 context.Context
+//   - int64s []int64
+func (_e *MockRepository_Expecter) GetGaiaFromPixels(context1 interface{}, int64s interface{}) *MockRepository_GetGaiaFromPixels_Call {
+	return &MockRepository_GetGaiaFromPixels_Call{Call: _e.mock.On("GetGaiaFromPixels", context1, int64s)}
+}
+
+func (_c *MockRepository_GetGaiaFromPixels_Call) Run(run func(context1 context.Context, int64s []int64)) *MockRepository_GetGaiaFromPixels_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []int64
+		if args[1] != nil {
+			arg1 = args[1].([]int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetGaiaFromPixels_Call) Return(getGaiaFromPixelsRows []repository.GetGaiaFromPixelsRow, err error) *MockRepository_GetGaiaFromPixels_Call {
+	_c.Call.Return(getGaiaFromPixelsRows, err)
+	return _c
+}
+
+func (_c *MockRepository_GetGaiaFromPixels_Call) RunAndReturn(run func(context1 context.Context, int64s []int64) ([]repository.GetGaiaFromPixelsRow, error)) *MockRepository_GetGaiaFromPixels_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetGeneric provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetGeneric(context1 context.Context, genericSchema repository.GenericSchema, s string) (repository.GenericRow, error) {
+	ret := _mock.Called(context1, genericSchema, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGeneric")
+	}
+
+	var r0 repository.GenericRow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GenericSchema, string) (repository.GenericRow, error)); ok {
+		return returnFunc(context1, genericSchema, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GenericSchema, string) repository.GenericRow); ok {
+		r0 = returnFunc(context1, genericSchema, s)
+	} else {
+		r0 = ret.Get(0).(repository.GenericRow)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, repository.GenericSchema, string) error); ok {
+		r1 = returnFunc(context1, genericSchema, s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetGeneric_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetGeneric'
+type MockRepository_GetGeneric_Call struct {
+	*mock.Call
+}
+
+// GetGeneric is a helper method to define mock.On call
+//   - context1 context.Context
+//   - genericSchema repository.GenericSchema
+//   - s string
+func (_e *MockRepository_Expecter) GetGeneric(context1 interface{}, genericSchema interface{}, s interface{}) *MockRepository_GetGeneric_Call {
+	return &MockRepository_GetGeneric_Call{Call: _e.mock.On("GetGeneric", context1, genericSchema, s)}
+}
+
+func (_c *MockRepository_GetGeneric_Call) Run(run func(context1 context.Context, genericSchema repository.GenericSchema, s string)) *MockRepository_GetGeneric_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 repository.GenericSchema
+		if args[1] != nil {
+			arg1 = args[1].(repository.GenericSchema)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetGeneric_Call) Return(genericRow repository.GenericRow, err error) *MockRepository_GetGeneric_Call {
+	_c.Call.Return(genericRow, err)
+	return _c
+}
+
+func (_c *MockRepository_GetGeneric_Call) RunAndReturn(run func(context1 context.Context, genericSchema repository.GenericSchema, s string) (repository.GenericRow, error)) *MockRepository_GetGeneric_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetGenericBetweenPixels provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetGenericBetweenPixels(context1 context.Context, genericSchema repository.GenericSchema, n int64, n1 int64) ([]repository.GenericRow, error) {
+	ret := _mock.Called(context1, genericSchema, n, n1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGenericBetweenPixels")
+	}
+
+	var r0 []repository.GenericRow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GenericSchema, int64, int64) ([]repository.GenericRow, error)); ok {
+		return returnFunc(context1, genericSchema, n, n1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GenericSchema, int64, int64) []repository.GenericRow); ok {
+		r0 = returnFunc(context1, genericSchema, n, n1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.GenericRow)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, repository.GenericSchema, int64, int64) error); ok {
+		r1 = returnFunc(context1, genericSchema, n, n1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetGenericBetweenPixels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetGenericBetweenPixels'
+type MockRepository_GetGenericBetweenPixels_Call struct {
+	*mock.Call
+}
+
+// GetGenericBetweenPixels is a helper method to define mock.On call
+//   - context1 context.Context
+//   - genericSchema repository.GenericSchema
+//   - n int64
+//   - n1 int64
+func (_e *MockRepository_Expecter) GetGenericBetweenPixels(context1 interface{}, genericSchema interface{}, n interface{}, n1 interface{}) *MockRepository_GetGenericBetweenPixels_Call {
+	return &MockRepository_GetGenericBetweenPixels_Call{Call: _e.mock.On("GetGenericBetweenPixels", context1, genericSchema, n, n1)}
+}
+
+func (_c *MockRepository_GetGenericBetweenPixels_Call) Run(run func(context1 context.Context, genericSchema repository.GenericSchema, n int64, n1 int64)) *MockRepository_GetGenericBetweenPixels_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 repository.GenericSchema
+		if args[1] != nil {
+			arg1 = args[1].(repository.GenericSchema)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetGenericBetweenPixels_Call) Return(genericRows []repository.GenericRow, err error) *MockRepository_GetGenericBetweenPixels_Call {
+	_c.Call.Return(genericRows, err)
+	return _c
+}
+
+func (_c *MockRepository_GetGenericBetweenPixels_Call) RunAndReturn(run func(context1 context.Context, genericSchema repository.GenericSchema, n int64, n1 int64) ([]repository.GenericRow, error)) *MockRepository_GetGenericBetweenPixels_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetGenericFromPixels provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetGenericFromPixels(context1 context.Context, genericSchema repository.GenericSchema, int64s []int64) ([]repository.GenericRow, error) {
+	ret := _mock.Called(context1, genericSchema, int64s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGenericFromPixels")
+	}
+
+	var r0 []repository.GenericRow
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GenericSchema, []int64) ([]repository.GenericRow, error)); ok {
+		return returnFunc(context1, genericSchema, int64s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.GenericSchema, []int64) []repository.GenericRow); ok {
+		r0 = returnFunc(context1, genericSchema, int64s)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.GenericRow)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, repository.GenericSchema, []int64) error); ok {
+		r1 = returnFunc(context1, genericSchema, int64s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetGenericFromPixels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetGenericFromPixels'
+type MockRepository_GetGenericFromPixels_Call struct {
+	*mock.Call
+}
+
+// GetGenericFromPixels is a helper method to define mock.On call
+//   - context1 context.Context
+//   - genericSchema repository.GenericSchema
+//   - int64s []int64
+func (_e *MockRepository_Expecter) GetGenericFromPixels(context1 interface{}, genericSchema interface{}, int64s interface{}) *MockRepository_GetGenericFromPixels_Call {
+	return &MockRepository_GetGenericFromPixels_Call{Call: _e.mock.On("GetGenericFromPixels", context1, genericSchema, int64s)}
+}
+
+func (_c *MockRepository_GetGenericFromPixels_Call) Run(run func(context1 context.Context, genericSchema repository.GenericSchema, int64s []int64)) *MockRepository_GetGenericFromPixels_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 repository.GenericSchema
+		if args[1] != nil {
+			arg1 = args[1].(repository.GenericSchema)
+		}
+		var arg2 []int64
+		if args[2] != nil {
+			arg2 = args[2].([]int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetGenericFromPixels_Call) Return(genericRows []repository.GenericRow, err error) *MockRepository_GetGenericFromPixels_Call {
+	_c.Call.Return(genericRows, err)
+	return _c
+}
+
+func (_c *MockRepository_GetGenericFromPixels_Call) RunAndReturn(run func(context1 context.Context, genericSchema repository.GenericSchema, int64s []int64) ([]repository.GenericRow, error)) *MockRepository_GetGenericFromPixels_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetGenericSchema provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetGenericSchema(context1 context.Context, s string) (repository.GenericSchema, error) {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGenericSchema")
+	}
+
+	var r0 repository.GenericSchema
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (repository.GenericSchema, error)); ok {
+		return returnFunc(context1, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) repository.GenericSchema); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		r0 = ret.Get(0).(repository.GenericSchema)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(context1, s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetGenericSchema_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetGenericSchema'
+type MockRepository_GetGenericSchema_Call struct {
+	*mock.Call
+}
+
+// GetGenericSchema is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockRepository_Expecter) GetGenericSchema(context1 interface{}, s interface{}) *MockRepository_GetGenericSchema_Call {
+	return &MockRepository_GetGenericSchema_Call{Call: _e.mock.On("GetGenericSchema", context1, s)}
+}
+
+func (_c *MockRepository_GetGenericSchema_Call) Run(run func(context1 context.Context, s string)) *MockRepository_GetGenericSchema_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetGenericSchema_Call) Return(genericSchema repository.GenericSchema, err error) *MockRepository_GetGenericSchema_Call {
+	_c.Call.Return(genericSchema, err)
+	return _c
+}
+
+func (_c *MockRepository_GetGenericSchema_Call) RunAndReturn(run func(context1 context.Context, s string) (repository.GenericSchema, error)) *MockRepository_GetGenericSchema_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetIndexVersion provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetIndexVersion(context1 context.Context) (int64, error) {
+	ret := _mock.Called(context1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIndexVersion")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return returnFunc(context1)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = returnFunc(context1)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(context1)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetIndexVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIndexVersion'
+type MockRepository_GetIndexVersion_Call struct {
+	*mock.Call
+}
+
+// GetIndexVersion is a helper method to define mock.On call
+//   - context1 context.Context
+func (_e *MockRepository_Expecter) GetIndexVersion(context1 interface{}) *MockRepository_GetIndexVersion_Call {
+	return &MockRepository_GetIndexVersion_Call{Call: _e.mock.On("GetIndexVersion", context1)}
+}
+
+func (_c *MockRepository_GetIndexVersion_Call) Run(run func(context1 context.Context)) *MockRepository_GetIndexVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetIndexVersion_Call) Return(int64 int64, err error) *MockRepository_GetIndexVersion_Call {
+	_c.Call.Return(int64, err)
+	return _c
+}
+
+func (_c *MockRepository_GetIndexVersion_Call) RunAndReturn(run func(context1 context.Context) (int64, error)) *MockRepository_GetIndexVersion_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,158 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/knn"
+
+	"github.com/dirodriguezm/healpix"
+)
+
+const (
+	// Barnard's star has the largest known proper motion, of about 10.4 arcsec/yr
+	defaultMaxProperMotion = 10400.0
+	// Reference epoch of Gaia DR3 astrometry
+	defaultReferenceEpoch = 2016.0
+
+	// Epochs of photographic plates and of planned surveys
+	minEpoch = 1900.0
+	maxEpoch = 2100.0
+
+	// Largest widening of the search radius, in arcsec. Barnard's star moves about
+	// this much in 6 years, so faster objects are only found close to the reference epoch.
+	maxPropagationMargin = 60.0
+)
+
+// PropagatePosition moves a position with the given proper motion by dt Julian years
+//
+// ra and dec are given in degrees, pmra (including the cos(dec) factor) and pmdec in mas/yr.
+// The motion is applied along the tangent plane and projected back to the sphere,
+// so it remains valid close to the poles. Parallax and radial velocity are ignored.
+func PropagatePosition(ra, dec, pmra, pmdec, dt float64) (float64, float64) {
+	alpha := ra * math.Pi / 180
+	delta := dec * math.Pi / 180
+	masToRadians := math.Pi / (180 * 3600 * 1000)
+	muAlpha := pmra * masToRadians * dt
+	muDelta := pmdec * masToRadians * dt
+
+	sinA, cosA := math.Sincos(alpha)
+	sinD, cosD := math.Sincos(delta)
+
+	// position plus displacement along the east (p) and north (q) directions
+	x := cosD*cosA - muAlpha*sinA - muDelta*sinD*cosA
+	y := cosD*sinA + muAlpha*cosA - muDelta*sinD*sinA
+	z := sinD + muDelta*cosD
+	norm := math.Sqrt(x*x + y*y + z*z)
+
+	newRa := math.Atan2(y, x) * 180 / math.Pi
+	if newRa < 0 {
+		newRa += 360
+	}
+	newDec := math.Asin(z/norm) * 180 / math.Pi
+	return newRa, newDec
+}
+
+// ConesearchAtEpoch works like Conesearch, but moves every object with a recorded
+// proper motion to the given epoch before filtering by distance.
+//
+// The epoch is given in Julian years. Returned objects have their coordinates at that epoch.
+func (c *ConesearchService) ConesearchAtEpoch(
+	ctx context.Context,
+	ra, dec, radius float64,
+	nneighbor int,
+	catalog string,
+	epoch float64,
+) ([]MastercatResult, error) {
+	if err := ValidateArguments(ra, dec, radius, nneighbor, catalog); err != nil {
+		return nil, err
+	}
+	if err := ValidateEpoch(epoch); err != nil {
+		return nil, err
+	}
+
+	objects, err := findObjects(ctx, healpix.RADec(ra, dec), arcsecToRadians(c.propagationRadius(radius, epoch)), c, catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	return ResultFromKnn(knn.NearestNeighborSearch(propagateObjects(objects, epoch), ra, dec, radius, nneighbor), 0), nil
+}
+
+// FindMetadataByConesearchAtEpoch works like FindMetadataByConesearch, but moves every object
+// with a recorded proper motion to the given epoch before filtering by distance.
+func (c *ConesearchService) FindMetadataByConesearchAtEpoch(
+	ctx context.Context,
+	ra, dec, radius float64,
+	nneighbor int,
+	catalog string,
+	epoch float64,
+) ([]MetadataResult, error) {
+	if err := ValidateArguments(ra, dec, radius, nneighbor, catalog); err != nil {
+		return nil, err
+	}
+	if err := ValidateEpoch(epoch); err != nil {
+		return nil, err
+	}
+
+	found, err := findMetadata(ctx, healpix.RADec(ra, dec), arcsecToRadians(c.propagationRadius(radius, epoch)), c, catalog)
+	if err != nil {
+		return nil, fmt.Errorf("could not find metadata: %w", err)
+	}
+
+	result := make([]MetadataResult, 0, len(found))
+	for _, f := range found {
+		neighbors := knn.NearestNeighborSearchForMetadata(propagateMetadata(f.objects, epoch), ra, dec, radius, nneighbor, f.catalog)
+		result = append(result, ResultFromKnnMetadata(neighbors)...)
+	}
+	return result, nil
+}
+
+// propagationRadius widens the radius, in arcsec, by the largest distance an object
+// can travel between the reference epoch and the given epoch, up to maxPropagationMargin
+func (c *ConesearchService) propagationRadius(radius, epoch float64) float64 {
+	return radius + min(c.MaxProperMotion/1000*math.Abs(epoch-c.ReferenceEpoch), maxPropagationMargin)
+}
+
+func propagateObjects(objects []repository.Mastercat, epoch float64) []repository.Mastercat {
+	result := make([]repository.Mastercat, len(objects))
+	for i, obj := range objects {
+		if pmra, pmdec, refEpoch, ok := obj.GetProperMotion(); ok {
+			obj.Ra, obj.Dec = PropagatePosition(obj.Ra, obj.Dec, pmra, pmdec, epoch-refEpoch)
+		}
+		result[i] = obj
+	}
+	return result
+}
+
+func propagateMetadata(objects []repository.MetadataWithCoordinates, epoch float64) []repository.MetadataWithCoordinates {
+	result := make([]repository.MetadataWithCoordinates, len(objects))
+	for i, obj := range objects {
+		result[i] = obj
+		withPm, ok := obj.(repository.MetadataWithProperMotion)
+		if !ok {
+			continue
+		}
+		if pmra, pmdec, refEpoch, ok := withPm.GetProperMotion(); ok {
+			ra, dec := obj.GetCoordinates()
+			result[i] = withPm.WithCoordinates(PropagatePosition(ra, dec, pmra, pmdec, epoch-refEpoch))
+		}
+	}
+	return result
+}
@@ -0,0 +1,135 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+
+	"github.com/dirodriguezm/healpix"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropagatePosition(t *testing.T) {
+	arcsec := 1.0 / 3600
+
+	ra, dec := PropagatePosition(10, 20, 1000, 1000, 0)
+	require.InDelta(t, 10, ra, 1e-12)
+	require.InDelta(t, 20, dec, 1e-12)
+
+	// 1 arcsec/yr to the north during 10 years
+	ra, dec = PropagatePosition(10, 20, 0, 1000, 10)
+	require.InDelta(t, 10, ra, 1e-9)
+	require.InDelta(t, 20+10*arcsec, dec, 1e-9)
+
+	// pmra includes cos(dec), so the change in ra is larger away from the equator
+	ra, dec = PropagatePosition(10, 60, 1000, 0, 10)
+	require.InDelta(t, 10+20*arcsec, ra, 1e-8)
+	require.InDelta(t, 60, dec, 1e-6)
+
+	// moving west across ra = 0
+	ra, _ = PropagatePosition(0, 0, -1000, 0, 10)
+	require.InDelta(t, 360-10*arcsec, ra, 1e-9)
+
+	// moving north across the pole
+	ra, dec = PropagatePosition(0, 90-5*arcsec, 0, 1000, 10)
+	require.InDelta(t, 180, ra, 1e-6)
+	require.InDelta(t, 90-5*arcsec, dec, 1e-9)
+}
+
+func TestConesearchAtEpoch(t *testing.T) {
+	pmra, pmdec, refEpoch := 0.0, 1000.0, 2016.0
+	objects := []repository.Mastercat{
+		{ID: "moving", Ra: 1, Dec: 1 - 10.0/3600, Cat: "gaia", Pmra: &pmra, Pmdec: &pmdec, RefEpoch: &refEpoch},
+		{ID: "static", Ra: 1, Dec: 1 - 10.0/3600, Cat: "gaia"},
+	}
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return(objects, nil)
+	catalogs := []repository.Catalog{{Name: "gaia", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs), WithMaxProperMotion(2000))
+	require.NoError(t, err)
+
+	result, err := service.ConesearchAtEpoch(context.Background(), 1, 1, 1, 10, "all", 2026)
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	require.Len(t, result, 1)
+	require.Len(t, result[0].Data, 1)
+	require.Equal(t, "moving", result[0].Data[0].ID)
+	require.InDelta(t, 1, result[0].Data[0].Dec, 1e-9)
+	require.Less(t, result[0].Data[0].Distance, 1e-3)
+
+	_, err = service.ConesearchAtEpoch(context.Background(), 1, 1, 1, 10, "all", 20260)
+	require.ErrorAs(t, err, &ValidationError{})
+
+	_, err = service.ConesearchAtEpoch(context.Background(), 1, 1, 1, 10, "all", 1000)
+	require.ErrorAs(t, err, &ValidationError{})
+}
+
+func TestPropagationRadius(t *testing.T) {
+	catalogs := []repository.Catalog{{Name: "gaia", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(&MockRepository{}), WithCatalogs(catalogs), WithMaxProperMotion(10400))
+	require.NoError(t, err)
+
+	require.InDelta(t, 1, service.propagationRadius(1, 2016), 1e-12)
+	require.InDelta(t, 1+10.4*4, service.propagationRadius(1, 2020), 1e-9)
+	// the widening is capped, however far the epoch is
+	require.InDelta(t, 1+maxPropagationMargin, service.propagationRadius(1, 2100), 1e-12)
+	require.InDelta(t, 1+maxPropagationMargin, service.propagationRadius(1, 1900), 1e-12)
+}
+
+func TestConesearchAtEpoch_WidensSearch(t *testing.T) {
+	repo := &MockRepository{}
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return([]repository.Mastercat{}, nil)
+	catalogs := []repository.Catalog{{Name: "gaia", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs), WithMaxProperMotion(1000))
+	require.NoError(t, err)
+
+	require.Equal(t, 11.0, service.propagationRadius(1, 2026))
+	require.Equal(t, 11.0, service.propagationRadius(1, 2006))
+
+	_, err = service.Conesearch(context.Background(), 1, 1, 1, 1, "all")
+	require.NoError(t, err)
+	_, err = service.ConesearchAtEpoch(context.Background(), 1, 1, 1, 1, "all", 2036)
+	require.NoError(t, err)
+
+	direct := repo.Calls[0].Arguments.Get(1).([]int64)
+	widened := repo.Calls[1].Arguments.Get(1).([]int64)
+	require.Greater(t, len(widened), len(direct))
+}
+
+func TestFindMetadataByConesearchAtEpoch(t *testing.T) {
+	pmra, pmdec, refEpoch := 0.0, 1000.0, 2016.0
+	objects := []repository.GetGaiaFromPixelsRow{
+		{ID: "moving", Ra: 1, Dec: 1 - 10.0/3600, Pmra: &pmra, Pmdec: &pmdec, RefEpoch: &refEpoch},
+		{ID: "static", Ra: 1, Dec: 1 - 10.0/3600},
+	}
+	repo := &MockRepository{}
+	repo.On("GetGaiaFromPixels", mock.Anything, mock.Anything).Return(objects, nil)
+	catalogs := []repository.Catalog{{Name: "gaia", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs), WithMaxProperMotion(2000))
+	require.NoError(t, err)
+
+	result, err := service.FindMetadataByConesearchAtEpoch(context.Background(), 1, 1, 1, 10, "gaia", 2026)
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	require.Len(t, result, 1)
+	require.Len(t, result[0].Data, 1)
+	require.Equal(t, "moving", result[0].Data[0].GetId())
+}
@@ -0,0 +1,445 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/dirodriguezm/healpix"
+)
+
+const (
+	// Number of coverage pixels that fit along the radius of the bounding disc of a region
+	regionPixelsPerRadius = 4
+	// Highest order allowed in a MOC
+	maxMOCOrder = 29
+)
+
+// Region is an area of the sky that can be searched with RegionSearch
+type Region interface {
+	// Contains reports whether the coordinates, in degrees, are inside the region
+	Contains(ra, dec float64) bool
+	// Center returns the coordinates, in degrees, from which distances to the results are measured
+	Center() (float64, float64)
+	// Coverage returns the sorted ranges of nested pixels at the given order that overlap the region
+	Coverage(order int) ([]healpix.PixelRange, error)
+}
+
+// Box is the region between two right ascensions and two declinations, in degrees
+//
+// When RaMin is greater than RaMax the box crosses RA=0.
+type Box struct {
+	RaMin  float64
+	RaMax  float64
+	DecMin float64
+	DecMax float64
+}
+
+func NewBox(raMin, raMax, decMin, decMax float64) (Box, error) {
+	if raMin < 0 || raMin > 360 {
+		return Box{}, NewValidationError("RA must be between 0 and 360", strconv.FormatFloat(raMin, 'f', 3, 64), "ra_min")
+	}
+	if raMax < 0 || raMax > 360 {
+		return Box{}, NewValidationError("RA must be between 0 and 360", strconv.FormatFloat(raMax, 'f', 3, 64), "ra_max")
+	}
+	if decMin < -90 || decMin > 90 {
+		return Box{}, NewValidationError("Dec must be between -90 and 90", strconv.FormatFloat(decMin, 'f', 3, 64), "dec_min")
+	}
+	if decMax < -90 || decMax > 90 {
+		return Box{}, NewValidationError("Dec must be between -90 and 90", strconv.FormatFloat(decMax, 'f', 3, 64), "dec_max")
+	}
+	if raMin == raMax {
+		return Box{}, NewValidationError("Ra_min and ra_max can't be equal", strconv.FormatFloat(raMin, 'f', 3, 64), "ra_max")
+	}
+	if decMin >= decMax {
+		return Box{}, NewValidationError("Dec_min must be lower than dec_max", strconv.FormatFloat(decMin, 'f', 3, 64), "dec_min")
+	}
+	return Box{RaMin: raMin, RaMax: raMax, DecMin: decMin, DecMax: decMax}, nil
+}
+
+func (b Box) Contains(ra, dec float64) bool {
+	if dec < b.DecMin || dec > b.DecMax {
+		return false
+	}
+	if b.RaMin <= b.RaMax {
+		return ra >= b.RaMin && ra <= b.RaMax
+	}
+	return ra >= b.RaMin || ra <= b.RaMax
+}
+
+func (b Box) Center() (float64, float64) {
+	return math.Mod(b.RaMin+b.width()/2, 360), (b.DecMin + b.DecMax) / 2
+}
+
+func (b Box) Coverage(order int) ([]healpix.PixelRange, error) {
+	ra, dec := b.Center()
+	return discCoverage(ra, dec, b.radius(), order)
+}
+
+// radius returns the distance in degrees from the center to the farthest point of the box
+//
+// Along each parallel the distance from the center grows towards the RA edges, so the
+// farthest point is on one of them: at a corner, or between the corners when the box
+// is wider than 180 degrees and its edges bend away from the center.
+func (b Box) radius() float64 {
+	ra, dec := b.Center()
+	radius := 0.0
+	for _, edgeRa := range []float64{b.RaMin, b.RaMax} {
+		for _, edgeDec := range b.farthestDecs(edgeRa) {
+			radius = max(radius, angularDistance(ra, dec, edgeRa, edgeDec))
+		}
+	}
+	return radius
+}
+
+// farthestDecs returns the declinations of the RA edge that can be farthest from the center:
+// its ends, and the antipode of the point of its meridian closest to the center when it is
+// inside the box
+func (b Box) farthestDecs(edgeRa float64) []float64 {
+	ra, dec := b.Center()
+	decs := []float64{b.DecMin, b.DecMax}
+	// the cosine of the distance along the meridian is proportional to cos(edgeDec - closest)
+	sinDec, cosDec := math.Sincos(dec * math.Pi / 180)
+	closest := math.Atan2(sinDec, cosDec*math.Cos((edgeRa-ra)*math.Pi/180)) * 180 / math.Pi
+	for _, farthest := range []float64{closest - 180, closest + 180} {
+		if farthest > b.DecMin && farthest < b.DecMax {
+			decs = append(decs, farthest)
+		}
+	}
+	return decs
+}
+
+func (b Box) width() float64 {
+	width := b.RaMax - b.RaMin
+	if width < 0 {
+		width += 360
+	}
+	return width
+}
+
+// Circle is the region within Radius degrees of a center, in degrees
+type Circle struct {
+	Ra     float64
+	Dec    float64
+	Radius float64
+}
+
+func NewCircle(ra, dec, radius float64) (Circle, error) {
+	if ra < 0 || ra > 360 {
+		return Circle{}, NewValidationError("RA must be between 0 and 360", strconv.FormatFloat(ra, 'f', 3, 64), "ra")
+	}
+	if dec < -90 || dec > 90 {
+		return Circle{}, NewValidationError("Dec must be between -90 and 90", strconv.FormatFloat(dec, 'f', 3, 64), "dec")
+	}
+	if radius <= 0 || radius > 180 {
+		return Circle{}, NewValidationError("Radius must be between 0 and 180 degrees", strconv.FormatFloat(radius, 'f', 3, 64), "radius")
+	}
+	return Circle{Ra: ra, Dec: dec, Radius: radius}, nil
+}
+
+func (c Circle) Contains(ra, dec float64) bool {
+	return angularDistance(c.Ra, c.Dec, ra, dec) <= c.Radius
+}
+
+func (c Circle) Center() (float64, float64) {
+	return c.Ra, c.Dec
+}
+
+func (c Circle) Coverage(order int) ([]healpix.PixelRange, error) {
+	return discCoverage(c.Ra, c.Dec, c.Radius, order)
+}
+
+// Polygon is a spherical polygon whose edges are great circle arcs
+//
+// The polygon must fit in the hemisphere centered on the mean of its vertices.
+type Polygon struct {
+	Ra  []float64
+	Dec []float64
+
+	center   [3]float64
+	east     [3]float64
+	north    [3]float64
+	vertices [][2]float64
+}
+
+func NewPolygon(ra, dec []float64) (Polygon, error) {
+	if len(ra) != len(dec) {
+		return Polygon{}, NewValidationError("Ra and Dec must have the same length", fmt.Sprintf("%d", len(ra)), "ra")
+	}
+	if len(ra) < 3 {
+		return Polygon{}, NewValidationError("A polygon must have at least three vertices", fmt.Sprintf("%d", len(ra)), "ra")
+	}
+	for i := range ra {
+		if err := ValidateRa(ra[i]); err != nil {
+			return Polygon{}, err
+		}
+		if err := ValidateDec(dec[i]); err != nil {
+			return Polygon{}, err
+		}
+	}
+
+	p := Polygon{Ra: ra, Dec: dec}
+	for i := range ra {
+		v := unitVector(ra[i], dec[i])
+		for k := range v {
+			p.center[k] += v[k]
+		}
+	}
+	if norm(p.center) == 0 {
+		return Polygon{}, NewValidationError("Polygon vertices can't be spread over the whole sphere", fmt.Sprintf("%d", len(ra)), "ra")
+	}
+	p.center = normalize(p.center)
+
+	// tangent plane basis at the center, used for the gnomonic projection
+	pole := [3]float64{0, 0, 1}
+	if math.Abs(p.center[2]) > 0.9 {
+		pole = [3]float64{1, 0, 0}
+	}
+	p.east = normalize(cross(pole, p.center))
+	p.north = cross(p.center, p.east)
+
+	for i := range ra {
+		x, y, ok := p.project(unitVector(ra[i], dec[i]))
+		if !ok {
+			return Polygon{}, NewValidationError("Polygon must fit in a hemisphere", strconv.FormatFloat(ra[i], 'f', 3, 64), "ra")
+		}
+		p.vertices = append(p.vertices, [2]float64{x, y})
+	}
+	return p, nil
+}
+
+// Contains uses the even-odd rule on the gnomonic projection of the polygon,
+// which maps its great circle edges to straight lines
+func (p Polygon) Contains(ra, dec float64) bool {
+	x, y, ok := p.project(unitVector(ra, dec))
+	if !ok {
+		return false
+	}
+	inside := false
+	for i, j := 0, len(p.vertices)-1; i < len(p.vertices); j, i = i, i+1 {
+		xi, yi := p.vertices[i][0], p.vertices[i][1]
+		xj, yj := p.vertices[j][0], p.vertices[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func (p Polygon) Center() (float64, float64) {
+	return vectorToRADec(p.center)
+}
+
+func (p Polygon) Coverage(order int) ([]healpix.PixelRange, error) {
+	ra, dec := p.Center()
+	// distances from the center along a great circle arc inside the
+	// hemisphere are largest at its endpoints, which are the vertices
+	radius := 0.0
+	for i := range p.Ra {
+		radius = max(radius, angularDistance(ra, dec, p.Ra[i], p.Dec[i]))
+	}
+	return discCoverage(ra, dec, radius, order)
+}
+
+func (p Polygon) project(v [3]float64) (float64, float64, bool) {
+	z := dot(v, p.center)
+	if z <= 1e-9 {
+		return 0, 0, false
+	}
+	return dot(v, p.east) / z, dot(v, p.north) / z, true
+}
+
+// MOC is a Multi-Order Coverage map: the nested HEALPix pixels covering a region, grouped by order
+type MOC struct {
+	Cells map[int][]int64
+
+	cells map[int]map[int64]bool
+}
+
+func NewMOC(cells map[int][]int64) (MOC, error) {
+	moc := MOC{Cells: cells, cells: make(map[int]map[int64]bool)}
+	for order, pixels := range cells {
+		if order < 0 || order > maxMOCOrder {
+			return MOC{}, NewValidationError(fmt.Sprintf("MOC order must be between 0 and %d", maxMOCOrder), strconv.Itoa(order), "moc")
+		}
+		npix := 12 * (int64(1) << (2 * order))
+		moc.cells[order] = make(map[int64]bool, len(pixels))
+		for _, pixel := range pixels {
+			if pixel < 0 || pixel >= npix {
+				return MOC{}, NewValidationError(fmt.Sprintf("Pixel out of range for order %d", order), strconv.FormatInt(pixel, 10), "moc")
+			}
+			moc.cells[order][pixel] = true
+		}
+	}
+	if len(moc.cells) == 0 {
+		return MOC{}, NewValidationError("MOC must have at least one cell", "0", "moc")
+	}
+	return moc, nil
+}
+
+func (m MOC) Contains(ra, dec float64) bool {
+	for order, pixels := range m.cells {
+		mapper, err := coverageMapper(order)
+		if err != nil {
+			return false
+		}
+		if pixels[mapper.PixelAt(healpix.RADec(ra, dec))] {
+			return true
+		}
+	}
+	return false
+}
+
+func (m MOC) Center() (float64, float64) {
+	var center [3]float64
+	for order, pixels := range m.cells {
+		mapper, err := coverageMapper(order)
+		if err != nil {
+			continue
+		}
+		// larger cells weigh more
+		weight := math.Pow(4, float64(maxMOCOrder-order))
+		for pixel := range pixels {
+			v := unitVector(pointingToRADec(mapper.PointingToCenter(pixel)))
+			for k := range v {
+				center[k] += weight * v[k]
+			}
+		}
+	}
+	if norm(center) == 0 {
+		return 0, 90
+	}
+	return vectorToRADec(normalize(center))
+}
+
+func (m MOC) Coverage(order int) ([]healpix.PixelRange, error) {
+	ranges := make([]healpix.PixelRange, 0)
+	for cellOrder, pixels := range m.Cells {
+		for _, pixel := range pixels {
+			if cellOrder <= order {
+				shift := 2 * (order - cellOrder)
+				ranges = append(ranges, healpix.PixelRange{Start: pixel << shift, Stop: (pixel + 1) << shift})
+			} else {
+				parent := pixel >> (2 * (cellOrder - order))
+				ranges = append(ranges, healpix.PixelRange{Start: parent, Stop: parent + 1})
+			}
+		}
+	}
+	return mergeRanges(ranges), nil
+}
+
+var coverageMappers sync.Map
+
+// coverageMapper returns a shared nested mapper of the given order
+func coverageMapper(order int) (*healpix.HEALPixMapper, error) {
+	if mapper, ok := coverageMappers.Load(order); ok {
+		return mapper.(*healpix.HEALPixMapper), nil
+	}
+	mapper, err := healpix.NewHEALPixMapper(order, healpix.Nest)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := coverageMappers.LoadOrStore(order, mapper)
+	return actual.(*healpix.HEALPixMapper), nil
+}
+
+// discCoverage returns the nested pixels at the given order that overlap a disc
+//
+// The disc is covered with coarser pixels, so that large regions only need a few
+// ranges, and each coarse pixel is then expanded into its range of pixels at the given order.
+func discCoverage(ra, dec, radius float64, order int) ([]healpix.PixelRange, error) {
+	radians := radius * math.Pi / 180
+	// pixels at order k are about sqrt(pi/3) / 2^k radians wide
+	coarseOrder := int(math.Ceil(math.Log2(math.Sqrt(math.Pi/3) * regionPixelsPerRadius / max(radians, 1e-12))))
+	coarseOrder = min(max(coarseOrder, 0), order)
+
+	mapper, err := coverageMapper(coarseOrder)
+	if err != nil {
+		return nil, err
+	}
+	shift := 2 * (order - coarseOrder)
+	ranges := mapper.QueryDiscInclusive(healpix.RADec(ra, dec), radians, 4)
+	for i := range ranges {
+		ranges[i].Start <<= shift
+		ranges[i].Stop <<= shift
+	}
+	return mergeRanges(ranges), nil
+}
+
+func mergeRanges(ranges []healpix.PixelRange) []healpix.PixelRange {
+	slices.SortFunc(ranges, func(a, b healpix.PixelRange) int {
+		return cmp.Compare(a.Start, b.Start)
+	})
+	merged := make([]healpix.PixelRange, 0, len(ranges))
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.Start <= merged[n-1].Stop {
+			merged[n-1].Stop = max(merged[n-1].Stop, r.Stop)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// angularDistance returns the distance in degrees between two points given in degrees
+func angularDistance(ra1, dec1, ra2, dec2 float64) float64 {
+	v1 := unitVector(ra1, dec1)
+	v2 := unitVector(ra2, dec2)
+	return math.Atan2(norm(cross(v1, v2)), dot(v1, v2)) * 180 / math.Pi
+}
+
+func pointingToRADec(p healpix.Pointing) (float64, float64) {
+	return p.Phi * 180 / math.Pi, 90 - p.Theta*180/math.Pi
+}
+
+func unitVector(ra, dec float64) [3]float64 {
+	sinA, cosA := math.Sincos(ra * math.Pi / 180)
+	sinD, cosD := math.Sincos(dec * math.Pi / 180)
+	return [3]float64{cosD * cosA, cosD * sinA, sinD}
+}
+
+func vectorToRADec(v [3]float64) (float64, float64) {
+	ra := math.Atan2(v[1], v[0]) * 180 / math.Pi
+	if ra < 0 {
+		ra += 360
+	}
+	return ra, math.Asin(max(-1, min(1, v[2]))) * 180 / math.Pi
+}
+
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func norm(v [3]float64) float64 {
+	return math.Sqrt(dot(v, v))
+}
+
+func normalize(v [3]float64) [3]float64 {
+	n := norm(v)
+	return [3]float64{v[0] / n, v[1] / n, v[2] / n}
+}
@@ -0,0 +1,202 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dirodriguezm/healpix"
+	"github.com/stretchr/testify/require"
+)
+
+func requireCovered(t *testing.T, region Region, order int, ra, dec float64) {
+	t.Helper()
+
+	mapper, err := healpix.NewHEALPixMapper(order, healpix.Nest)
+	require.NoError(t, err)
+	ranges, err := region.Coverage(order)
+	require.NoError(t, err)
+
+	pixel := mapper.PixelAt(healpix.RADec(ra, dec))
+	for _, r := range ranges {
+		if pixel >= r.Start && pixel < r.Stop {
+			return
+		}
+	}
+	t.Fatalf("pixel of (%f, %f) is not covered by the region", ra, dec)
+}
+
+func TestBox(t *testing.T) {
+	box, err := NewBox(10, 20, -5, 5)
+	require.NoError(t, err)
+	require.True(t, box.Contains(15, 0))
+	require.True(t, box.Contains(10, -5))
+	require.False(t, box.Contains(25, 0))
+	require.False(t, box.Contains(15, 6))
+
+	ra, dec := box.Center()
+	require.InDelta(t, 15, ra, 1e-9)
+	require.InDelta(t, 0, dec, 1e-9)
+
+	for _, corner := range [][2]float64{{10, -5}, {10, 5}, {20, -5}, {20, 5}, {15, 0}} {
+		requireCovered(t, box, 18, corner[0], corner[1])
+	}
+}
+
+func TestCircle(t *testing.T) {
+	circle, err := NewCircle(359.9, 10, 0.5)
+	require.NoError(t, err)
+	require.True(t, circle.Contains(359.9, 10.4))
+	require.True(t, circle.Contains(0.2, 10))
+	require.False(t, circle.Contains(359.9, 10.6))
+
+	for _, point := range [][2]float64{{359.9, 10}, {359.9, 10.49}, {0.3, 10}, {359.5, 9.8}} {
+		requireCovered(t, circle, 18, point[0], point[1])
+	}
+
+	_, err = NewCircle(10, 10, 0)
+	var validationErr ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Equal(t, "radius", validationErr.Field)
+}
+
+func TestBox_Wraparound(t *testing.T) {
+	box, err := NewBox(350, 10, 0, 10)
+	require.NoError(t, err)
+	require.True(t, box.Contains(355, 5))
+	require.True(t, box.Contains(5, 5))
+	require.False(t, box.Contains(180, 5))
+	require.False(t, box.Contains(20, 5))
+
+	ra, dec := box.Center()
+	require.InDelta(t, 0, ra, 1e-9)
+	require.InDelta(t, 5, dec, 1e-9)
+
+	requireCovered(t, box, 10, 350, 0)
+	requireCovered(t, box, 10, 10, 10)
+}
+
+func TestBox_WiderThanHalfTheSky(t *testing.T) {
+	box, err := NewBox(0.5, 359.5, -10, 10)
+	require.NoError(t, err)
+	require.True(t, box.Contains(0.6, 0))
+
+	ra, dec := box.Center()
+	require.InDelta(t, 180, ra, 1e-9)
+	require.InDelta(t, 0, dec, 1e-9)
+
+	// the middle of the RA edges is farther from the center than the corners
+	require.InDelta(t, 179.5, box.radius(), 1e-9)
+	require.Greater(t, box.radius(), angularDistance(ra, dec, 0.5, -10))
+	requireCovered(t, box, 18, 0.6, 0)
+	requireCovered(t, box, 18, 359.4, 0)
+	requireCovered(t, box, 18, 0.5, -10)
+}
+
+func TestBox_Validation(t *testing.T) {
+	testCases := map[string]struct {
+		box   [4]float64
+		field string
+	}{
+		"ra_min":       {[4]float64{-1, 10, 0, 1}, "ra_min"},
+		"ra_max":       {[4]float64{0, 361, 0, 1}, "ra_max"},
+		"dec_min":      {[4]float64{0, 10, -91, 1}, "dec_min"},
+		"dec_max":      {[4]float64{0, 10, 0, 91}, "dec_max"},
+		"empty ra":     {[4]float64{10, 10, 0, 1}, "ra_max"},
+		"inverted dec": {[4]float64{0, 10, 1, 0}, "dec_min"},
+	}
+
+	for name, tc := range testCases {
+		_, err := NewBox(tc.box[0], tc.box[1], tc.box[2], tc.box[3])
+		var validationErr ValidationError
+		require.True(t, errors.As(err, &validationErr), name)
+		require.Equal(t, tc.field, validationErr.Field, name)
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	// concave polygon shaped like an arrow pointing to the east
+	polygon, err := NewPolygon([]float64{10, 12, 10, 11}, []float64{-1, 0, 1, 0})
+	require.NoError(t, err)
+	require.True(t, polygon.Contains(11.5, 0))
+	require.True(t, polygon.Contains(10.75, 0.5))
+	require.False(t, polygon.Contains(10.5, 0), "the notch is outside the polygon")
+	require.False(t, polygon.Contains(13, 0))
+	require.False(t, polygon.Contains(190, 0), "the antipode is outside the polygon")
+
+	requireCovered(t, polygon, 18, 11.5, 0)
+	requireCovered(t, polygon, 18, 10, 1)
+}
+
+func TestPolygon_AroundThePole(t *testing.T) {
+	polygon, err := NewPolygon([]float64{0, 90, 180, 270}, []float64{80, 80, 80, 80})
+	require.NoError(t, err)
+	require.True(t, polygon.Contains(45, 89))
+	require.True(t, polygon.Contains(200, 85))
+	require.False(t, polygon.Contains(45, 75))
+
+	_, dec := polygon.Center()
+	require.InDelta(t, 90, dec, 1e-9)
+	requireCovered(t, polygon, 12, 135, 81)
+}
+
+func TestPolygon_Validation(t *testing.T) {
+	_, err := NewPolygon([]float64{1, 2}, []float64{1, 2})
+	require.ErrorAs(t, err, &ValidationError{})
+
+	_, err = NewPolygon([]float64{1, 2, 3}, []float64{1, 2})
+	require.ErrorAs(t, err, &ValidationError{})
+
+	_, err = NewPolygon([]float64{1, 2, 400}, []float64{1, 2, 3})
+	require.ErrorAs(t, err, &ValidationError{})
+
+	// vertices on a great circle around the equator don't fit in a hemisphere
+	_, err = NewPolygon([]float64{0, 120, 240}, []float64{0, 0, 0})
+	require.ErrorAs(t, err, &ValidationError{})
+}
+
+func TestMOC(t *testing.T) {
+	mapper, err := healpix.NewHEALPixMapper(5, healpix.Nest)
+	require.NoError(t, err)
+	pixel := mapper.PixelAt(healpix.RADec(10, 10))
+
+	moc, err := NewMOC(map[int][]int64{5: {pixel}})
+	require.NoError(t, err)
+	require.True(t, moc.Contains(10, 10))
+	require.False(t, moc.Contains(100, 10))
+
+	ranges, err := moc.Coverage(7)
+	require.NoError(t, err)
+	require.Equal(t, []healpix.PixelRange{{Start: pixel * 16, Stop: (pixel + 1) * 16}}, ranges)
+
+	ranges, err = moc.Coverage(3)
+	require.NoError(t, err)
+	require.Equal(t, []healpix.PixelRange{{Start: pixel / 16, Stop: pixel/16 + 1}}, ranges)
+
+	ra, dec := moc.Center()
+	require.Less(t, angularDistance(ra, dec, 10, 10), 2.0)
+}
+
+func TestMOC_Validation(t *testing.T) {
+	_, err := NewMOC(map[int][]int64{})
+	require.ErrorAs(t, err, &ValidationError{})
+
+	_, err = NewMOC(map[int][]int64{30: {1}})
+	require.ErrorAs(t, err, &ValidationError{})
+
+	_, err = NewMOC(map[int][]int64{0: {12}})
+	require.ErrorAs(t, err, &ValidationError{})
+}
@@ -0,0 +1,215 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/knn"
+
+	"github.com/dirodriguezm/healpix"
+)
+
+const (
+	DefaultPageSize = 1000
+	MaxPageSize     = 10000
+
+	// Number of rows read at once while scanning a pixel range
+	regionBatchSize = 5000
+	// Radius that includes the whole sky, in arcsec
+	wholeSkyRadius = 180 * 3600
+)
+
+// RegionCursor points to the last object of a page of a region search. Each order is scanned
+// by pixel, catalog and id, so the next page resumes the scan right after it.
+type RegionCursor struct {
+	Order   int64  `json:"o"`
+	Ipix    int64  `json:"p"`
+	Catalog string `json:"c"`
+	ID      string `json:"i"`
+}
+
+// RegionSearch returns one page of the objects inside the region, starting after the
+// given cursor, or at the first object when it is nil
+//
+// Objects are paginated in pixel and id order, so pages are stable while the
+// catalogs don't change. Inside a page, objects are ordered by their distance
+// to the center of the region. The returned cursor is nil when there are no more pages.
+func (c *ConesearchService) RegionSearch(ctx context.Context, region Region, catalog string, after *RegionCursor, pageSize int) ([]MastercatResult, *RegionCursor, error) {
+	if err := ValidateCatalog(catalog); err != nil {
+		return nil, nil, err
+	}
+	if err := ValidatePageSize(pageSize); err != nil {
+		return nil, nil, err
+	}
+
+	objects, next, err := c.findObjectsInRegion(ctx, region, after, pageSize, func(cat string) bool {
+		return catalog == "all" || strings.EqualFold(cat, catalog)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ra, dec := region.Center()
+	return ResultFromKnn(knn.NearestNeighborSearch(objects, ra, dec, wholeSkyRadius, len(objects)), 0), next, nil
+}
+
+// FindMetadataByRegionSearch works like RegionSearch, but returns the metadata of each object
+func (c *ConesearchService) FindMetadataByRegionSearch(ctx context.Context, region Region, catalogName string, after *RegionCursor, pageSize int) ([]MetadataResult, *RegionCursor, error) {
+	if err := ValidateCatalog(catalogName); err != nil {
+		return nil, nil, err
+	}
+	if err := ValidatePageSize(pageSize); err != nil {
+		return nil, nil, err
+	}
+	catalogName = strings.ToLower(catalogName)
+
+	descs, err := c.metadataCatalogs(catalogName)
+	if err != nil {
+		return nil, nil, err
+	}
+	objects, next, err := c.findObjectsInRegion(ctx, region, after, pageSize, func(cat string) bool {
+		return slices.ContainsFunc(descs, func(desc catalog.CatalogDescriptor) bool {
+			return strings.EqualFold(desc.Name, cat)
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// metadata is looked up by the pixels of the page, and then narrowed down to the objects of the page
+	ids := make(map[string]bool, len(objects))
+	pixels := make([]int64, 0, len(objects))
+	for _, obj := range objects {
+		ids[obj.ID] = true
+		if !slices.Contains(pixels, obj.Ipix) {
+			pixels = append(pixels, obj.Ipix)
+		}
+	}
+	metadata := make([]repository.MetadataWithCoordinates, 0, len(objects))
+	for chunk := range slices.Chunk(pixels, maxPixelsPerQuery) {
+		rows, err := c.getMetadata(ctx, chunk, catalogName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not find metadata: %w", err)
+		}
+		for _, row := range rows {
+			if ids[row.GetId()] {
+				metadata = append(metadata, row)
+			}
+		}
+	}
+
+	ra, dec := region.Center()
+	return ResultFromKnnMetadata(knn.NearestNeighborSearchForMetadata(metadata, ra, dec, wholeSkyRadius, len(metadata), catalogName)), next, nil
+}
+
+// findObjectsInRegion scans the pixels covering the region, from the cursor on, and returns
+// up to limit objects inside it, with the cursor of the last one when there are more
+func (c *ConesearchService) findObjectsInRegion(
+	ctx context.Context,
+	region Region,
+	after *RegionCursor,
+	limit int,
+	includeCatalog func(string) bool,
+) ([]repository.Mastercat, *RegionCursor, error) {
+	if c.Scheme != healpix.Nest {
+		return nil, nil, errors.New("region searches require the nested HEALPix scheme")
+	}
+
+	orders := make([]int64, 0, len(c.mappers))
+	for order := range c.mappers {
+		if after == nil || order >= after.Order {
+			orders = append(orders, order)
+		}
+	}
+	slices.Sort(orders)
+
+	result := make([]repository.Mastercat, 0, limit)
+	var last RegionCursor
+	seen := make(map[string]bool)
+	for _, order := range orders {
+		ranges, err := region.Coverage(int(order))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, r := range ranges {
+			params := repository.FindObjectsInPixelRangeParams{
+				Start:     r.Start,
+				Stop:      r.Stop,
+				AfterIpix: r.Start - 1,
+				Limit:     regionBatchSize,
+			}
+			if after != nil && after.Order == order {
+				if after.Ipix >= r.Stop {
+					continue
+				}
+				if after.Ipix >= r.Start {
+					params.AfterIpix, params.AfterCat, params.AfterID = after.Ipix, after.Catalog, after.ID
+				}
+			}
+
+			for {
+				objects, err := c.findObjectsInPixelRange(ctx, order, params)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				for _, obj := range objects {
+					key := obj.Cat + "/" + obj.ID
+					if seen[key] || !includeCatalog(obj.Cat) || !region.Contains(obj.Ra, obj.Dec) {
+						continue
+					}
+					seen[key] = true
+					if len(result) == limit {
+						return result, &last, nil
+					}
+					result = append(result, obj)
+					last = RegionCursor{Order: order, Ipix: obj.Ipix, Catalog: obj.Cat, ID: obj.ID}
+				}
+
+				if len(objects) < regionBatchSize {
+					break
+				}
+				lastRow := objects[len(objects)-1]
+				params.AfterIpix, params.AfterCat, params.AfterID = lastRow.Ipix, lastRow.Cat, lastRow.ID
+			}
+		}
+	}
+	return result, nil, nil
+}
+
+// findObjectsInPixelRange returns a batch of the objects in a pixel range of an order,
+// leaving out the catalogs indexed at other orders
+func (c *ConesearchService) findObjectsInPixelRange(ctx context.Context, order int64, params repository.FindObjectsInPixelRangeParams) ([]repository.Mastercat, error) {
+	catalogs := c.catalogsAt(order)
+	if catalogs == nil {
+		return c.repository.FindObjectsInPixelRange(ctx, params)
+	}
+	return c.repository.FindObjectsOfCatalogsInPixelRange(ctx, repository.FindObjectsOfCatalogsInPixelRangeParams{
+		Start:     params.Start,
+		Stop:      params.Stop,
+		Cat:       catalogs,
+		AfterIpix: params.AfterIpix,
+		AfterCat:  params.AfterCat,
+		AfterID:   params.AfterID,
+		Limit:     params.Limit,
+	})
+}
@@ -0,0 +1,213 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+
+	"github.com/dirodriguezm/healpix"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// scanPixelRange returns the objects of a pixel range after the keyset of the params, like the repository does
+func scanPixelRange(objects []repository.Mastercat) func(context.Context, repository.FindObjectsInPixelRangeParams) ([]repository.Mastercat, error) {
+	return func(_ context.Context, params repository.FindObjectsInPixelRangeParams) ([]repository.Mastercat, error) {
+		sorted := slices.Clone(objects)
+		slices.SortFunc(sorted, func(a, b repository.Mastercat) int {
+			return cmp.Or(cmp.Compare(a.Ipix, b.Ipix), cmp.Compare(a.Cat, b.Cat), cmp.Compare(a.ID, b.ID))
+		})
+		result := make([]repository.Mastercat, 0)
+		for _, obj := range sorted {
+			after := cmp.Or(
+				cmp.Compare(obj.Ipix, params.AfterIpix),
+				cmp.Compare(obj.Cat, params.AfterCat),
+				cmp.Compare(obj.ID, params.AfterID),
+			) > 0
+			if obj.Ipix >= params.Start && obj.Ipix < params.Stop && after && len(result) < int(params.Limit) {
+				result = append(result, obj)
+			}
+		}
+		return result, nil
+	}
+}
+
+func TestRegionSearch(t *testing.T) {
+	mapper, err := healpix.NewHEALPixMapper(18, healpix.Nest)
+	require.NoError(t, err)
+	object := func(id string, ra, dec float64, cat string) repository.Mastercat {
+		return repository.Mastercat{ID: id, Ipix: mapper.PixelAt(healpix.RADec(ra, dec)), Ra: ra, Dec: dec, Cat: cat}
+	}
+	objects := []repository.Mastercat{
+		object("A", 15, 0, "vlass"),
+		object("B", 11, 1, "vlass"),
+		object("C", 15, 1, "allwise"),
+		object("D", 15, 0, "allwise"),
+		object("outside", 25, 0, "vlass"),
+	}
+	repo := &MockRepository{}
+	repo.On("FindObjectsInPixelRange", mock.Anything, mock.Anything).Return(scanPixelRange(objects))
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}, {Name: "allwise", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	box, err := NewBox(10, 20, -5, 5)
+	require.NoError(t, err)
+
+	t.Run("single page", func(t *testing.T) {
+		result, next, err := service.RegionSearch(context.Background(), box, "vlass", nil, 10)
+		require.NoError(t, err)
+		require.Nil(t, next)
+		require.Len(t, result, 1)
+		require.Len(t, result[0].Data, 2)
+		require.Equal(t, "A", result[0].Data[0].ID, "objects are ordered by distance to the center")
+		require.Equal(t, "B", result[0].Data[1].ID)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		ids := []string{}
+		var after *RegionCursor
+		for {
+			result, next, err := service.RegionSearch(context.Background(), box, "all", after, 1)
+			require.NoError(t, err)
+			for _, r := range result {
+				for _, m := range r.Data {
+					ids = append(ids, m.ID)
+				}
+			}
+			if next == nil {
+				break
+			}
+			after = next
+		}
+		require.ElementsMatch(t, []string{"A", "B", "C", "D"}, ids)
+	})
+
+	t.Run("pages resume the scan from the cursor", func(t *testing.T) {
+		repo.Calls = nil
+		_, next, err := service.RegionSearch(context.Background(), box, "all", nil, 2)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+
+		firstCall := len(repo.Calls)
+		_, _, err = service.RegionSearch(context.Background(), box, "all", next, 2)
+		require.NoError(t, err)
+		// the ranges before the cursor are skipped, and its range is scanned from it
+		params := repo.Calls[firstCall].Arguments.Get(1).(repository.FindObjectsInPixelRangeParams)
+		require.LessOrEqual(t, params.Start, next.Ipix)
+		require.Greater(t, params.Stop, next.Ipix)
+		require.Equal(t, next.Ipix, params.AfterIpix)
+		require.Equal(t, next.Catalog, params.AfterCat)
+		require.Equal(t, next.ID, params.AfterID)
+	})
+}
+
+func TestRegionSearch_WideBox(t *testing.T) {
+	mapper, err := healpix.NewHEALPixMapper(18, healpix.Nest)
+	require.NoError(t, err)
+	objects := []repository.Mastercat{
+		{ID: "far", Ipix: mapper.PixelAt(healpix.RADec(0.6, 0)), Ra: 0.6, Dec: 0, Cat: "vlass"},
+		{ID: "outside", Ipix: mapper.PixelAt(healpix.RADec(0.1, 0)), Ra: 0.1, Dec: 0, Cat: "vlass"},
+	}
+	repo := &MockRepository{}
+	repo.On("FindObjectsInPixelRange", mock.Anything, mock.Anything).Return(scanPixelRange(objects))
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs([]repository.Catalog{{Name: "vlass", Nside: 18}}))
+	require.NoError(t, err)
+
+	box, err := NewBox(0.5, 359.5, -10, 10)
+	require.NoError(t, err)
+
+	result, next, err := service.RegionSearch(context.Background(), box, "all", nil, 10)
+	require.NoError(t, err)
+	require.Nil(t, next)
+	require.Len(t, result, 1)
+	require.Len(t, result[0].Data, 1)
+	require.Equal(t, "far", result[0].Data[0].ID, "objects near the far RA edge of the box are found")
+}
+
+func TestRegionSearch_Validation(t *testing.T) {
+	repo := &MockRepository{}
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	box, err := NewBox(10, 20, -5, 5)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		catalog  string
+		pageSize int
+		field    string
+	}{
+		"catalog":   {"nope", 1, "catalog"},
+		"page size": {"all", MaxPageSize + 1, "page_size"},
+	}
+	for name, tc := range testCases {
+		_, _, err := service.RegionSearch(context.Background(), box, tc.catalog, nil, tc.pageSize)
+		var validationErr ValidationError
+		require.True(t, errors.As(err, &validationErr), name)
+		require.Equal(t, tc.field, validationErr.Field, name)
+	}
+	repo.AssertNotCalled(t, "FindObjectsInPixelRange", mock.Anything, mock.Anything)
+}
+
+func TestRegionSearch_WithRepositoryError(t *testing.T) {
+	repo := &MockRepository{}
+	repo.On("FindObjectsInPixelRange", mock.Anything, mock.Anything).Return(nil, errors.New("repository error"))
+	catalogs := []repository.Catalog{{Name: "vlass", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	box, err := NewBox(10, 20, -5, 5)
+	require.NoError(t, err)
+
+	_, _, err = service.RegionSearch(context.Background(), box, "all", nil, 10)
+	require.Error(t, err)
+	require.Equal(t, "repository error", err.Error())
+}
+
+func TestFindMetadataByRegionSearch(t *testing.T) {
+	objects := []repository.Mastercat{
+		{ID: "A", Ipix: 1, Ra: 15, Dec: 0, Cat: "allwise"},
+		{ID: "V", Ipix: 1, Ra: 15, Dec: 0, Cat: "vlass"},
+	}
+	metadata := []repository.GetAllwiseFromPixelsRow{
+		{ID: "A", Ra: 15, Dec: 0},
+		{ID: "not-in-page", Ra: 15, Dec: 0},
+	}
+	repo := &MockRepository{}
+	repo.On("FindObjectsInPixelRange", mock.Anything, mock.Anything).Return(objects, nil)
+	repo.On("GetAllwiseFromPixels", mock.Anything, []int64{1}).Return(metadata, nil)
+	catalogs := []repository.Catalog{{Name: "allwise", Nside: 18}}
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs(catalogs))
+	require.NoError(t, err)
+
+	box, err := NewBox(10, 20, -5, 5)
+	require.NoError(t, err)
+
+	result, next, err := service.FindMetadataByRegionSearch(context.Background(), box, "allwise", nil, 10)
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	require.Nil(t, next)
+	require.Len(t, result, 1)
+	require.Len(t, result[0].Data, 1)
+	require.Equal(t, "A", result[0].Data[0].GetId())
+}
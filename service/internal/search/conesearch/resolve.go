@@ -0,0 +1,87 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+)
+
+// allwiseDesignation matches names like WISEA J123456.78-012345.6, which hold the
+// truncated position of the object instead of the id it was indexed with
+var allwiseDesignation = regexp.MustCompile(`(?i)^(?:WISEA\s*)?J(\d{2})(\d{2})(\d{2}\.\d+)([+-])(\d{2})(\d{2})(\d{2}\.\d+)$`)
+
+// allwiseDesignationRadius is the radius in arcsec around the position of a designation
+// where the object is searched. Designations are truncated to 0.15 arcsec.
+const allwiseDesignationRadius = 1.0
+
+// ResolveName finds the indexed object with the given name.
+//
+// The name is looked up among the ids of every catalog, like Gaia designations
+// and eROSITA IAU names. AllWISE designations are resolved by searching the
+// AllWISE object at the position encoded in them.
+func (c *ConesearchService) ResolveName(ctx context.Context, name string) (repository.Mastercat, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return repository.Mastercat{}, NewValidationError("Name can't be empty", name, "name")
+	}
+
+	objs, err := c.repository.FindObjectsById(ctx, name)
+	if err != nil {
+		return repository.Mastercat{}, fmt.Errorf("could not find object by name: %w", err)
+	}
+	if len(objs) > 0 {
+		return objs[0], nil
+	}
+
+	if ra, dec, ok := parseAllwiseDesignation(name); ok {
+		result, err := c.Conesearch(ctx, ra, dec, allwiseDesignationRadius, 1, "allwise")
+		if err != nil {
+			return repository.Mastercat{}, fmt.Errorf("could not find object by designation: %w", err)
+		}
+		if len(result) > 0 && len(result[0].Data) > 0 {
+			return result[0].Data[0].Mastercat, nil
+		}
+	}
+
+	return repository.Mastercat{}, NewValidationError("Object not found in the indexed catalogs", name, "name")
+}
+
+// parseAllwiseDesignation returns the position encoded in an AllWISE designation
+func parseAllwiseDesignation(name string) (float64, float64, bool) {
+	m := allwiseDesignation.FindStringSubmatch(name)
+	if m == nil {
+		return 0, 0, false
+	}
+	values := make([]float64, 0, 6)
+	for _, group := range []string{m[1], m[2], m[3], m[5], m[6], m[7]} {
+		v, err := strconv.ParseFloat(group, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		values = append(values, v)
+	}
+	ra := (values[0] + values[1]/60 + values[2]/3600) * 15
+	dec := values[3] + values[4]/60 + values[5]/3600
+	if m[4] == "-" {
+		dec = -dec
+	}
+	return ra, dec, true
+}
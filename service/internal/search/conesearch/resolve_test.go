@@ -0,0 +1,69 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conesearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dirodriguezm/healpix"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveName_ById(t *testing.T) {
+	gaia := repository.Mastercat{ID: "Gaia DR3 4295806720", Ra: 44.99, Dec: 0.0053, Cat: "gaia"}
+	repo := &MockRepository{}
+	repo.On("FindObjectsById", mock.Anything, "Gaia DR3 4295806720").Return([]repository.Mastercat{gaia}, nil).Once()
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs([]repository.Catalog{{Name: "gaia", Nside: 18}}))
+	require.NoError(t, err)
+
+	obj, err := service.ResolveName(context.Background(), " Gaia DR3 4295806720 ")
+	require.NoError(t, err)
+	require.Equal(t, gaia, obj)
+	repo.AssertExpectations(t)
+}
+
+func TestResolveName_AllwiseDesignation(t *testing.T) {
+	// the designation holds the position of the object truncated
+	allwise := repository.Mastercat{ID: "0000m016_ac51-000001", Ra: 10.00004, Dec: -1.50001, Cat: "allwise", Ipix: pixelAt(t, 18, 10.00004, -1.50001)}
+	repo := &MockRepository{}
+	repo.On("FindObjectsById", mock.Anything, mock.Anything).Return([]repository.Mastercat{}, nil)
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return([]repository.Mastercat{allwise}, nil)
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs([]repository.Catalog{{Name: "allwise", Nside: 18}}))
+	require.NoError(t, err)
+
+	for _, name := range []string{"WISEA J004000.00-013000.0", "J004000.00-013000.0", "wisea j004000.00-013000.0"} {
+		obj, err := service.ResolveName(context.Background(), name)
+		require.NoError(t, err, name)
+		require.Equal(t, allwise, obj, name)
+	}
+}
+
+func TestResolveName_NotFound(t *testing.T) {
+	repo := &MockRepository{}
+	repo.On("FindObjectsById", mock.Anything, mock.Anything).Return([]repository.Mastercat{}, nil)
+	repo.On("FindObjects", mock.Anything, mock.Anything).Return([]repository.Mastercat{}, nil)
+	service, err := NewConesearchService(WithScheme(healpix.Nest), WithRepository(repo), WithCatalogs([]repository.Catalog{{Name: "allwise", Nside: 18}}))
+	require.NoError(t, err)
+
+	for _, name := range []string{"M31", "J004000.00-013000.0", ""} {
+		_, err := service.ResolveName(context.Background(), name)
+		var validationErr ValidationError
+		require.ErrorAs(t, err, &validationErr, name)
+		require.Equal(t, "name", validationErr.Field, name)
+	}
+}
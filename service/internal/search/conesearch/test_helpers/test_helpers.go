@@ -47,6 +47,9 @@ func RegisterCatalogsInDB(ctx context.Context, dbFile string) error {
 	if err := repo.InsertCatalog(ctx, repository.InsertCatalogParams{Name: "allwise", Nside: 18}); err != nil {
 		return fmt.Errorf("could not insert catalog allwise: %w", err)
 	}
+	if err := repo.InsertCatalog(ctx, repository.InsertCatalogParams{Name: "erosita", Nside: 18}); err != nil {
+		return fmt.Errorf("could not insert catalog erosita: %w", err)
+	}
 	return nil
 }
 
@@ -69,6 +72,15 @@ func Migrate(dbFile string, rootPath string) error {
 }
 
 func InsertAllwiseMastercat(nobjects int, db *sql.DB) error {
+	return insertMastercat(nobjects, db, "allwise")
+}
+
+// InsertErositaMastercat inserts objects erosita-i at ra = dec = i degrees, like the allwise objects
+func InsertErositaMastercat(nobjects int, db *sql.DB) error {
+	return insertMastercat(nobjects, db, "erosita")
+}
+
+func insertMastercat(nobjects int, db *sql.DB, catalog string) error {
 	repo := repository.New(db)
 	for i := range nobjects {
 		ra := i
@@ -95,14 +107,14 @@ func InsertAllwiseMastercat(nobjects int, db *sql.DB) error {
 
 		// insert object
 		err = repo.InsertObject(context.Background(), repository.InsertObjectParams{
-			ID:   fmt.Sprintf("allwise-%d", i),
+			ID:   fmt.Sprintf("%s-%d", catalog, i),
 			Ipix: ipix,
 			Ra:   float64(ra),
 			Dec:  float64(dec),
-			Cat:  "allwise",
+			Cat:  catalog,
 		})
 		if err != nil {
-			return fmt.Errorf("could not insert allwise mastercat: %w", err)
+			return fmt.Errorf("could not insert %s mastercat: %w", catalog, err)
 		}
 	}
 
@@ -136,3 +148,22 @@ func InsertAllwiseMetadata(nobjects int, db *sql.DB) error {
 	}
 	return nil
 }
+
+func InsertErositaMetadata(nobjects int, db *sql.DB) error {
+	repo := repository.New(db)
+	for i := range nobjects {
+		metadata := repository.Erosita{
+			ID:      fmt.Sprintf("erosita-%d", i),
+			Detuid:  repository.NullString{sql.NullString{String: fmt.Sprintf("eb01_%d", i), Valid: true}},
+			Ra:      repository.NullFloat64{sql.NullFloat64{Float64: float64(i), Valid: true}},
+			Dec:     repository.NullFloat64{sql.NullFloat64{Float64: float64(i), Valid: true}},
+			PosErr:  repository.NullFloat64{sql.NullFloat64{Float64: 1.5, Valid: true}},
+			MlFlux1: repository.NullFloat64{sql.NullFloat64{Float64: 1e-14, Valid: true}},
+		}
+		err := repo.InsertErositaWithoutParams(context.Background(), metadata)
+		if err != nil {
+			return fmt.Errorf("could not insert erosita metadata: %w", err)
+		}
+	}
+	return nil
+}
@@ -16,9 +16,10 @@ package conesearch
 
 import (
 	"fmt"
-	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
 )
 
 type ValidationError struct {
@@ -49,6 +50,30 @@ func ValidateRadius(rad float64) error {
 	return nil
 }
 
+func ValidatePosErr(posErr float64) error {
+	if posErr < 0 {
+		err := "Positional error can't be lower than 0"
+		return NewValidationError(err, strconv.FormatFloat(posErr, 'f', 3, 64), "pos_err")
+	}
+	return nil
+}
+
+func ValidateEpoch(epoch float64) error {
+	if epoch < minEpoch || epoch > maxEpoch {
+		err := fmt.Sprintf("Epoch must be a Julian year between %.0f and %.0f", minEpoch, maxEpoch)
+		return NewValidationError(err, strconv.FormatFloat(epoch, 'f', 3, 64), "epoch")
+	}
+	return nil
+}
+
+func ValidatePageSize(pageSize int) error {
+	if pageSize <= 0 || pageSize > MaxPageSize {
+		err := fmt.Sprintf("Page size must be between 1 and %d", MaxPageSize)
+		return NewValidationError(err, strconv.Itoa(pageSize), "page_size")
+	}
+	return nil
+}
+
 func ValidateRa(ra float64) error {
 	err := ValidationError{
 		ErrValue: strconv.FormatFloat(ra, 'f', 3, 64),
@@ -88,10 +113,12 @@ func ValidateNneighbor(nneighbor int) error {
 	return nil
 }
 
-func ValidateCatalog(catalog string) error {
-	available := []string{"vlass", "ztf", "allwise", "gaia", "all"}
-	if !slices.Contains(available, strings.ToLower(catalog)) {
-		err := NewValidationError("Catalog not available", catalog, "catalog")
+func ValidateCatalog(catalogName string) error {
+	if strings.EqualFold(catalogName, "all") {
+		return nil
+	}
+	if _, ok := catalog.Lookup(catalogName); !ok {
+		err := NewValidationError("Catalog not available", catalogName, "catalog")
 		return err
 	}
 	return nil
@@ -116,10 +143,12 @@ func ValidateArguments(ra, dec, radius float64, nneighbor int, catalog string) e
 	return nil
 }
 
+// ValidateBulkArguments validates the positions of a bulk conesearch.
+// The radius and nneighbor must hold a single value, or one value for each position.
 func ValidateBulkArguments(
 	ra, dec []float64,
-	radius float64,
-	nneighbor int,
+	radius []float64,
+	nneighbor []int,
 	catalog string,
 ) error {
 	if len(ra) != len(dec) {
@@ -136,14 +165,64 @@ func ValidateBulkArguments(
 			return err
 		}
 	}
-	if err := ValidateRadius(radius); err != nil {
-		return err
+	if len(radius) != 1 && len(radius) != len(ra) {
+		return NewValidationError("Radius must have a single element or one for each position", fmt.Sprintf("%d", len(radius)), "radius")
 	}
-	if err := ValidateNneighbor(nneighbor); err != nil {
-		return err
+	for i := range radius {
+		if err := ValidateRadius(radius[i]); err != nil {
+			return err
+		}
+	}
+	if len(nneighbor) != 1 && len(nneighbor) != len(ra) {
+		return NewValidationError("Nneighbor must have a single element or one for each position", fmt.Sprintf("%d", len(nneighbor)), "nneighbor")
+	}
+	for i := range nneighbor {
+		if err := ValidateNneighbor(nneighbor[i]); err != nil {
+			return err
+		}
 	}
 	if err := ValidateCatalog(catalog); err != nil {
 		return err
 	}
 	return nil
 }
+
+func ValidateCrossmatchArguments(
+	rows []CrossmatchInput,
+	radius float64,
+	catalogs []string,
+) error {
+	if len(rows) == 0 {
+		return NewValidationError("Input table must have at least one row", fmt.Sprintf("%d", len(rows)), "rows")
+	}
+	for i := range rows {
+		if rows[i].ID == "" {
+			return NewValidationError("Every row must have an id", fmt.Sprintf("row %d", i), "id")
+		}
+		if err := ValidateRa(rows[i].Ra); err != nil {
+			return err
+		}
+		if err := ValidateDec(rows[i].Dec); err != nil {
+			return err
+		}
+		if rows[i].PosErr != nil {
+			if err := ValidatePosErr(*rows[i].PosErr); err != nil {
+				return err
+			}
+		}
+		if rows[i].Epoch != nil {
+			if err := ValidateEpoch(*rows[i].Epoch); err != nil {
+				return err
+			}
+		}
+	}
+	if err := ValidateRadius(radius); err != nil {
+		return err
+	}
+	for _, catalog := range catalogs {
+		if err := ValidateCatalog(catalog); err != nil {
+			return err
+		}
+	}
+	return nil
+}
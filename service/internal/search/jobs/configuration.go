@@ -0,0 +1,103 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"errors"
+	"time"
+)
+
+type JobsOption func(service *JobsService) error
+
+func WithRepository(repository Repository) JobsOption {
+	return func(service *JobsService) error {
+		service.repository = repository
+		return nil
+	}
+}
+
+func WithSearcher(searcher BulkSearcher) JobsOption {
+	return func(service *JobsService) error {
+		service.searcher = searcher
+		return nil
+	}
+}
+
+// WithDirectory sets where the input and result files of the jobs are kept
+func WithDirectory(directory string) JobsOption {
+	return func(service *JobsService) error {
+		if directory == "" {
+			return errors.New("jobs directory can't be empty")
+		}
+		service.directory = directory
+		return nil
+	}
+}
+
+func WithWorkers(workers int) JobsOption {
+	return func(service *JobsService) error {
+		if workers <= 0 {
+			return errors.New("workers must be a positive integer")
+		}
+		service.workers = workers
+		return nil
+	}
+}
+
+// WithQueueSize sets how many jobs can wait to be executed.
+// Jobs submitted when the queue is full are rejected.
+func WithQueueSize(size int) JobsOption {
+	return func(service *JobsService) error {
+		if size <= 0 {
+			return errors.New("queue size must be a positive integer")
+		}
+		service.queue = make(chan string, size)
+		return nil
+	}
+}
+
+// WithExpiration sets how long a job and its results are kept after it was submitted
+func WithExpiration(expiration time.Duration) JobsOption {
+	return func(service *JobsService) error {
+		if expiration <= 0 {
+			return errors.New("expiration must be positive")
+		}
+		service.expiration = expiration
+		return nil
+	}
+}
+
+// WithCleanupInterval sets how often expired jobs are removed
+func WithCleanupInterval(interval time.Duration) JobsOption {
+	return func(service *JobsService) error {
+		if interval <= 0 {
+			return errors.New("cleanup interval must be positive")
+		}
+		service.cleanupInterval = interval
+		return nil
+	}
+}
+
+// WithBulkSettings sets the chunk size and concurrency of the bulk conesearches of a job
+func WithBulkSettings(chunkSize, maxConcurrency int) JobsOption {
+	return func(service *JobsService) error {
+		if chunkSize <= 0 || maxConcurrency <= 0 {
+			return errors.New("bulk chunk size and concurrency must be positive integers")
+		}
+		service.chunkSize = chunkSize
+		service.maxBulkConcurrency = maxConcurrency
+		return nil
+	}
+}
@@ -0,0 +1,541 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	csv_reader "github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/reader/csv"
+	"github.com/dirodriguezm/xmatch/service/internal/catalog_indexer/source"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+)
+
+// Phases of a job, named as in the Universal Worker Service pattern
+const (
+	PhaseQueued    = "QUEUED"
+	PhaseExecuting = "EXECUTING"
+	PhaseCompleted = "COMPLETED"
+	PhaseError     = "ERROR"
+)
+
+// Formats of the result file of a job
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+const inputFile = "input.csv"
+
+var (
+	ErrJobNotFound     = errors.New("job not found")
+	ErrJobNotCompleted = errors.New("job has not completed")
+	ErrQueueFull       = errors.New("too many jobs waiting to be executed")
+)
+
+type Repository interface {
+	InsertJob(context.Context, repository.InsertJobParams) error
+	GetJob(context.Context, string) (repository.Job, error)
+	GetJobsByPhase(context.Context, string) ([]repository.Job, error)
+	GetExpiredJobs(context.Context, int64) ([]repository.Job, error)
+	StartJob(context.Context, repository.StartJobParams) error
+	UpdateJobProgress(context.Context, repository.UpdateJobProgressParams) error
+	FinishJob(context.Context, repository.FinishJobParams) error
+	DeleteJob(context.Context, string) error
+}
+
+// BulkSearcher runs the conesearch of a batch of positions
+type BulkSearcher interface {
+	BulkConesearch(
+		ctx context.Context,
+		ra, dec []float64,
+		radius []float64,
+		nneighbor []int,
+		catalog string,
+		chunkSize int,
+		maxBulkConcurrency int,
+	) ([]conesearch.MastercatResult, error)
+}
+
+// JobParameters are the arguments of the bulk conesearch run by a job
+type JobParameters struct {
+	Radius    float64 `json:"radius"`
+	Catalog   string  `json:"catalog"`
+	Nneighbor int     `json:"nneighbor"`
+	// format of the result file, csv or parquet
+	Format string `json:"format"`
+}
+
+// Job is the status of a crossmatch job.
+//
+// Progress counts the input positions already searched, out of Total.
+// The job and its files are removed once the destruction time is reached.
+type Job struct {
+	ID              string        `json:"job_id"`
+	Phase           string        `json:"phase"`
+	Parameters      JobParameters `json:"parameters"`
+	Progress        int           `json:"progress"`
+	Total           int           `json:"total"`
+	CreationTime    time.Time     `json:"creation_time"`
+	StartTime       *time.Time    `json:"start_time,omitempty"`
+	EndTime         *time.Time    `json:"end_time,omitempty"`
+	DestructionTime time.Time     `json:"destruction_time"`
+	Error           string        `json:"error,omitempty"`
+}
+
+// JobsService runs bulk conesearches in the background.
+//
+// Jobs are persisted in the jobs table, while their input positions and
+// results are kept as files in a directory of their own. A pool of workers
+// executes the queued jobs, searching the positions in batches so the
+// progress of each job can be followed.
+type JobsService struct {
+	repository         Repository
+	searcher           BulkSearcher
+	directory          string
+	workers            int
+	expiration         time.Duration
+	cleanupInterval    time.Duration
+	chunkSize          int
+	maxBulkConcurrency int
+
+	queue   chan string
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+	now     func() time.Time
+}
+
+func NewJobsService(options ...JobsOption) (*JobsService, error) {
+	service := &JobsService{
+		directory:          filepath.Join(os.TempDir(), "xmatch-jobs"),
+		workers:            1,
+		expiration:         24 * time.Hour,
+		cleanupInterval:    10 * time.Minute,
+		chunkSize:          500,
+		maxBulkConcurrency: 1,
+		queue:              make(chan string, 100),
+		running:            map[string]context.CancelFunc{},
+		now:                time.Now,
+	}
+	for _, opt := range options {
+		if err := opt(service); err != nil {
+			return nil, err
+		}
+	}
+	if service.repository == nil {
+		return nil, fmt.Errorf("Repository was nil while creating JobsService")
+	}
+	if service.searcher == nil {
+		return nil, fmt.Errorf("BulkSearcher was nil while creating JobsService")
+	}
+	if err := os.MkdirAll(service.directory, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create jobs directory: %w", err)
+	}
+	return service, nil
+}
+
+// Start runs the workers and the removal of expired jobs until the context is done.
+//
+// Jobs left queued by a previous run are queued again, and jobs that were
+// executing are marked as failed, since their results are incomplete.
+func (s *JobsService) Start(ctx context.Context) {
+	s.resume(ctx)
+
+	var wg sync.WaitGroup
+	for range s.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(s.cleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+			s.removeExpired(ctx)
+		}
+	}
+}
+
+// Submit stores the positions of a new job and queues it.
+// The ids are written next to the results, to identify each input position.
+func (s *JobsService) Submit(ctx context.Context, ids []string, ra, dec []float64, params JobParameters) (Job, error) {
+	if err := conesearch.ValidateBulkArguments(ra, dec, []float64{params.Radius}, []int{params.Nneighbor}, params.Catalog); err != nil {
+		return Job{}, err
+	}
+	if len(ids) != len(ra) {
+		return Job{}, conesearch.NewValidationError("Ids and positions must have the same length", strconv.Itoa(len(ids)), "id")
+	}
+	if len(ra) == 0 {
+		return Job{}, conesearch.NewValidationError("Jobs must have at least one position", "0", "ra")
+	}
+	params.Format = strings.ToLower(params.Format)
+	if params.Format != FormatCSV && params.Format != FormatParquet {
+		return Job{}, conesearch.NewValidationError("Format must be csv or parquet", params.Format, "format")
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return Job{}, err
+	}
+	if err := s.writeInput(id, ids, ra, dec); err != nil {
+		s.removeFiles(id)
+		return Job{}, err
+	}
+
+	now := s.now()
+	err = s.repository.InsertJob(ctx, repository.InsertJobParams{
+		ID:           id,
+		Phase:        PhaseQueued,
+		Radius:       params.Radius,
+		Catalog:      params.Catalog,
+		Nneighbor:    int64(params.Nneighbor),
+		ResultFormat: params.Format,
+		Total:        int64(len(ra)),
+		CreatedAt:    now.Unix(),
+		ExpiresAt:    now.Add(s.expiration).Unix(),
+	})
+	if err != nil {
+		s.removeFiles(id)
+		return Job{}, fmt.Errorf("could not insert job: %w", err)
+	}
+
+	// the job is read before it is queued, so it is returned as submitted
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		s.delete(ctx, id)
+		return Job{}, err
+	}
+	select {
+	case s.queue <- id:
+	default:
+		s.delete(ctx, id)
+		return Job{}, ErrQueueFull
+	}
+	return job, nil
+}
+
+// Get returns the status of a job
+func (s *JobsService) Get(ctx context.Context, id string) (Job, error) {
+	row, err := s.repository.GetJob(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Job{}, ErrJobNotFound
+	}
+	if err != nil {
+		return Job{}, fmt.Errorf("could not get job: %w", err)
+	}
+	return jobFromRow(row), nil
+}
+
+// Result returns the path and format of the result file of a completed job
+func (s *JobsService) Result(ctx context.Context, id string) (string, string, error) {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return "", "", err
+	}
+	if job.Phase != PhaseCompleted {
+		return "", "", ErrJobNotCompleted
+	}
+	return s.resultFile(id, job.Parameters.Format), job.Parameters.Format, nil
+}
+
+// Delete aborts a job if it is executing, and removes it with its files
+func (s *JobsService) Delete(ctx context.Context, id string) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	return s.delete(ctx, id)
+}
+
+func (s *JobsService) delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	if cancel, ok := s.running[id]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	if err := s.repository.DeleteJob(ctx, id); err != nil {
+		return fmt.Errorf("could not delete job: %w", err)
+	}
+	s.removeFiles(id)
+	return nil
+}
+
+func (s *JobsService) resume(ctx context.Context) {
+	executing, err := s.repository.GetJobsByPhase(ctx, PhaseExecuting)
+	if err != nil {
+		slog.Error("could not get executing jobs", "error", err)
+	}
+	for _, job := range executing {
+		s.finish(ctx, job.ID, errors.New("the service stopped while the job was executing"))
+	}
+
+	queued, err := s.repository.GetJobsByPhase(ctx, PhaseQueued)
+	if err != nil {
+		slog.Error("could not get queued jobs", "error", err)
+	}
+	for _, job := range queued {
+		select {
+		case s.queue <- job.ID:
+		default:
+			s.finish(ctx, job.ID, ErrQueueFull)
+		}
+	}
+}
+
+func (s *JobsService) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.queue:
+			s.run(ctx, id)
+		}
+	}
+}
+
+// run executes a queued job, searching its positions in batches of one chunk
+// for each concurrent search, and records the progress after each batch
+func (s *JobsService) run(ctx context.Context, id string) {
+	row, err := s.repository.GetJob(ctx, id)
+	if err != nil {
+		// the job was deleted while it was queued
+		return
+	}
+	if row.Phase != PhaseQueued {
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.mu.Lock()
+	s.running[id] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, id)
+		s.mu.Unlock()
+	}()
+
+	err = s.repository.StartJob(jobCtx, repository.StartJobParams{
+		Phase:     PhaseExecuting,
+		StartedAt: nullInt64(s.now().Unix()),
+		ID:        id,
+	})
+	if err != nil {
+		slog.Error("could not start job", "id", id, "error", err)
+		return
+	}
+
+	err = s.execute(jobCtx, jobFromRow(row))
+	if jobCtx.Err() != nil && ctx.Err() == nil {
+		// the job was deleted while it was executing
+		return
+	}
+	s.finish(ctx, id, err)
+}
+
+func (s *JobsService) execute(ctx context.Context, job Job) error {
+	ids, ra, dec, err := s.readInput(job.ID)
+	if err != nil {
+		return err
+	}
+
+	writer, err := newResultWriter(s.resultFile(job.ID, job.Parameters.Format), job.Parameters.Format)
+	if err != nil {
+		return err
+	}
+
+	batchSize := s.chunkSize * s.maxBulkConcurrency
+	for start := 0; start < len(ra); start += batchSize {
+		end := min(start+batchSize, len(ra))
+		results, err := s.searcher.BulkConesearch(
+			ctx,
+			ra[start:end],
+			dec[start:end],
+			[]float64{job.Parameters.Radius},
+			[]int{job.Parameters.Nneighbor},
+			job.Parameters.Catalog,
+			s.chunkSize,
+			s.maxBulkConcurrency,
+		)
+		if err != nil {
+			writer.close()
+			return err
+		}
+		if err := writer.write(resultRows(results, ids, start)); err != nil {
+			writer.close()
+			return fmt.Errorf("could not write results: %w", err)
+		}
+
+		err = s.repository.UpdateJobProgress(ctx, repository.UpdateJobProgressParams{Progress: int64(end), ID: job.ID})
+		if err != nil {
+			writer.close()
+			return fmt.Errorf("could not update job progress: %w", err)
+		}
+	}
+	return writer.close()
+}
+
+// finish records the end of a job, which failed if err is not nil
+func (s *JobsService) finish(ctx context.Context, id string, err error) {
+	params := repository.FinishJobParams{
+		Phase:      PhaseCompleted,
+		FinishedAt: nullInt64(s.now().Unix()),
+		ID:         id,
+	}
+	if err != nil {
+		slog.Error("job failed", "id", id, "error", err)
+		params.Phase = PhaseError
+		params.Error = repository.NullString{NullString: sql.NullString{String: err.Error(), Valid: true}}
+	}
+	// the end of the job is recorded even when the service is stopping
+	if err := s.repository.FinishJob(context.WithoutCancel(ctx), params); err != nil {
+		slog.Error("could not finish job", "id", id, "error", err)
+	}
+}
+
+// removeExpired deletes the jobs whose destruction time was reached
+func (s *JobsService) removeExpired(ctx context.Context) {
+	expired, err := s.repository.GetExpiredJobs(ctx, s.now().Unix())
+	if err != nil {
+		slog.Error("could not get expired jobs", "error", err)
+		return
+	}
+	for _, job := range expired {
+		if err := s.delete(ctx, job.ID); err != nil {
+			slog.Error("could not remove expired job", "id", job.ID, "error", err)
+		}
+	}
+}
+
+func (s *JobsService) jobDirectory(id string) string {
+	return filepath.Join(s.directory, id)
+}
+
+func (s *JobsService) resultFile(id, format string) string {
+	return filepath.Join(s.jobDirectory(id), "result."+format)
+}
+
+func (s *JobsService) removeFiles(id string) {
+	if err := os.RemoveAll(s.jobDirectory(id)); err != nil {
+		slog.Error("could not remove job files", "id", id, "error", err)
+	}
+}
+
+// writeInput stores the positions of a job as a csv file with id, ra and dec columns
+func (s *JobsService) writeInput(id string, ids []string, ra, dec []float64) error {
+	if err := os.MkdirAll(s.jobDirectory(id), 0o755); err != nil {
+		return fmt.Errorf("could not create job directory: %w", err)
+	}
+	file, err := os.Create(filepath.Join(s.jobDirectory(id), inputFile))
+	if err != nil {
+		return fmt.Errorf("could not create job input: %w", err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"id", "ra", "dec"})
+	for i := range ra {
+		w.Write([]string{ids[i], strconv.FormatFloat(ra[i], 'g', -1, 64), strconv.FormatFloat(dec[i], 'g', -1, 64)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("could not write job input: %w", err)
+	}
+	return file.Close()
+}
+
+// readInput reads the positions of a job with the csv reader of the indexer
+func (s *JobsService) readInput(id string) ([]string, []float64, []float64, error) {
+	src := &source.Source{Sources: []string{filepath.Join(s.jobDirectory(id), inputFile)}, CatalogName: "job"}
+	schema := repository.GenericSchema{Catalog: "job", IdColumn: "id", RaColumn: "ra", DecColumn: "dec"}
+	r, err := csv_reader.NewCsvReader(src, csv_reader.WithFirstLineHeader(true), csv_reader.WithGenericSchema(schema))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("could not read job input: %w", err)
+	}
+	rows, err := r.Read()
+	if err != nil {
+		r.Close()
+		return nil, nil, nil, fmt.Errorf("could not read job input: %w", err)
+	}
+
+	ids := make([]string, len(rows))
+	ra := make([]float64, len(rows))
+	dec := make([]float64, len(rows))
+	for i := range rows {
+		row := rows[i].(repository.GenericRow)
+		ids[i], ra[i], dec[i] = row.ID, row.Ra, row.Dec
+	}
+	return ids, ra, dec, nil
+}
+
+func jobFromRow(row repository.Job) Job {
+	job := Job{
+		ID:    row.ID,
+		Phase: row.Phase,
+		Parameters: JobParameters{
+			Radius:    row.Radius,
+			Catalog:   row.Catalog,
+			Nneighbor: int(row.Nneighbor),
+			Format:    row.ResultFormat,
+		},
+		Progress:        int(row.Progress),
+		Total:           int(row.Total),
+		CreationTime:    time.Unix(row.CreatedAt, 0).UTC(),
+		DestructionTime: time.Unix(row.ExpiresAt, 0).UTC(),
+		Error:           row.Error.String,
+	}
+	if row.StartedAt.Valid {
+		start := time.Unix(row.StartedAt.Int64, 0).UTC()
+		job.StartTime = &start
+	}
+	if row.FinishedAt.Valid {
+		end := time.Unix(row.FinishedAt.Int64, 0).UTC()
+		job.EndTime = &end
+	}
+	return job
+}
+
+func nullInt64(v int64) repository.NullInt64 {
+	return repository.NullInt64{NullInt64: sql.NullInt64{Int64: v, Valid: true}}
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("could not create job id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,302 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch/test_helpers"
+	"github.com/dirodriguezm/xmatch/service/internal/testutils"
+	"github.com/xitongsys/parquet-go-source/local"
+	preader "github.com/xitongsys/parquet-go/reader"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSearcher finds one object at each searched position
+type fakeSearcher struct {
+	mu    sync.Mutex
+	calls [][]float64
+	err   error
+	block chan struct{}
+}
+
+func (f *fakeSearcher) BulkConesearch(
+	ctx context.Context,
+	ra, dec []float64,
+	radius []float64,
+	nneighbor []int,
+	catalog string,
+	chunkSize int,
+	maxBulkConcurrency int,
+) ([]conesearch.MastercatResult, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, ra)
+	f.mu.Unlock()
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	results := make([]conesearch.MastercatResult, len(ra))
+	for i := range ra {
+		m := repository.Mastercat{ID: fmt.Sprintf("obj-%v", ra[i]), Ra: ra[i], Dec: dec[i], Cat: "allwise"}
+		results[i] = conesearch.MastercatResult{
+			Catalog: "allwise",
+			Data:    []conesearch.MastercatExtended{{Mastercat: m, Distance: 0.5}},
+			Index:   i,
+		}
+	}
+	return results, nil
+}
+
+func newTestService(t *testing.T, searcher BulkSearcher, opts ...JobsOption) *JobsService {
+	t.Helper()
+	rootPath, err := testutils.FindRootModulePath(5)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "test.db")
+	require.NoError(t, test_helpers.Migrate(dbFile, rootPath))
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_busy_timeout=5000", dbFile))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	service, err := NewJobsService(append([]JobsOption{
+		WithRepository(repository.New(db)),
+		WithSearcher(searcher),
+		WithDirectory(filepath.Join(dir, "jobs")),
+		WithBulkSettings(2, 1),
+	}, opts...)...)
+	require.NoError(t, err)
+	return service
+}
+
+func startService(t *testing.T, service *JobsService) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		service.Start(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func waitForPhase(t *testing.T, service *JobsService, id, phase string) Job {
+	t.Helper()
+	var job Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = service.Get(context.Background(), id)
+		require.NoError(t, err)
+		return job.Phase == phase
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+var testParams = JobParameters{Radius: 1, Catalog: "all", Nneighbor: 1, Format: FormatCSV}
+
+func TestSubmit_RunsJobInBatches(t *testing.T) {
+	searcher := &fakeSearcher{}
+	service := newTestService(t, searcher)
+	startService(t, service)
+
+	job, err := service.Submit(context.Background(), []string{"a", "b", "c"}, []float64{1, 2, 3}, []float64{-1, -2, -3}, testParams)
+	require.NoError(t, err)
+	require.Equal(t, 3, job.Total)
+	require.Equal(t, testParams, job.Parameters)
+
+	job = waitForPhase(t, service, job.ID, PhaseCompleted)
+	require.Equal(t, 3, job.Progress)
+	require.NotNil(t, job.StartTime)
+	require.NotNil(t, job.EndTime)
+	require.Empty(t, job.Error)
+	require.Equal(t, [][]float64{{1, 2}, {3}}, searcher.calls)
+
+	fileName, format, err := service.Result(context.Background(), job.ID)
+	require.NoError(t, err)
+	require.Equal(t, FormatCSV, format)
+	file, err := os.Open(fileName)
+	require.NoError(t, err)
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		resultHeader,
+		{"0", "a", "allwise", "obj-1", "1", "-1", "0.5"},
+		{"1", "b", "allwise", "obj-2", "2", "-2", "0.5"},
+		{"2", "c", "allwise", "obj-3", "3", "-3", "0.5"},
+	}, rows)
+}
+
+func TestSubmit_ParquetResult(t *testing.T) {
+	service := newTestService(t, &fakeSearcher{})
+	startService(t, service)
+
+	params := testParams
+	params.Format = "Parquet"
+	job, err := service.Submit(context.Background(), []string{"a", "b"}, []float64{1, 2}, []float64{1, 2}, params)
+	require.NoError(t, err)
+	require.Equal(t, FormatParquet, job.Parameters.Format)
+	waitForPhase(t, service, job.ID, PhaseCompleted)
+
+	fileName, _, err := service.Result(context.Background(), job.ID)
+	require.NoError(t, err)
+	fr, err := local.NewLocalFileReader(fileName)
+	require.NoError(t, err)
+	defer fr.Close()
+	pr, err := preader.NewParquetReader(fr, new(ResultRow), 1)
+	require.NoError(t, err)
+	defer pr.ReadStop()
+	rows := make([]ResultRow, pr.GetNumRows())
+	require.NoError(t, pr.Read(&rows))
+	require.Equal(t, []ResultRow{
+		{Index: 0, InputID: "a", Catalog: "allwise", ID: "obj-1", Ra: 1, Dec: 1, Distance: 0.5},
+		{Index: 1, InputID: "b", Catalog: "allwise", ID: "obj-2", Ra: 2, Dec: 2, Distance: 0.5},
+	}, rows)
+}
+
+func TestSubmit_Validation(t *testing.T) {
+	service := newTestService(t, &fakeSearcher{})
+
+	testCases := map[string]struct {
+		ids    []string
+		params JobParameters
+		field  string
+	}{
+		"invalid radius": {[]string{"a"}, JobParameters{Radius: -1, Catalog: "all", Nneighbor: 1, Format: FormatCSV}, "radius"},
+		"invalid format": {[]string{"a"}, JobParameters{Radius: 1, Catalog: "all", Nneighbor: 1, Format: "fits"}, "format"},
+		"missing ids":    {[]string{}, testParams, "id"},
+	}
+	for name, tc := range testCases {
+		_, err := service.Submit(context.Background(), tc.ids, []float64{1}, []float64{1}, tc.params)
+		var validationErr conesearch.ValidationError
+		require.ErrorAs(t, err, &validationErr, name)
+		require.Equal(t, tc.field, validationErr.Field, name)
+	}
+}
+
+func TestSubmit_QueueFull(t *testing.T) {
+	service := newTestService(t, &fakeSearcher{}, WithQueueSize(1))
+
+	_, err := service.Submit(context.Background(), []string{"a"}, []float64{1}, []float64{1}, testParams)
+	require.NoError(t, err)
+	_, err = service.Submit(context.Background(), []string{"b"}, []float64{1}, []float64{1}, testParams)
+	require.ErrorIs(t, err, ErrQueueFull)
+
+	// the rejected job is not kept
+	entries, err := os.ReadDir(service.directory)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestRun_SearchError(t *testing.T) {
+	service := newTestService(t, &fakeSearcher{err: errors.New("database is gone")})
+	startService(t, service)
+
+	job, err := service.Submit(context.Background(), []string{"a"}, []float64{1}, []float64{1}, testParams)
+	require.NoError(t, err)
+
+	job = waitForPhase(t, service, job.ID, PhaseError)
+	require.Equal(t, "database is gone", job.Error)
+	_, _, err = service.Result(context.Background(), job.ID)
+	require.ErrorIs(t, err, ErrJobNotCompleted)
+}
+
+func TestDelete_AbortsExecutingJob(t *testing.T) {
+	searcher := &fakeSearcher{block: make(chan struct{})}
+	service := newTestService(t, searcher)
+	startService(t, service)
+
+	job, err := service.Submit(context.Background(), []string{"a"}, []float64{1}, []float64{1}, testParams)
+	require.NoError(t, err)
+	waitForPhase(t, service, job.ID, PhaseExecuting)
+
+	require.NoError(t, service.Delete(context.Background(), job.ID))
+	_, err = service.Get(context.Background(), job.ID)
+	require.ErrorIs(t, err, ErrJobNotFound)
+	require.NoDirExists(t, service.jobDirectory(job.ID))
+	require.ErrorIs(t, service.Delete(context.Background(), job.ID), ErrJobNotFound)
+}
+
+func TestRemoveExpired(t *testing.T) {
+	service := newTestService(t, &fakeSearcher{}, WithExpiration(time.Hour))
+
+	expired, err := service.Submit(context.Background(), []string{"a"}, []float64{1}, []float64{1}, testParams)
+	require.NoError(t, err)
+	service.now = func() time.Time { return time.Now().Add(30 * time.Minute) }
+	kept, err := service.Submit(context.Background(), []string{"a"}, []float64{1}, []float64{1}, testParams)
+	require.NoError(t, err)
+
+	service.now = func() time.Time { return time.Now().Add(time.Hour) }
+	service.removeExpired(context.Background())
+
+	_, err = service.Get(context.Background(), expired.ID)
+	require.ErrorIs(t, err, ErrJobNotFound)
+	require.NoDirExists(t, service.jobDirectory(expired.ID))
+	_, err = service.Get(context.Background(), kept.ID)
+	require.NoError(t, err)
+	require.DirExists(t, service.jobDirectory(kept.ID))
+}
+
+func TestStart_ResumesJobs(t *testing.T) {
+	service := newTestService(t, &fakeSearcher{})
+
+	queued, err := service.Submit(context.Background(), []string{"a"}, []float64{1}, []float64{1}, testParams)
+	require.NoError(t, err)
+	interrupted, err := service.Submit(context.Background(), []string{"b"}, []float64{1}, []float64{1}, testParams)
+	require.NoError(t, err)
+	err = service.repository.StartJob(context.Background(), repository.StartJobParams{
+		Phase:     PhaseExecuting,
+		StartedAt: nullInt64(time.Now().Unix()),
+		ID:        interrupted.ID,
+	})
+	require.NoError(t, err)
+
+	// a new service finds the jobs left by the previous one
+	restarted, err := NewJobsService(
+		WithRepository(service.repository),
+		WithSearcher(&fakeSearcher{}),
+		WithDirectory(service.directory),
+	)
+	require.NoError(t, err)
+	startService(t, restarted)
+
+	waitForPhase(t, restarted, queued.ID, PhaseCompleted)
+	job := waitForPhase(t, restarted, interrupted.ID, PhaseError)
+	require.NotEmpty(t, job.Error)
+}
@@ -0,0 +1,143 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+	pwriter "github.com/xitongsys/parquet-go/writer"
+)
+
+// ResultRow is a match of an input position, as written to the result file of a job.
+// Index is the position of the input row in the submitted file.
+type ResultRow struct {
+	Index    int64   `parquet:"name=index, type=INT64"`
+	InputID  string  `parquet:"name=input_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Catalog  string  `parquet:"name=catalog, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ID       string  `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Ra       float64 `parquet:"name=ra, type=DOUBLE"`
+	Dec      float64 `parquet:"name=dec, type=DOUBLE"`
+	Distance float64 `parquet:"name=distance, type=DOUBLE"`
+}
+
+var resultHeader = []string{"index", "input_id", "catalog", "id", "ra", "dec", "distance"}
+
+// resultRows flattens the results of a batch of positions starting at offset
+func resultRows(results []conesearch.MastercatResult, ids []string, offset int) []ResultRow {
+	rows := make([]ResultRow, 0, len(results))
+	for _, result := range results {
+		index := offset + result.Index
+		for _, m := range result.Data {
+			rows = append(rows, ResultRow{
+				Index:    int64(index),
+				InputID:  ids[index],
+				Catalog:  result.Catalog,
+				ID:       m.ID,
+				Ra:       m.Ra,
+				Dec:      m.Dec,
+				Distance: m.Distance,
+			})
+		}
+	}
+	return rows
+}
+
+type resultWriter interface {
+	write([]ResultRow) error
+	close() error
+}
+
+func newResultWriter(fileName, format string) (resultWriter, error) {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("could not create result file: %w", err)
+	}
+
+	switch format {
+	case FormatParquet:
+		w, err := pwriter.NewParquetWriterFromWriter(file, new(ResultRow), 1)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("could not create parquet writer: %w", err)
+		}
+		return &parquetResultWriter{file: file, writer: w}, nil
+	default:
+		w := csv.NewWriter(file)
+		if err := w.Write(resultHeader); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("could not write result header: %w", err)
+		}
+		return &csvResultWriter{file: file, writer: w}, nil
+	}
+}
+
+type csvResultWriter struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+func (w *csvResultWriter) write(rows []ResultRow) error {
+	for _, row := range rows {
+		err := w.writer.Write([]string{
+			strconv.FormatInt(row.Index, 10),
+			row.InputID,
+			row.Catalog,
+			row.ID,
+			strconv.FormatFloat(row.Ra, 'g', -1, 64),
+			strconv.FormatFloat(row.Dec, 'g', -1, 64),
+			strconv.FormatFloat(row.Distance, 'g', -1, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvResultWriter) close() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+type parquetResultWriter struct {
+	file   *os.File
+	writer *pwriter.ParquetWriter
+}
+
+func (w *parquetResultWriter) write(rows []ResultRow) error {
+	for _, row := range rows {
+		if err := w.writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *parquetResultWriter) close() error {
+	if err := w.writer.WriteStop(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
@@ -15,36 +15,36 @@
 package knn
 
 import (
-	"fmt"
 	"math"
-	"strings"
 
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
 	"github.com/dirodriguezm/xmatch/service/internal/repository"
 
 	"github.com/kyroy/kdtree"
-	"github.com/kyroy/kdtree/points"
 )
 
+// knnObject stores an object as a unit vector, so that the euclidean
+// distance used by the kd-tree is the chord between two points of the sphere.
+// The chord grows monotonically with the angular distance, which makes the
+// kd-tree neighbors the true nearest neighbors on the sky, including across
+// RA=0/360 and around the poles.
 type knnObject struct {
 	Obj         repository.Mastercat
 	MetadataObj repository.MetadataWithCoordinates
-	catalog     string
+	ra, dec     float64
+	vector      [3]float64
+}
+
+func newKnnObject(ra, dec float64) knnObject {
+	return knnObject{ra: ra, dec: dec, vector: unitVector(ra, dec)}
 }
 
 func (knn knnObject) Dimensions() int {
-	return 2
+	return 3
 }
 
 func (knn knnObject) Dimension(i int) float64 {
-	var dimensions []float64
-	switch strings.ToLower(knn.catalog) {
-	case "":
-		dimensions = []float64{knn.Obj.Ra, knn.Obj.Dec}
-	default:
-		ra, dec := knn.MetadataObj.GetCoordinates()
-		dimensions = []float64{ra, dec}
-	}
-	return dimensions[i]
+	return knn.vector[i]
 }
 
 type KnnResult[T any] struct {
@@ -52,6 +52,8 @@ type KnnResult[T any] struct {
 	Distance []float64
 }
 
+// NearestNeighborSearch returns up to maxNeighbors objects inside radius (arcsec),
+// ordered by increasing angular distance to the given coordinates
 func NearestNeighborSearch(
 	objects []repository.Mastercat,
 	ra, dec, radius float64,
@@ -59,16 +61,19 @@ func NearestNeighborSearch(
 ) KnnResult[repository.Mastercat] {
 	pts := []kdtree.Point{}
 	for _, obj := range objects {
-		pts = append(pts, knnObject{Obj: obj})
+		point := newKnnObject(obj.Ra, obj.Dec)
+		point.Obj = obj
+		pts = append(pts, point)
 	}
 	tree := kdtree.New(pts)
 
-	nearObjs := tree.KNN(&points.Point2D{X: ra, Y: dec}, maxNeighbors)
+	center := newKnnObject(ra, dec)
+	nearObjs := tree.KNN(center, maxNeighbors)
 
 	// now we need to check that distance between nearest objects and center is actually lower than radius
 	result := KnnResult[repository.Mastercat]{}
 	for _, obj := range nearObjs {
-		dist := haversineDistance(obj, &points.Point2D{X: ra, Y: dec})
+		dist := haversineDistance(obj.(knnObject), center)
 		if dist > radius {
 			continue
 		}
@@ -78,92 +83,59 @@ func NearestNeighborSearch(
 	return result
 }
 
+// NearestNeighborSearchForMetadata works like NearestNeighborSearch for metadata rows
 func NearestNeighborSearchForMetadata(
 	objects []repository.MetadataWithCoordinates,
 	ra, dec, radius float64,
 	maxNeighbors int,
-	catalog string,
+	catalogName string,
 ) KnnResult[repository.Metadata] {
 	pts := []kdtree.Point{}
 	for _, obj := range objects {
-		pts = append(pts, knnObject{MetadataObj: obj, catalog: catalog})
+		point := newKnnObject(obj.GetCoordinates())
+		point.MetadataObj = obj
+		pts = append(pts, point)
 	}
 	tree := kdtree.New(pts)
 
-	nearObjs := tree.KNN(&points.Point2D{X: ra, Y: dec}, maxNeighbors)
+	center := newKnnObject(ra, dec)
+	nearObjs := tree.KNN(center, maxNeighbors)
 
 	// now we need to check that distance between nearest objects and center is actually lower than radius
 	result := KnnResult[repository.Metadata]{}
 	for _, obj := range nearObjs {
-		dist := haversineDistance(obj, &points.Point2D{X: ra, Y: dec})
+		dist := haversineDistance(obj.(knnObject), center)
 		if dist > radius {
 			continue
 		}
 		result.Distance = append(result.Distance, dist)
-		switch m := obj.(knnObject).MetadataObj; m.(type) {
-		case repository.GetAllwiseFromPixelsRow:
-			result.Data = append(result.Data, convertToAllwise(obj.(knnObject).MetadataObj.(repository.GetAllwiseFromPixelsRow)))
-		case repository.GetGaiaFromPixelsRow:
-			result.Data = append(result.Data, convertToGaia(obj.(knnObject).MetadataObj.(repository.GetGaiaFromPixelsRow)))
-		default:
+		m := obj.(knnObject).MetadataObj
+		desc, ok := catalog.LookupDisplayName(m.GetCatalog())
+		if !ok || desc.ToMetadata == nil {
 			panic("Unknown catalog to KNN Search for Metadata")
 		}
+		result.Data = append(result.Data, desc.ToMetadata(m))
 	}
 	return result
 }
 
-func convertToAllwise(obj repository.GetAllwiseFromPixelsRow) repository.Allwise {
-	return repository.Allwise{
-		ID:         obj.ID,
-		Cntr:       obj.Cntr,
-		W1mpro:     obj.W1mpro,
-		W1sigmpro:  obj.W1sigmpro,
-		W2mpro:     obj.W2mpro,
-		W2sigmpro:  obj.W2sigmpro,
-		W3mpro:     obj.W3mpro,
-		W3sigmpro:  obj.W3sigmpro,
-		W4mpro:     obj.W4mpro,
-		W4sigmpro:  obj.W4sigmpro,
-		JM2mass:    obj.JM2mass,
-		JMsig2mass: obj.JMsig2mass,
-		HM2mass:    obj.HM2mass,
-		HMsig2mass: obj.HMsig2mass,
-		KM2mass:    obj.KM2mass,
-		KMsig2mass: obj.KMsig2mass,
-	}
-}
-
-func convertToGaia(obj repository.GetGaiaFromPixelsRow) repository.Gaia {
-	return repository.Gaia{
-		ID:                  obj.ID,
-		PhotGMeanFlux:       obj.PhotGMeanFlux,
-		PhotGMeanFluxError:  obj.PhotGMeanFluxError,
-		PhotGMeanMag:        obj.PhotGMeanMag,
-		PhotBpMeanFlux:      obj.PhotBpMeanFlux,
-		PhotBpMeanFluxError: obj.PhotBpMeanFluxError,
-		PhotBpMeanMag:       obj.PhotBpMeanMag,
-		PhotRpMeanFlux:      obj.PhotRpMeanFlux,
-		PhotRpMeanFluxError: obj.PhotRpMeanFluxError,
-		PhotRpMeanMag:       obj.PhotRpMeanMag,
+// unitVector returns the cartesian coordinates of a point of the unit sphere given in degrees
+func unitVector(ra, dec float64) [3]float64 {
+	raRad := ra * math.Pi / 180.0
+	decRad := dec * math.Pi / 180.0
+	return [3]float64{
+		math.Cos(decRad) * math.Cos(raRad),
+		math.Cos(decRad) * math.Sin(raRad),
+		math.Sin(decRad),
 	}
 }
 
 // Return the distance in arcsec, between two points in a sphere, using the Haversine Formula
-func haversineDistance(p1, p2 kdtree.Point) float64 {
-	if p1.Dimensions() != 2 || p2.Dimensions() != 2 {
-		err := fmt.Errorf("Can't calculate distance between points of dimension %v and %v", p1.Dimensions(), p2.Dimensions())
-		panic(err)
-	}
-
-	ra1 := p1.Dimension(0)
-	dec1 := p1.Dimension(1)
-	ra2 := p2.Dimension(0)
-	dec2 := p2.Dimension(1)
-
-	ra1Rad := ra1 * math.Pi / 180.0
-	dec1Rad := dec1 * math.Pi / 180.0
-	ra2Rad := ra2 * math.Pi / 180.0
-	dec2Rad := dec2 * math.Pi / 180.0
+func haversineDistance(p1, p2 knnObject) float64 {
+	ra1Rad := p1.ra * math.Pi / 180.0
+	dec1Rad := p1.dec * math.Pi / 180.0
+	ra2Rad := p2.ra * math.Pi / 180.0
+	dec2Rad := p2.dec * math.Pi / 180.0
 
 	deltaRA := ra2Rad - ra1Rad
 	deltaDec := dec2Rad - dec1Rad
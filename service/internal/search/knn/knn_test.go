@@ -15,6 +15,7 @@
 package knn
 
 import (
+	"math"
 	"testing"
 
 	"github.com/dirodriguezm/xmatch/service/internal/repository"
@@ -51,3 +52,61 @@ func TestKnn(t *testing.T) {
 		}
 	}
 }
+
+func TestKnn_RaWraparound(t *testing.T) {
+	objectList := []repository.Mastercat{
+		{ID: "west", Ra: 359.9999, Dec: 0},
+		{ID: "east", Ra: 0.0002, Dec: 0},
+		{ID: "far", Ra: 180, Dec: 0},
+	}
+
+	result := NearestNeighborSearch(objectList, 0, 0, 1, 3)
+	require.Len(t, result.Data, 2)
+	require.Equal(t, "west", result.Data[0].ID)
+	require.Equal(t, "east", result.Data[1].ID)
+	require.InDelta(t, 0.36, result.Distance[0], 1e-6)
+	require.InDelta(t, 0.72, result.Distance[1], 1e-6)
+
+	// searching from the other side of the boundary
+	result = NearestNeighborSearch(objectList, 359.9999, 0, 1, 1)
+	require.Len(t, result.Data, 1)
+	require.Equal(t, "west", result.Data[0].ID)
+}
+
+func TestKnn_PolarCone(t *testing.T) {
+	arcsec := 1.0 / 3600
+	objectList := []repository.Mastercat{
+		// across the pole, 1 arcsec away from the target
+		{ID: "across", Ra: 180, Dec: 90 - 0.5*arcsec},
+		// same ra as the target but farther away
+		{ID: "same-ra", Ra: 0, Dec: 90 - 2.5*arcsec},
+		// far in ra but close on the sky
+		{ID: "other-ra", Ra: 90, Dec: 90 - 0.5*arcsec},
+	}
+
+	result := NearestNeighborSearch(objectList, 0, 90-0.5*arcsec, 3, 3)
+	require.Len(t, result.Data, 3)
+	require.Equal(t, "other-ra", result.Data[0].ID)
+	require.Equal(t, "across", result.Data[1].ID)
+	require.Equal(t, "same-ra", result.Data[2].ID)
+	require.InDelta(t, 0.5*math.Sqrt2, result.Distance[0], 1e-3)
+	require.InDelta(t, 1, result.Distance[1], 1e-3)
+	require.InDelta(t, 2, result.Distance[2], 1e-3)
+
+	result = NearestNeighborSearch(objectList, 0, 90-0.5*arcsec, 1.5, 1)
+	require.Len(t, result.Data, 1)
+	require.Equal(t, "other-ra", result.Data[0].ID)
+}
+
+func TestKnn_HighDeclinationOrder(t *testing.T) {
+	// at dec=80 one degree in ra is about 0.17 degrees on the sky,
+	// so the closest object in the ra/dec plane is not the closest on the sky
+	objectList := []repository.Mastercat{
+		{ID: "ra-offset", Ra: 10.001, Dec: 80},
+		{ID: "dec-offset", Ra: 10, Dec: 80.0005},
+	}
+
+	result := NearestNeighborSearch(objectList, 10, 80, 5, 1)
+	require.Len(t, result.Data, 1)
+	require.Equal(t, "ra-offset", result.Data[0].ID)
+}
@@ -0,0 +1,323 @@
+package gaia
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/httpclient"
+)
+
+// DefaultURL is the Gaia archive, a local mirror must serve the same TAP and datalink paths
+const DefaultURL = "https://gea.esac.esa.int"
+
+const (
+	tapPath      = "/tap-server/tap/sync"
+	datalinkPath = "/data-server/data"
+)
+
+type GaiaClient struct {
+	url     string
+	release string
+	table   string
+	client  *httpclient.Client
+}
+
+type GaiaClientOption func(*GaiaClient) error
+
+// WithURL points the client to another Gaia archive, like a local mirror
+func WithURL(u string) GaiaClientOption {
+	return func(client *GaiaClient) error {
+		if _, err := url.ParseRequestURI(u); err != nil {
+			return fmt.Errorf("invalid Gaia url %q: %w", u, err)
+		}
+		client.url = strings.TrimSuffix(u, "/")
+		return nil
+	}
+}
+
+// WithHTTPClient makes the requests through client, which sets their timeout, retries and limits
+func WithHTTPClient(client *httpclient.Client) GaiaClientOption {
+	return func(c *GaiaClient) error {
+		if client == nil {
+			return fmt.Errorf("Gaia http client can't be nil")
+		}
+		c.client = client
+		return nil
+	}
+}
+
+func NewGaiaClient(options ...GaiaClientOption) (*GaiaClient, error) {
+	client := &GaiaClient{
+		url:     DefaultURL,
+		release: "Gaia DR3",
+		table:   "gaiadr3.gaia_source",
+	}
+	for _, opt := range options {
+		if err := opt(client); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// FetchLightcurve finds the Gaia sources with epoch photometry in the cone,
+// then retrieves their per-transit photometry through the datalink service,
+// combined in a single table with one row for each transit and band
+func (client *GaiaClient) FetchLightcurve(ctx context.Context, ra, dec, radius float64, nobjects int) lightcurve.ClientResult {
+	sourceIds, err := client.fetchSourceIds(ctx, ra, dec, radius, nobjects)
+	if err != nil {
+		return lightcurve.ClientResult{Error: fmt.Errorf("could not find Gaia sources: %w", err)}
+	}
+	if len(sourceIds) == 0 {
+		return lightcurve.ClientResult{Lightcurve: lightcurve.Lightcurve{}}
+	}
+
+	detections, err := client.fetchEpochPhotometry(ctx, sourceIds)
+	if err != nil {
+		return lightcurve.ClientResult{Error: fmt.Errorf("could not fetch Gaia epoch photometry: %w", err)}
+	}
+
+	return lightcurve.ClientResult{
+		Lightcurve: lightcurve.Lightcurve{Detections: detections},
+	}
+}
+
+func (client *GaiaClient) fetchSourceIds(ctx context.Context, ra, dec, radius float64, nobjects int) ([]string, error) {
+	top := ""
+	if nobjects > 0 {
+		top = fmt.Sprintf("TOP %d ", nobjects)
+	}
+	query := fmt.Sprintf(
+		"SELECT %ssource_id FROM %s WHERE has_epoch_photometry = 'True' "+
+			"AND 1 = CONTAINS(POINT('ICRS', ra, dec), CIRCLE('ICRS', %s, %s, %s)) "+
+			"ORDER BY DISTANCE(POINT('ICRS', ra, dec), POINT('ICRS', %s, %s))",
+		top, client.table, formatFloat(ra), formatFloat(dec), formatFloat(arcSecToDeg(radius)), formatFloat(ra), formatFloat(dec),
+	)
+
+	body, err := client.get(ctx, tapPath, map[string]string{
+		"REQUEST": "doQuery",
+		"LANG":    "ADQL",
+		"FORMAT":  "csv",
+		"QUERY":   query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := parseCsv(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse response: %w", err)
+	}
+
+	sourceIds := make([]string, 0, len(rows))
+	for _, row := range rows {
+		sourceId, ok := row["source_id"]
+		if !ok {
+			return nil, fmt.Errorf("response has no source_id column")
+		}
+		sourceIds = append(sourceIds, sourceId)
+	}
+	return sourceIds, nil
+}
+
+func (client *GaiaClient) fetchEpochPhotometry(ctx context.Context, sourceIds []string) ([]lightcurve.LightcurveObject, error) {
+	body, err := client.get(ctx, datalinkPath, map[string]string{
+		"RETRIEVAL_TYPE": "EPOCH_PHOTOMETRY",
+		"DATA_STRUCTURE": "COMBINED",
+		"FORMAT":         "CSV",
+		"RELEASE":        client.release,
+		"ID":             strings.Join(sourceIds, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := unzipIfNeeded(body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	detections := make([]lightcurve.LightcurveObject, 0)
+	for _, file := range files {
+		rows, err := parseCsv(bytes.NewReader(file))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse response: %w", err)
+		}
+		for _, row := range rows {
+			detection, ok, err := detectionFromRow(row)
+			if err != nil {
+				return nil, fmt.Errorf("could not convert row to detection: %w", err)
+			}
+			if ok {
+				detections = append(detections, detection)
+			}
+		}
+	}
+	return detections, nil
+}
+
+func (client *GaiaClient) get(ctx context.Context, path string, params map[string]string) ([]byte, error) {
+	u, err := url.Parse(client.url + path)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse url: %w", err)
+	}
+	u = addQueryParameters(u, params)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create request: %w", err)
+	}
+
+	resp, err := client.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response body: %w", err)
+	}
+	return body, nil
+}
+
+func (client *GaiaClient) httpClient() *httpclient.Client {
+	if client.client == nil {
+		return &httpclient.Client{}
+	}
+	return client.client
+}
+
+// unzipIfNeeded returns the files of a zip archive, which the datalink service sends for
+// some requests, or the body itself when it is not an archive
+func unzipIfNeeded(body []byte) ([][]byte, error) {
+	if !bytes.HasPrefix(body, []byte("PK\x03\x04")) {
+		return [][]byte{body}, nil
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([][]byte, 0, len(archive.File))
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, content)
+	}
+	return files, nil
+}
+
+// parseCsv reads a csv with a header into one map per row, keyed by column name
+func parseCsv(r io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]map[string]string, len(records))
+	for i, record := range records {
+		row := make(map[string]string, len(header))
+		for j, column := range header {
+			row[strings.TrimSpace(column)] = strings.TrimSpace(record[j])
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// detectionFromRow converts a row of epoch photometry, telling false when the transit has no magnitude
+func detectionFromRow(row map[string]string) (Detection, bool, error) {
+	if row["mag"] == "" || strings.EqualFold(row["mag"], "nan") {
+		return Detection{}, false, nil
+	}
+
+	var err error
+	detection := Detection{Band: row["band"]}
+	if detection.SourceId, err = strconv.ParseInt(row["source_id"], 10, 64); err != nil {
+		return detection, false, fmt.Errorf("could not parse source_id: %w", err)
+	}
+	if detection.TransitId, err = strconv.ParseInt(row["transit_id"], 10, 64); err != nil {
+		return detection, false, fmt.Errorf("could not parse transit_id: %w", err)
+	}
+	if detection.Time, err = strconv.ParseFloat(row["time"], 64); err != nil {
+		return detection, false, fmt.Errorf("could not parse time: %w", err)
+	}
+	detection.Mjd = detection.Time + gaiaTimeToMjd
+	if detection.Mag, err = strconv.ParseFloat(row["mag"], 64); err != nil {
+		return detection, false, fmt.Errorf("could not parse mag: %w", err)
+	}
+	if detection.Flux, err = parseOptionalFloat(row["flux"]); err != nil {
+		return detection, false, fmt.Errorf("could not parse flux: %w", err)
+	}
+	if detection.FluxError, err = parseOptionalFloat(row["flux_error"]); err != nil {
+		return detection, false, fmt.Errorf("could not parse flux_error: %w", err)
+	}
+	detection.RejectedByPhotometry = strings.EqualFold(row["rejected_by_photometry"], "true")
+	detection.RejectedByVariability = strings.EqualFold(row["rejected_by_variability"], "true")
+
+	return detection, true, nil
+}
+
+func parseOptionalFloat(value string) (float64, error) {
+	if value == "" {
+		return -999, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func addQueryParameters(u *url.URL, params map[string]string) *url.URL {
+	newURL := *u
+
+	q := u.Query()
+	for key, value := range params {
+		q.Set(key, value)
+	}
+
+	newURL.RawQuery = q.Encode()
+
+	return &newURL
+}
+
+func arcSecToDeg(value float64) float64 {
+	return value / 3600.0
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,197 @@
+package gaia
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/httpclient"
+	"github.com/stretchr/testify/require"
+)
+
+// epochPhotometry is a combined datalink response for two sources, with one row for each
+// transit and band. Transits rejected by the photometric pipeline keep their magnitude,
+// while transits without photometry in a band have empty values.
+const epochPhotometry = `solution_id,source_id,transit_id,band,time,mag,flux,flux_error,flux_over_error,rejected_by_photometry,rejected_by_variability,other_flags
+375316653866487564,4295806720,17091913127413259,G,1705.9316435940105,17.427544,2013.4567,11.2345,179.22086,false,false,4097
+375316653866487564,4295806720,17091913127413259,BP,1705.9316435940106,17.798131,1037.8812,25.441,40.79561,false,false,0
+375316653866487564,4295806720,17091913127413259,RP,1705.9316435940106,16.85863,1431.2259,19.8831,71.98203,true,false,1
+375316653866487564,4295806720,17095976337282150,G,1706.6664221540873,17.435904,1998.0132,10.8807,183.6291,false,false,4097
+375316653866487564,4295806720,17095976337282150,BP,1706.6664221540875,,,,,false,false,
+375316653866487564,4295806848,36871209785231873,G,1730.3721034581258,16.312801,5621.336,18.4409,304.82981,false,true,0
+375316653866487564,4295806848,36871209785231873,BP,1730.3721034581259,16.691868,2875.1003,,,false,true,0
+`
+
+func newArchive(t *testing.T, sourceIds string, datalink func(w http.ResponseWriter, r *http.Request)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case tapPath:
+			require.Equal(t, "doQuery", r.URL.Query().Get("REQUEST"))
+			require.Equal(t, "csv", r.URL.Query().Get("FORMAT"))
+			require.Contains(t, r.URL.Query().Get("QUERY"), "TOP 10 source_id FROM gaiadr3.gaia_source")
+			require.Contains(t, r.URL.Query().Get("QUERY"), "CIRCLE('ICRS', 10, -10, 0.001)")
+			_, err := w.Write([]byte(sourceIds))
+			require.NoError(t, err)
+		case datalinkPath:
+			require.Equal(t, "EPOCH_PHOTOMETRY", r.URL.Query().Get("RETRIEVAL_TYPE"))
+			require.Equal(t, "COMBINED", r.URL.Query().Get("DATA_STRUCTURE"))
+			require.Equal(t, "CSV", r.URL.Query().Get("FORMAT"))
+			require.Equal(t, "Gaia DR3", r.URL.Query().Get("RELEASE"))
+			datalink(w, r)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestFetchLightcurve(t *testing.T) {
+	detection := func(sourceId, transitId int64, band string, time, mag, flux, fluxError float64) Detection {
+		return Detection{SourceId: sourceId, TransitId: transitId, Band: band, Time: time, Mjd: time + gaiaTimeToMjd, Mag: mag, Flux: flux, FluxError: fluxError}
+	}
+	rejected := detection(4295806720, 17091913127413259, "RP", 1705.9316435940106, 16.85863, 1431.2259, 19.8831)
+	rejected.RejectedByPhotometry = true
+	variable := detection(4295806848, 36871209785231873, "G", 1730.3721034581258, 16.312801, 5621.336, 18.4409)
+	variable.RejectedByVariability = true
+	variableBP := detection(4295806848, 36871209785231873, "BP", 1730.3721034581259, 16.691868, 2875.1003, -999)
+	variableBP.RejectedByVariability = true
+	expected := []lightcurve.LightcurveObject{
+		detection(4295806720, 17091913127413259, "G", 1705.9316435940105, 17.427544, 2013.4567, 11.2345),
+		detection(4295806720, 17091913127413259, "BP", 1705.9316435940106, 17.798131, 1037.8812, 25.441),
+		rejected,
+		detection(4295806720, 17095976337282150, "G", 1706.6664221540873, 17.435904, 1998.0132, 10.8807),
+		variable,
+		variableBP,
+	}
+
+	t.Run("returns the epoch photometry of the sources in the cone", func(t *testing.T) {
+		server := newArchive(t, "source_id\n4295806720\n4295806848\n", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "4295806720,4295806848", r.URL.Query().Get("ID"))
+			_, err := w.Write([]byte(epochPhotometry))
+			require.NoError(t, err)
+		})
+		defer server.Close()
+
+		client, err := NewGaiaClient(WithURL(server.URL))
+		require.NoError(t, err)
+
+		result := client.FetchLightcurve(context.Background(), 10, -10, 3.6, 10)
+
+		require.NoError(t, result.Error)
+		require.Equal(t, expected, result.Lightcurve.Detections)
+	})
+
+	t.Run("reads zipped datalink responses", func(t *testing.T) {
+		server := newArchive(t, "source_id\n4295806720\n4295806848\n", func(w http.ResponseWriter, _ *http.Request) {
+			var archive bytes.Buffer
+			zipWriter := zip.NewWriter(&archive)
+			f, err := zipWriter.Create("EPOCH_PHOTOMETRY_COMBINED.csv")
+			require.NoError(t, err)
+			_, err = f.Write([]byte(epochPhotometry))
+			require.NoError(t, err)
+			require.NoError(t, zipWriter.Close())
+
+			w.Header().Set("Content-Type", "application/zip")
+			_, err = w.Write(archive.Bytes())
+			require.NoError(t, err)
+		})
+		defer server.Close()
+
+		client, err := NewGaiaClient(WithURL(server.URL + "/"))
+		require.NoError(t, err)
+
+		result := client.FetchLightcurve(context.Background(), 10, -10, 3.6, 10)
+
+		require.NoError(t, result.Error)
+		require.Equal(t, expected, result.Lightcurve.Detections)
+	})
+
+	t.Run("skips the datalink when the cone is empty", func(t *testing.T) {
+		server := newArchive(t, "source_id\n", func(w http.ResponseWriter, _ *http.Request) {
+			t.Error("datalink should not be called without sources")
+		})
+		defer server.Close()
+
+		client, err := NewGaiaClient(WithURL(server.URL))
+		require.NoError(t, err)
+
+		result := client.FetchLightcurve(context.Background(), 10, -10, 3.6, 10)
+
+		require.NoError(t, result.Error)
+		require.Empty(t, result.Lightcurve.Detections)
+	})
+
+	t.Run("retries when the datalink is unavailable", func(t *testing.T) {
+		attempts := 0
+		server := newArchive(t, "source_id\n4295806720\n4295806848\n", func(w http.ResponseWriter, _ *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, err := w.Write([]byte(epochPhotometry))
+			require.NoError(t, err)
+		})
+		defer server.Close()
+
+		httpClient, err := httpclient.New("gaia_dr3", httpclient.WithRetries(1, time.Millisecond))
+		require.NoError(t, err)
+		client, err := NewGaiaClient(WithURL(server.URL), WithHTTPClient(httpClient))
+		require.NoError(t, err)
+
+		result := client.FetchLightcurve(context.Background(), 10, -10, 3.6, 10)
+
+		require.NoError(t, result.Error)
+		require.Equal(t, 2, attempts)
+		require.Len(t, result.Lightcurve.Detections, 6)
+	})
+
+	t.Run("fails with the status of the archive", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		client, err := NewGaiaClient(WithURL(server.URL))
+		require.NoError(t, err)
+
+		result := client.FetchLightcurve(context.Background(), 10, -10, 3.6, 10)
+
+		require.EqualError(t, result.Error, "could not find Gaia sources: unexpected status code: 400")
+	})
+}
+
+func TestDetectionFromRow(t *testing.T) {
+	rows, err := parseCsv(strings.NewReader(epochPhotometry))
+	require.NoError(t, err)
+	require.Len(t, rows, 7)
+
+	detection, ok, err := detectionFromRow(rows[0])
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "4295806720", detection.GetObjectId())
+	require.Equal(t, "4295806720_17091913127413259_G", detection.GetId())
+	require.InDelta(t, 56902.9316435940105, detection.GetMjd(), 1e-9)
+	require.InDelta(t, 0.006058, detection.GetBrightnessError(), 1e-6)
+
+	_, ok, err = detectionFromRow(rows[4])
+	require.NoError(t, err)
+	require.False(t, ok, "transits without magnitude are skipped")
+
+	_, _, err = detectionFromRow(map[string]string{"source_id": "x", "mag": "1"})
+	require.ErrorContains(t, err, "could not parse source_id")
+}
+
+func TestNewGaiaClient_InvalidOptions(t *testing.T) {
+	_, err := NewGaiaClient(WithURL("not a url"))
+	require.Error(t, err)
+
+	_, err = NewGaiaClient(WithHTTPClient(nil))
+	require.Error(t, err)
+}
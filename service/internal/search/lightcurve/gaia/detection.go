@@ -0,0 +1,48 @@
+package gaia
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// gaiaTimeToMjd converts the time of Gaia epoch photometry, BJD(TCB) - 2455197.5, to MJD
+const gaiaTimeToMjd = 2455197.5 - 2400000.5
+
+// Detection is a Gaia DR3 per-transit measurement in one of the G, BP or RP bands
+type Detection struct {
+	SourceId              int64   `json:"source_id"`
+	TransitId             int64   `json:"transit_id"`
+	Band                  string  `json:"band"`
+	Time                  float64 `json:"time"`
+	Mjd                   float64 `json:"mjd"`
+	Mag                   float64 `json:"mag"`
+	Flux                  float64 `json:"flux"`
+	FluxError             float64 `json:"flux_error"`
+	RejectedByPhotometry  bool    `json:"rejected_by_photometry"`
+	RejectedByVariability bool    `json:"rejected_by_variability"`
+}
+
+func (d Detection) GetId() string {
+	return fmt.Sprintf("%d_%d_%s", d.SourceId, d.TransitId, d.Band)
+}
+
+func (d Detection) GetObjectId() string {
+	return strconv.FormatInt(d.SourceId, 10)
+}
+
+func (d Detection) GetBrightness() float64 {
+	return d.Mag
+}
+
+// GetBrightnessError propagates the flux error to the magnitude, as Gaia doesn't publish per-transit magnitude errors
+func (d Detection) GetBrightnessError() float32 {
+	if d.Flux <= 0 {
+		return -999
+	}
+	return float32(2.5 / math.Ln10 * d.FluxError / d.Flux)
+}
+
+func (d Detection) GetMjd() float64 {
+	return d.Mjd
+}
@@ -0,0 +1,55 @@
+package gaia
+
+import (
+	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+	lc "github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
+)
+
+// Filter keeps the detections of the Gaia sources found by the metadata conesearch
+func Filter(lightcurve lc.Lightcurve, objects []conesearch.MetadataResult) lc.Lightcurve {
+	newLightcurve := lc.Lightcurve{}
+	allSourceIds := make(map[string]struct{})
+
+	for _, catalog := range objects {
+		if !strings.EqualFold(catalog.Catalog, "gaia") {
+			continue
+		}
+
+		for _, object := range catalog.Data {
+			gaia, ok := object.Metadata.(repository.Gaia)
+			if !ok {
+				continue
+			}
+
+			allSourceIds[sourceIdFromDesignation(gaia.ID)] = struct{}{}
+		}
+	}
+
+	for _, detection := range lightcurve.Detections {
+		if _, ok := allSourceIds[detection.GetObjectId()]; ok {
+			newLightcurve.Detections = append(newLightcurve.Detections, detection)
+		}
+	}
+
+	for _, nonDetection := range lightcurve.NonDetections {
+		newLightcurve.NonDetections = append(newLightcurve.NonDetections, nonDetection)
+	}
+
+	for _, forcedPhotometry := range lightcurve.ForcedPhotometry {
+		newLightcurve.ForcedPhotometry = append(newLightcurve.ForcedPhotometry, forcedPhotometry)
+	}
+
+	return newLightcurve
+}
+
+// sourceIdFromDesignation returns the source id of a designation like "Gaia DR3 4295806720"
+func sourceIdFromDesignation(designation string) string {
+	fields := strings.Fields(designation)
+	if len(fields) == 0 {
+		return designation
+	}
+	return fields[len(fields)-1]
+}
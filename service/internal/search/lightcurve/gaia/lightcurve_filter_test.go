@@ -0,0 +1,41 @@
+package gaia
+
+import (
+	"testing"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+	lc "github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	t.Run("keeps detections matching gaia designations", func(t *testing.T) {
+		lightcurve := lc.Lightcurve{
+			Detections: []lc.LightcurveObject{
+				Detection{SourceId: 4295806720, TransitId: 1, Band: "G"},
+				Detection{SourceId: 38655544960, TransitId: 1, Band: "G"},
+			},
+		}
+		objects := []conesearch.MetadataResult{{Catalog: "gaia", Data: []conesearch.MetadataExtended{
+			{Metadata: repository.Gaia{ID: "Gaia DR3 4295806720"}},
+		}}}
+
+		filtered := Filter(lightcurve, objects)
+
+		require.Equal(t, []lc.LightcurveObject{Detection{SourceId: 4295806720, TransitId: 1, Band: "G"}}, filtered.Detections)
+	})
+
+	t.Run("ignores non gaia catalogs", func(t *testing.T) {
+		lightcurve := lc.Lightcurve{
+			Detections: []lc.LightcurveObject{Detection{SourceId: 1}},
+		}
+		objects := []conesearch.MetadataResult{
+			{Catalog: "allwise", Data: []conesearch.MetadataExtended{{Metadata: repository.Allwise{ID: "1", Cntr: 1}}}},
+		}
+
+		filtered := Filter(lightcurve, objects)
+
+		require.Empty(t, filtered.Detections)
+	})
+}
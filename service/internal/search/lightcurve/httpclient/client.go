@@ -0,0 +1,259 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultUserAgent is sent by the clients that don't configure a user agent
+const DefaultUserAgent = "xmatch-service"
+
+// maxBackoff caps the wait between retries
+const maxBackoff = 10 * time.Second
+
+// ErrCircuitOpen is returned, without making the request, while the circuit of a source is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// Client makes the requests of an external lightcurve source.
+//
+// It retries requests that time out or answer with a 5xx status, stops calling a source
+// that keeps failing and limits the requests in flight. The zero value is a plain client
+// without timeout, retries, circuit breaker or concurrency limit.
+type Client struct {
+	name      string
+	client    *http.Client
+	userAgent string
+	retries   int
+	backoff   time.Duration
+	breaker   *circuitBreaker
+	slots     chan struct{}
+}
+
+type Option func(*Client) error
+
+// WithTimeout limits the time each attempt of a request can take, a zero timeout disables the limit
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("%s timeout can't be negative", c.name)
+		}
+		c.client = &http.Client{Timeout: timeout}
+		return nil
+	}
+}
+
+// WithRetries retries a request that times out or answers with a 5xx status.
+// The first retry waits backoff, and each following retry waits twice the previous one.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) error {
+		if retries < 0 {
+			return fmt.Errorf("%s retries can't be negative", c.name)
+		}
+		if backoff < 0 {
+			return fmt.Errorf("%s retry backoff can't be negative", c.name)
+		}
+		c.retries = retries
+		c.backoff = backoff
+		return nil
+	}
+}
+
+// WithCircuitBreaker stops calling the source for cooldown after threshold consecutive failed requests.
+// After the cooldown a single request is let through, and its result closes or opens the circuit again.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) error {
+		if threshold <= 0 {
+			return fmt.Errorf("%s circuit breaker threshold must be positive", c.name)
+		}
+		if cooldown < 0 {
+			return fmt.Errorf("%s circuit breaker cooldown can't be negative", c.name)
+		}
+		c.breaker = &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+		return nil
+	}
+}
+
+// WithMaxConcurrency limits the requests to the source in flight at the same time
+func WithMaxConcurrency(n int) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return fmt.Errorf("%s max concurrency must be positive", c.name)
+		}
+		c.slots = make(chan struct{}, n)
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests that don't have one
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		if userAgent == "" {
+			return fmt.Errorf("%s user agent can't be empty", c.name)
+		}
+		c.userAgent = userAgent
+		return nil
+	}
+}
+
+// New creates the client of the source called name, which is used in its errors
+func New(name string, options ...Option) (*Client, error) {
+	c := &Client{
+		name:      name,
+		client:    http.DefaultClient,
+		userAgent: DefaultUserAgent,
+	}
+	for _, opt := range options {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Do sends the request, retrying it when it times out or the source answers with a 5xx status.
+//
+// The response of the last attempt is returned, even when its status is a 5xx, so the caller
+// decides how to report it. The response body must be closed to free the concurrency slot.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, fmt.Errorf("%s: %w", c.name, err)
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.getUserAgent())
+	}
+
+	ctx := req.Context()
+	// a body that can't be read again can't be retried
+	retries := c.retries
+	if req.Body != nil && req.GetBody == nil {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(req, attempt)
+		if attempt >= retries || !retryable(ctx, resp, err) {
+			c.record(ctx, resp, err)
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := wait(ctx, c.backoffFor(attempt)); err != nil {
+			c.breaker.cancel()
+			return nil, err
+		}
+	}
+}
+
+// send makes one attempt of the request, holding a concurrency slot until the response body is closed
+func (c *Client) send(req *http.Request, attempt int) (*http.Response, error) {
+	if attempt > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+
+	release, err := c.acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.slots <- struct{}{}:
+		return func() { <-c.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// record tells the circuit breaker how the source answered.
+// Requests cancelled by the caller say nothing about the source.
+func (c *Client) record(ctx context.Context, resp *http.Response, err error) {
+	switch {
+	case ctx.Err() != nil:
+		c.breaker.cancel()
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		c.breaker.failure()
+	default:
+		c.breaker.success()
+	}
+}
+
+func (c *Client) backoffFor(attempt int) time.Duration {
+	backoff := c.backoff << attempt
+	if backoff > maxBackoff || backoff < 0 {
+		return maxBackoff
+	}
+	return backoff
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.client == nil {
+		return http.DefaultClient
+	}
+	return c.client
+}
+
+func (c *Client) getUserAgent() string {
+	if c.userAgent == "" {
+		return DefaultUserAgent
+	}
+	return c.userAgent
+}
+
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+func wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
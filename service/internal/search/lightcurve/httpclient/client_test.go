@@ -0,0 +1,273 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, client *Client, ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	return client.Do(req)
+}
+
+func TestNew_InvalidOptions(t *testing.T) {
+	testCases := map[string]Option{
+		"negative timeout":       WithTimeout(-1),
+		"negative retries":       WithRetries(-1, 0),
+		"negative backoff":       WithRetries(1, -1),
+		"zero breaker threshold": WithCircuitBreaker(0, time.Second),
+		"negative cooldown":      WithCircuitBreaker(1, -1),
+		"zero concurrency":       WithMaxConcurrency(0),
+		"empty user agent":       WithUserAgent(""),
+	}
+
+	for name, opt := range testCases {
+		_, err := New("test", opt)
+		require.Error(t, err, name)
+	}
+}
+
+func TestDo_UserAgent(t *testing.T) {
+	userAgents := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		userAgents <- r.UserAgent()
+	}))
+	defer server.Close()
+
+	client, err := New("test")
+	require.NoError(t, err)
+	resp, err := get(t, client, context.Background(), server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, DefaultUserAgent, <-userAgents)
+
+	client, err = New("test", WithUserAgent("xmatch-test/1.0"))
+	require.NoError(t, err)
+	resp, err = get(t, client, context.Background(), server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "xmatch-test/1.0", <-userAgents)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("User-Agent", "caller")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "caller", <-userAgents, "the user agent of the request is kept")
+}
+
+func TestDo_RetriesServerErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client, err := New("test", WithRetries(2, time.Millisecond))
+	require.NoError(t, err)
+
+	resp, err := get(t, client, context.Background(), server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "ok", string(body))
+	require.Equal(t, int32(3), attempts.Load())
+}
+
+func TestDo_ReturnsLastResponseWhenRetriesRunOut(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, err := New("test", WithRetries(2, time.Millisecond))
+	require.NoError(t, err)
+
+	resp, err := get(t, client, context.Background(), server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	require.Equal(t, int32(3), attempts.Load())
+}
+
+func TestDo_DoesNotRetryClientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, err := New("test", WithRetries(2, time.Millisecond))
+	require.NoError(t, err)
+
+	resp, err := get(t, client, context.Background(), server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Equal(t, int32(1), attempts.Load())
+}
+
+func TestDo_RetriesTimeouts(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client, err := New("test", WithTimeout(50*time.Millisecond), WithRetries(1, time.Millisecond))
+	require.NoError(t, err)
+
+	resp, err := get(t, client, context.Background(), server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(2), attempts.Load())
+}
+
+func TestDo_StopsRetryingWhenTheContextIsDone(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := New("test", WithRetries(5, time.Second))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = get(t, client, ctx, server.URL)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, int32(1), attempts.Load())
+}
+
+func TestDo_CircuitBreaker(t *testing.T) {
+	var attempts atomic.Int32
+	var failing atomic.Bool
+	failing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New("irsa", WithCircuitBreaker(2, time.Minute))
+	require.NoError(t, err)
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+
+	for range 2 {
+		resp, err := get(t, client, context.Background(), server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	_, err = get(t, client, context.Background(), server.URL)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.EqualError(t, err, "irsa: circuit breaker is open")
+	require.Equal(t, int32(2), attempts.Load(), "an open circuit doesn't call the source")
+
+	// after the cooldown a failed probe opens the circuit again
+	now = now.Add(time.Minute)
+	resp, err := get(t, client, context.Background(), server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	_, err = get(t, client, context.Background(), server.URL)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.Equal(t, int32(3), attempts.Load())
+
+	// and a successful probe closes it
+	failing.Store(false)
+	now = now.Add(time.Minute)
+	for range 2 {
+		resp, err := get(t, client, context.Background(), server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	require.Equal(t, int32(5), attempts.Load())
+}
+
+func TestDo_CircuitBreakerIgnoresCancelledRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	client, err := New("test", WithCircuitBreaker(1, time.Minute))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = get(t, client, ctx, server.URL)
+	require.ErrorIs(t, err, context.Canceled)
+
+	resp, err := get(t, client, context.Background(), server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestDo_MaxConcurrency(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client, err := New("test", WithMaxConcurrency(2))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := get(t, client, context.Background(), server.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(2), maxInFlight.Load())
+	require.Empty(t, client.slots, "closing the bodies frees the slots")
+}
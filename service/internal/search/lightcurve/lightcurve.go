@@ -1,5 +1,16 @@
 package lightcurve
 
+import "time"
+
+// XwaveSource is the name of the x-wave lightcurve store in the source statuses
+const XwaveSource = "xwave"
+
+// Status of a lightcurve source
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
 // Lightcurve represents astronomical lightcurve data with detections, non-detections, and forced photometry
 //
 // swagger:model Lightcurve
@@ -16,3 +27,38 @@ type LightcurveObject interface {
 	GetBrightnessError() float32
 	GetMjd() float64
 }
+
+// SourceStatus tells how a lightcurve source answered a request
+type SourceStatus struct {
+	Source  string
+	Status  string
+	Latency time.Duration
+	// Error is the reason the source failed, nil when the status is ok
+	Error error
+}
+
+func newSourceStatus(source string, latency time.Duration, err error) SourceStatus {
+	status := SourceStatus{Source: source, Status: StatusOK, Latency: latency, Error: err}
+	if err != nil {
+		status.Status = StatusError
+	}
+	return status
+}
+
+// LightcurveResult is the lightcurve of the sources that answered a request,
+// along with the status of every source that was queried
+type LightcurveResult struct {
+	Lightcurve
+	Sources []SourceStatus
+}
+
+// Failed returns the statuses of the sources that could not be queried
+func (r LightcurveResult) Failed() []SourceStatus {
+	failed := make([]SourceStatus, 0)
+	for _, status := range r.Sources {
+		if status.Status != StatusOK {
+			failed = append(failed, status)
+		}
+	}
+	return failed
+}
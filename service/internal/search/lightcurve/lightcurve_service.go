@@ -14,19 +14,29 @@
 package lightcurve
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
 	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
 )
 
 type ExternalClient interface {
-	FetchLightcurve(float64, float64, float64, int) ClientResult
+	FetchLightcurve(context.Context, float64, float64, float64, int) ClientResult
 }
 
 type ConesearchService interface {
-	FindMetadataByConesearch(float64, float64, float64, int, string) ([]conesearch.MetadataResult, error)
+	FindMetadataByConesearch(context.Context, float64, float64, float64, int, string) ([]conesearch.MetadataResult, error)
+}
+
+// Repository reads the detections of the x-wave lightcurve store
+type Repository interface {
+	BulkGetXwaveDetections(context.Context, []string) ([]repository.XwaveDetection, error)
 }
 
 type LightcurveFilter func(Lightcurve, []conesearch.MetadataResult) Lightcurve
@@ -40,6 +50,8 @@ type ClientResult struct {
 	Catalog    string
 	Filter     LightcurveFilter
 	Error      error
+	// Latency is the time the client took to answer
+	Latency time.Duration
 }
 
 type Source struct {
@@ -51,15 +63,20 @@ type Source struct {
 type LightcurveService struct {
 	sources           []Source
 	conesearchService ConesearchService
+	repository        Repository
 }
 
 func New(
 	sources []Source,
 	conesearchService ConesearchService,
+	repository Repository,
 ) (*LightcurveService, error) {
 	if conesearchService == nil {
 		return nil, fmt.Errorf("conesearchService was nil while creating LightcurveService")
 	}
+	if repository == nil {
+		return nil, fmt.Errorf("repository was nil while creating LightcurveService")
+	}
 	if len(sources) == 0 {
 		return nil, fmt.Errorf("sources was empty while creating LightcurveService")
 	}
@@ -71,7 +88,7 @@ func New(
 			sources[i].Filter = DummyLightcurveFilter
 		}
 	}
-	return &LightcurveService{sources, conesearchService}, nil
+	return &LightcurveService{sources, conesearchService, repository}, nil
 }
 
 // GetLightcurve retrieves lightcurve data by querying multiple external clients concurrently.
@@ -90,55 +107,71 @@ func New(
 //
 //	Merge lightcurves from xwave and external clients
 //
+// A source that fails doesn't fail the request. The lightcurve holds the data of the
+// sources that answered, and the result tells the status of every source.
+//
 // # Parameters:
+//   - ctx: Context of the request, cancelling it stops the external clients and the conesearch
 //   - ra: Right ascension coordinate in degrees
 //   - dec: Declination coordinate in degrees
 //   - radius: Search radius in degrees
 //   - nobjects: Maximum number of objects to retrieve
 //
 // Returns:
-//   - Lightcurve: Combined lightcurve data from all external clients
-//   - error: Any error encountered during the fetch operation
-func (service *LightcurveService) GetLightcurve(ra, dec, radius float64, nobjects int, catalog string) (Lightcurve, error) {
+//   - LightcurveResult: Combined lightcurve data from the sources that answered, and the status of each source
+//   - error: Any error of the conesearch, or the error of the context when it is done
+func (service *LightcurveService) GetLightcurve(ctx context.Context, ra, dec, radius float64, nobjects int, catalog string) (LightcurveResult, error) {
 	selectedSources, err := service.selectSources(catalog)
 	if err != nil {
-		return Lightcurve{}, err
+		return LightcurveResult{}, err
 	}
 
 	// Step 1: Fetch external clients and conesearch data concurrently
 	clientData := make(chan ClientResult, len(selectedSources))
-	service.fetchClientData(clientData, selectedSources, ra, dec, radius, nobjects)
+	service.fetchClientData(ctx, clientData, selectedSources, ra, dec, radius, nobjects)
 	metadataResult := make(chan []conesearch.MetadataResult, 1)
 	errors := make(chan error, 1)
-	service.fetchConesearchData(metadataResult, errors, ra, dec, radius, nobjects, metadataCatalog(catalog))
+	service.fetchConesearchData(ctx, metadataResult, errors, ra, dec, radius, nobjects, metadataCatalog(catalog))
 
 	// Wait for all data to be fetched
-	clientResults, err := service.collectClientResults(clientData)
-	mergedClientResult := service.mergeCollectedClientResults(clientResults)
-	if err != nil {
-		return mergedClientResult, err
+	allClientResults := service.collectClientResults(clientData)
+	if err := ctx.Err(); err != nil {
+		return LightcurveResult{}, err
 	}
+	statuses := sourceStatuses(allClientResults)
+	clientResults := successfulClientResults(allClientResults)
+	mergedClientResult := service.mergeCollectedClientResults(clientResults)
 	objectIds, objects, err := service.extractObjectIds(metadataResult, errors)
 	if err != nil {
-		return Lightcurve{}, err
+		return LightcurveResult{}, err
 	}
 	if len(objectIds) == 0 {
-		return mergedClientResult, nil
+		return LightcurveResult{Lightcurve: mergedClientResult, Sources: statuses}, nil
 	}
 
 	// Step 2: Fetch Xwave data and apply filters concurrently
 	xwaveLightcurve := make(chan Lightcurve, 1)
 	xwaveError := make(chan error, 1)
-	service.getXwaveLightcurve(objectIds, xwaveLightcurve, xwaveError)
+	start := time.Now()
+	service.getXwaveLightcurve(ctx, objectIds, xwaveLightcurve, xwaveError)
 	filteredOutput := make(chan Lightcurve, 1)
 	service.filterLightcurve(filteredOutput, objects, clientResults)
 
 	// Wait for data to be fetched and filtered. Then merge the results.
 	select {
 	case xWaveLightcurve := <-xwaveLightcurve:
-		return service.mergeLightcurves([]Lightcurve{xWaveLightcurve, <-filteredOutput}), nil
+		statuses = append(statuses, newSourceStatus(XwaveSource, time.Since(start), nil))
+		return LightcurveResult{
+			Lightcurve: service.mergeLightcurves([]Lightcurve{xWaveLightcurve, <-filteredOutput}),
+			Sources:    statuses,
+		}, nil
 	case err := <-xwaveError:
-		return Lightcurve{}, fmt.Errorf("could not get x-wave lightcurve: %w", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return LightcurveResult{}, ctxErr
+		}
+		err = fmt.Errorf("could not get x-wave lightcurve: %w", err)
+		statuses = append(statuses, newSourceStatus(XwaveSource, time.Since(start), err))
+		return LightcurveResult{Lightcurve: <-filteredOutput, Sources: statuses}, nil
 	}
 }
 
@@ -147,19 +180,22 @@ func (service *LightcurveService) GetLightcurve(ra, dec, radius float64, nobject
 // The channel is closed once all goroutines have completed.
 //
 // Parameters:
+//   - ctx: Context of the request
 //   - output: Channel to send ClientResult data to
 //   - ra: Right ascension coordinate in degrees
 //   - dec: Declination coordinate in degrees
 //   - radius: Search radius in degrees
 //   - nobjects: Maximum number of objects to retrieve
-func (service *LightcurveService) fetchClientData(output chan<- ClientResult, sources []Source, ra, dec, radius float64, nobjects int) {
+func (service *LightcurveService) fetchClientData(ctx context.Context, output chan<- ClientResult, sources []Source, ra, dec, radius float64, nobjects int) {
 	var wg sync.WaitGroup
 
 	for _, source := range sources {
 		wg.Add(1)
 		go func(source Source) {
 			defer wg.Done()
-			result := source.Client.FetchLightcurve(ra, dec, radius, nobjects)
+			start := time.Now()
+			result := source.Client.FetchLightcurve(ctx, ra, dec, radius, nobjects)
+			result.Latency = time.Since(start)
 			result.Catalog = source.Catalog
 			result.Filter = source.Filter
 			output <- result
@@ -177,6 +213,7 @@ func (service *LightcurveService) fetchClientData(output chan<- ClientResult, so
 // If an error occurs, it sends the error through the errors channel.
 //
 // Parameters:
+//   - ctx: Context of the request
 //   - output: Channel to send metadata results to
 //   - errors: Channel to send errors to
 //   - ra: Right ascension coordinate in degrees
@@ -184,6 +221,7 @@ func (service *LightcurveService) fetchClientData(output chan<- ClientResult, so
 //   - radius: Search radius in degrees
 //   - nobjects: Maximum number of objects to retrieve
 func (service *LightcurveService) fetchConesearchData(
+	ctx context.Context,
 	output chan<- []conesearch.MetadataResult,
 	errors chan<- error,
 	ra, dec, radius float64,
@@ -193,7 +231,7 @@ func (service *LightcurveService) fetchConesearchData(
 	go func() {
 		defer close(output)
 
-		result, err := service.getObjects(ra, dec, radius, nobjects, catalog)
+		result, err := service.getObjects(ctx, ra, dec, radius, nobjects, catalog)
 		if err != nil {
 			errors <- err
 			return
@@ -238,6 +276,7 @@ func (service *LightcurveService) extractObjectIds(
 // It queries the conesearch service with the specified parameters and extracts object IDs from the results.
 //
 // Parameters:
+//   - ctx: Context of the request
 //   - ra: Right ascension coordinate in degrees
 //   - dec: Declination coordinate in degrees
 //   - radius: Search radius in degrees
@@ -246,25 +285,35 @@ func (service *LightcurveService) extractObjectIds(
 // Returns:
 //   - []MetadataResult: Slice of objects indexed by catalog found in the search area
 //   - error: Any error encountered during the conesearch operation
-func (service *LightcurveService) getObjects(ra, dec, radius float64, neighbors int, catalog string) ([]conesearch.MetadataResult, error) {
-	objects, err := service.conesearchService.FindMetadataByConesearch(ra, dec, radius, neighbors, catalog)
+func (service *LightcurveService) getObjects(ctx context.Context, ra, dec, radius float64, neighbors int, catalog string) ([]conesearch.MetadataResult, error) {
+	objects, err := service.conesearchService.FindMetadataByConesearch(ctx, ra, dec, radius, neighbors, catalog)
 	if err != nil {
 		return nil, fmt.Errorf("could not execute conesearch: %w", err)
 	}
 	return objects, nil
 }
 
-// getXwaveLightcurve retrieves lightcurve data from the Xwave service for the given object IDs.
-// Currently returns an empty lightcurve as a placeholder implementation.
+// getXwaveLightcurve retrieves the detections of the x-wave lightcurve store for the given object IDs.
+// The store is filled by the indexer with the photometry of objects already in the mastercat.
 //
 // Parameters:
+//   - ctx: Context of the request
 //   - ids: Slice of object IDs to fetch lightcurve data for
 //   - result: Channel to send the result to
-//   - err: Channel to send errors to
-func (service *LightcurveService) getXwaveLightcurve(_ []string, result chan<- Lightcurve, _ chan error) {
+//   - errors: Channel to send errors to
+func (service *LightcurveService) getXwaveLightcurve(ctx context.Context, ids []string, result chan<- Lightcurve, errors chan<- error) {
 	go func() {
-		defer close(result)
-		result <- Lightcurve{}
+		detections, err := service.repository.BulkGetXwaveDetections(ctx, ids)
+		if err != nil {
+			errors <- err
+			return
+		}
+
+		lightcurve := Lightcurve{}
+		for i := range detections {
+			lightcurve.Detections = append(lightcurve.Detections, NewXwaveDetection(detections[i]))
+		}
+		result <- lightcurve
 	}()
 }
 
@@ -294,22 +343,67 @@ func (service *LightcurveService) filterLightcurve(output chan<- Lightcurve, obj
 	}()
 }
 
-func (service *LightcurveService) collectClientResults(results <-chan ClientResult) ([]ClientResult, error) {
+// collectClientResults waits for the results of every client, including the ones that failed
+func (service *LightcurveService) collectClientResults(results <-chan ClientResult) []ClientResult {
 	clientResults := make([]ClientResult, 0)
-
 	for result := range results {
-		if result.Error != nil {
-			return clientResults, result.Error
+		clientResults = append(clientResults, result)
+	}
+	return clientResults
+}
+
+// successfulClientResults returns the results of the clients that answered without errors
+func successfulClientResults(results []ClientResult) []ClientResult {
+	successful := make([]ClientResult, 0, len(results))
+	for _, result := range results {
+		if result.Error == nil {
+			successful = append(successful, result)
 		}
+	}
+	return successful
+}
 
-		clientResults = append(clientResults, result)
+// sourceStatuses returns the status of the source of each client result
+func sourceStatuses(results []ClientResult) []SourceStatus {
+	statuses := make([]SourceStatus, len(results))
+	for i, result := range results {
+		statuses[i] = newSourceStatus(result.Catalog, result.Latency, result.Error)
 	}
+	return statuses
+}
 
-	return clientResults, nil
+// Catalogs returns the catalogs a lightcurve can be requested for: all, the catalog of each source,
+// and the registered catalogs whose lightcurve comes from one of the sources, like allwise for neowise
+func (service *LightcurveService) Catalogs() []string {
+	catalogs := []string{"all"}
+	for _, source := range service.sources {
+		if !slices.Contains(catalogs, source.Catalog) {
+			catalogs = append(catalogs, source.Catalog)
+		}
+	}
+	for _, desc := range catalog.All() {
+		if desc.LightcurveSource != "" && slices.Contains(catalogs, desc.LightcurveSource) && !slices.Contains(catalogs, desc.Name) {
+			catalogs = append(catalogs, desc.Name)
+		}
+	}
+	return catalogs
+}
+
+// sourceCatalog returns the catalog of the sources serving the lightcurve of a catalog. Registered
+// catalogs are served by their lightcurve source, unless a source has the name of the catalog.
+func (service *LightcurveService) sourceCatalog(catalogName string) string {
+	normalizedCatalog := strings.ToLower(catalogName)
+	if slices.ContainsFunc(service.sources, func(source Source) bool { return source.Catalog == normalizedCatalog }) {
+		return normalizedCatalog
+	}
+	if desc, ok := catalog.Lookup(normalizedCatalog); ok && desc.LightcurveSource != "" {
+		return desc.LightcurveSource
+	}
+	return normalizedCatalog
 }
 
 func (service *LightcurveService) selectSources(catalog string) ([]Source, error) {
-	normalizedCatalog := normalizeCatalog(catalog)
+	normalizedCatalog := service.sourceCatalog(catalog)
 	if normalizedCatalog == "all" {
 		return service.sources, nil
 	}
@@ -327,25 +421,22 @@ func (service *LightcurveService) selectSources(catalog string) ([]Source, error
 	return selectedSources, nil
 }
 
-func metadataCatalog(catalog string) string {
-	normalizedCatalog := normalizeCatalog(catalog)
-	if normalizedCatalog == "neowise" {
-		return "allwise"
+// metadataCatalog returns the catalog whose objects are searched for the lightcurve of a catalog:
+// the registered catalog of a source, like allwise for neowise, or the catalog itself when it has
+// metadata. Every catalog is searched for the other sources, like ztf or a configured mirror.
+func metadataCatalog(catalogName string) string {
+	normalizedCatalog := strings.ToLower(catalogName)
+	if desc, ok := catalog.LookupLightcurveSource(normalizedCatalog); ok && desc.HasMetadata() {
+		return desc.Name
 	}
-	return normalizedCatalog
-}
-
-func normalizeCatalog(catalog string) string {
-	normalizedCatalog := strings.ToLower(catalog)
-	if normalizedCatalog == "allwise" {
-		return "neowise"
+	if desc, ok := catalog.Lookup(normalizedCatalog); ok && desc.HasMetadata() {
+		return desc.Name
 	}
-	return normalizedCatalog
+	return "all"
 }
 
 // mergeClientResults merges lightcurve data from multiple external client results received through a channel.
 // It aggregates detections, non-detections, and forced photometry from all successful client responses.
-// Returns early with an error if any client result contains an error.
 //
 // Parameters:
 //   - results: Channel of ClientResult containing lightcurve data from external clients
@@ -354,12 +445,15 @@ func normalizeCatalog(catalog string) string {
 //   - Lightcurve: Merged lightcurve data from all successful clients
 //   - error: First error encountered from any client, if any
 func (service *LightcurveService) mergeClientResults(results <-chan ClientResult) (Lightcurve, error) {
-	clientResults, err := service.collectClientResults(results)
-	if err != nil {
-		return service.mergeCollectedClientResults(clientResults), err
-	}
+	clientResults := service.collectClientResults(results)
+	merged := service.mergeCollectedClientResults(successfulClientResults(clientResults))
 
-	return service.mergeCollectedClientResults(clientResults), nil
+	for _, result := range clientResults {
+		if result.Error != nil {
+			return merged, result.Error
+		}
+	}
+	return merged, nil
 }
 
 func (service *LightcurveService) mergeCollectedClientResults(results []ClientResult) Lightcurve {
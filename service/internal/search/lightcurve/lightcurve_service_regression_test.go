@@ -1,10 +1,14 @@
 package lightcurve_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
 	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
 	lc "github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/gaia"
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/ztfdr"
 	"github.com/stretchr/testify/require"
 )
@@ -14,7 +18,7 @@ type stubExternalClient struct {
 	called *int
 }
 
-func (c stubExternalClient) FetchLightcurve(float64, float64, float64, int) lc.ClientResult {
+func (c stubExternalClient) FetchLightcurve(context.Context, float64, float64, float64, int) lc.ClientResult {
 	if c.called != nil {
 		(*c.called)++
 	}
@@ -26,13 +30,24 @@ type stubConesearchService struct {
 	catalogTarget *string
 }
 
-func (s *stubConesearchService) FindMetadataByConesearch(_, _, _ float64, _ int, catalog string) ([]conesearch.MetadataResult, error) {
+func (s *stubConesearchService) FindMetadataByConesearch(_ context.Context, _, _, _ float64, _ int, catalog string) ([]conesearch.MetadataResult, error) {
 	if s.catalogTarget != nil {
 		*s.catalogTarget = catalog
 	}
 	return s.results, nil
 }
 
+type stubRepository struct {
+	detections []repository.XwaveDetection
+	ids        []string
+	err        error
+}
+
+func (r *stubRepository) BulkGetXwaveDetections(_ context.Context, ids []string) ([]repository.XwaveDetection, error) {
+	r.ids = ids
+	return r.detections, r.err
+}
+
 type metadataStub struct {
 	id      string
 	catalog string
@@ -60,10 +75,11 @@ func TestGetLightcurve_AppliesZtfDrFilter(t *testing.T) {
 			Catalog: "ztf",
 			Data:    []conesearch.MetadataExtended{{Metadata: metadataStub{id: "1", catalog: "ztf"}}},
 		}}},
+		&stubRepository{},
 	)
 	require.NoError(t, err)
 
-	result, err := service.GetLightcurve(10, -10, 0.2, 10, "ztf")
+	result, err := service.GetLightcurve(context.Background(), 10, -10, 0.2, 10, "ztf")
 	require.NoError(t, err)
 	require.Len(t, result.Detections, 1)
 	require.Equal(t, "1", result.Detections[0].GetObjectId())
@@ -79,10 +95,11 @@ func TestGetLightcurve_ReturnsClientDataWhenConesearchHasNoMatches(t *testing.T)
 			Filter: ztfdr.Filter,
 		}},
 		&stubConesearchService{},
+		&stubRepository{},
 	)
 	require.NoError(t, err)
 
-	result, err := service.GetLightcurve(10, -10, 0.2, 10, "ztf")
+	result, err := service.GetLightcurve(context.Background(), 10, -10, 0.2, 10, "ztf")
 	require.NoError(t, err)
 	require.Len(t, result.Detections, 1)
 	require.Equal(t, "1", result.Detections[0].GetObjectId())
@@ -116,15 +133,16 @@ func TestGetLightcurve_ExecutesOnlySelectedCatalogClient(t *testing.T) {
 				Data:    []conesearch.MetadataExtended{{Metadata: metadataStub{id: "1", catalog: "ztf"}}},
 			}},
 		},
+		&stubRepository{},
 	)
 	require.NoError(t, err)
 
-	result, err := service.GetLightcurve(10, -10, 0.2, 10, "ztf")
+	result, err := service.GetLightcurve(context.Background(), 10, -10, 0.2, 10, "ztf")
 	require.NoError(t, err)
 	require.Len(t, result.Detections, 1)
 	require.Equal(t, 1, ztfCalls)
 	require.Equal(t, 0, neowiseCalls)
-	require.Equal(t, "ztf", conesearchCatalog)
+	require.Equal(t, "all", conesearchCatalog, "ztf has no metadata, so every catalog is searched")
 }
 
 func TestGetLightcurve_AllwiseAliasUsesNeowiseSource(t *testing.T) {
@@ -149,16 +167,33 @@ func TestGetLightcurve_AllwiseAliasUsesNeowiseSource(t *testing.T) {
 			},
 		},
 		&stubConesearchService{catalogTarget: &conesearchCatalog},
+		&stubRepository{},
 	)
 	require.NoError(t, err)
 
-	_, err = service.GetLightcurve(10, -10, 0.2, 10, "allwise")
+	_, err = service.GetLightcurve(context.Background(), 10, -10, 0.2, 10, "allwise")
 	require.NoError(t, err)
 	require.Equal(t, 0, ztfCalls)
 	require.Equal(t, 1, neowiseCalls)
 	require.Equal(t, "allwise", conesearchCatalog)
 }
 
+func TestCatalogs_DerivedFromSourcesAndRegistry(t *testing.T) {
+	service, err := lc.New(
+		[]lc.Source{
+			{Catalog: "ztf", Client: stubExternalClient{}, Filter: lc.DummyLightcurveFilter},
+			{Catalog: "ztf-mirror", Client: stubExternalClient{}, Filter: lc.DummyLightcurveFilter},
+			{Catalog: "neowise", Client: stubExternalClient{}, Filter: lc.DummyLightcurveFilter},
+		},
+		&stubConesearchService{},
+		&stubRepository{},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"all", "ztf", "ztf-mirror", "neowise", "allwise"}, service.Catalogs())
+	require.NotContains(t, service.Catalogs(), "gaia", "gaia has no configured source")
+}
+
 func TestGetLightcurve_AllCatalogDoesNotDuplicateFilteredSource(t *testing.T) {
 	service, err := lc.New(
 		[]lc.Source{
@@ -179,11 +214,140 @@ func TestGetLightcurve_AllCatalogDoesNotDuplicateFilteredSource(t *testing.T) {
 			Catalog: "ztf",
 			Data:    []conesearch.MetadataExtended{{Metadata: metadataStub{id: "1", catalog: "ztf"}}},
 		}}},
+		&stubRepository{},
 	)
 	require.NoError(t, err)
 
-	result, err := service.GetLightcurve(10, -10, 0.2, 10, "all")
+	result, err := service.GetLightcurve(context.Background(), 10, -10, 0.2, 10, "all")
 	require.NoError(t, err)
 	require.Len(t, result.Detections, 1)
 	require.Equal(t, "1", result.Detections[0].GetObjectId())
 }
+
+func TestGetLightcurve_MergesXwaveDetectionsOfConesearchObjects(t *testing.T) {
+	repo := &stubRepository{detections: []repository.XwaveDetection{
+		{ObjectID: "1", Mjd: 60000, Band: "g", Mag: 15, MagErr: 0.1},
+	}}
+	service, err := lc.New(
+		[]lc.Source{{
+			Catalog: "ztf",
+			Client: stubExternalClient{result: lc.ClientResult{Lightcurve: lc.Lightcurve{Detections: []lc.LightcurveObject{
+				ztfdr.Detection{Oid: 1, Hmjd: 1},
+			}}}},
+			Filter: ztfdr.Filter,
+		}},
+		&stubConesearchService{results: []conesearch.MetadataResult{{
+			Catalog: "ztf",
+			Data:    []conesearch.MetadataExtended{{Metadata: metadataStub{id: "1", catalog: "ztf"}}},
+		}}},
+		repo,
+	)
+	require.NoError(t, err)
+
+	result, err := service.GetLightcurve(context.Background(), 10, -10, 0.2, 10, "ztf")
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, repo.ids)
+	require.Len(t, result.Detections, 2)
+	require.Equal(t, lc.XwaveDetection{ObjectId: "1", Mjd: 60000, Band: "g", Mag: 15, MagErr: 0.1}, result.Detections[0])
+	require.Equal(t, ztfdr.Detection{Oid: 1, Hmjd: 1}, result.Detections[1])
+}
+
+func TestGetLightcurve_ReturnsPartialResultsWhenASourceFails(t *testing.T) {
+	service, err := lc.New(
+		[]lc.Source{
+			{
+				Catalog: "ztf",
+				Client: stubExternalClient{result: lc.ClientResult{Lightcurve: lc.Lightcurve{Detections: []lc.LightcurveObject{
+					ztfdr.Detection{Oid: 1, Hmjd: 1},
+				}}}},
+				Filter: ztfdr.Filter,
+			},
+			{
+				Catalog: "neowise",
+				Client:  stubExternalClient{result: lc.ClientResult{Error: errors.New("irsa is down")}},
+				Filter:  lc.DummyLightcurveFilter,
+			},
+		},
+		&stubConesearchService{results: []conesearch.MetadataResult{{
+			Catalog: "ztf",
+			Data:    []conesearch.MetadataExtended{{Metadata: metadataStub{id: "1", catalog: "ztf"}}},
+		}}},
+		&stubRepository{},
+	)
+	require.NoError(t, err)
+
+	result, err := service.GetLightcurve(context.Background(), 10, -10, 0.2, 10, "all")
+	require.NoError(t, err)
+	require.Len(t, result.Detections, 1)
+	require.Equal(t, ztfdr.Detection{Oid: 1, Hmjd: 1}, result.Detections[0])
+
+	statuses := map[string]lc.SourceStatus{}
+	for _, status := range result.Sources {
+		statuses[status.Source] = status
+	}
+	require.Len(t, statuses, 3)
+	require.Equal(t, lc.StatusOK, statuses["ztf"].Status)
+	require.NoError(t, statuses["ztf"].Error)
+	require.Equal(t, lc.StatusError, statuses["neowise"].Status)
+	require.EqualError(t, statuses["neowise"].Error, "irsa is down")
+	require.Equal(t, lc.StatusOK, statuses[lc.XwaveSource].Status)
+
+	failed := result.Failed()
+	require.Len(t, failed, 1)
+	require.Equal(t, "neowise", failed[0].Source)
+}
+
+func TestGetLightcurve_ReturnsClientDataWhenXwaveFails(t *testing.T) {
+	service, err := lc.New(
+		[]lc.Source{{
+			Catalog: "ztf",
+			Client: stubExternalClient{result: lc.ClientResult{Lightcurve: lc.Lightcurve{Detections: []lc.LightcurveObject{
+				ztfdr.Detection{Oid: 1, Hmjd: 1},
+			}}}},
+			Filter: ztfdr.Filter,
+		}},
+		&stubConesearchService{results: []conesearch.MetadataResult{{
+			Catalog: "ztf",
+			Data:    []conesearch.MetadataExtended{{Metadata: metadataStub{id: "1", catalog: "ztf"}}},
+		}}},
+		&stubRepository{err: errors.New("database is locked")},
+	)
+	require.NoError(t, err)
+
+	result, err := service.GetLightcurve(context.Background(), 10, -10, 0.2, 10, "ztf")
+	require.NoError(t, err)
+	require.Equal(t, []lc.LightcurveObject{ztfdr.Detection{Oid: 1, Hmjd: 1}}, result.Detections)
+
+	failed := result.Failed()
+	require.Len(t, failed, 1)
+	require.Equal(t, lc.XwaveSource, failed[0].Source)
+	require.ErrorContains(t, failed[0].Error, "database is locked")
+}
+
+func TestGetLightcurve_AppliesGaiaFilter(t *testing.T) {
+	conesearchCatalog := ""
+	service, err := lc.New(
+		[]lc.Source{{
+			Catalog: "gaia",
+			Client: stubExternalClient{result: lc.ClientResult{Lightcurve: lc.Lightcurve{Detections: []lc.LightcurveObject{
+				gaia.Detection{SourceId: 1, TransitId: 10, Band: "G"},
+				gaia.Detection{SourceId: 2, TransitId: 10, Band: "G"},
+			}}}},
+			Filter: gaia.Filter,
+		}},
+		&stubConesearchService{
+			catalogTarget: &conesearchCatalog,
+			results: []conesearch.MetadataResult{{
+				Catalog: "gaia",
+				Data:    []conesearch.MetadataExtended{{Metadata: repository.Gaia{ID: "Gaia DR3 1"}}},
+			}},
+		},
+		&stubRepository{},
+	)
+	require.NoError(t, err)
+
+	result, err := service.GetLightcurve(context.Background(), 10, -10, 0.2, 10, "gaia")
+	require.NoError(t, err)
+	require.Equal(t, "gaia", conesearchCatalog)
+	require.Equal(t, []lc.LightcurveObject{gaia.Detection{SourceId: 1, TransitId: 10, Band: "G"}}, result.Detections)
+}
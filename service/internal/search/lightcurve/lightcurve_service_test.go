@@ -14,6 +14,7 @@
 package lightcurve
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -36,6 +37,15 @@ type testMetadata struct {
 	catalog string
 }
 
+type testRepository struct {
+	detections []repository.XwaveDetection
+	err        error
+}
+
+func (r *testRepository) BulkGetXwaveDetections(context.Context, []string) ([]repository.XwaveDetection, error) {
+	return r.detections, r.err
+}
+
 func (m testMetadata) GetId() string {
 	return m.id
 }
@@ -75,6 +85,7 @@ func testSource(client ExternalClient) Source {
 func TestGetObjectIds_Empty(t *testing.T) {
 	mockService := NewMockConesearchService(t)
 	mockService.EXPECT().FindMetadataByConesearch(
+		mock.Anything,
 		mock.AnythingOfType("float64"),
 		mock.AnythingOfType("float64"),
 		mock.AnythingOfType("float64"),
@@ -82,10 +93,10 @@ func TestGetObjectIds_Empty(t *testing.T) {
 		"ztf",
 	).Return([]conesearch.MetadataResult{}, nil)
 
-	lightcurveService, err := New([]Source{testSource(NewMockExternalClient(t))}, mockService)
+	lightcurveService, err := New([]Source{testSource(NewMockExternalClient(t))}, mockService, &testRepository{})
 	require.NoError(t, err)
 
-	objs, err := lightcurveService.getObjects(0, 0, 0, 1, "ztf")
+	objs, err := lightcurveService.getObjects(context.Background(), 0, 0, 0, 1, "ztf")
 	require.NoError(t, err)
 
 	require.Equal(t, []conesearch.MetadataResult{}, objs)
@@ -94,6 +105,7 @@ func TestGetObjectIds_Empty(t *testing.T) {
 func TestGetObjectIds_NonEmpty(t *testing.T) {
 	mockService := NewMockConesearchService(t)
 	mockService.EXPECT().FindMetadataByConesearch(
+		mock.Anything,
 		mock.AnythingOfType("float64"),
 		mock.AnythingOfType("float64"),
 		mock.AnythingOfType("float64"),
@@ -110,10 +122,10 @@ func TestGetObjectIds_NonEmpty(t *testing.T) {
 		},
 	}, nil)
 
-	lightcurveService, err := New([]Source{testSource(NewMockExternalClient(t))}, mockService)
+	lightcurveService, err := New([]Source{testSource(NewMockExternalClient(t))}, mockService, &testRepository{})
 	require.NoError(t, err)
 
-	objs, err := lightcurveService.getObjects(0, 0, 0, 1, "allwise")
+	objs, err := lightcurveService.getObjects(context.Background(), 0, 0, 0, 1, "allwise")
 	require.NoError(t, err)
 
 	ids := make([]string, 0)
@@ -126,6 +138,31 @@ func TestGetObjectIds_NonEmpty(t *testing.T) {
 	require.Equal(t, []string{"ALLWISE1", "GAIA1"}, ids)
 }
 
+func TestGetObjectIds_Erosita(t *testing.T) {
+	mockService := NewMockConesearchService(t)
+	mockService.EXPECT().FindMetadataByConesearch(
+		mock.Anything,
+		mock.AnythingOfType("float64"),
+		mock.AnythingOfType("float64"),
+		mock.AnythingOfType("float64"),
+		1,
+		"all",
+	).Return([]conesearch.MetadataResult{
+		{
+			Catalog: "eROSITA",
+			Data:    []conesearch.MetadataExtended{{Metadata: repository.Erosita{ID: "EROSITA1"}, Distance: 0.5}},
+		},
+	}, nil)
+
+	lightcurveService, err := New([]Source{testSource(NewMockExternalClient(t))}, mockService, &testRepository{})
+	require.NoError(t, err)
+
+	objs, err := lightcurveService.getObjects(context.Background(), 0, 0, 0, 1, metadataCatalog("all"))
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	require.Equal(t, "EROSITA1", objs[0].Data[0].GetId())
+}
+
 func TestMergeClientResults_NoError(t *testing.T) {
 	clientResult1 := ClientResult{
 		Lightcurve: Lightcurve{
@@ -145,7 +182,7 @@ func TestMergeClientResults_NoError(t *testing.T) {
 	results <- clientResult2
 	close(results)
 
-	lightcurveService, err := New([]Source{testSource(NewMockExternalClient(t))}, NewMockConesearchService(t))
+	lightcurveService, err := New([]Source{testSource(NewMockExternalClient(t))}, NewMockConesearchService(t), &testRepository{})
 	require.NoError(t, err)
 
 	lightcurve, err := lightcurveService.mergeClientResults(results)
@@ -179,7 +216,7 @@ func TestMergeClientResults_WithError(t *testing.T) {
 	results <- clientResult3
 	close(results)
 
-	lightcurveService, err := New([]Source{testSource(NewMockExternalClient(t))}, NewMockConesearchService(t))
+	lightcurveService, err := New([]Source{testSource(NewMockExternalClient(t))}, NewMockConesearchService(t), &testRepository{})
 	require.NoError(t, err)
 
 	lightcurve, err := lightcurveService.mergeClientResults(results)
@@ -191,7 +228,7 @@ func TestMergeClientResults_WithError(t *testing.T) {
 }
 
 func TestExtractObjectIds_PreservesCatalog(t *testing.T) {
-	lightcurveService, err := New([]Source{testSource(NewMockExternalClient(t))}, NewMockConesearchService(t))
+	lightcurveService, err := New([]Source{testSource(NewMockExternalClient(t))}, NewMockConesearchService(t), &testRepository{})
 	require.NoError(t, err)
 
 	metadataResult := make(chan []conesearch.MetadataResult, 1)
@@ -223,7 +260,7 @@ func TestMergeLightcurves(t *testing.T) {
 		Detections: []LightcurveObject{TestDetection{"2", "GAIA1", 1, 1, 1}},
 	}
 
-	service, err := New([]Source{testSource(NewMockExternalClient(t))}, NewMockConesearchService(t))
+	service, err := New([]Source{testSource(NewMockExternalClient(t))}, NewMockConesearchService(t), &testRepository{})
 	require.NoError(t, err)
 
 	result := service.mergeLightcurves([]Lightcurve{lightcurve1, lightcurve2})
@@ -233,3 +270,50 @@ func TestMergeLightcurves(t *testing.T) {
 		TestDetection{"2", "GAIA1", 1, 1, 1},
 	}, result.Detections)
 }
+
+func TestGetXwaveLightcurve(t *testing.T) {
+	repo := &testRepository{detections: []repository.XwaveDetection{
+		{ObjectID: "ALLWISE1", Mjd: 60000.5, Band: "g", Mag: 15.2, MagErr: 0.1},
+		{ObjectID: "ALLWISE1", Mjd: 60001.5, Band: "r", Mag: 14.8, MagErr: 0.2},
+	}}
+	service, err := New([]Source{testSource(NewMockExternalClient(t))}, NewMockConesearchService(t), repo)
+	require.NoError(t, err)
+
+	result := make(chan Lightcurve, 1)
+	errors := make(chan error, 1)
+	service.getXwaveLightcurve(context.Background(), []string{"ALLWISE1"}, result, errors)
+
+	lightcurve := <-result
+	require.Len(t, lightcurve.Detections, 2)
+	require.Equal(t, "ALLWISE1_g_60000.5", lightcurve.Detections[0].GetId())
+	require.Equal(t, "ALLWISE1", lightcurve.Detections[1].GetObjectId())
+	require.Equal(t, 14.8, lightcurve.Detections[1].GetBrightness())
+	require.Equal(t, float32(0.2), lightcurve.Detections[1].GetBrightnessError())
+}
+
+func TestGetXwaveLightcurve_Error(t *testing.T) {
+	repo := &testRepository{err: fmt.Errorf("database is locked")}
+	service, err := New([]Source{testSource(NewMockExternalClient(t))}, NewMockConesearchService(t), repo)
+	require.NoError(t, err)
+
+	result := make(chan Lightcurve, 1)
+	errors := make(chan error, 1)
+	service.getXwaveLightcurve(context.Background(), []string{"ALLWISE1"}, result, errors)
+
+	require.EqualError(t, <-errors, "database is locked")
+}
+
+func TestMetadataCatalog(t *testing.T) {
+	testCases := map[string]string{
+		"all":        "all",
+		"neowise":    "allwise",
+		"AllWISE":    "allwise",
+		"gaia":       "gaia",
+		"ztf":        "all",
+		"ztf-mirror": "all",
+		"vlass":      "all",
+	}
+	for catalogName, expected := range testCases {
+		require.Equal(t, expected, metadataCatalog(catalogName), catalogName)
+	}
+}
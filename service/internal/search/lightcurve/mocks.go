@@ -5,6 +5,8 @@
 package lightcurve
 
 import (
+	"context"
+
 	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -36,17 +38,44 @@ func (_m *MockExternalClient) EXPECT() *MockExternalClient_Expecter {
 	return &MockExternalClient_Expecter{mock: &_m.Mock}
 }
 
+// NewMockConesearchService creates a new instance of MockConesearchService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockConesearchService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockConesearchService {
+	mock := &MockConesearchService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockConesearchService is an autogenerated mock type for the ConesearchService type
+type MockConesearchService struct {
+	mock.Mock
+}
+
+type MockConesearchService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockConesearchService) EXPECT() *MockConesearchService_Expecter {
+	return &MockConesearchService_Expecter{mock: &_m.Mock}
+}
+
 // FetchLightcurve provides a mock function for the type MockExternalClient
-func (_mock *MockExternalClient) FetchLightcurve(f float64, f1 float64, f2 float64, n int) ClientResult {
-	ret := _mock.Called(f, f1, f2, n)
+func (_mock *MockExternalClient) FetchLightcurve(ctx context.Context, f float64, f1 float64, f2 float64, n int) ClientResult {
+	ret := _mock.Called(ctx, f, f1, f2, n)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FetchLightcurve")
 	}
 
 	var r0 ClientResult
-	if returnFunc, ok := ret.Get(0).(func(float64, float64, float64, int) ClientResult); ok {
-		r0 = returnFunc(f, f1, f2, n)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, float64, float64, float64, int) ClientResult); ok {
+		r0 = returnFunc(ctx, f, f1, f2, n)
 	} else {
 		r0 = ret.Get(0).(ClientResult)
 	}
@@ -59,19 +88,20 @@ type MockExternalClient_FetchLightcurve_Call struct {
 }
 
 // FetchLightcurve is a helper method to define mock.On call
+//   - ctx context.Context
 //   - f float64
 //   - f1 float64
 //   - f2 float64
 //   - n int
-func (_e *MockExternalClient_Expecter) FetchLightcurve(f interface{}, f1 interface{}, f2 interface{}, n interface{}) *MockExternalClient_FetchLightcurve_Call {
-	return &MockExternalClient_FetchLightcurve_Call{Call: _e.mock.On("FetchLightcurve", f, f1, f2, n)}
+func (_e *MockExternalClient_Expecter) FetchLightcurve(ctx interface{}, f interface{}, f1 interface{}, f2 interface{}, n interface{}) *MockExternalClient_FetchLightcurve_Call {
+	return &MockExternalClient_FetchLightcurve_Call{Call: _e.mock.On("FetchLightcurve", ctx, f, f1, f2, n)}
 }
 
-func (_c *MockExternalClient_FetchLightcurve_Call) Run(run func(f float64, f1 float64, f2 float64, n int)) *MockExternalClient_FetchLightcurve_Call {
+func (_c *MockExternalClient_FetchLightcurve_Call) Run(run func(ctx context.Context, f float64, f1 float64, f2 float64, n int)) *MockExternalClient_FetchLightcurve_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 float64
+		var arg0 context.Context
 		if args[0] != nil {
-			arg0 = args[0].(float64)
+			arg0 = args[0].(context.Context)
 		}
 		var arg1 float64
 		if args[1] != nil {
@@ -81,15 +111,20 @@ func (_c *MockExternalClient_FetchLightcurve_Call) Run(run func(f float64, f1 fl
 		if args[2] != nil {
 			arg2 = args[2].(float64)
 		}
-		var arg3 int
+		var arg3 float64
 		if args[3] != nil {
-			arg3 = args[3].(int)
+			arg3 = args[3].(float64)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
 			arg3,
+			arg4,
 		)
 	})
 	return _c
@@ -100,41 +135,14 @@ func (_c *MockExternalClient_FetchLightcurve_Call) Return(clientResult ClientRes
 	return _c
 }
 
-func (_c *MockExternalClient_FetchLightcurve_Call) RunAndReturn(run func(f float64, f1 float64, f2 float64, n int) ClientResult) *MockExternalClient_FetchLightcurve_Call {
+func (_c *MockExternalClient_FetchLightcurve_Call) RunAndReturn(run func(ctx context.Context, f float64, f1 float64, f2 float64, n int) ClientResult) *MockExternalClient_FetchLightcurve_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockConesearchService creates a new instance of MockConesearchService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockConesearchService(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockConesearchService {
-	mock := &MockConesearchService{}
-	mock.Mock.Test(t)
-
-	t.Cleanup(func() { mock.AssertExpectations(t) })
-
-	return mock
-}
-
-// MockConesearchService is an autogenerated mock type for the ConesearchService type
-type MockConesearchService struct {
-	mock.Mock
-}
-
-type MockConesearchService_Expecter struct {
-	mock *mock.Mock
-}
-
-func (_m *MockConesearchService) EXPECT() *MockConesearchService_Expecter {
-	return &MockConesearchService_Expecter{mock: &_m.Mock}
-}
-
 // FindMetadataByConesearch provides a mock function for the type MockConesearchService
-func (_mock *MockConesearchService) FindMetadataByConesearch(f float64, f1 float64, f2 float64, n int, s string) ([]conesearch.MetadataResult, error) {
-	ret := _mock.Called(f, f1, f2, n, s)
+func (_mock *MockConesearchService) FindMetadataByConesearch(ctx context.Context, f float64, f1 float64, f2 float64, n int, s string) ([]conesearch.MetadataResult, error) {
+	ret := _mock.Called(ctx, f, f1, f2, n, s)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindMetadataByConesearch")
@@ -142,18 +150,18 @@ func (_mock *MockConesearchService) FindMetadataByConesearch(f float64, f1 float
 
 	var r0 []conesearch.MetadataResult
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(float64, float64, float64, int, string) ([]conesearch.MetadataResult, error)); ok {
-		return returnFunc(f, f1, f2, n, s)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, float64, float64, float64, int, string) ([]conesearch.MetadataResult, error)); ok {
+		return returnFunc(ctx, f, f1, f2, n, s)
 	}
-	if returnFunc, ok := ret.Get(0).(func(float64, float64, float64, int, string) []conesearch.MetadataResult); ok {
-		r0 = returnFunc(f, f1, f2, n, s)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, float64, float64, float64, int, string) []conesearch.MetadataResult); ok {
+		r0 = returnFunc(ctx, f, f1, f2, n, s)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]conesearch.MetadataResult)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(float64, float64, float64, int, string) error); ok {
-		r1 = returnFunc(f, f1, f2, n, s)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, float64, float64, float64, int, string) error); ok {
+		r1 = returnFunc(ctx, f, f1, f2, n, s)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -166,20 +174,21 @@ type MockConesearchService_FindMetadataByConesearch_Call struct {
 }
 
 // FindMetadataByConesearch is a helper method to define mock.On call
+//   - ctx context.Context
 //   - f float64
 //   - f1 float64
 //   - f2 float64
 //   - n int
 //   - s string
-func (_e *MockConesearchService_Expecter) FindMetadataByConesearch(f interface{}, f1 interface{}, f2 interface{}, n interface{}, s interface{}) *MockConesearchService_FindMetadataByConesearch_Call {
-	return &MockConesearchService_FindMetadataByConesearch_Call{Call: _e.mock.On("FindMetadataByConesearch", f, f1, f2, n, s)}
+func (_e *MockConesearchService_Expecter) FindMetadataByConesearch(ctx interface{}, f interface{}, f1 interface{}, f2 interface{}, n interface{}, s interface{}) *MockConesearchService_FindMetadataByConesearch_Call {
+	return &MockConesearchService_FindMetadataByConesearch_Call{Call: _e.mock.On("FindMetadataByConesearch", ctx, f, f1, f2, n, s)}
 }
 
-func (_c *MockConesearchService_FindMetadataByConesearch_Call) Run(run func(f float64, f1 float64, f2 float64, n int, s string)) *MockConesearchService_FindMetadataByConesearch_Call {
+func (_c *MockConesearchService_FindMetadataByConesearch_Call) Run(run func(ctx context.Context, f float64, f1 float64, f2 float64, n int, s string)) *MockConesearchService_FindMetadataByConesearch_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 float64
+		var arg0 context.Context
 		if args[0] != nil {
-			arg0 = args[0].(float64)
+			arg0 = args[0].(context.Context)
 		}
 		var arg1 float64
 		if args[1] != nil {
@@ -189,13 +198,17 @@ func (_c *MockConesearchService_FindMetadataByConesearch_Call) Run(run func(f fl
 		if args[2] != nil {
 			arg2 = args[2].(float64)
 		}
-		var arg3 int
+		var arg3 float64
 		if args[3] != nil {
-			arg3 = args[3].(int)
+			arg3 = args[3].(float64)
 		}
-		var arg4 string
+		var arg4 int
 		if args[4] != nil {
-			arg4 = args[4].(string)
+			arg4 = args[4].(int)
+		}
+		var arg5 string
+		if args[5] != nil {
+			arg5 = args[5].(string)
 		}
 		run(
 			arg0,
@@ -203,6 +216,7 @@ func (_c *MockConesearchService_FindMetadataByConesearch_Call) Run(run func(f fl
 			arg2,
 			arg3,
 			arg4,
+			arg5,
 		)
 	})
 	return _c
@@ -213,7 +227,7 @@ func (_c *MockConesearchService_FindMetadataByConesearch_Call) Return(metadataRe
 	return _c
 }
 
-func (_c *MockConesearchService_FindMetadataByConesearch_Call) RunAndReturn(run func(f float64, f1 float64, f2 float64, n int, s string) ([]conesearch.MetadataResult, error)) *MockConesearchService_FindMetadataByConesearch_Call {
+func (_c *MockConesearchService_FindMetadataByConesearch_Call) RunAndReturn(run func(ctx context.Context, f float64, f1 float64, f2 float64, n int, s string) ([]conesearch.MetadataResult, error)) *MockConesearchService_FindMetadataByConesearch_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1,6 +1,7 @@
 package neowise
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,26 +10,61 @@ import (
 	"strings"
 
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/httpclient"
 	"github.com/dirodriguezm/xmatch/service/internal/utils"
 )
 
+// DefaultURL is the IRSA Gator endpoint queried by the client
+const DefaultURL = "https://irsa.ipac.caltech.edu/cgi-bin/Gator/nph-query"
+
 type NeowiseClient struct {
 	url     string
 	headers map[string]string
 	catalog string
 	columns []string
+	client  *httpclient.Client
+}
+
+type NeowiseClientOption func(*NeowiseClient) error
+
+// WithURL points the client to another Gator endpoint, like a mirror or a local stand-in
+func WithURL(u string) NeowiseClientOption {
+	return func(client *NeowiseClient) error {
+		if _, err := url.ParseRequestURI(u); err != nil {
+			return fmt.Errorf("invalid NEOWISE url %q: %w", u, err)
+		}
+		client.url = u
+		return nil
+	}
 }
 
-func NewNeowiseClient() *NeowiseClient {
-	return &NeowiseClient{
-		url:     "https://irsa.ipac.caltech.edu/cgi-bin/Gator/nph-query",
+// WithHTTPClient makes the requests through client, which sets their timeout, retries and limits
+func WithHTTPClient(client *httpclient.Client) NeowiseClientOption {
+	return func(c *NeowiseClient) error {
+		if client == nil {
+			return fmt.Errorf("NEOWISE http client can't be nil")
+		}
+		c.client = client
+		return nil
+	}
+}
+
+func NewNeowiseClient(options ...NeowiseClientOption) (*NeowiseClient, error) {
+	client := &NeowiseClient{
+		url:     DefaultURL,
 		headers: map[string]string{},
 		catalog: "neowiser_p1bs_psd",
 		columns: []string{"mjd", "ra", "dec", "w1mpro", "w1sigmpro", "w2mpro", "w2sigmpro", "allwise_cntr", "source_id"},
 	}
+	for _, opt := range options {
+		if err := opt(client); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
 }
 
-func (client *NeowiseClient) FetchLightcurve(ra, dec, radius float64, nobjects int) lightcurve.ClientResult {
+func (client *NeowiseClient) FetchLightcurve(ctx context.Context, ra, dec, radius float64, nobjects int) lightcurve.ClientResult {
 	u, err := url.Parse(client.url)
 	if err != nil {
 		return lightcurve.ClientResult{
@@ -46,7 +82,14 @@ func (client *NeowiseClient) FetchLightcurve(ra, dec, radius float64, nobjects i
 		"selcols":  strings.Join(client.columns, ","),
 	})
 
-	resp, err := http.Get(u.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return lightcurve.ClientResult{
+			Error: fmt.Errorf("could not create request: %s", err),
+		}
+	}
+
+	resp, err := client.httpClient().Do(req)
 	if err != nil {
 		return lightcurve.ClientResult{
 			Error: fmt.Errorf("could not make request: %s", err),
@@ -54,6 +97,12 @@ func (client *NeowiseClient) FetchLightcurve(ra, dec, radius float64, nobjects i
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return lightcurve.ClientResult{
+			Error: fmt.Errorf("unexpected status code: %d", resp.StatusCode),
+		}
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return lightcurve.ClientResult{
@@ -79,6 +128,13 @@ func (client *NeowiseClient) FetchLightcurve(ra, dec, radius float64, nobjects i
 	}
 }
 
+func (client *NeowiseClient) httpClient() *httpclient.Client {
+	if client.client == nil {
+		return &httpclient.Client{}
+	}
+	return client.client
+}
+
 func addQueryParameters(u *url.URL, params map[string]string) *url.URL {
 	newURL := *u
 
@@ -1,10 +1,16 @@
 package neowise
 
 import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/httpclient"
 	"github.com/dirodriguezm/xmatch/service/internal/utils"
 	"github.com/stretchr/testify/require"
 )
@@ -62,6 +68,55 @@ func Test_convertToLightcurveObject(t *testing.T) {
 	}
 }
 
+func TestFetchLightcurve(t *testing.T) {
+	votable, err := xml.Marshal(buildFakeVOTable(
+		[]string{"mjd", "ra", "dec", "clon", "clat", "w1mpro", "w1sigmpro", "w2mpro", "w2sigmpro", "cntr", "source_id"},
+		[][]string{{"1.0", "2.0", "3.0", "ignore", "ignore", "4.0", "5.0", "6.0", "7.0", "1", "id1"}},
+	))
+	require.NoError(t, err)
+
+	t.Run("retries when IRSA is unavailable", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			require.Equal(t, "neowiser_p1bs_psd", r.URL.Query().Get("catalog"))
+			require.Equal(t, httpclient.DefaultUserAgent, r.UserAgent())
+			if attempts == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			_, err := w.Write(votable)
+			require.NoError(t, err)
+		}))
+		defer server.Close()
+
+		httpClient, err := httpclient.New("neowise", httpclient.WithRetries(2, time.Millisecond))
+		require.NoError(t, err)
+		client, err := NewNeowiseClient(WithURL(server.URL+"/cgi-bin/Gator/nph-query"), WithHTTPClient(httpClient))
+		require.NoError(t, err)
+
+		result := client.FetchLightcurve(context.Background(), 1, 2, 3, 0)
+
+		require.NoError(t, result.Error)
+		require.Equal(t, 2, attempts)
+		require.Equal(t, []lightcurve.LightcurveObject{Detection{1.0, 2.0, 3.0, 4.0, 5.0, 6.0, 7.0, 1, "id1"}}, result.Lightcurve.Detections)
+	})
+
+	t.Run("fails with the status of IRSA", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client, err := NewNeowiseClient(WithURL(server.URL))
+		require.NoError(t, err)
+
+		result := client.FetchLightcurve(context.Background(), 1, 2, 3, 0)
+
+		require.EqualError(t, result.Error, "unexpected status code: 503")
+	})
+}
+
 func buildFakeVOTable(fields []string, data [][]string) *utils.VOTable {
 	tableFields := make([]utils.Field, len(fields))
 	for i, field := range fields {
@@ -0,0 +1,47 @@
+package lightcurve
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+)
+
+// XwaveDetection is a detection of the x-wave lightcurve store
+type XwaveDetection struct {
+	ObjectId string  `json:"object_id"`
+	Mjd      float64 `json:"mjd"`
+	Band     string  `json:"band"`
+	Mag      float64 `json:"mag"`
+	MagErr   float64 `json:"mag_err"`
+}
+
+func NewXwaveDetection(d repository.XwaveDetection) XwaveDetection {
+	return XwaveDetection{
+		ObjectId: d.ObjectID,
+		Mjd:      d.Mjd,
+		Band:     d.Band,
+		Mag:      d.Mag,
+		MagErr:   d.MagErr,
+	}
+}
+
+func (d XwaveDetection) GetId() string {
+	return fmt.Sprintf("%s_%s_%s", d.ObjectId, d.Band, strconv.FormatFloat(d.Mjd, 'f', -1, 64))
+}
+
+func (d XwaveDetection) GetObjectId() string {
+	return d.ObjectId
+}
+
+func (d XwaveDetection) GetBrightness() float64 {
+	return d.Mag
+}
+
+func (d XwaveDetection) GetBrightnessError() float32 {
+	return float32(d.MagErr)
+}
+
+func (d XwaveDetection) GetMjd() float64 {
+	return d.Mjd
+}
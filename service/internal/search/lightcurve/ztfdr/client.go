@@ -1,6 +1,7 @@
 package ztfdr
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"strconv"
 
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/httpclient"
 )
 
 type lightCurveResponse struct {
@@ -24,17 +26,51 @@ type lightCurveResponse struct {
 	Magerr   []float64 `json:"magerr"`
 }
 
+// DefaultURL is the ALeRCE ZTF DR lightcurve endpoint queried by the client
+const DefaultURL = "https://api.alerce.online/ztf/dr/v1/light_curve/"
+
 type ZtfDrClient struct {
-	url string
+	url    string
+	client *httpclient.Client
+}
+
+type ZtfDrClientOption func(*ZtfDrClient) error
+
+// WithURL points the client to another lightcurve endpoint, like a mirror or a local stand-in
+func WithURL(u string) ZtfDrClientOption {
+	return func(client *ZtfDrClient) error {
+		if _, err := url.ParseRequestURI(u); err != nil {
+			return fmt.Errorf("invalid ZTF DR url %q: %w", u, err)
+		}
+		client.url = u
+		return nil
+	}
+}
+
+// WithHTTPClient makes the requests through client, which sets their timeout, retries and limits
+func WithHTTPClient(client *httpclient.Client) ZtfDrClientOption {
+	return func(c *ZtfDrClient) error {
+		if client == nil {
+			return fmt.Errorf("ZTF DR http client can't be nil")
+		}
+		c.client = client
+		return nil
+	}
 }
 
-func NewZtfDrClient() *ZtfDrClient {
-	return &ZtfDrClient{
-		url: "https://api.alerce.online/ztf/dr/v1/light_curve/",
+func NewZtfDrClient(options ...ZtfDrClientOption) (*ZtfDrClient, error) {
+	client := &ZtfDrClient{
+		url: DefaultURL,
+	}
+	for _, opt := range options {
+		if err := opt(client); err != nil {
+			return nil, err
+		}
 	}
+	return client, nil
 }
 
-func (client *ZtfDrClient) FetchLightcurve(ra, dec, radius float64, _ int) lightcurve.ClientResult {
+func (client *ZtfDrClient) FetchLightcurve(ctx context.Context, ra, dec, radius float64, _ int) lightcurve.ClientResult {
 	u, err := url.Parse(client.url)
 	if err != nil {
 		return lightcurve.ClientResult{Error: fmt.Errorf("could not parse url: %w", err)}
@@ -46,7 +82,12 @@ func (client *ZtfDrClient) FetchLightcurve(ra, dec, radius float64, _ int) light
 		"radius": strconv.FormatFloat(radius, 'f', -1, 64),
 	})
 
-	resp, err := http.Get(u.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return lightcurve.ClientResult{Error: fmt.Errorf("could not create request: %w", err)}
+	}
+
+	resp, err := client.httpClient().Do(req)
 	if err != nil {
 		return lightcurve.ClientResult{Error: fmt.Errorf("could not make request: %w", err)}
 	}
@@ -79,6 +120,13 @@ func (client *ZtfDrClient) FetchLightcurve(ra, dec, radius float64, _ int) light
 	}
 }
 
+func (client *ZtfDrClient) httpClient() *httpclient.Client {
+	if client.client == nil {
+		return &httpclient.Client{}
+	}
+	return client.client
+}
+
 func parseLightcurveResponse(body []byte) ([]lightCurveResponse, error) {
 	var responses []lightCurveResponse
 	if err := json.Unmarshal(body, &responses); err == nil {
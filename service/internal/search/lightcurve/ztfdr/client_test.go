@@ -1,12 +1,15 @@
 package ztfdr
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve"
+	"github.com/dirodriguezm/xmatch/service/internal/search/lightcurve/httpclient"
 	"github.com/stretchr/testify/require"
 )
 
@@ -125,7 +128,7 @@ func TestFetchLightcurve(t *testing.T) {
 
 		client := &ZtfDrClient{url: server.URL + "/light_curve/"}
 
-		result := client.FetchLightcurve(1, 2, 3, 0)
+		result := client.FetchLightcurve(context.Background(), 1, 2, 3, 0)
 
 		require.NoError(t, result.Error)
 		require.Equal(t, lightcurve.Lightcurve{Detections: []lightcurve.LightcurveObject{
@@ -144,7 +147,7 @@ func TestFetchLightcurve(t *testing.T) {
 
 		client := &ZtfDrClient{url: server.URL + "/light_curve/"}
 
-		result := client.FetchLightcurve(1, 2, 3, 0)
+		result := client.FetchLightcurve(context.Background(), 1, 2, 3, 0)
 
 		require.NoError(t, result.Error)
 		require.Equal(t, lightcurve.Lightcurve{Detections: []lightcurve.LightcurveObject{
@@ -161,9 +164,85 @@ func TestFetchLightcurve(t *testing.T) {
 
 		client := &ZtfDrClient{url: server.URL + "/light_curve/"}
 
-		result := client.FetchLightcurve(1, 2, 3, 0)
+		result := client.FetchLightcurve(context.Background(), 1, 2, 3, 0)
 
 		require.NoError(t, result.Error)
 		require.Equal(t, lightcurve.Lightcurve{}, result.Lightcurve)
 	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			t.Error("request should not be sent with a cancelled context")
+		}))
+		defer server.Close()
+
+		client := &ZtfDrClient{url: server.URL + "/light_curve/"}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		result := client.FetchLightcurve(ctx, 1, 2, 3, 0)
+
+		require.ErrorIs(t, result.Error, context.Canceled)
+	})
+
+	t.Run("uses the configured url and timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/mirror/light_curve/", r.URL.Path)
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer server.Close()
+
+		httpClient, err := httpclient.New("ztf_dr", httpclient.WithTimeout(50*time.Millisecond))
+		require.NoError(t, err)
+		client, err := NewZtfDrClient(WithURL(server.URL+"/mirror/light_curve/"), WithHTTPClient(httpClient))
+		require.NoError(t, err)
+
+		result := client.FetchLightcurve(context.Background(), 1, 2, 3, 0)
+
+		require.ErrorContains(t, result.Error, "Timeout")
+	})
+
+	t.Run("retries when the service is unavailable", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			require.Equal(t, "xmatch-test", r.UserAgent())
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, err := w.Write([]byte(`{"_id":1,"hmjd":[9.1],"mag":[20.1],"magerr":[0.1]}`))
+			require.NoError(t, err)
+		}))
+		defer server.Close()
+
+		httpClient, err := httpclient.New("ztf_dr", httpclient.WithRetries(1, time.Millisecond), httpclient.WithUserAgent("xmatch-test"))
+		require.NoError(t, err)
+		client, err := NewZtfDrClient(WithURL(server.URL+"/light_curve/"), WithHTTPClient(httpClient))
+		require.NoError(t, err)
+
+		result := client.FetchLightcurve(context.Background(), 1, 2, 3, 0)
+
+		require.NoError(t, result.Error)
+		require.Equal(t, 2, attempts)
+		require.Equal(t, []lightcurve.LightcurveObject{Detection{Oid: 1, Hmjd: 9.1, Mag: 20.1, Magerr: 0.1}}, result.Lightcurve.Detections)
+	})
+
+	t.Run("fails when the service keeps failing", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		httpClient, err := httpclient.New("ztf_dr", httpclient.WithRetries(1, time.Millisecond), httpclient.WithCircuitBreaker(1, time.Minute))
+		require.NoError(t, err)
+		client, err := NewZtfDrClient(WithURL(server.URL+"/light_curve/"), WithHTTPClient(httpClient))
+		require.NoError(t, err)
+
+		result := client.FetchLightcurve(context.Background(), 1, 2, 3, 0)
+		require.EqualError(t, result.Error, "unexpected status code: 500")
+
+		result = client.FetchLightcurve(context.Background(), 1, 2, 3, 0)
+		require.ErrorIs(t, result.Error, httpclient.ErrCircuitOpen)
+	})
 }
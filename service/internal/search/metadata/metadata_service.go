@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"sync"
 
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
 	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
 )
 
@@ -58,71 +60,135 @@ func (m *MetadataService) BulkFindByID(ctx context.Context, ids []string, catalo
 	return m.bulkQueryCatalog(ctx, ids, catalog)
 }
 
-func (m *MetadataService) queryCatalog(ctx context.Context, id string, catalog string) (any, error) {
-	switch strings.ToLower(catalog) {
-	case "allwise":
-		result, err := m.repository.GetAllwise(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		return result, nil
-	case "gaia":
-		result, err := m.repository.GetGaia(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		return result, nil
-	case "vlass":
-		return nil, ArgumentError{Name: "catalog", Value: catalog, Reason: "Search not yet implemented for catalog"}
-	case "ztf":
-		return nil, ArgumentError{Name: "catalog", Value: catalog, Reason: "Search not yet implemented for catalog"}
-	case "erosita":
-		result, err := m.repository.GetErosita(ctx, id)
-		if err != nil {
-			return nil, err
+// BulkFindByIDStream works like BulkFindByID, but queries the ids in chunks and hands
+// the rows of each chunk to emit as soon as the chunk is done.
+//
+// Chunks may finish in any order, but emit is never called concurrently. The search
+// stops at the first error, either from the repository or from emit.
+func (m *MetadataService) BulkFindByIDStream(
+	ctx context.Context,
+	ids []string,
+	catalog string,
+	chunkSize int,
+	maxBulkConcurrency int,
+	emit func([]any) error,
+) error {
+	if err := m.validateCatalog(catalog); err != nil {
+		return err
+	}
+	for i := range ids {
+		if err := m.validateID(ids[i]); err != nil {
+			return err
 		}
-		return result, nil
-	default:
-		return nil, ArgumentError{Name: "catalog", Value: catalog, Reason: "Unknown catalog"}
 	}
+
+	desc, err := m.lookupCatalog(catalog)
+	if err != nil {
+		return err
+	}
+	query := func(ctx context.Context, ids []string) ([]any, error) {
+		return desc.BulkGetRows(ctx, m.repository, ids)
+	}
+	return streamChunks(ctx, ids, chunkSize, maxBulkConcurrency, query, emit)
+}
+
+func (m *MetadataService) queryCatalog(ctx context.Context, id string, catalog string) (any, error) {
+	desc, err := m.lookupCatalog(catalog)
+	if err != nil {
+		return nil, err
+	}
+	return desc.Get(ctx, m.repository, id)
 }
 
 func (m *MetadataService) bulkQueryCatalog(ctx context.Context, ids []string, catalog string) (any, error) {
-	switch strings.ToLower(catalog) {
-	case "allwise":
-		result, err := m.repository.BulkGetAllwise(ctx, ids)
-		if err != nil {
-			return nil, err
-		}
-		return result, nil
-	case "gaia":
-		result, err := m.repository.BulkGetGaia(ctx, ids)
-		if err != nil {
-			return nil, err
-		}
-		return result, nil
-	case "vlass":
-		return nil, ArgumentError{Name: "catalog", Value: catalog, Reason: "Search not yet implemented for catalog"}
-	case "ztf":
-		return nil, ArgumentError{Name: "catalog", Value: catalog, Reason: "Search not yet implemented for catalog"}
-	case "erosita":
-		result, err := m.repository.BulkGetErosita(ctx, ids)
-		if err != nil {
-			return nil, err
-		}
-		return result, nil
-	default:
-		return nil, ArgumentError{Name: "catalog", Value: catalog, Reason: "Unknown catalog"}
+	desc, err := m.lookupCatalog(catalog)
+	if err != nil {
+		return nil, err
+	}
+	return desc.BulkGet(ctx, m.repository, ids)
+}
+
+// lookupCatalog returns the registered catalog, if its metadata can be searched
+func (m *MetadataService) lookupCatalog(catalogName string) (catalog.CatalogDescriptor, error) {
+	desc, ok := catalog.Lookup(catalogName)
+	if !ok {
+		return desc, ArgumentError{Name: "catalog", Value: catalogName, Reason: "Unknown catalog"}
+	}
+	if !desc.HasMetadata() {
+		return desc, ArgumentError{Name: "catalog", Value: catalogName, Reason: "Search not yet implemented for catalog"}
 	}
+	return desc, nil
+}
+
+func streamChunks[T any](
+	ctx context.Context,
+	ids []string,
+	chunkSize int,
+	maxBulkConcurrency int,
+	query func(context.Context, []string) ([]T, error),
+	emit func([]any) error,
+) error {
+	numChunks := (len(ids) + chunkSize - 1) / chunkSize
+	errChan := make(chan error, numChunks)
+	var wg sync.WaitGroup
+	var emitMutex sync.Mutex
+	failed := false
+
+	sem := make(chan struct{}, maxBulkConcurrency)
+
+	for chunk := range slices.Chunk(ids, chunkSize) {
+		wg.Add(1)
+
+		go func(chunk []string) {
+			sem <- struct{}{}
+
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			emitMutex.Lock()
+			stopped := failed
+			emitMutex.Unlock()
+			if stopped {
+				return
+			}
+
+			rows, err := query(ctx, chunk)
+
+			emitMutex.Lock()
+			defer emitMutex.Unlock()
+			if failed {
+				return
+			}
+			if err == nil {
+				results := make([]any, len(rows))
+				for i := range rows {
+					results[i] = rows[i]
+				}
+				err = emit(results)
+			}
+			if err != nil {
+				failed = true
+				errChan <- err
+			}
+		}(chunk)
+	}
+
+	wg.Wait()
+	close(errChan)
+	for err := range errChan {
+		return err
+	}
+	return nil
 }
 
-func (m *MetadataService) validateCatalog(catalog string) error {
-	allowedCatalogs := []string{"allwise", "vlass", "ztf", "gaia", "erosita"}
-	if !slices.Contains(allowedCatalogs, strings.ToLower(catalog)) {
+func (m *MetadataService) validateCatalog(catalogName string) error {
+	if _, ok := catalog.Lookup(catalogName); !ok {
 		return ValidationError{
 			Field:  "catalog",
-			Reason: fmt.Sprintf("Allowed catalogs are %v", allowedCatalogs),
-			Value:  catalog,
+			Reason: fmt.Sprintf("Allowed catalogs are %v", catalog.Names()),
+			Value:  catalogName,
 		}
 	}
 	return nil
@@ -85,6 +85,43 @@ func TestMetadata_BulkFindByID(t *testing.T) {
 	}
 }
 
+func TestMetadata_BulkFindByIDStream(t *testing.T) {
+	repo := &conesearch.MockRepository{}
+	repo.On("BulkGetAllwise", mock.Anything, []string{"allwise1", "allwise2"}).Return([]repository.BulkGetAllwiseRow{{ID: "allwise1"}, {ID: "allwise2"}}, nil)
+	repo.On("BulkGetAllwise", mock.Anything, []string{"allwise3"}).Return([]repository.BulkGetAllwiseRow{{ID: "allwise3"}}, nil)
+
+	m := &MetadataService{
+		repository: repo,
+	}
+
+	ids := []string{}
+	batches := 0
+	err := m.BulkFindByIDStream(context.Background(), []string{"allwise1", "allwise2", "allwise3"}, "allwise", 2, 2, func(rows []any) error {
+		batches++
+		for _, row := range rows {
+			ids = append(ids, row.(repository.BulkGetAllwiseRow).ID)
+		}
+		return nil
+	})
+	require.Nil(t, err)
+	repo.AssertExpectations(t)
+	require.Equal(t, 2, batches)
+	require.ElementsMatch(t, []string{"allwise1", "allwise2", "allwise3"}, ids)
+}
+
+func TestMetadata_BulkFindByIDStream_Validation(t *testing.T) {
+	repo := &conesearch.MockRepository{}
+	m := &MetadataService{
+		repository: repo,
+	}
+
+	err := m.BulkFindByIDStream(context.Background(), []string{"allwise1", "drop table"}, "allwise", 1, 1, func(rows []any) error {
+		return nil
+	})
+	require.ErrorAs(t, err, &ValidationError{})
+	repo.AssertNotCalled(t, "BulkGetAllwise", mock.Anything, mock.Anything)
+}
+
 func TestMetadata_Bulk_EmptyResult(t *testing.T) {
 	repo := &conesearch.MockRepository{}
 
@@ -0,0 +1,658 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tap
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Query is a parsed ADQL query
+//
+// Only a subset of ADQL is supported: a single table, no joins or subqueries,
+// and the geometry functions POINT, CIRCLE, CONTAINS and DISTANCE.
+type Query struct {
+	// Top is the row limit of the query, or -1 when there is none
+	Top     int
+	Star    bool
+	Items   []SelectItem
+	Table   string
+	Alias   string
+	Where   Expr
+	OrderBy []OrderItem
+}
+
+type SelectItem struct {
+	Expr  Expr
+	Alias string
+}
+
+type OrderItem struct {
+	Expr Expr
+	Desc bool
+}
+
+// Expr is a node of an ADQL expression
+type Expr interface {
+	fmt.Stringer
+}
+
+type ColumnRef struct {
+	Table string
+	Name  string
+}
+
+type NumberLit struct {
+	Value float64
+	Text  string
+}
+
+type StringLit struct {
+	Value string
+}
+
+type NullLit struct{}
+
+// BinaryExpr is a logical, comparison or arithmetic operation
+type BinaryExpr struct {
+	Op    string
+	Left  Expr
+	Right Expr
+}
+
+type NotExpr struct {
+	Expr Expr
+}
+
+type NegExpr struct {
+	Expr Expr
+}
+
+type IsNullExpr struct {
+	Expr Expr
+	Not  bool
+}
+
+type BetweenExpr struct {
+	Expr Expr
+	Low  Expr
+	High Expr
+	Not  bool
+}
+
+type FuncCall struct {
+	Name string
+	Args []Expr
+}
+
+func (e ColumnRef) String() string {
+	if e.Table != "" {
+		return e.Table + "." + e.Name
+	}
+	return e.Name
+}
+
+func (e NumberLit) String() string  { return e.Text }
+func (e StringLit) String() string  { return "'" + strings.ReplaceAll(e.Value, "'", "''") + "'" }
+func (e NullLit) String() string    { return "NULL" }
+func (e NotExpr) String() string    { return "NOT " + e.Expr.String() }
+func (e NegExpr) String() string    { return "-" + e.Expr.String() }
+func (e BinaryExpr) String() string { return e.Left.String() + " " + e.Op + " " + e.Right.String() }
+
+func (e IsNullExpr) String() string {
+	if e.Not {
+		return e.Expr.String() + " IS NOT NULL"
+	}
+	return e.Expr.String() + " IS NULL"
+}
+
+func (e BetweenExpr) String() string {
+	op := " BETWEEN "
+	if e.Not {
+		op = " NOT BETWEEN "
+	}
+	return e.Expr.String() + op + e.Low.String() + " AND " + e.High.String()
+}
+
+func (e FuncCall) String() string {
+	args := make([]string, len(e.Args))
+	for i := range e.Args {
+		args[i] = e.Args[i].String()
+	}
+	return e.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Functions of the ADQL subset, with their number of arguments
+var functions = map[string][]int{
+	"POINT":    {2, 3},
+	"CIRCLE":   {2, 3, 4},
+	"CONTAINS": {2},
+	"DISTANCE": {2, 4},
+}
+
+var reservedWords = map[string]bool{
+	"SELECT": true, "TOP": true, "FROM": true, "WHERE": true, "ORDER": true, "BY": true,
+	"ASC": true, "DESC": true, "AND": true, "OR": true, "NOT": true, "AS": true,
+	"IS": true, "NULL": true, "BETWEEN": true,
+	"JOIN": true, "UNION": true, "GROUP": true, "HAVING": true, "OFFSET": true,
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenNumber
+	tokenString
+	tokenSymbol
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	upper string
+}
+
+func tokenize(adql string) ([]token, error) {
+	runes := []rune(adql)
+	tokens := []token{}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			text := string(runes[start:i])
+			tokens = append(tokens, token{kind: tokenWord, text: text, upper: strings.ToUpper(text)})
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, queryError("Unterminated quoted identifier", string(runes[i:]))
+			}
+			text := string(runes[i+1 : end])
+			tokens = append(tokens, token{kind: tokenWord, text: text, upper: strings.ToUpper(text)})
+			i = end + 1
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				i++
+				if i < len(runes) && (runes[i] == '+' || runes[i] == '-') {
+					i++
+				}
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i])})
+		case r == '\'':
+			var value strings.Builder
+			i++
+			for {
+				if i == len(runes) {
+					return nil, queryError("Unterminated string", adql)
+				}
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						value.WriteRune('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				value.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value.String()})
+		default:
+			text := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "<=", ">=", "<>", "!=":
+					text = two
+				}
+			}
+			if len(text) == 1 && !strings.Contains("(),.*=<>+-/;", text) {
+				return nil, queryError("Unexpected character", text)
+			}
+			tokens = append(tokens, token{kind: tokenSymbol, text: text})
+			i += len([]rune(text))
+		}
+	}
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse parses an ADQL query
+func Parse(adql string) (*Query, error) {
+	tokens, err := tokenize(adql)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	query, err := p.query()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind == tokenSymbol && p.peek().text == ";" {
+		p.pos++
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, p.unexpected()
+	}
+	return query, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokenWord && t.upper == word
+}
+
+func (p *parser) acceptKeyword(word string) bool {
+	if p.isKeyword(word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) isSymbol(symbol string) bool {
+	t := p.peek()
+	return t.kind == tokenSymbol && t.text == symbol
+}
+
+func (p *parser) acceptSymbol(symbol string) bool {
+	if p.isSymbol(symbol) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(word string) error {
+	if !p.acceptKeyword(word) {
+		return queryError("Expected "+word, p.describe())
+	}
+	return nil
+}
+
+func (p *parser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return queryError("Expected "+symbol, p.describe())
+	}
+	return nil
+}
+
+func (p *parser) describe() string {
+	t := p.peek()
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenString:
+		return "'" + t.text + "'"
+	}
+	return t.text
+}
+
+func (p *parser) unexpected() error {
+	return queryError("Unexpected token", p.describe())
+}
+
+func (p *parser) identifier() (string, error) {
+	t := p.peek()
+	if t.kind != tokenWord || reservedWords[t.upper] {
+		return "", queryError("Expected an identifier", p.describe())
+	}
+	p.pos++
+	return t.text, nil
+}
+
+func (p *parser) query() (*Query, error) {
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+	query := &Query{Top: -1}
+	if p.acceptKeyword("TOP") {
+		t := p.next()
+		top, err := strconv.Atoi(t.text)
+		if t.kind != tokenNumber || err != nil || top < 0 {
+			return nil, queryError("TOP must be a non negative integer", t.text)
+		}
+		query.Top = top
+	}
+
+	if p.acceptSymbol("*") {
+		query.Star = true
+	} else {
+		for {
+			item, err := p.selectItem()
+			if err != nil {
+				return nil, err
+			}
+			query.Items = append(query.Items, item)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	table, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	query.Table = table
+	if p.acceptKeyword("AS") || (p.peek().kind == tokenWord && !reservedWords[p.peek().upper]) {
+		if query.Alias, err = p.identifier(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.acceptKeyword("WHERE") {
+		if query.Where, err = p.or(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.acceptKeyword("ORDER") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			expr, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			item := OrderItem{Expr: expr}
+			if p.acceptKeyword("DESC") {
+				item.Desc = true
+			} else {
+				p.acceptKeyword("ASC")
+			}
+			query.OrderBy = append(query.OrderBy, item)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	return query, nil
+}
+
+func (p *parser) selectItem() (SelectItem, error) {
+	// table.* is the same as * on a single table
+	if p.peek().kind == tokenWord && p.tokens[p.pos+1].text == "." && p.tokens[p.pos+2].text == "*" {
+		return SelectItem{}, queryError("Qualified * is not supported, use *", p.describe()+".*")
+	}
+	expr, err := p.or()
+	if err != nil {
+		return SelectItem{}, err
+	}
+	item := SelectItem{Expr: expr}
+	if p.acceptKeyword("AS") {
+		if item.Alias, err = p.identifier(); err != nil {
+			return SelectItem{}, err
+		}
+	}
+	return item, nil
+}
+
+func (p *parser) or() (Expr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("OR") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = BinaryExpr{Op: "OR", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (Expr, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("AND") {
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = BinaryExpr{Op: "AND", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) not() (Expr, error) {
+	if p.acceptKeyword("NOT") {
+		expr, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return NotExpr{Expr: expr}, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (Expr, error) {
+	left, err := p.additive()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("IS") {
+		not := p.acceptKeyword("NOT")
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		return IsNullExpr{Expr: left, Not: not}, nil
+	}
+
+	not := false
+	if p.isKeyword("NOT") && p.tokens[p.pos+1].upper == "BETWEEN" {
+		p.pos++
+		not = true
+	}
+	if p.acceptKeyword("BETWEEN") {
+		low, err := p.additive()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+		high, err := p.additive()
+		if err != nil {
+			return nil, err
+		}
+		return BetweenExpr{Expr: left, Low: low, High: high, Not: not}, nil
+	}
+
+	for _, op := range []string{"=", "!=", "<>", "<=", ">=", "<", ">"} {
+		if p.acceptSymbol(op) {
+			right, err := p.additive()
+			if err != nil {
+				return nil, err
+			}
+			if op == "<>" {
+				op = "!="
+			}
+			return BinaryExpr{Op: op, Left: left, Right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) additive() (Expr, error) {
+	left, err := p.multiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isSymbol("+") || p.isSymbol("-") {
+		op := p.next().text
+		right, err := p.multiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = BinaryExpr{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) multiplicative() (Expr, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isSymbol("*") || p.isSymbol("/") {
+		op := p.next().text
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = BinaryExpr{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (Expr, error) {
+	if p.acceptSymbol("-") {
+		expr, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if number, ok := expr.(NumberLit); ok {
+			return NumberLit{Value: -number.Value, Text: "-" + number.Text}, nil
+		}
+		return NegExpr{Expr: expr}, nil
+	}
+	if p.acceptSymbol("+") {
+		return p.unary()
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (Expr, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.pos++
+		value, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, queryError("Invalid number", t.text)
+		}
+		return NumberLit{Value: value, Text: t.text}, nil
+	case tokenString:
+		p.pos++
+		return StringLit{Value: t.text}, nil
+	case tokenSymbol:
+		if p.acceptSymbol("(") {
+			expr, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expectSymbol(")")
+		}
+	case tokenWord:
+		if p.acceptKeyword("NULL") {
+			return NullLit{}, nil
+		}
+		if p.tokens[p.pos+1].text == "(" {
+			return p.function()
+		}
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		if p.acceptSymbol(".") {
+			column, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			return ColumnRef{Table: name, Name: column}, nil
+		}
+		return ColumnRef{Name: name}, nil
+	}
+	return nil, p.unexpected()
+}
+
+func (p *parser) function() (Expr, error) {
+	name := p.next().upper
+	arities, ok := functions[name]
+	if !ok {
+		return nil, queryError("Unsupported function", name)
+	}
+	p.pos++ // (
+
+	call := FuncCall{Name: name}
+	if !p.isSymbol(")") {
+		for {
+			arg, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			call.Args = append(call.Args, arg)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(arities, len(call.Args)) {
+		return nil, queryError(fmt.Sprintf("Wrong number of arguments for %s", name), call.String())
+	}
+	return call, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	query, err := Parse(`SELECT TOP 10 m.id, ra AS "right", DISTANCE(POINT('ICRS', ra, dec), POINT(10.5, -2)) AS dist
+		FROM mastercat AS m
+		WHERE CONTAINS(POINT(ra, dec), CIRCLE(10.5, -2, 0.01)) = 1 AND (pos_err IS NOT NULL OR cat <> 'it''s')
+		ORDER BY dist DESC, id`)
+	require.NoError(t, err)
+
+	require.Equal(t, 10, query.Top)
+	require.False(t, query.Star)
+	require.Equal(t, "mastercat", query.Table)
+	require.Equal(t, "m", query.Alias)
+	require.Equal(t, []SelectItem{
+		{Expr: ColumnRef{Table: "m", Name: "id"}},
+		{Expr: ColumnRef{Name: "ra"}, Alias: "right"},
+		{
+			Expr: FuncCall{Name: "DISTANCE", Args: []Expr{
+				FuncCall{Name: "POINT", Args: []Expr{StringLit{Value: "ICRS"}, ColumnRef{Name: "ra"}, ColumnRef{Name: "dec"}}},
+				FuncCall{Name: "POINT", Args: []Expr{NumberLit{Value: 10.5, Text: "10.5"}, NumberLit{Value: -2, Text: "-2"}}},
+			}},
+			Alias: "dist",
+		},
+	}, query.Items)
+	require.Equal(t,
+		"CONTAINS(POINT(ra, dec), CIRCLE(10.5, -2, 0.01)) = 1 AND pos_err IS NOT NULL OR cat != 'it''s'",
+		query.Where.String(),
+	)
+	require.Equal(t, []OrderItem{
+		{Expr: ColumnRef{Name: "dist"}, Desc: true},
+		{Expr: ColumnRef{Name: "id"}},
+	}, query.OrderBy)
+}
+
+func TestParse_Precedence(t *testing.T) {
+	query, err := Parse("select * from allwise where not w1mpro between 1 and 2 or w2mpro < 1 + 2 * 3")
+	require.NoError(t, err)
+	require.True(t, query.Star)
+	require.Equal(t, -1, query.Top)
+
+	or, ok := query.Where.(BinaryExpr)
+	require.True(t, ok)
+	require.Equal(t, "OR", or.Op)
+	require.IsType(t, NotExpr{}, or.Left)
+	require.Equal(t, BinaryExpr{
+		Op:    "<",
+		Left:  ColumnRef{Name: "w2mpro"},
+		Right: BinaryExpr{Op: "+", Left: NumberLit{Value: 1, Text: "1"}, Right: BinaryExpr{Op: "*", Left: NumberLit{Value: 2, Text: "2"}, Right: NumberLit{Value: 3, Text: "3"}}},
+	}, or.Right)
+}
+
+func TestParse_Errors(t *testing.T) {
+	testCases := map[string]string{
+		"":                                          "Expected SELECT",
+		"DELETE FROM mastercat":                     "Expected SELECT",
+		"SELECT id":                                 "Expected FROM",
+		"SELECT TOP -1 * FROM mastercat":            "TOP must be a non negative integer",
+		"SELECT * FROM mastercat WHERE":             "Unexpected token",
+		"SELECT * FROM mastercat WHERE ra > 'a":     "Unterminated string",
+		"SELECT * FROM mastercat; DROP TABLE x":     "Unexpected token",
+		"SELECT * FROM a JOIN b ON a.id = b.id":     "Unexpected token",
+		"SELECT COUNT(*) FROM mastercat":            "Unsupported function",
+		"SELECT * FROM mastercat WHERE POINT(1)":    "Wrong number of arguments for POINT",
+		"SELECT * FROM mastercat WHERE ra > 1 | 2":  "Unexpected character",
+		"SELECT * FROM (SELECT * FROM mastercat) t": "Expected an identifier",
+	}
+	for adql, reason := range testCases {
+		t.Run(adql, func(t *testing.T) {
+			_, err := Parse(adql)
+			require.Error(t, err)
+			var validationErr ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			require.Equal(t, "QUERY", validationErr.Field)
+			require.Equal(t, reason, validationErr.Reason)
+		})
+	}
+}
+
+func TestEval_ThreeValuedLogic(t *testing.T) {
+	row := []any{nil, int64(2)}
+	unknown := columnExpr{index: 0}
+	two := columnExpr{index: 1}
+
+	require.Nil(t, eval(BinaryExpr{Op: "=", Left: unknown, Right: NumberLit{Value: 1}}, row))
+	require.Equal(t, false, eval(BinaryExpr{Op: "AND", Left: BinaryExpr{Op: "=", Left: unknown, Right: NumberLit{Value: 1}}, Right: BinaryExpr{Op: ">", Left: two, Right: NumberLit{Value: 3}}}, row))
+	require.Equal(t, true, eval(BinaryExpr{Op: "OR", Left: BinaryExpr{Op: "=", Left: unknown, Right: NumberLit{Value: 1}}, Right: BinaryExpr{Op: "<", Left: two, Right: NumberLit{Value: 3}}}, row))
+	require.Nil(t, eval(NotExpr{Expr: BinaryExpr{Op: "=", Left: unknown, Right: NumberLit{Value: 1}}}, row))
+	require.Equal(t, true, eval(IsNullExpr{Expr: unknown}, row))
+	require.Equal(t, true, eval(BetweenExpr{Expr: two, Low: NumberLit{Value: 1}, High: NumberLit{Value: 2}}, row))
+	require.Equal(t, 1.0, eval(BinaryExpr{Op: "/", Left: two, Right: NumberLit{Value: 2}}, row))
+}
@@ -0,0 +1,40 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tap
+
+import "fmt"
+
+type ValidationError struct {
+	Reason   string
+	ErrValue string
+	Field    string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("Invalid field %s with value %s. %s", e.Field, e.ErrValue, e.Reason)
+}
+
+func NewValidationError(reason string, errValue string, field string) error {
+	return ValidationError{
+		Reason:   reason,
+		ErrValue: errValue,
+		Field:    field,
+	}
+}
+
+// queryError is a ValidationError on the ADQL query
+func queryError(reason string, value string) error {
+	return NewValidationError(reason, value, "QUERY")
+}
@@ -0,0 +1,235 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tap
+
+import (
+	"cmp"
+	"math"
+	"strings"
+)
+
+// eval evaluates an expression on a row of the table, with the semantics of SQL
+//
+// Numbers are float64 and unknown values are nil, so that conditions follow
+// the three valued logic of SQL.
+func eval(expr Expr, row []any) any {
+	switch e := expr.(type) {
+	case columnExpr:
+		return number(row[e.index])
+	case NumberLit:
+		return e.Value
+	case StringLit:
+		return e.Value
+	case NullLit:
+		return nil
+	case NotExpr:
+		return not(eval(e.Expr, row))
+	case NegExpr:
+		value, ok := eval(e.Expr, row).(float64)
+		if !ok {
+			return nil
+		}
+		return -value
+	case IsNullExpr:
+		return (eval(e.Expr, row) == nil) != e.Not
+	case BetweenExpr:
+		value := eval(e.Expr, row)
+		between := and(
+			compare(">=", value, eval(e.Low, row)),
+			compare("<=", value, eval(e.High, row)),
+		)
+		if e.Not {
+			return not(between)
+		}
+		return between
+	case BinaryExpr:
+		switch e.Op {
+		case "AND":
+			return and(eval(e.Left, row), eval(e.Right, row))
+		case "OR":
+			return or(eval(e.Left, row), eval(e.Right, row))
+		case "+", "-", "*", "/":
+			return arithmetic(e.Op, eval(e.Left, row), eval(e.Right, row))
+		}
+		return compare(e.Op, eval(e.Left, row), eval(e.Right, row))
+	case containsExpr:
+		ra, dec, ok := evalPoint(e.Point, row)
+		centerRa, centerDec, centerOk := evalPoint(e.Circle.Center, row)
+		radius, radiusOk := eval(e.Circle.Radius, row).(float64)
+		if !ok || !centerOk || !radiusOk {
+			return nil
+		}
+		if angularDistance(ra, dec, centerRa, centerDec) <= radius {
+			return 1.0
+		}
+		return 0.0
+	case distanceExpr:
+		ra, dec, ok := evalPoint(e.From, row)
+		toRa, toDec, toOk := evalPoint(e.To, row)
+		if !ok || !toOk {
+			return nil
+		}
+		return angularDistance(ra, dec, toRa, toDec)
+	}
+	return nil
+}
+
+func evalPoint(point pointExpr, row []any) (float64, float64, bool) {
+	ra, raOk := eval(point.Ra, row).(float64)
+	dec, decOk := eval(point.Dec, row).(float64)
+	return ra, dec, raOk && decOk
+}
+
+// number converts the integers read from the database to float64
+func number(value any) any {
+	switch v := value.(type) {
+	case int64:
+		return float64(v)
+	case []byte:
+		return string(v)
+	}
+	return value
+}
+
+// truth returns the truth value of a condition, or nil when it's unknown
+func truth(value any) *bool {
+	var result bool
+	switch v := value.(type) {
+	case bool:
+		result = v
+	case float64:
+		result = v != 0
+	default:
+		return nil
+	}
+	return &result
+}
+
+func not(value any) any {
+	t := truth(value)
+	if t == nil {
+		return nil
+	}
+	return !*t
+}
+
+func and(left, right any) any {
+	l, r := truth(left), truth(right)
+	if (l != nil && !*l) || (r != nil && !*r) {
+		return false
+	}
+	if l == nil || r == nil {
+		return nil
+	}
+	return true
+}
+
+func or(left, right any) any {
+	l, r := truth(left), truth(right)
+	if (l != nil && *l) || (r != nil && *r) {
+		return true
+	}
+	if l == nil || r == nil {
+		return nil
+	}
+	return false
+}
+
+func arithmetic(op string, left, right any) any {
+	l, lOk := left.(float64)
+	r, rOk := right.(float64)
+	if !lOk || !rOk {
+		return nil
+	}
+	switch op {
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	}
+	if r == 0 {
+		return nil
+	}
+	return l / r
+}
+
+func compare(op string, left, right any) any {
+	if left == nil || right == nil {
+		return nil
+	}
+	c := compareValues(left, right)
+	switch op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+// compareValues orders values like SQLite: NULL first, then numbers, and then strings
+func compareValues(left, right any) int {
+	rank := func(value any) int {
+		switch value.(type) {
+		case nil:
+			return 0
+		case float64, bool:
+			return 1
+		}
+		return 2
+	}
+	if rank(left) != rank(right) {
+		return cmp.Compare(rank(left), rank(right))
+	}
+	switch l := left.(type) {
+	case float64:
+		return cmp.Compare(l, toFloat(right))
+	case bool:
+		return cmp.Compare(toFloat(l), toFloat(right))
+	case string:
+		return strings.Compare(l, right.(string))
+	}
+	return 0
+}
+
+func toFloat(value any) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case bool:
+		if v {
+			return 1
+		}
+	}
+	return 0
+}
+
+// angularDistance returns the distance in degrees between two points given in degrees
+func angularDistance(ra1, dec1, ra2, dec2 float64) float64 {
+	ra1, dec1 = ra1*math.Pi/180, dec1*math.Pi/180
+	ra2, dec2 = ra2*math.Pi/180, dec2*math.Pi/180
+	sinDec := math.Sin((dec2 - dec1) / 2)
+	sinRa := math.Sin((ra2 - ra1) / 2)
+	a := sinDec*sinDec + math.Cos(dec1)*math.Cos(dec2)*sinRa*sinRa
+	return 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a)) * 180 / math.Pi
+}
@@ -0,0 +1,548 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tap
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+)
+
+// columnExpr is a column reference resolved against the table of the query
+type columnExpr struct {
+	Column
+	// index of the column in the table
+	index int
+}
+
+// pointExpr, circleExpr, containsExpr and distanceExpr are the geometry
+// functions, with their arguments normalized to coordinates in degrees
+type pointExpr struct {
+	Ra  Expr
+	Dec Expr
+}
+
+type circleExpr struct {
+	Center pointExpr
+	Radius Expr
+}
+
+type containsExpr struct {
+	Point  pointExpr
+	Circle circleExpr
+}
+
+type distanceExpr struct {
+	From pointExpr
+	To   pointExpr
+}
+
+func (e columnExpr) String() string { return e.Name }
+func (e pointExpr) String() string  { return "POINT(" + e.Ra.String() + ", " + e.Dec.String() + ")" }
+func (e circleExpr) String() string {
+	return "CIRCLE(" + e.Center.String() + ", " + e.Radius.String() + ")"
+}
+func (e containsExpr) String() string {
+	return "CONTAINS(" + e.Point.String() + ", " + e.Circle.String() + ")"
+}
+func (e distanceExpr) String() string {
+	return "DISTANCE(" + e.From.String() + ", " + e.To.String() + ")"
+}
+
+// plan is a query resolved against a table of the schema
+type plan struct {
+	table   Table
+	columns []Column
+	items   []Expr
+	orderBy []OrderItem
+	top     int
+
+	// conditions of the WHERE clause that are translated to SQL
+	sqlWhere []Expr
+	// conditions of the WHERE clause that are evaluated after reading the rows.
+	// They are only used together with a cone.
+	goWhere []Expr
+	// cone constraining the position of the rows of queries that use geometry
+	cone *conesearch.Circle
+}
+
+func newPlan(query *Query) (*plan, error) {
+	table, ok := FindTable(query.Table)
+	if !ok {
+		return nil, queryError("Unknown table", query.Table)
+	}
+	r := resolver{table: table, names: []string{table.Name}}
+	if query.Alias != "" {
+		r.names = append(r.names, query.Alias)
+	}
+	p := &plan{table: table, top: query.Top}
+
+	if query.Star {
+		for i, column := range table.Columns {
+			p.items = append(p.items, columnExpr{Column: column, index: i})
+			p.columns = append(p.columns, column)
+		}
+	}
+	aliases := map[string]Expr{}
+	for i, item := range query.Items {
+		expr, err := r.resolve(item.Expr)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := expr.(pointExpr); ok {
+			return nil, queryError("POINT can only be used inside CONTAINS or DISTANCE", item.Expr.String())
+		}
+		if _, ok := expr.(circleExpr); ok {
+			return nil, queryError("CIRCLE can only be used inside CONTAINS", item.Expr.String())
+		}
+		column := describe(expr, i)
+		if item.Alias != "" {
+			column.Name = item.Alias
+			aliases[strings.ToLower(item.Alias)] = expr
+		}
+		p.items = append(p.items, expr)
+		p.columns = append(p.columns, column)
+	}
+
+	var where Expr
+	if query.Where != nil {
+		var err error
+		if where, err = r.resolve(query.Where); err != nil {
+			return nil, err
+		}
+		if !isCondition(where) {
+			return nil, queryError("WHERE must be a condition, like a comparison", query.Where.String())
+		}
+	}
+
+	for _, item := range query.OrderBy {
+		// ORDER BY can also reference the aliases of the selected columns
+		if ref, ok := item.Expr.(ColumnRef); ok && ref.Table == "" {
+			if expr, ok := aliases[strings.ToLower(ref.Name)]; ok {
+				p.orderBy = append(p.orderBy, OrderItem{Expr: expr, Desc: item.Desc})
+				continue
+			}
+		}
+		expr, err := r.resolve(item.Expr)
+		if err != nil {
+			return nil, err
+		}
+		p.orderBy = append(p.orderBy, OrderItem{Expr: expr, Desc: item.Desc})
+	}
+
+	usesGeometry := where != nil && hasGeometry(where)
+	for _, expr := range p.items {
+		usesGeometry = usesGeometry || hasGeometry(expr)
+	}
+	for _, item := range p.orderBy {
+		usesGeometry = usesGeometry || hasGeometry(item.Expr)
+	}
+
+	for _, condition := range conjuncts(where) {
+		if !usesGeometry || !hasGeometry(condition) {
+			p.sqlWhere = append(p.sqlWhere, condition)
+			continue
+		}
+		p.goWhere = append(p.goWhere, condition)
+		if p.cone != nil {
+			continue
+		}
+		cone, err := coneOf(condition)
+		if err != nil {
+			return nil, err
+		}
+		p.cone = cone
+	}
+	if usesGeometry && p.cone == nil {
+		return nil, queryError(
+			"Queries using geometry must constrain the position with CONTAINS(POINT(ra, dec), CIRCLE(ra, dec, radius)) = 1 or DISTANCE(POINT(ra, dec), POINT(ra, dec)) < radius",
+			"WHERE",
+		)
+	}
+	return p, nil
+}
+
+// resolver resolves the column references and geometry functions of an expression
+type resolver struct {
+	table Table
+	// names that can qualify a column of the table
+	names []string
+}
+
+func (r resolver) resolve(expr Expr) (Expr, error) {
+	switch e := expr.(type) {
+	case ColumnRef:
+		if e.Table != "" && !r.qualifies(e.Table) {
+			return nil, queryError("Unknown table", e.Table)
+		}
+		for i, column := range r.table.Columns {
+			if strings.EqualFold(column.Name, e.Name) {
+				return columnExpr{Column: column, index: i}, nil
+			}
+		}
+		return nil, queryError("Unknown column", e.String())
+	case NumberLit, StringLit, NullLit:
+		return e, nil
+	case BinaryExpr:
+		left, err := r.scalar(e.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := r.scalar(e.Right)
+		if err != nil {
+			return nil, err
+		}
+		return BinaryExpr{Op: e.Op, Left: left, Right: right}, nil
+	case NotExpr:
+		inner, err := r.scalar(e.Expr)
+		return NotExpr{Expr: inner}, err
+	case NegExpr:
+		inner, err := r.scalar(e.Expr)
+		return NegExpr{Expr: inner}, err
+	case IsNullExpr:
+		inner, err := r.scalar(e.Expr)
+		return IsNullExpr{Expr: inner, Not: e.Not}, err
+	case BetweenExpr:
+		inner, err := r.scalar(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		low, err := r.scalar(e.Low)
+		if err != nil {
+			return nil, err
+		}
+		high, err := r.scalar(e.High)
+		if err != nil {
+			return nil, err
+		}
+		return BetweenExpr{Expr: inner, Low: low, High: high, Not: e.Not}, nil
+	case FuncCall:
+		return r.function(e)
+	}
+	return nil, queryError("Unsupported expression", expr.String())
+}
+
+// scalar resolves an expression that can't be a POINT or a CIRCLE
+func (r resolver) scalar(expr Expr) (Expr, error) {
+	resolved, err := r.resolve(expr)
+	if err != nil {
+		return nil, err
+	}
+	switch resolved.(type) {
+	case pointExpr, circleExpr:
+		return nil, queryError("Geometry can only be used inside CONTAINS or DISTANCE", expr.String())
+	}
+	return resolved, nil
+}
+
+func (r resolver) qualifies(name string) bool {
+	for _, n := range r.names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r resolver) function(call FuncCall) (Expr, error) {
+	args := call.Args
+	switch call.Name {
+	case "POINT":
+		if len(args) == 3 {
+			if err := validateCoordSys(args[0]); err != nil {
+				return nil, err
+			}
+			args = args[1:]
+		}
+		ra, err := r.scalar(args[0])
+		if err != nil {
+			return nil, err
+		}
+		dec, err := r.scalar(args[1])
+		if err != nil {
+			return nil, err
+		}
+		return pointExpr{Ra: ra, Dec: dec}, nil
+	case "CIRCLE":
+		if len(args) == 4 {
+			if err := validateCoordSys(args[0]); err != nil {
+				return nil, err
+			}
+			args = args[1:]
+		}
+		var center pointExpr
+		if len(args) == 2 {
+			var err error
+			if center, err = r.point(args[0]); err != nil {
+				return nil, err
+			}
+		} else {
+			var err error
+			if center, err = r.point(FuncCall{Name: "POINT", Args: args[:2]}); err != nil {
+				return nil, err
+			}
+		}
+		radius, err := r.scalar(args[len(args)-1])
+		if err != nil {
+			return nil, err
+		}
+		return circleExpr{Center: center, Radius: radius}, nil
+	case "CONTAINS":
+		point, err := r.point(args[0])
+		if err != nil {
+			return nil, err
+		}
+		region, err := r.resolve(args[1])
+		if err != nil {
+			return nil, err
+		}
+		circle, ok := region.(circleExpr)
+		if !ok {
+			return nil, queryError("The second argument of CONTAINS must be a CIRCLE", args[1].String())
+		}
+		return containsExpr{Point: point, Circle: circle}, nil
+	case "DISTANCE":
+		if len(args) == 4 {
+			args = []Expr{
+				FuncCall{Name: "POINT", Args: args[:2]},
+				FuncCall{Name: "POINT", Args: args[2:]},
+			}
+		}
+		from, err := r.point(args[0])
+		if err != nil {
+			return nil, err
+		}
+		to, err := r.point(args[1])
+		if err != nil {
+			return nil, err
+		}
+		return distanceExpr{From: from, To: to}, nil
+	}
+	return nil, queryError("Unsupported function", call.Name)
+}
+
+func (r resolver) point(expr Expr) (pointExpr, error) {
+	resolved, err := r.resolve(expr)
+	if err != nil {
+		return pointExpr{}, err
+	}
+	point, ok := resolved.(pointExpr)
+	if !ok {
+		return pointExpr{}, queryError("Expected a POINT", expr.String())
+	}
+	return point, nil
+}
+
+func validateCoordSys(expr Expr) error {
+	coordSys, ok := expr.(StringLit)
+	if !ok {
+		return queryError("The coordinate system must be a string", expr.String())
+	}
+	if coordSys.Value != "" && !strings.EqualFold(coordSys.Value, "ICRS") {
+		return queryError("Only ICRS coordinates are supported", coordSys.Value)
+	}
+	return nil
+}
+
+// describe returns the output column of a selected expression
+func describe(expr Expr, position int) Column {
+	switch e := expr.(type) {
+	case columnExpr:
+		return e.Column
+	case distanceExpr:
+		return Column{Name: "distance", Description: "Angular distance", Datatype: "double", Unit: "deg", Ucd: "pos.angDistance"}
+	case containsExpr:
+		return Column{Name: "contains", Datatype: "int"}
+	}
+
+	column := Column{Name: "col" + strconv.Itoa(position+1), Datatype: "double"}
+	switch e := expr.(type) {
+	case StringLit:
+		column.Datatype, column.ArraySize = "char", "*"
+	case NotExpr, IsNullExpr, BetweenExpr:
+		column.Datatype = "boolean"
+	case BinaryExpr:
+		switch e.Op {
+		case "+", "-", "*", "/":
+		default:
+			column.Datatype = "boolean"
+		}
+	}
+	return column
+}
+
+func hasGeometry(expr Expr) bool {
+	switch e := expr.(type) {
+	case pointExpr, circleExpr, containsExpr, distanceExpr:
+		return true
+	case BinaryExpr:
+		return hasGeometry(e.Left) || hasGeometry(e.Right)
+	case NotExpr:
+		return hasGeometry(e.Expr)
+	case NegExpr:
+		return hasGeometry(e.Expr)
+	case IsNullExpr:
+		return hasGeometry(e.Expr)
+	case BetweenExpr:
+		return hasGeometry(e.Expr) || hasGeometry(e.Low) || hasGeometry(e.High)
+	}
+	return false
+}
+
+func hasColumns(expr Expr) bool {
+	switch e := expr.(type) {
+	case columnExpr:
+		return true
+	case pointExpr:
+		return hasColumns(e.Ra) || hasColumns(e.Dec)
+	case circleExpr:
+		return hasColumns(e.Center) || hasColumns(e.Radius)
+	case containsExpr:
+		return hasColumns(e.Point) || hasColumns(e.Circle)
+	case distanceExpr:
+		return hasColumns(e.From) || hasColumns(e.To)
+	case BinaryExpr:
+		return hasColumns(e.Left) || hasColumns(e.Right)
+	case NotExpr:
+		return hasColumns(e.Expr)
+	case NegExpr:
+		return hasColumns(e.Expr)
+	case IsNullExpr:
+		return hasColumns(e.Expr)
+	case BetweenExpr:
+		return hasColumns(e.Expr) || hasColumns(e.Low) || hasColumns(e.High)
+	}
+	return false
+}
+
+// isCondition reports whether an expression is true or false, and not a value like a column
+func isCondition(expr Expr) bool {
+	switch e := expr.(type) {
+	case NotExpr:
+		return isCondition(e.Expr)
+	case IsNullExpr, BetweenExpr, containsExpr:
+		return true
+	case BinaryExpr:
+		switch e.Op {
+		case "AND", "OR":
+			return isCondition(e.Left) && isCondition(e.Right)
+		case "=", "!=", "<", "<=", ">", ">=":
+			return true
+		}
+	}
+	return false
+}
+
+// conjuncts splits a condition on its top level ANDs
+func conjuncts(expr Expr) []Expr {
+	if expr == nil {
+		return nil
+	}
+	if e, ok := expr.(BinaryExpr); ok && e.Op == "AND" {
+		return append(conjuncts(e.Left), conjuncts(e.Right)...)
+	}
+	return []Expr{expr}
+}
+
+// coneOf returns the cone of the positions that satisfy a condition, which must be
+// CONTAINS(POINT(ra, dec), CIRCLE(...)) = 1 or DISTANCE(POINT(ra, dec), POINT(...)) < radius
+func coneOf(condition Expr) (*conesearch.Circle, error) {
+	switch e := condition.(type) {
+	case containsExpr:
+		return containsCone(e, condition)
+	case BinaryExpr:
+		switch {
+		case e.Op == "=" && isOne(e.Right):
+			if contains, ok := e.Left.(containsExpr); ok {
+				return containsCone(contains, condition)
+			}
+		case e.Op == "=" && isOne(e.Left):
+			if contains, ok := e.Right.(containsExpr); ok {
+				return containsCone(contains, condition)
+			}
+		case e.Op == "<" || e.Op == "<=":
+			if distance, ok := e.Left.(distanceExpr); ok {
+				return distanceCone(distance, e.Right, condition)
+			}
+		case e.Op == ">" || e.Op == ">=":
+			if distance, ok := e.Right.(distanceExpr); ok {
+				return distanceCone(distance, e.Left, condition)
+			}
+		}
+	}
+	return nil, queryError(
+		"Geometry can only be used in conditions like CONTAINS(POINT(ra, dec), CIRCLE(ra, dec, radius)) = 1 or DISTANCE(POINT(ra, dec), POINT(ra, dec)) < radius",
+		condition.String(),
+	)
+}
+
+func containsCone(contains containsExpr, condition Expr) (*conesearch.Circle, error) {
+	if !isPosition(contains.Point) {
+		return nil, queryError("CONTAINS must test the position POINT(ra, dec) of the table", condition.String())
+	}
+	return newCone(contains.Circle.Center, contains.Circle.Radius, condition)
+}
+
+func distanceCone(distance distanceExpr, radius Expr, condition Expr) (*conesearch.Circle, error) {
+	switch {
+	case isPosition(distance.From):
+		return newCone(distance.To, radius, condition)
+	case isPosition(distance.To):
+		return newCone(distance.From, radius, condition)
+	}
+	return nil, queryError("DISTANCE must be measured from the position POINT(ra, dec) of the table", condition.String())
+}
+
+func newCone(center pointExpr, radius Expr, condition Expr) (*conesearch.Circle, error) {
+	ra, raOk := constant(center.Ra)
+	dec, decOk := constant(center.Dec)
+	r, radiusOk := constant(radius)
+	if !raOk || !decOk || !radiusOk {
+		return nil, queryError("The center and radius of the searched region must be numbers", condition.String())
+	}
+
+	circle, err := conesearch.NewCircle(ra, dec, r)
+	if err != nil {
+		if validationErr := (conesearch.ValidationError{}); errors.As(err, &validationErr) {
+			return nil, queryError(validationErr.Reason, fmt.Sprintf("%s = %s", validationErr.Field, validationErr.ErrValue))
+		}
+		return nil, err
+	}
+	return &circle, nil
+}
+
+// isPosition reports whether a point is made of the ra and dec columns of the table
+func isPosition(point pointExpr) bool {
+	ra, raOk := point.Ra.(columnExpr)
+	dec, decOk := point.Dec.(columnExpr)
+	return raOk && decOk && ra.Name == "ra" && dec.Name == "dec"
+}
+
+func isOne(expr Expr) bool {
+	value, ok := constant(expr)
+	return ok && value == 1
+}
+
+// constant evaluates a numeric expression that doesn't depend on the rows
+func constant(expr Expr) (float64, bool) {
+	if hasColumns(expr) {
+		return 0, false
+	}
+	value, ok := eval(expr, nil).(float64)
+	return value, ok
+}
@@ -0,0 +1,152 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tap
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/catalog"
+)
+
+// Column is a column of a TAP table, described with VOTable metadata
+type Column struct {
+	Name        string
+	Description string
+	Datatype    string
+	ArraySize   string
+	Unit        string
+	Ucd         string
+
+	// expression that reads the column from the FROM clause of its table
+	sql string
+}
+
+// Table is a table that can be queried with ADQL
+type Table struct {
+	Name        string
+	Description string
+	Columns     []Column
+
+	from string
+	// catalog indexing the positions of the table. Tables without one
+	// are indexed by the pixels of every catalog.
+	catalog string
+}
+
+// Column finds a column by its name, ignoring the case
+func (t Table) Column(name string) (Column, bool) {
+	i := slices.IndexFunc(t.Columns, func(c Column) bool { return strings.EqualFold(c.Name, name) })
+	if i < 0 {
+		return Column{}, false
+	}
+	return t.Columns[i], true
+}
+
+// FindTable finds a table of the schema by its name, ignoring the case
+func FindTable(name string) (Table, bool) {
+	tables := Schema()
+	i := slices.IndexFunc(tables, func(t Table) bool { return strings.EqualFold(t.Name, name) })
+	if i < 0 {
+		return Table{}, false
+	}
+	return tables[i], true
+}
+
+// Schema returns every table that can be queried with ADQL: the mastercat,
+// and the metadata table of each registered catalog
+func Schema() []Table {
+	tables := []Table{mastercatSchema}
+	for _, desc := range catalog.All() {
+		if desc.HasMetadata() && !desc.Detections {
+			tables = append(tables, catalogTable(desc))
+		}
+	}
+	return tables
+}
+
+func positionColumns(table string) []Column {
+	return []Column{
+		{Name: "ra", Description: "Right ascension", Datatype: "double", Unit: "deg", Ucd: "pos.eq.ra;meta.main", sql: table + ".ra"},
+		{Name: "dec", Description: "Declination", Datatype: "double", Unit: "deg", Ucd: "pos.eq.dec;meta.main", sql: table + ".dec"},
+		{Name: "pos_err", Description: "Positional error", Datatype: "double", Unit: "arcsec", Ucd: "stat.error;pos", sql: table + ".pos_err"},
+	}
+}
+
+func properMotionColumns(table string) []Column {
+	return []Column{
+		{Name: "pmra", Description: "Proper motion in right ascension, times cos(dec)", Datatype: "double", Unit: "mas/yr", Ucd: "pos.pm;pos.eq.ra", sql: table + ".pmra"},
+		{Name: "pmdec", Description: "Proper motion in declination", Datatype: "double", Unit: "mas/yr", Ucd: "pos.pm;pos.eq.dec", sql: table + ".pmdec"},
+		{Name: "parallax", Description: "Parallax", Datatype: "double", Unit: "mas", Ucd: "pos.parallax", sql: table + ".parallax"},
+		{Name: "ref_epoch", Description: "Reference epoch of the position", Datatype: "double", Unit: "yr", Ucd: "time.epoch", sql: table + ".ref_epoch"},
+	}
+}
+
+var mastercatSchema = Table{
+	Name:        "mastercat",
+	Description: "Positions of the objects of every catalog",
+	Columns: slices.Concat(
+		[]Column{
+			{Name: "id", Description: "Object id in its catalog", Datatype: "char", ArraySize: "*", Ucd: "meta.id;meta.main", sql: "mastercat.id"},
+			{Name: "cat", Description: "Catalog of the object", Datatype: "char", ArraySize: "*", Ucd: "meta.dataset", sql: "mastercat.cat"},
+			{Name: "ipix", Description: "HEALPix pixel of the position, in the order of the catalog", Datatype: "long", Ucd: "pos.healpix", sql: "mastercat.ipix"},
+		},
+		positionColumns("mastercat"),
+		properMotionColumns("mastercat"),
+	),
+	from: "mastercat",
+}
+
+// catalogTable publishes the metadata table of a catalog, with the position of its objects in the mastercat
+func catalogTable(desc catalog.CatalogDescriptor) Table {
+	displayName := cmp.Or(desc.DisplayName, desc.Name)
+	table := fmt.Sprintf("%q", desc.Name)
+	columns := slices.Concat(
+		[]Column{
+			{Name: "id", Description: displayName + " source id", Datatype: "char", ArraySize: "*", Ucd: "meta.id;meta.main", sql: table + ".id"},
+		},
+		positionColumns("mastercat"),
+		properMotionColumns("mastercat"),
+	)
+	for _, column := range desc.Columns {
+		// the id and position columns of the mastercat take precedence
+		if slices.ContainsFunc(columns, func(c Column) bool { return strings.EqualFold(c.Name, column.Name) }) {
+			continue
+		}
+		arraySize := ""
+		if column.Datatype == "char" {
+			arraySize = "*"
+		}
+		columns = append(columns, Column{
+			Name:        column.Name,
+			Description: column.Description,
+			Datatype:    column.Datatype,
+			ArraySize:   arraySize,
+			Unit:        column.Unit,
+			Ucd:         column.Ucd,
+			sql:         fmt.Sprintf("%s.%q", table, column.Name),
+		})
+	}
+
+	return Table{
+		Name:        desc.Name,
+		Description: displayName + " source catalog",
+		Columns:     columns,
+		from:        fmt.Sprintf("%s JOIN mastercat ON mastercat.id = %s.id AND mastercat.cat = '%s'", table, table, desc.Name),
+		catalog:     desc.Name,
+	}
+}
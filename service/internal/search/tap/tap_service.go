@@ -0,0 +1,405 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tap
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch"
+)
+
+const (
+	DefaultMaxRec = 1000
+	MaxRec        = 100000
+)
+
+// Result holds the rows returned by an ADQL query
+type Result struct {
+	Columns []Column
+	Rows    [][]any
+	// Overflow is true when the rows were truncated to MAXREC
+	Overflow bool
+}
+
+// TapService runs ADQL queries on the catalog database
+//
+// Queries without geometry are translated to SQL. Geometry is evaluated
+// after reading the rows in the HEALPix pixels that cover the cone
+// constraining the position of the query.
+type TapService struct {
+	repository conesearch.Repository
+	catalogs   []repository.Catalog
+}
+
+func NewTapService(repo conesearch.Repository, catalogs []repository.Catalog) (*TapService, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("Repository was nil while creating TapService")
+	}
+	if len(catalogs) == 0 {
+		return nil, fmt.Errorf("catalogs was empty while creating TapService")
+	}
+	return &TapService{repository: repo, catalogs: catalogs}, nil
+}
+
+// Query runs an ADQL query, returning up to maxrec rows
+func (t *TapService) Query(ctx context.Context, adql string, maxrec int) (Result, error) {
+	if strings.TrimSpace(adql) == "" {
+		return Result{}, queryError("Query can not be empty", adql)
+	}
+	if maxrec < 0 || maxrec > MaxRec {
+		return Result{}, NewValidationError(fmt.Sprintf("MAXREC must be between 0 and %d", MaxRec), strconv.Itoa(maxrec), "MAXREC")
+	}
+
+	query, err := Parse(adql)
+	if err != nil {
+		return Result{}, err
+	}
+	p, err := newPlan(query)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// one more row than requested tells whether the result overflows
+	limit := maxrec + 1
+	if p.top >= 0 && p.top <= maxrec {
+		limit = p.top
+	}
+
+	var rows [][]any
+	if p.cone == nil {
+		rows, err = t.querySQL(ctx, p, limit)
+	} else {
+		rows, err = t.queryCone(ctx, p, limit)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Columns: p.columns, Rows: rows}
+	if len(rows) > maxrec {
+		result.Rows = rows[:maxrec]
+		result.Overflow = true
+	}
+	for _, row := range result.Rows {
+		for i := range row {
+			row[i] = convert(row[i], p.columns[i].Datatype)
+		}
+	}
+	return result, nil
+}
+
+// querySQL runs a query without geometry fully in the database
+func (t *TapService) querySQL(ctx context.Context, p *plan, limit int) ([][]any, error) {
+	args := []any{}
+	items, err := joinSQL(p.items, ", ", &args)
+	if err != nil {
+		return nil, err
+	}
+
+	var query strings.Builder
+	fmt.Fprintf(&query, "SELECT %s FROM %s", items, p.table.from)
+	if len(p.sqlWhere) > 0 {
+		where, err := joinSQL(p.sqlWhere, " AND ", &args)
+		if err != nil {
+			return nil, err
+		}
+		query.WriteString(" WHERE " + where)
+	}
+	if len(p.orderBy) > 0 {
+		order := make([]string, len(p.orderBy))
+		for i, item := range p.orderBy {
+			if order[i], err = toSQL(item.Expr, &args); err != nil {
+				return nil, err
+			}
+			if item.Desc {
+				order[i] += " DESC"
+			}
+		}
+		query.WriteString(" ORDER BY " + strings.Join(order, ", "))
+	}
+	query.WriteString(" LIMIT ?")
+	args = append(args, limit)
+
+	rows := [][]any{}
+	err = t.scan(ctx, query.String(), args, len(p.items), func(row []any) bool {
+		rows = append(rows, row)
+		return true
+	})
+	return rows, err
+}
+
+// queryCone reads every column of the rows in the pixels covering the cone of the
+// query, and evaluates its geometry, order and selected columns on each row
+func (t *TapService) queryCone(ctx context.Context, p *plan, limit int) ([][]any, error) {
+	pixels, pixelArgs, err := t.coverage(p)
+	if err != nil {
+		return nil, err
+	}
+	if pixels == "" || limit == 0 {
+		return [][]any{}, nil
+	}
+
+	args := pixelArgs
+	columns := make([]string, len(p.table.Columns))
+	for i, column := range p.table.Columns {
+		columns[i] = column.sql
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE (%s)", strings.Join(columns, ", "), p.table.from, pixels)
+	if len(p.sqlWhere) > 0 {
+		where, err := joinSQL(p.sqlWhere, " AND ", &args)
+		if err != nil {
+			return nil, err
+		}
+		query += " AND " + where
+	}
+
+	matches := [][]any{}
+	err = t.scan(ctx, query, args, len(columns), func(row []any) bool {
+		for _, condition := range p.goWhere {
+			if value := truth(eval(condition, row)); value == nil || !*value {
+				return true
+			}
+		}
+		matches = append(matches, row)
+		if len(p.orderBy) == 0 {
+			return len(matches) < limit
+		}
+		// rows can only be sorted after reading all of them, so only
+		// the first ones in the order are kept while reading
+		if len(matches) > 2*limit {
+			matches = p.sort(matches)[:limit]
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(p.orderBy) > 0 {
+		matches = p.sort(matches)
+		matches = matches[:min(len(matches), limit)]
+	}
+
+	rows := make([][]any, len(matches))
+	for i, match := range matches {
+		rows[i] = make([]any, len(p.items))
+		for j, item := range p.items {
+			if column, ok := item.(columnExpr); ok {
+				rows[i][j] = match[column.index]
+				continue
+			}
+			rows[i][j] = eval(item, match)
+		}
+	}
+	return rows, nil
+}
+
+// sort sorts rows holding every column of the table by the ORDER BY of the query.
+// The sort is stable, so rows keep the order they were read in when they are equal.
+func (p *plan) sort(rows [][]any) [][]any {
+	slices.SortStableFunc(rows, func(a, b []any) int {
+		for _, item := range p.orderBy {
+			c := compareValues(eval(item.Expr, a), eval(item.Expr, b))
+			if item.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return rows
+}
+
+// coverage returns the SQL condition selecting the pixels that cover the cone of the query
+//
+// Each catalog is indexed at its own order, so the pixels of a catalog are
+// only compared with the objects of that catalog.
+func (t *TapService) coverage(p *plan) (string, []any, error) {
+	catalogsByOrder := map[int64][]string{}
+	for _, catalog := range t.catalogs {
+		if p.table.catalog != "" && !strings.EqualFold(catalog.Name, p.table.catalog) {
+			continue
+		}
+		catalogsByOrder[catalog.Nside] = append(catalogsByOrder[catalog.Nside], catalog.Name)
+	}
+	orders := make([]int64, 0, len(catalogsByOrder))
+	for order := range catalogsByOrder {
+		orders = append(orders, order)
+	}
+	slices.Sort(orders)
+
+	conditions := []string{}
+	args := []any{}
+	for _, order := range orders {
+		ranges, err := p.cone.Coverage(int(order))
+		if err != nil {
+			return "", nil, err
+		}
+		pixels := make([]string, len(ranges))
+		for i, r := range ranges {
+			pixels[i] = "(mastercat.ipix >= ? AND mastercat.ipix < ?)"
+			args = append(args, r.Start, r.Stop)
+		}
+		catalogs := catalogsByOrder[order]
+		for _, catalog := range catalogs {
+			args = append(args, catalog)
+		}
+		conditions = append(conditions, fmt.Sprintf(
+			"((%s) AND mastercat.cat IN (%s))",
+			strings.Join(pixels, " OR "),
+			strings.TrimSuffix(strings.Repeat("?, ", len(catalogs)), ", "),
+		))
+	}
+	return strings.Join(conditions, " OR "), args, nil
+}
+
+// scan runs a query and passes each row to each, until it returns false
+func (t *TapService) scan(
+	ctx context.Context,
+	query string,
+	args []any,
+	width int,
+	each func([]any) bool,
+) error {
+	rows, err := t.repository.GetDbInstance().QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("could not run query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := make([]any, width)
+		pointers := make([]any, width)
+		for i := range row {
+			pointers[i] = &row[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return fmt.Errorf("could not read row: %w", err)
+		}
+		if !each(row) {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("could not read rows: %w", err)
+	}
+	return nil
+}
+
+// toSQL translates an expression without geometry to SQL, appending its parameters to args
+func toSQL(expr Expr, args *[]any) (string, error) {
+	switch e := expr.(type) {
+	case columnExpr:
+		return e.sql, nil
+	case NumberLit:
+		*args = append(*args, e.Value)
+		return "?", nil
+	case StringLit:
+		*args = append(*args, e.Value)
+		return "?", nil
+	case NullLit:
+		return "NULL", nil
+	case NotExpr:
+		inner, err := toSQL(e.Expr, args)
+		return "(NOT " + inner + ")", err
+	case NegExpr:
+		inner, err := toSQL(e.Expr, args)
+		return "(-" + inner + ")", err
+	case IsNullExpr:
+		inner, err := toSQL(e.Expr, args)
+		if e.Not {
+			return "(" + inner + " IS NOT NULL)", err
+		}
+		return "(" + inner + " IS NULL)", err
+	case BetweenExpr:
+		op := " BETWEEN "
+		if e.Not {
+			op = " NOT BETWEEN "
+		}
+		parts, err := sqlParts(args, e.Expr, e.Low, e.High)
+		if err != nil {
+			return "", err
+		}
+		return "(" + parts[0] + op + parts[1] + " AND " + parts[2] + ")", nil
+	case BinaryExpr:
+		parts, err := sqlParts(args, e.Left, e.Right)
+		if err != nil {
+			return "", err
+		}
+		left, right := parts[0], parts[1]
+		switch e.Op {
+		case "!=":
+			return "(" + left + " <> " + right + ")", nil
+		case "/":
+			// ADQL divisions are never integer divisions
+			return "(CAST(" + left + " AS REAL) / " + right + ")", nil
+		}
+		return "(" + left + " " + e.Op + " " + right + ")", nil
+	}
+	return "", queryError("Expression can not be translated to SQL", expr.String())
+}
+
+// sqlParts translates several expressions in order, so their parameters are appended in that order
+func sqlParts(args *[]any, exprs ...Expr) ([]string, error) {
+	parts := make([]string, len(exprs))
+	for i := range exprs {
+		var err error
+		if parts[i], err = toSQL(exprs[i], args); err != nil {
+			return nil, err
+		}
+	}
+	return parts, nil
+}
+
+func joinSQL(exprs []Expr, separator string, args *[]any) (string, error) {
+	parts, err := sqlParts(args, exprs...)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(parts, separator), nil
+}
+
+// convert returns a value read from the database or evaluated with the type of its column
+func convert(value any, datatype string) any {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case bool:
+		if datatype != "boolean" {
+			return toFloat(v)
+		}
+	case int64:
+		switch datatype {
+		case "double":
+			return float64(v)
+		case "boolean":
+			return v != 0
+		}
+	case float64:
+		switch datatype {
+		case "long", "int":
+			return int64(v)
+		case "boolean":
+			return v != 0
+		}
+	}
+	return value
+}
@@ -0,0 +1,230 @@
+// Copyright 2024-2025 Diego Rodriguez Mancini
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tap_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dirodriguezm/xmatch/service/internal/repository"
+	"github.com/dirodriguezm/xmatch/service/internal/search/conesearch/test_helpers"
+	"github.com/dirodriguezm/xmatch/service/internal/search/tap"
+	"github.com/dirodriguezm/xmatch/service/internal/testutils"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+var service *tap.TapService
+
+func TestMain(m *testing.M) {
+	rootPath, err := testutils.FindRootModulePath(5)
+	if err != nil {
+		panic(err)
+	}
+
+	dbDir, err := os.MkdirTemp("", "tap_test_db_*")
+	if err != nil {
+		panic(err)
+	}
+	dbFile := filepath.Join(dbDir, "test.db")
+	if err := test_helpers.Migrate(dbFile, rootPath); err != nil {
+		panic(fmt.Errorf("Error during migrations: %w", err))
+	}
+
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s", dbFile))
+	if err != nil {
+		panic(err)
+	}
+	ctx := context.Background()
+	if err := test_helpers.RegisterCatalogsInDB(ctx, dbFile); err != nil {
+		panic(fmt.Errorf("registering catalogs: %w", err))
+	}
+	// objects allwise-i are at ra = dec = i degrees
+	if err := test_helpers.InsertAllwiseMastercat(5, db); err != nil {
+		panic(err)
+	}
+	if err := test_helpers.InsertAllwiseMetadata(5, db); err != nil {
+		panic(err)
+	}
+
+	repo := repository.New(db)
+	catalogs, err := repo.GetCatalogs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	service, err = tap.NewTapService(repo, catalogs)
+	if err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+
+	db.Close()
+	os.RemoveAll(dbDir)
+	os.Exit(code)
+}
+
+func column(result tap.Result, index int) []any {
+	values := make([]any, len(result.Rows))
+	for i, row := range result.Rows {
+		values[i] = row[index]
+	}
+	return values
+}
+
+func TestQuery(t *testing.T) {
+	result, err := service.Query(context.Background(), "SELECT TOP 2 id, ra, ipix FROM mastercat WHERE dec >= 1 ORDER BY ra DESC", tap.DefaultMaxRec)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"id", "ra", "ipix"}, []string{result.Columns[0].Name, result.Columns[1].Name, result.Columns[2].Name})
+	require.Equal(t, "pos.eq.ra;meta.main", result.Columns[1].Ucd)
+	require.Equal(t, []any{"allwise-4", "allwise-3"}, column(result, 0))
+	require.Equal(t, []any{4.0, 3.0}, column(result, 1))
+	require.IsType(t, int64(0), result.Rows[0][2])
+	require.False(t, result.Overflow)
+}
+
+func TestQuery_Star(t *testing.T) {
+	result, err := service.Query(context.Background(), "SELECT * FROM allwise WHERE id = 'allwise-2'", tap.DefaultMaxRec)
+	require.NoError(t, err)
+
+	table, ok := tap.FindTable("allwise")
+	require.True(t, ok)
+	require.Equal(t, table.Columns, result.Columns)
+	require.Len(t, result.Rows, 1)
+	require.Equal(t, 2.0, result.Rows[0][indexOf(table, "dec")])
+	require.Equal(t, 1.0, result.Rows[0][indexOf(table, "w1mpro")])
+}
+
+func indexOf(table tap.Table, name string) int {
+	for i, column := range table.Columns {
+		if column.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestQuery_Contains(t *testing.T) {
+	result, err := service.Query(
+		context.Background(),
+		"SELECT a.id, a.w1mpro FROM allwise AS a WHERE 1 = CONTAINS(POINT('ICRS', a.ra, a.dec), CIRCLE('ICRS', 1.01, 1, 0.05))",
+		tap.DefaultMaxRec,
+	)
+	require.NoError(t, err)
+	require.Equal(t, [][]any{{"allwise-1", 1.0}}, result.Rows)
+}
+
+func TestQuery_Distance(t *testing.T) {
+	result, err := service.Query(
+		context.Background(),
+		"SELECT id, DISTANCE(POINT(ra, dec), POINT(2, 2)) AS dist FROM mastercat WHERE DISTANCE(POINT(ra, dec), POINT(2, 2)) < 1.5 AND cat = 'allwise' ORDER BY dist",
+		tap.DefaultMaxRec,
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "dist", result.Columns[1].Name)
+	require.Equal(t, "deg", result.Columns[1].Unit)
+	require.Len(t, result.Rows, 3)
+	require.Equal(t, "allwise-2", result.Rows[0][0])
+	require.InDelta(t, 0, result.Rows[0][1], 1e-9)
+	require.ElementsMatch(t, []any{"allwise-1", "allwise-3"}, column(result, 0)[1:])
+	require.InDelta(t, 1.414, result.Rows[1][1], 1e-3)
+}
+
+func TestQuery_OrderedCone(t *testing.T) {
+	// the cone holds every object, but only the first ones in the order are kept while reading
+	result, err := service.Query(
+		context.Background(),
+		"SELECT TOP 2 id FROM mastercat WHERE CONTAINS(POINT(ra, dec), CIRCLE(2, 2, 5)) = 1 ORDER BY ra DESC",
+		tap.DefaultMaxRec,
+	)
+	require.NoError(t, err)
+	require.Equal(t, [][]any{{"allwise-4"}, {"allwise-3"}}, result.Rows)
+
+	result, err = service.Query(
+		context.Background(),
+		"SELECT id FROM mastercat WHERE CONTAINS(POINT(ra, dec), CIRCLE(2, 2, 5)) = 1 ORDER BY ra",
+		1,
+	)
+	require.NoError(t, err)
+	require.Equal(t, [][]any{{"allwise-0"}}, result.Rows)
+	require.True(t, result.Overflow)
+}
+
+func TestQuery_Erosita(t *testing.T) {
+	table, ok := tap.FindTable("erosita")
+	require.True(t, ok, "every catalog with metadata in the registry has a table")
+	require.Equal(t, "ml_flux_1", table.Columns[indexOf(table, "ml_flux_1")].Name)
+
+	result, err := service.Query(context.Background(), "SELECT id, ml_flux_1 FROM erosita", tap.DefaultMaxRec)
+	require.NoError(t, err)
+	require.Empty(t, result.Rows)
+}
+
+func TestQuery_Overflow(t *testing.T) {
+	result, err := service.Query(context.Background(), "SELECT id FROM mastercat", 2)
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 2)
+	require.True(t, result.Overflow)
+
+	result, err = service.Query(context.Background(), "SELECT TOP 2 id FROM mastercat WHERE CONTAINS(POINT(ra, dec), CIRCLE(2, 2, 3)) = 1", 2)
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 2)
+	require.False(t, result.Overflow)
+
+	result, err = service.Query(context.Background(), "SELECT id FROM mastercat WHERE CONTAINS(POINT(ra, dec), CIRCLE(2, 2, 3)) = 1", 2)
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 2)
+	require.True(t, result.Overflow)
+}
+
+func TestQuery_Errors(t *testing.T) {
+	testCases := map[string]string{
+		"SELECT * FROM vlass":                                                                 "Unknown table",
+		"SELECT * FROM xwave":                                                                 "Unknown table",
+		"SELECT id FROM gaia WHERE ra":                                                        "WHERE must be a condition, like a comparison",
+		"SELECT id FROM gaia WHERE dec > 1 AND NOT ra + 1":                                    "WHERE must be a condition, like a comparison",
+		"SELECT magnitude FROM allwise":                                                       "Unknown column",
+		"SELECT x.id FROM allwise AS a":                                                       "Unknown table",
+		"SELECT DISTANCE(POINT(ra, dec), POINT(1, 1)) FROM gaia":                              "Queries using geometry must constrain the position with CONTAINS(POINT(ra, dec), CIRCLE(ra, dec, radius)) = 1 or DISTANCE(POINT(ra, dec), POINT(ra, dec)) < radius",
+		"SELECT id FROM gaia WHERE DISTANCE(POINT(ra, dec), POINT(1, 1)) > 1":                 "Geometry can only be used in conditions like CONTAINS(POINT(ra, dec), CIRCLE(ra, dec, radius)) = 1 or DISTANCE(POINT(ra, dec), POINT(ra, dec)) < radius",
+		"SELECT id FROM gaia WHERE CONTAINS(POINT(ra + 1, dec), CIRCLE(1, 1, 1)) = 1":         "CONTAINS must test the position POINT(ra, dec) of the table",
+		"SELECT id FROM gaia WHERE CONTAINS(POINT(ra, dec), CIRCLE(1, 1, pos_err)) = 1":       "The center and radius of the searched region must be numbers",
+		"SELECT id FROM gaia WHERE CONTAINS(POINT(ra, dec), CIRCLE(1, 1, 200)) = 1":           "Radius must be between 0 and 180 degrees",
+		"SELECT id FROM gaia WHERE CONTAINS(POINT('GALACTIC', ra, dec), CIRCLE(1, 1, 1)) = 1": "Only ICRS coordinates are supported",
+		"SELECT POINT(ra, dec) FROM gaia":                                                     "POINT can only be used inside CONTAINS or DISTANCE",
+		"SELECT id FROM gaia WHERE CIRCLE(1, 1, 1) = 1":                                       "Geometry can only be used inside CONTAINS or DISTANCE",
+	}
+	for adql, reason := range testCases {
+		t.Run(adql, func(t *testing.T) {
+			_, err := service.Query(context.Background(), adql, tap.DefaultMaxRec)
+			var validationErr tap.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			require.Equal(t, "QUERY", validationErr.Field)
+			require.Equal(t, reason, validationErr.Reason)
+		})
+	}
+
+	_, err := service.Query(context.Background(), "SELECT id FROM mastercat", tap.MaxRec+1)
+	var validationErr tap.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, "MAXREC", validationErr.Field)
+}
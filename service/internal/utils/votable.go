@@ -4,6 +4,11 @@ import (
 	"encoding/xml"
 )
 
+const (
+	VOTableVersion   = "1.3"
+	VOTableNamespace = "http://www.ivoa.net/xml/VOTable/v1.3"
+)
+
 // VOTable represents the VOTable structure
 type VOTable struct {
 	XMLName  xml.Name `xml:"VOTABLE"`
@@ -16,20 +21,23 @@ type VOTable struct {
 type Resource struct {
 	Type   string   `xml:"type,attr"`
 	Infos  []Info   `xml:"INFO"`
+	Coosys []Coosys `xml:"COOSYS"`
 	Params []Param  `xml:"PARAM"`
 	Tables []Table  `xml:"TABLE"`
-	Coosys []Coosys `xml:"COOSYS"`
 }
 
 // Info represents an INFO element in VOTable
 type Info struct {
+	ID          string `xml:"ID,attr,omitempty"`
 	Name        string `xml:"name,attr"`
 	Value       string `xml:"value,attr"`
 	Description string `xml:"DESCRIPTION,omitempty"`
+	Text        string `xml:",chardata"`
 }
 
 // Param represents a PARAM element in VOTable
 type Param struct {
+	ID    string `xml:"ID,attr,omitempty"`
 	Name  string `xml:"name,attr"`
 	Value string `xml:"value,attr"`
 	Unit  string `xml:"unit,attr,omitempty"`
@@ -117,3 +125,21 @@ func NewVOTableFromBytes(xmlRepr []byte) (*VOTable, error) {
 	}
 	return &votable, nil
 }
+
+// NewVOTable creates a VOTable with a single results resource holding the given tables
+func NewVOTable(tables ...Table) *VOTable {
+	return &VOTable{
+		Version:  VOTableVersion,
+		Xmlns:    VOTableNamespace,
+		Resource: Resource{Type: "results", Tables: tables},
+	}
+}
+
+// Bytes returns the XML representation of the VOTable, including the XML declaration
+func (v *VOTable) Bytes() ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}